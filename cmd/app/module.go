@@ -1,26 +1,46 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"log"
 
 	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/file"
+	"github.com/codepzj/Stellux-Server/internal/mail"
+	"github.com/codepzj/Stellux-Server/internal/post"
+	"github.com/codepzj/Stellux-Server/internal/search"
 	"github.com/gin-gonic/gin"
 )
 
 type HttpServer struct {
-	engine *gin.Engine
-	cfg    *conf.Config
+	engine     *gin.Engine
+	cfg        *conf.Config
+	postServ   post.Service
+	mailServ   mail.Service
+	searchServ search.Service
+	fileServ   file.Service
 }
 
-func NewHttpServer(engine *gin.Engine, cfg *conf.Config) *HttpServer {
+func NewHttpServer(engine *gin.Engine, cfg *conf.Config, postServ post.Service, mailServ mail.Service, searchServ search.Service, fileServ file.Service) *HttpServer {
 	return &HttpServer{
-		engine: engine,
-		cfg:    cfg,
+		engine:     engine,
+		cfg:        cfg,
+		postServ:   postServ,
+		mailServ:   mailServ,
+		searchServ: searchServ,
+		fileServ:   fileServ,
 	}
 }
 
 func (s *HttpServer) Start() {
+	// 后台任务
+	s.postServ.StartPublishScheduler(context.Background())
+	s.mailServ.StartOutboxWorker(context.Background())
+	s.searchServ.StartIndexer(context.Background())
+	s.fileServ.StartGC(context.Background())
+	s.fileServ.StartUploadCleaner(context.Background())
+
 	addr := fmt.Sprintf(":%d", s.cfg.Server.Port)
 	if err := s.engine.Run(addr); err != nil {
 		log.Fatalf("启动服务器失败: %v", err)
@@ -5,8 +5,12 @@ package app
 
 import (
 	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/antispam"
+	"github.com/codepzj/Stellux-Server/internal/audit"
+	"github.com/codepzj/Stellux-Server/internal/comment"
 	"github.com/codepzj/Stellux-Server/internal/document"
 	"github.com/codepzj/Stellux-Server/internal/document_content"
+	"github.com/codepzj/Stellux-Server/internal/feed"
 	"github.com/codepzj/Stellux-Server/internal/infra"
 	"github.com/codepzj/Stellux-Server/internal/ioc"
 	"github.com/codepzj/Stellux-Server/internal/config"
@@ -15,13 +19,22 @@ import (
 	"github.com/codepzj/Stellux-Server/internal/file"
 	"github.com/codepzj/Stellux-Server/internal/friend"
 	"github.com/codepzj/Stellux-Server/internal/label"
+	"github.com/codepzj/Stellux-Server/internal/mail"
 	"github.com/codepzj/Stellux-Server/internal/post"
+	"github.com/codepzj/Stellux-Server/internal/rbac"
+	"github.com/codepzj/Stellux-Server/internal/search"
+	"github.com/codepzj/Stellux-Server/internal/sitemap"
 	"github.com/google/wire"
 )
 
 // 基础设施
 var InfraProvider = wire.NewSet(
 	infra.NewMongoDB,
+	infra.NewMarkdownRenderer,
+	infra.NewMailer,
+	infra.NewTokenManager,
+	infra.NewLimiterStore,
+	infra.NewStorage,
 )
 
 // 控制反转
@@ -35,29 +48,53 @@ func InitApp(cfg *conf.Config) *HttpServer {
 		InfraProvider,
 		IocProvider,
 
+		rbac.InitRbacModule,
+		wire.FieldsOf(new(*rbac.Module), "Hdl", "Svc"),
+
+		audit.InitAuditModule,
+		wire.FieldsOf(new(*audit.Module), "Hdl", "Svc"),
+
 		user.InitUserModule,
-		wire.FieldsOf(new(*user.Module), "Hdl"),
+		wire.FieldsOf(new(*user.Module), "Hdl", "SessionSvc", "PersonalTokenSvc"),
+
+		sitemap.InitSitemapModule,
+		wire.FieldsOf(new(*sitemap.Module), "Hdl", "Svc"),
+
+		search.InitSearchModule,
+		wire.FieldsOf(new(*search.Module), "Hdl", "Svc"),
 
 		post.InitPostModule,
-		wire.FieldsOf(new(*post.Module), "Hdl"),
+		wire.FieldsOf(new(*post.Module), "Hdl", "Svc"),
 
 		label.InitLabelModule,
-		wire.FieldsOf(new(*label.Module), "Hdl"),
+		wire.FieldsOf(new(*label.Module), "Hdl", "Svc"),
 
 		file.InitFileModule,
-		wire.FieldsOf(new(*file.Module), "Hdl"),
+		wire.FieldsOf(new(*file.Module), "Hdl", "Svc"),
 
 		document.InitDocumentModule,
-		wire.FieldsOf(new(*document.Module), "Hdl"),
+		wire.FieldsOf(new(*document.Module), "Hdl", "Svc"),
 
 		document_content.InitDocumentContentModule,
-		wire.FieldsOf(new(*document_content.Module), "Hdl"),
+		wire.FieldsOf(new(*document_content.Module), "Hdl", "Svc"),
+
+		mail.InitMailModule,
+		wire.FieldsOf(new(*mail.Module), "Hdl", "Svc"),
+
+		antispam.InitAntiSpamModule,
+		wire.FieldsOf(new(*antispam.Module), "Hdl", "Svc"),
 
 		friend.InitFriendModule,
 		wire.FieldsOf(new(*friend.Module), "Hdl"),
 
 		config.InitConfigModule,
-		wire.FieldsOf(new(*config.Module), "Hdl"),
+		wire.FieldsOf(new(*config.Module), "Hdl", "Svc"),
+
+		comment.InitCommentModule,
+		wire.FieldsOf(new(*comment.Module), "Hdl"),
+
+		feed.InitFeedModule,
+		wire.FieldsOf(new(*feed.Module), "Hdl"),
 
 		NewHttpServer,
 	)
@@ -8,15 +8,23 @@ package app
 
 import (
 	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/antispam"
+	"github.com/codepzj/Stellux-Server/internal/audit"
+	"github.com/codepzj/Stellux-Server/internal/comment"
 	"github.com/codepzj/Stellux-Server/internal/config"
 	"github.com/codepzj/Stellux-Server/internal/document"
 	"github.com/codepzj/Stellux-Server/internal/document_content"
+	"github.com/codepzj/Stellux-Server/internal/feed"
 	"github.com/codepzj/Stellux-Server/internal/file"
 	"github.com/codepzj/Stellux-Server/internal/friend"
 	"github.com/codepzj/Stellux-Server/internal/infra"
 	"github.com/codepzj/Stellux-Server/internal/ioc"
 	"github.com/codepzj/Stellux-Server/internal/label"
+	"github.com/codepzj/Stellux-Server/internal/mail"
 	"github.com/codepzj/Stellux-Server/internal/post"
+	"github.com/codepzj/Stellux-Server/internal/rbac"
+	"github.com/codepzj/Stellux-Server/internal/search"
+	"github.com/codepzj/Stellux-Server/internal/sitemap"
 	"github.com/codepzj/Stellux-Server/internal/user"
 	"github.com/google/wire"
 )
@@ -25,32 +33,67 @@ import (
 
 func InitApp(cfg *conf.Config) *HttpServer {
 	database := infra.NewMongoDB(cfg)
-	module := user.InitUserModule(database)
+	rbacModule := rbac.InitRbacModule(database)
+	iRbacService := rbacModule.Svc
+	manager := infra.NewTokenManager(cfg)
+	store := infra.NewLimiterStore(cfg, database)
+	auditModule := audit.InitAuditModule(database)
+	iAuditService := auditModule.Svc
+	module := user.InitUserModule(database, cfg, manager, store, iRbacService, iAuditService)
 	userHandler := module.Hdl
-	postModule := post.InitPostModule(database)
+	sitemapModule := sitemap.InitSitemapModule(database)
+	iSitemapService := sitemapModule.Svc
+	searchModule := search.InitSearchModule(database, cfg)
+	iSearchService := searchModule.Svc
+	renderer := infra.NewMarkdownRenderer(cfg)
+	postModule := post.InitPostModule(database, cfg, iSearchService, iSitemapService, renderer, iAuditService)
 	postHandler := postModule.Hdl
-	labelModule := label.InitLabelModule(database)
+	iPostService := postModule.Svc
+	labelModule := label.InitLabelModule(database, iSitemapService, iAuditService)
 	labelHandler := labelModule.Hdl
-	fileModule := file.InitFileModule(database)
+	iLabelService := labelModule.Svc
+	storageStorage := infra.NewStorage(cfg)
+	fileModule := file.InitFileModule(database, iAuditService, storageStorage, cfg)
 	fileHandler := fileModule.Hdl
-	documentModule := document.InitDocumentModule(database)
+	iFileService := fileModule.Svc
+	documentModule := document.InitDocumentModule(database, iSitemapService, iAuditService)
 	documentHandler := documentModule.Hdl
-	document_contentModule := document_content.InitDocumentContentModule(database)
+	iDocumentService := documentModule.Svc
+	document_contentModule := document_content.InitDocumentContentModule(database, iSearchService, iSitemapService, renderer, iAuditService)
 	documentContentHandler := document_contentModule.Hdl
-	friendModule := friend.InitFriendModule(database)
+	iDocumentContentService := document_contentModule.Svc
+	mailer := infra.NewMailer(cfg)
+	mailModule := mail.InitMailModule(database, mailer, cfg)
+	iMailService := mailModule.Svc
+	antispamModule := antispam.InitAntiSpamModule(database, cfg)
+	iAntiSpamService := antispamModule.Svc
+	friendModule := friend.InitFriendModule(database, iAntiSpamService, iAuditService)
 	friendHandler := friendModule.Hdl
-	configModule := config.InitConfigModule(database)
+	configModule := config.InitConfigModule(database, iSitemapService, iAuditService)
 	configHandler := configModule.Hdl
-	v := ioc.InitMiddleWare()
-	engine := ioc.NewGin(userHandler, postHandler, labelHandler, fileHandler, documentHandler, documentContentHandler, friendHandler, configHandler, v)
-	httpServer := NewHttpServer(engine, cfg)
+	iConfigService := configModule.Svc
+	commentModule := comment.InitCommentModule(database, iConfigService, iAntiSpamService, iMailService)
+	commentHandler := commentModule.Hdl
+	feedModule := feed.InitFeedModule(iPostService, iLabelService, iDocumentService, iDocumentContentService, iConfigService)
+	feedHandler := feedModule.Hdl
+	sitemapHandler := sitemapModule.Hdl
+	antiSpamHandler := antispamModule.Hdl
+	mailHandler := mailModule.Hdl
+	searchHandler := searchModule.Hdl
+	rbacHandler := rbacModule.Hdl
+	auditHandler := auditModule.Hdl
+	iSessionService := module.SessionSvc
+	iPersonalTokenService := module.PersonalTokenSvc
+	v := ioc.InitMiddleWare(iRbacService)
+	engine := ioc.NewGin(userHandler, postHandler, labelHandler, fileHandler, documentHandler, documentContentHandler, friendHandler, configHandler, commentHandler, antiSpamHandler, mailHandler, searchHandler, feedHandler, sitemapHandler, rbacHandler, auditHandler, manager, iSessionService, iPersonalTokenService, v, cfg)
+	httpServer := NewHttpServer(engine, cfg, iPostService, iMailService, iSearchService, iFileService)
 	return httpServer
 }
 
 // wire.go:
 
 // 基础设施
-var InfraProvider = wire.NewSet(infra.NewMongoDB)
+var InfraProvider = wire.NewSet(infra.NewMongoDB, infra.NewMarkdownRenderer, infra.NewMailer, infra.NewTokenManager, infra.NewLimiterStore, infra.NewStorage)
 
 // 控制反转
 var IocProvider = wire.NewSet(ioc.InitMiddleWare, ioc.NewGin)
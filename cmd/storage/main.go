@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/audit"
+	"github.com/codepzj/Stellux-Server/internal/file"
+	"github.com/codepzj/Stellux-Server/internal/infra"
+)
+
+// 在存储之间迁移文件并改写文件访问地址, 迁移完成后将配置中的Storage.DRIVER改为目标存储
+// eg: go run ./cmd/storage -cfg_path conf/dev.yaml -from local -to s3
+var (
+	cfgPath    = flag.String("cfg_path", "conf/dev.yaml", "配置文件路径,eg: conf/dev.yaml")
+	from       = flag.String("from", "local", "源存储: local, s3")
+	to         = flag.String("to", "s3", "目标存储: local, s3")
+	keepSource = flag.Bool("keep_source", false, "迁移后保留源存储中的文件")
+)
+
+func main() {
+	flag.Parse()
+	if *from == *to {
+		log.Fatalln("源存储和目标存储不能相同")
+	}
+
+	cfg := conf.GetConfig(*cfgPath)
+	infra.InitLogger(cfg)
+
+	fromStorage, err := infra.NewStorageByDriver(cfg, *from)
+	if err != nil {
+		log.Fatalln("初始化源存储失败", err)
+	}
+	toStorage, err := infra.NewStorageByDriver(cfg, *to)
+	if err != nil {
+		log.Fatalln("初始化目标存储失败", err)
+	}
+
+	db := infra.NewMongoDB(cfg)
+	fileModule := file.InitFileModule(db, audit.InitAuditModule(db).Svc, toStorage, cfg)
+	result, err := fileModule.Svc.MigrateFiles(context.Background(), fromStorage, toStorage, *keepSource)
+	if err != nil {
+		log.Fatalln("迁移文件失败", err)
+	}
+	log.Printf("迁移完成: 共%d个文件, 成功%d个, 缺失%d个, 失败%d个\n", result.Total, result.Migrated, result.Missing, result.Failed)
+	if result.Failed > 0 {
+		log.Fatalln("部分文件迁移失败, 请检查日志后重新执行")
+	}
+}
@@ -8,8 +8,19 @@ import (
 )
 
 type Config struct {
-	MongoDB MongoDB `mapstructure:"MongoDB"`
-	Server  Server  `mapstructure:"Server"`
+	MongoDB  MongoDB  `mapstructure:"MongoDB"`
+	Server   Server   `mapstructure:"Server"`
+	Mail     Mail     `mapstructure:"Mail"`
+	Revision Revision `mapstructure:"Revision"`
+	Search   Search   `mapstructure:"Search"`
+	Markdown Markdown `mapstructure:"Markdown"`
+	Auth     Auth     `mapstructure:"Auth"`
+	Login    Login    `mapstructure:"Login"`
+	Storage  Storage  `mapstructure:"Storage"`
+	Image    Image    `mapstructure:"Image"`
+	Upload   Upload   `mapstructure:"Upload"`
+	FileGC   FileGC   `mapstructure:"FileGC"`
+	AntiSpam AntiSpam `mapstructure:"AntiSpam"`
 }
 
 type MongoDB struct {
@@ -23,9 +34,10 @@ type MongoDB struct {
 }
 
 type Server struct {
-	Port      int    `mapstructure:"PORT"`
-	JwtSecret string `mapstructure:"JWT_SECRET"`
-	Log       Log    `mapstructure:"Log"`
+	Port           int      `mapstructure:"PORT"`
+	JwtSecret      string   `mapstructure:"JWT_SECRET"`
+	TrustedProxies []string `mapstructure:"TRUSTED_PROXIES"` // 信任的反向代理IP或网段, 只有来自这些地址的X-Forwarded-For才会用于获取客户端IP, 为空时不信任任何代理
+	Log            Log      `mapstructure:"Log"`
 }
 
 type Log struct {
@@ -39,6 +51,108 @@ type Log struct {
 	Compress   bool   `mapstructure:"COMPRESS"`
 }
 
+type Mail struct {
+	Driver     string `mapstructure:"DRIVER"`      // 发送方式: smtp, log
+	Host       string `mapstructure:"HOST"`        // SMTP服务器地址
+	Port       int    `mapstructure:"PORT"`        // SMTP服务器端口, 465使用SSL, 其余端口按服务器能力使用STARTTLS
+	Username   string `mapstructure:"USERNAME"`    // SMTP用户名
+	Password   string `mapstructure:"PASSWORD"`    // SMTP密码或授权码
+	From       string `mapstructure:"FROM"`        // 发件人邮箱
+	FromName   string `mapstructure:"FROM_NAME"`   // 发件人名称
+	LogDir     string `mapstructure:"LOG_DIR"`     // log方式下邮件保存目录
+	AdminEmail string `mapstructure:"ADMIN_EMAIL"` // 管理员邮箱, 接收待审核评论提醒
+	SiteURL    string `mapstructure:"SITE_URL"`    // 站点地址, 用于生成文章链接
+	ServerURL  string `mapstructure:"SERVER_URL"`  // 服务端地址, 用于生成退订链接
+	Secret     string `mapstructure:"SECRET"`      // 退订令牌签名密钥, 必须配置
+	MaxRetry   int    `mapstructure:"MAX_RETRY"`   // 发送失败最大重试次数
+}
+
+type Revision struct {
+	MaxCount int `mapstructure:"MAX_COUNT"` // 每篇文章最多保留的历史版本数, 0为不限制
+	MaxDays  int `mapstructure:"MAX_DAYS"`  // 历史版本最长保留天数, 0为不限制
+}
+
+type Search struct {
+	IndexPath string `mapstructure:"INDEX_PATH"` // 全文索引文件路径, 为空时使用data/search.idx
+}
+
+type Markdown struct {
+	CacheSize int `mapstructure:"CACHE_SIZE"` // 缓存的渲染结果数量, 为0时使用默认值512
+}
+
+type AntiSpam struct {
+	TokenSecret        string `mapstructure:"TOKEN_SECRET"`          // 表单令牌签名密钥, 多实例部署需配置相同的密钥, 为空时启动时随机生成
+	TokenMaxAgeMinutes int    `mapstructure:"TOKEN_MAX_AGE_MINUTES"` // 表单令牌有效期(分钟), 为0时使用默认值120
+}
+
+type Auth struct {
+	AccessTokenMinutes int       `mapstructure:"ACCESS_TOKEN_MINUTES"` // 访问令牌有效期(分钟), 为0时使用默认值15
+	RefreshTokenDays   int       `mapstructure:"REFRESH_TOKEN_DAYS"`   // 刷新令牌有效期(天), 每次刷新后顺延, 为0时使用默认值7
+	ActiveKid          string    `mapstructure:"ACTIVE_KID"`           // 签发访问令牌使用的密钥, 为空时使用第一把可签名的密钥
+	Keys               []AuthKey `mapstructure:"KEYS"`                 // 令牌签名密钥, 为空时使用Server.JWT_SECRET作为HS256密钥
+}
+
+// AuthKey 令牌签名密钥, 轮换时新增密钥并切换ACTIVE_KID, 旧密钥保留到其签发的令牌过期后再移除
+type AuthKey struct {
+	Kid            string `mapstructure:"KID"`              // 密钥Id, 写入令牌头的kid
+	Alg            string `mapstructure:"ALG"`              // 签名算法: HS256, RS256, EdDSA
+	Secret         string `mapstructure:"SECRET"`           // HS256密钥
+	PrivateKeyFile string `mapstructure:"PRIVATE_KEY_FILE"` // RS256/EdDSA私钥PEM文件
+	PublicKeyFile  string `mapstructure:"PUBLIC_KEY_FILE"`  // RS256/EdDSA公钥PEM文件, 只配置公钥的密钥仅用于验证
+}
+
+type Login struct {
+	Store           string `mapstructure:"STORE"`             // 失败记录存储: memory, mongo, 多实例部署需使用mongo
+	FreeAttempts    int    `mapstructure:"FREE_ATTEMPTS"`     // 不受限制的连续失败次数, 之后指数退避, 为0时使用默认值3
+	MaxFailures     int    `mapstructure:"MAX_FAILURES"`      // 同一用户名连续失败达到该次数后锁定, 为0时使用默认值10
+	IPMaxFailures   int    `mapstructure:"IP_MAX_FAILURES"`   // 同一IP连续失败达到该次数后锁定, 为0时使用默认值50
+	MaxDelaySeconds int    `mapstructure:"MAX_DELAY_SECONDS"` // 退避等待最长时间(秒), 为0时使用默认值60
+	LockMinutes     int    `mapstructure:"LOCK_MINUTES"`      // 锁定时长(分钟), 最近一次失败超过该时长后失败次数清零, 为0时使用默认值15
+}
+
+type Storage struct {
+	Driver   string `mapstructure:"DRIVER"`    // 文件存储: local, s3, 多实例或容器部署需使用s3
+	LocalDir string `mapstructure:"LOCAL_DIR"` // local方式下文件保存目录, 为空时使用static/images
+	S3       S3     `mapstructure:"S3"`
+}
+
+// S3 S3兼容对象存储, 支持AWS S3、MinIO等
+type S3 struct {
+	Endpoint  string `mapstructure:"ENDPOINT"`   // 服务地址, 不带协议, 如127.0.0.1:9000
+	AccessKey string `mapstructure:"ACCESS_KEY"` // 访问密钥Id
+	SecretKey string `mapstructure:"SECRET_KEY"` // 访问密钥
+	Bucket    string `mapstructure:"BUCKET"`     // 存储桶, 不存在时自动创建
+	Region    string `mapstructure:"REGION"`     // 区域, MinIO可为空
+	UseSSL    bool   `mapstructure:"USE_SSL"`    // 是否使用https连接
+	Prefix    string `mapstructure:"PREFIX"`     // 对象key前缀
+	PublicURL string `mapstructure:"PUBLIC_URL"` // 公开访问地址, 如CDN域名, 为空时由服务端转发/images请求
+}
+
+type Image struct {
+	VariantWidths []int  `mapstructure:"VARIANT_WIDTHS"` // 上传图片时生成的缩略图宽度, 为空时使用320,768,1280
+	MaxWidth      int    `mapstructure:"MAX_WIDTH"`      // 按需缩放允许的最大宽度, 为0时使用默认值2560
+	CacheDir      string `mapstructure:"CACHE_DIR"`      // 按需缩放结果的缓存目录, 为空时使用data/image_cache
+}
+
+type Upload struct {
+	Rules                []UploadRule `mapstructure:"RULES"`                  // 允许上传的文件类型及大小限制, 为空时只允许jpeg/png/gif/webp图片且不超过10MB
+	ResumableDir         string       `mapstructure:"RESUMABLE_DIR"`          // 断点续传的临时目录, 为空时使用data/uploads, 断点续传只支持单实例, 多实例部署需把上传请求固定转发到同一实例
+	ResumableExpireHours int          `mapstructure:"RESUMABLE_EXPIRE_HOURS"` // 断点续传会话最后一次写入后的保留时长(小时), 为0时使用默认值24
+}
+
+// UploadRule 一组文件类型共用的大小限制, 类型按文件内容识别, 与扩展名无关
+type UploadRule struct {
+	MimeTypes []string `mapstructure:"MIME_TYPES"` // MIME类型, 如image/png
+	MaxSize   int64    `mapstructure:"MAX_SIZE"`   // 单个文件最大字节数
+}
+
+// FileGC 未被文章、文档、友链、网站配置、用户头像和评论引用的文件的垃圾回收
+type FileGC struct {
+	IntervalHours int   `mapstructure:"INTERVAL_HOURS"` // 回收间隔(小时), 为0时使用默认值24, 小于0时不自动回收
+	GraceDays     int   `mapstructure:"GRACE_DAYS"`     // 文件未被引用超过该天数后删除, 为0时使用默认值7
+	DryRun        *bool `mapstructure:"DRY_RUN"`        // 只标记和报告未被引用的文件, 不删除, 未配置时默认为true
+}
+
 func GetConfig(cfgPath string) *Config {
 	v := viper.New()
 
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// 反垃圾规则类型
+const (
+	RuleTypeKeyword       = "keyword"         // 关键词黑名单
+	RuleTypeRegex         = "regex"           // 正则黑名单
+	RuleTypeIp            = "ip"              // IP黑名单, 支持CIDR
+	RuleTypeEmail         = "email"           // 邮箱黑名单, 以@开头时匹配整个域名
+	RuleTypeLinkLimit     = "link_limit"      // 内容中允许的最大链接数
+	RuleTypeMinSubmitTime = "min_submit_time" // 最短提交时间(秒)
+)
+
+type Rule struct {
+	Id        bson.ObjectID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Type      string
+	Value     string
+	Remark    string
+	Enabled   bool
+}
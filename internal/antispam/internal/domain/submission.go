@@ -0,0 +1,19 @@
+package domain
+
+// Submission 待检测的访客提交内容
+type Submission struct {
+	Content  string // 正文
+	Nickname string // 昵称
+	Email    string // 邮箱
+	SiteUrl  string // 网站链接
+	Ip       string // 客户端IP
+	Honeypot string // 蜜罐字段, 正常用户不会填写
+	Token    string // 表单令牌, 用于校验最短提交时间
+}
+
+// Verdict 检测结果
+type Verdict struct {
+	Spam    bool   // 是否为垃圾内容
+	Checker string // 命中的检测器
+	Reason  string // 命中原因
+}
@@ -0,0 +1,113 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type Rule struct {
+	ID        bson.ObjectID `bson:"_id,omitempty"`
+	CreatedAt time.Time     `bson:"created_at"`
+	UpdatedAt time.Time     `bson:"updated_at"`
+	Type      string        `bson:"type"`    // 规则类型
+	Value     string        `bson:"value"`   // 规则内容
+	Remark    string        `bson:"remark"`  // 备注
+	Enabled   bool          `bson:"enabled"` // 是否启用
+}
+
+type IRuleDao interface {
+	Create(ctx context.Context, rule *Rule) error
+	GetByID(ctx context.Context, id bson.ObjectID) (*Rule, error)
+	GetList(ctx context.Context, filter bson.D) ([]*Rule, error)
+	Update(ctx context.Context, id bson.ObjectID, rule *Rule) error
+	Delete(ctx context.Context, id bson.ObjectID) error
+}
+
+var _ IRuleDao = (*RuleDao)(nil)
+
+func NewRuleDao(db *mongo.Database) *RuleDao {
+	return &RuleDao{coll: db.Collection("spam_rule")}
+}
+
+type RuleDao struct {
+	coll *mongo.Collection
+}
+
+// Create 创建规则
+func (d *RuleDao) Create(ctx context.Context, rule *Rule) error {
+	rule.ID = bson.NewObjectID()
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = time.Now()
+	res, err := d.coll.InsertOne(ctx, rule)
+	if err != nil {
+		return err
+	}
+	if res.InsertedID == nil {
+		return errors.New("创建规则失败")
+	}
+	return nil
+}
+
+// GetByID 根据id获取规则
+func (d *RuleDao) GetByID(ctx context.Context, id bson.ObjectID) (*Rule, error) {
+	var rule Rule
+	err := d.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&rule)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// GetList 获取规则列表
+func (d *RuleDao) GetList(ctx context.Context, filter bson.D) ([]*Rule, error) {
+	opts := options.Find().SetSort(bson.M{"created_at": -1})
+	cursor, err := d.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rules []*Rule
+	if err = cursor.All(ctx, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Update 更新规则
+func (d *RuleDao) Update(ctx context.Context, id bson.ObjectID, rule *Rule) error {
+	update := bson.M{
+		"$set": bson.M{
+			"type":       rule.Type,
+			"value":      rule.Value,
+			"remark":     rule.Remark,
+			"enabled":    rule.Enabled,
+			"updated_at": time.Now(),
+		},
+	}
+	res, err := d.coll.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("规则不存在")
+	}
+	return nil
+}
+
+// Delete 删除规则
+func (d *RuleDao) Delete(ctx context.Context, id bson.ObjectID) error {
+	res, err := d.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return errors.New("规则不存在")
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// UsedToken 已使用的表单令牌, 令牌过期后由TTL索引自动清理
+type UsedToken struct {
+	Nonce     string    `bson:"_id"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+type IUsedTokenDao interface {
+	Create(ctx context.Context, token *UsedToken) error
+}
+
+var _ IUsedTokenDao = (*UsedTokenDao)(nil)
+
+func NewUsedTokenDao(db *mongo.Database) *UsedTokenDao {
+	d := &UsedTokenDao{coll: db.Collection("spam_used_token")}
+	d.ensureIndexes()
+	return d
+}
+
+type UsedTokenDao struct {
+	coll *mongo.Collection
+}
+
+func (d *UsedTokenDao) ensureIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := d.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		logger.Warn("创建表单令牌索引失败",
+			logger.WithError(err),
+		)
+	}
+}
+
+// Create 记录已使用的令牌, 令牌已使用过时返回重复键错误
+func (d *UsedTokenDao) Create(ctx context.Context, token *UsedToken) error {
+	_, err := d.coll.InsertOne(ctx, token)
+	return err
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/antispam/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/antispam/internal/repository/dao"
+	"github.com/samber/lo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type IRuleRepository interface {
+	Create(ctx context.Context, rule *domain.Rule) error
+	GetByID(ctx context.Context, id bson.ObjectID) (*domain.Rule, error)
+	GetList(ctx context.Context, ruleType string) ([]*domain.Rule, error)
+	GetEnabledList(ctx context.Context) ([]*domain.Rule, error)
+	Update(ctx context.Context, rule *domain.Rule) error
+	Delete(ctx context.Context, id bson.ObjectID) error
+	UseToken(ctx context.Context, nonce string, expiresAt time.Time) (bool, error)
+}
+
+var _ IRuleRepository = (*RuleRepository)(nil)
+
+func NewRuleRepository(dao dao.IRuleDao, usedTokenDao dao.IUsedTokenDao) *RuleRepository {
+	return &RuleRepository{dao: dao, usedTokenDao: usedTokenDao}
+}
+
+type RuleRepository struct {
+	dao          dao.IRuleDao
+	usedTokenDao dao.IUsedTokenDao
+}
+
+// Create 创建规则
+func (r *RuleRepository) Create(ctx context.Context, rule *domain.Rule) error {
+	return r.dao.Create(ctx, r.RuleDomainToDao(rule))
+}
+
+// GetByID 根据id获取规则
+func (r *RuleRepository) GetByID(ctx context.Context, id bson.ObjectID) (*domain.Rule, error) {
+	rule, err := r.dao.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.RuleDaoToDomain(rule), nil
+}
+
+// GetList 获取规则列表, ruleType为空时查询全部类型
+func (r *RuleRepository) GetList(ctx context.Context, ruleType string) ([]*domain.Rule, error) {
+	filter := bson.D{}
+	if ruleType != "" {
+		filter = append(filter, bson.E{Key: "type", Value: ruleType})
+	}
+	rules, err := r.dao.GetList(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return r.RuleDaoToDomainList(rules), nil
+}
+
+// GetEnabledList 获取所有启用的规则
+func (r *RuleRepository) GetEnabledList(ctx context.Context) ([]*domain.Rule, error) {
+	rules, err := r.dao.GetList(ctx, bson.D{{Key: "enabled", Value: true}})
+	if err != nil {
+		return nil, err
+	}
+	return r.RuleDaoToDomainList(rules), nil
+}
+
+// Update 更新规则
+func (r *RuleRepository) Update(ctx context.Context, rule *domain.Rule) error {
+	return r.dao.Update(ctx, rule.Id, r.RuleDomainToDao(rule))
+}
+
+// Delete 删除规则
+func (r *RuleRepository) Delete(ctx context.Context, id bson.ObjectID) error {
+	return r.dao.Delete(ctx, id)
+}
+
+func (r *RuleRepository) RuleDomainToDao(rule *domain.Rule) *dao.Rule {
+	return &dao.Rule{
+		ID:      rule.Id,
+		Type:    rule.Type,
+		Value:   rule.Value,
+		Remark:  rule.Remark,
+		Enabled: rule.Enabled,
+	}
+}
+
+func (r *RuleRepository) RuleDaoToDomain(rule *dao.Rule) *domain.Rule {
+	return &domain.Rule{
+		Id:        rule.ID,
+		CreatedAt: rule.CreatedAt,
+		UpdatedAt: rule.UpdatedAt,
+		Type:      rule.Type,
+		Value:     rule.Value,
+		Remark:    rule.Remark,
+		Enabled:   rule.Enabled,
+	}
+}
+
+func (r *RuleRepository) RuleDaoToDomainList(rules []*dao.Rule) []*domain.Rule {
+	return lo.Map(rules, func(rule *dao.Rule, _ int) *domain.Rule {
+		return r.RuleDaoToDomain(rule)
+	})
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/antispam/internal/repository/dao"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// UseToken 标记表单令牌已使用, 令牌此前已使用过时返回false
+func (r *RuleRepository) UseToken(ctx context.Context, nonce string, expiresAt time.Time) (bool, error) {
+	err := r.usedTokenDao.Create(ctx, &dao.UsedToken{Nonce: nonce, ExpiresAt: expiresAt})
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
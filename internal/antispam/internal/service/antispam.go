@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/antispam/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/antispam/internal/repository"
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type IAntiSpamService interface {
+	Check(ctx context.Context, sub *domain.Submission) (*domain.Verdict, error)
+	RegisterChecker(checker Checker)
+	IssueSubmitToken() string
+	AdminCreateRule(ctx context.Context, rule *domain.Rule) error
+	AdminUpdateRule(ctx context.Context, rule *domain.Rule) error
+	AdminDeleteRule(ctx context.Context, id bson.ObjectID) error
+	AdminGetRuleList(ctx context.Context, ruleType string) ([]*domain.Rule, error)
+}
+
+var _ IAntiSpamService = (*AntiSpamService)(nil)
+
+func NewAntiSpamService(repo repository.IRuleRepository, cfg *conf.Config) *AntiSpamService {
+	if cfg.AntiSpam.TokenSecret == "" {
+		logger.Warn("未配置AntiSpam.TOKEN_SECRET, 表单令牌在重启后失效, 且不能在多实例间通用")
+	}
+	token := NewSubmitToken(cfg.AntiSpam.TokenSecret, time.Duration(cfg.AntiSpam.TokenMaxAgeMinutes)*time.Minute)
+	return &AntiSpamService{
+		repo:  repo,
+		token: token,
+		checkers: []Checker{
+			&HoneypotChecker{},
+			&SubmitTimeChecker{token: token, repo: repo},
+			&IpChecker{},
+			&EmailChecker{},
+			&LinkChecker{},
+			&KeywordChecker{},
+			&RegexChecker{},
+		},
+	}
+}
+
+type AntiSpamService struct {
+	repo     repository.IRuleRepository
+	token    *SubmitToken
+	mu       sync.RWMutex
+	checkers []Checker
+}
+
+// Check 依次执行检测器, 命中任意一个即判定为垃圾内容
+func (s *AntiSpamService) Check(ctx context.Context, sub *domain.Submission) (*domain.Verdict, error) {
+	rules, err := s.repo.GetEnabledList(ctx)
+	if err != nil {
+		logger.Error("查询反垃圾规则失败",
+			logger.WithError(err),
+		)
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, checker := range s.checkers {
+		if hit, reason := checker.Check(ctx, sub, rules); hit {
+			logger.Warn("命中反垃圾规则",
+				logger.WithString("checker", checker.Name()),
+				logger.WithString("reason", reason),
+				logger.WithString("ip", sub.Ip),
+			)
+			return &domain.Verdict{Spam: true, Checker: checker.Name(), Reason: reason}, nil
+		}
+	}
+	return &domain.Verdict{}, nil
+}
+
+// RegisterChecker 注册自定义检测器, 追加在内置检测器之后执行
+func (s *AntiSpamService) RegisterChecker(checker Checker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkers = append(s.checkers, checker)
+}
+
+// IssueSubmitToken 签发表单令牌, 前端在展示表单时获取并随提交携带
+func (s *AntiSpamService) IssueSubmitToken() string {
+	return s.token.Issue()
+}
+
+// AdminCreateRule 创建反垃圾规则
+func (s *AntiSpamService) AdminCreateRule(ctx context.Context, rule *domain.Rule) error {
+	if err := validateRule(rule); err != nil {
+		return err
+	}
+	err := s.repo.Create(ctx, rule)
+	if err != nil {
+		logger.Error("创建反垃圾规则失败",
+			logger.WithError(err),
+			logger.WithString("type", rule.Type),
+		)
+		return err
+	}
+
+	logger.Info("创建反垃圾规则成功",
+		logger.WithString("type", rule.Type),
+		logger.WithString("value", rule.Value),
+	)
+	return nil
+}
+
+// AdminUpdateRule 更新反垃圾规则
+func (s *AntiSpamService) AdminUpdateRule(ctx context.Context, rule *domain.Rule) error {
+	if err := validateRule(rule); err != nil {
+		return err
+	}
+	if _, err := s.repo.GetByID(ctx, rule.Id); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return errors.New("规则不存在")
+		}
+		logger.Error("查询反垃圾规则失败",
+			logger.WithError(err),
+			logger.WithString("ruleId", rule.Id.Hex()),
+		)
+		return err
+	}
+	err := s.repo.Update(ctx, rule)
+	if err != nil {
+		logger.Error("更新反垃圾规则失败",
+			logger.WithError(err),
+			logger.WithString("ruleId", rule.Id.Hex()),
+		)
+		return err
+	}
+
+	logger.Info("更新反垃圾规则成功",
+		logger.WithString("ruleId", rule.Id.Hex()),
+	)
+	return nil
+}
+
+// AdminDeleteRule 删除反垃圾规则
+func (s *AntiSpamService) AdminDeleteRule(ctx context.Context, id bson.ObjectID) error {
+	err := s.repo.Delete(ctx, id)
+	if err != nil {
+		logger.Error("删除反垃圾规则失败",
+			logger.WithError(err),
+			logger.WithString("ruleId", id.Hex()),
+		)
+		return err
+	}
+
+	logger.Info("删除反垃圾规则成功",
+		logger.WithString("ruleId", id.Hex()),
+	)
+	return nil
+}
+
+// AdminGetRuleList 获取反垃圾规则列表
+func (s *AntiSpamService) AdminGetRuleList(ctx context.Context, ruleType string) ([]*domain.Rule, error) {
+	rules, err := s.repo.GetList(ctx, ruleType)
+	if err != nil {
+		logger.Error("查询反垃圾规则列表失败",
+			logger.WithError(err),
+			logger.WithString("type", ruleType),
+		)
+		return nil, err
+	}
+	return rules, nil
+}
+
+// validateRule 按规则类型校验规则内容
+func validateRule(rule *domain.Rule) error {
+	rule.Value = strings.TrimSpace(rule.Value)
+	if rule.Value == "" {
+		return errors.New("规则内容不能为空")
+	}
+	switch rule.Type {
+	case domain.RuleTypeRegex:
+		if _, err := regexp.Compile(rule.Value); err != nil {
+			return errors.New("正则表达式格式错误")
+		}
+	case domain.RuleTypeIp:
+		if strings.Contains(rule.Value, "/") {
+			if _, _, err := net.ParseCIDR(rule.Value); err != nil {
+				return errors.New("IP网段格式错误")
+			}
+		} else if net.ParseIP(rule.Value) == nil {
+			return errors.New("IP格式错误")
+		}
+	case domain.RuleTypeLinkLimit:
+		if n, err := strconv.Atoi(rule.Value); err != nil || n < 0 {
+			return errors.New("链接数量上限必须为非负整数")
+		}
+	case domain.RuleTypeMinSubmitTime:
+		if n, err := strconv.Atoi(rule.Value); err != nil || n <= 0 {
+			return errors.New("最短提交时间必须为正整数")
+		}
+	}
+	return nil
+}
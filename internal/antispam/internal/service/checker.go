@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/antispam/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/antispam/internal/repository"
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+)
+
+// Checker 垃圾内容检测器, 命中时返回true和原因
+type Checker interface {
+	Name() string
+	Check(ctx context.Context, sub *domain.Submission, rules []*domain.Rule) (bool, string)
+}
+
+var (
+	_ Checker = (*HoneypotChecker)(nil)
+	_ Checker = (*SubmitTimeChecker)(nil)
+	_ Checker = (*KeywordChecker)(nil)
+	_ Checker = (*RegexChecker)(nil)
+	_ Checker = (*LinkChecker)(nil)
+	_ Checker = (*IpChecker)(nil)
+	_ Checker = (*EmailChecker)(nil)
+)
+
+var linkRegex = regexp.MustCompile(`(?i)https?://|www\.`)
+
+// filterRules 按类型筛选规则
+func filterRules(rules []*domain.Rule, ruleType string) []*domain.Rule {
+	var result []*domain.Rule
+	for _, rule := range rules {
+		if rule.Type == ruleType {
+			result = append(result, rule)
+		}
+	}
+	return result
+}
+
+// HoneypotChecker 蜜罐检测, 隐藏字段被填写即视为机器人
+type HoneypotChecker struct{}
+
+func (c *HoneypotChecker) Name() string { return "honeypot" }
+
+func (c *HoneypotChecker) Check(_ context.Context, sub *domain.Submission, _ []*domain.Rule) (bool, string) {
+	if sub.Honeypot != "" {
+		return true, "蜜罐字段被填写"
+	}
+	return false, ""
+}
+
+// SubmitTimeChecker 最短提交时间检测, 表单令牌签发后过快提交或重复使用令牌视为机器人
+type SubmitTimeChecker struct {
+	token *SubmitToken
+	repo  repository.IRuleRepository
+}
+
+func (c *SubmitTimeChecker) Name() string { return "submit_time" }
+
+func (c *SubmitTimeChecker) Check(ctx context.Context, sub *domain.Submission, rules []*domain.Rule) (bool, string) {
+	var minSeconds int
+	for _, rule := range filterRules(rules, domain.RuleTypeMinSubmitTime) {
+		seconds, err := strconv.Atoi(rule.Value)
+		if err == nil && seconds > minSeconds {
+			minSeconds = seconds
+		}
+	}
+	if minSeconds == 0 {
+		return false, ""
+	}
+
+	issuedAt, nonce, err := c.token.Parse(sub.Token)
+	if err != nil {
+		return true, err.Error()
+	}
+	if elapsed := time.Since(issuedAt); elapsed < time.Duration(minSeconds)*time.Second {
+		return true, fmt.Sprintf("提交过快: %.1f秒", elapsed.Seconds())
+	}
+	// 通过检测后才记录使用, 过快提交的用户稍后仍可使用同一令牌
+	unused, err := c.repo.UseToken(ctx, nonce, c.token.ExpiresAt(issuedAt))
+	if err != nil {
+		logger.Error("记录表单令牌失败",
+			logger.WithError(err),
+		)
+		return false, ""
+	}
+	if !unused {
+		return true, "表单令牌已使用"
+	}
+	return false, ""
+}
+
+// KeywordChecker 关键词黑名单检测, 不区分大小写
+type KeywordChecker struct{}
+
+func (c *KeywordChecker) Name() string { return "keyword" }
+
+func (c *KeywordChecker) Check(_ context.Context, sub *domain.Submission, rules []*domain.Rule) (bool, string) {
+	text := strings.ToLower(strings.Join([]string{sub.Content, sub.Nickname, sub.SiteUrl}, "\n"))
+	for _, rule := range filterRules(rules, domain.RuleTypeKeyword) {
+		if rule.Value != "" && strings.Contains(text, strings.ToLower(rule.Value)) {
+			return true, "命中关键词: " + rule.Value
+		}
+	}
+	return false, ""
+}
+
+// RegexChecker 正则黑名单检测
+type RegexChecker struct{}
+
+func (c *RegexChecker) Name() string { return "regex" }
+
+func (c *RegexChecker) Check(_ context.Context, sub *domain.Submission, rules []*domain.Rule) (bool, string) {
+	text := strings.Join([]string{sub.Content, sub.Nickname, sub.SiteUrl}, "\n")
+	for _, rule := range filterRules(rules, domain.RuleTypeRegex) {
+		re, err := regexp.Compile(rule.Value)
+		if err != nil {
+			continue
+		}
+		if re.MatchString(text) {
+			return true, "命中正则: " + rule.Value
+		}
+	}
+	return false, ""
+}
+
+// LinkChecker 链接数量检测, 多条规则取最小阈值
+type LinkChecker struct{}
+
+func (c *LinkChecker) Name() string { return "link" }
+
+func (c *LinkChecker) Check(_ context.Context, sub *domain.Submission, rules []*domain.Rule) (bool, string) {
+	limit := -1
+	for _, rule := range filterRules(rules, domain.RuleTypeLinkLimit) {
+		n, err := strconv.Atoi(rule.Value)
+		if err == nil && (limit < 0 || n < limit) {
+			limit = n
+		}
+	}
+	if limit < 0 {
+		return false, ""
+	}
+	if count := len(linkRegex.FindAllStringIndex(sub.Content, -1)); count > limit {
+		return true, fmt.Sprintf("链接数量%d超过上限%d", count, limit)
+	}
+	return false, ""
+}
+
+// IpChecker IP黑名单检测, 支持单个IP和CIDR网段
+type IpChecker struct{}
+
+func (c *IpChecker) Name() string { return "ip" }
+
+func (c *IpChecker) Check(_ context.Context, sub *domain.Submission, rules []*domain.Rule) (bool, string) {
+	ip := net.ParseIP(sub.Ip)
+	if ip == nil {
+		return false, ""
+	}
+	for _, rule := range filterRules(rules, domain.RuleTypeIp) {
+		if strings.Contains(rule.Value, "/") {
+			_, ipNet, err := net.ParseCIDR(rule.Value)
+			if err == nil && ipNet.Contains(ip) {
+				return true, "命中IP网段: " + rule.Value
+			}
+			continue
+		}
+		if blocked := net.ParseIP(rule.Value); blocked != nil && blocked.Equal(ip) {
+			return true, "命中IP: " + rule.Value
+		}
+	}
+	return false, ""
+}
+
+// EmailChecker 邮箱黑名单检测, 规则以@开头时匹配整个域名
+type EmailChecker struct{}
+
+func (c *EmailChecker) Name() string { return "email" }
+
+func (c *EmailChecker) Check(_ context.Context, sub *domain.Submission, rules []*domain.Rule) (bool, string) {
+	email := strings.ToLower(strings.TrimSpace(sub.Email))
+	if email == "" {
+		return false, ""
+	}
+	for _, rule := range filterRules(rules, domain.RuleTypeEmail) {
+		value := strings.ToLower(rule.Value)
+		if strings.HasPrefix(value, "@") && strings.HasSuffix(email, value) {
+			return true, "命中邮箱域名: " + rule.Value
+		}
+		if email == value {
+			return true, "命中邮箱: " + rule.Value
+		}
+	}
+	return false, ""
+}
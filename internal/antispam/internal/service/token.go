@@ -0,0 +1,84 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSubmitTokenMaxAge = 2 * time.Hour // 表单令牌默认有效期
+	submitTokenNonceLen      = 16
+)
+
+// SubmitToken 签发和校验带签发时间的表单令牌, 每个令牌带随机数, 使用后记录以防重放
+// 未配置签名密钥时在进程启动时随机生成, 重启后旧令牌失效, 其他实例也无法校验
+type SubmitToken struct {
+	key    []byte
+	maxAge time.Duration
+}
+
+func NewSubmitToken(secret string, maxAge time.Duration) *SubmitToken {
+	key := []byte(secret)
+	if secret == "" {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			panic(err)
+		}
+	}
+	if maxAge <= 0 {
+		maxAge = defaultSubmitTokenMaxAge
+	}
+	return &SubmitToken{key: key, maxAge: maxAge}
+}
+
+// Issue 签发令牌, 格式为 base64(签发时间+随机数).base64(签名)
+func (t *SubmitToken) Issue() string {
+	payload := make([]byte, 8+submitTokenNonceLen)
+	binary.BigEndian.PutUint64(payload, uint64(time.Now().UnixMilli()))
+	if _, err := rand.Read(payload[8:]); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(t.sign(payload))
+}
+
+// Parse 校验令牌并返回签发时间和随机数, 随机数用于记录令牌是否已使用
+func (t *SubmitToken) Parse(token string) (time.Time, string, error) {
+	if token == "" {
+		return time.Time{}, "", errors.New("缺少表单令牌")
+	}
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return time.Time{}, "", errors.New("表单令牌格式错误")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || len(payload) != 8+submitTokenNonceLen {
+		return time.Time{}, "", errors.New("表单令牌格式错误")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(sig, t.sign(payload)) {
+		return time.Time{}, "", errors.New("表单令牌签名无效")
+	}
+	issuedAt := time.UnixMilli(int64(binary.BigEndian.Uint64(payload)))
+	if time.Since(issuedAt) > t.maxAge {
+		return time.Time{}, "", errors.New("表单令牌已过期")
+	}
+	return issuedAt, hex.EncodeToString(payload[8:]), nil
+}
+
+// ExpiresAt 令牌的过期时间, 已使用的记录保留到此时
+func (t *SubmitToken) ExpiresAt(issuedAt time.Time) time.Time {
+	return issuedAt.Add(t.maxAge)
+}
+
+func (t *SubmitToken) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, t.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,95 @@
+package web
+
+import (
+	"github.com/codepzj/Stellux-Server/internal/antispam/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/antispam/internal/service"
+	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+	"github.com/codepzj/Stellux-Server/internal/pkg/middleware"
+	"github.com/codepzj/Stellux-Server/internal/pkg/permission"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func NewAntiSpamHandler(serv service.IAntiSpamService) *AntiSpamHandler {
+	return &AntiSpamHandler{
+		serv: serv,
+	}
+}
+
+type AntiSpamHandler struct {
+	serv service.IAntiSpamService
+}
+
+func (h *AntiSpamHandler) RegisterGinRoutes(engine *gin.Engine) {
+	antispamGroup := engine.Group("/antispam")
+	{
+		antispamGroup.GET("/token", apiwrap.Wrap(h.GetSubmitToken)) // 获取表单令牌
+	}
+	adminGroup := engine.Group("/admin-api/antispam")
+	{
+		adminGroup.GET("/rule/list", middleware.RequirePermission(permission.AntiSpamRead), apiwrap.WrapWithQuery(h.AdminGetRuleList))        // 获取规则列表
+		adminGroup.POST("/rule/create", middleware.RequirePermission(permission.AntiSpamWrite), apiwrap.WrapWithJson(h.AdminCreateRule))      // 创建规则
+		adminGroup.PUT("/rule/update", middleware.RequirePermission(permission.AntiSpamWrite), apiwrap.WrapWithJson(h.AdminUpdateRule))       // 更新规则
+		adminGroup.DELETE("/rule/delete/:id", middleware.RequirePermission(permission.AntiSpamWrite), apiwrap.WrapWithUri(h.AdminDeleteRule)) // 删除规则
+	}
+}
+
+// GetSubmitToken 获取表单令牌
+func (h *AntiSpamHandler) GetSubmitToken(c *gin.Context) (int, string, any) {
+	return 200, "获取表单令牌成功", &SubmitTokenVO{Token: h.serv.IssueSubmitToken()}
+}
+
+// AdminGetRuleList 获取规则列表
+func (h *AntiSpamHandler) AdminGetRuleList(c *gin.Context, req RuleTypeRequest) (int, string, any) {
+	rules, err := h.serv.AdminGetRuleList(c, req.Type)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "获取规则列表成功", h.RuleListToVOList(rules)
+}
+
+// AdminCreateRule 创建规则
+func (h *AntiSpamHandler) AdminCreateRule(c *gin.Context, req RuleRequest) (int, string, any) {
+	err := h.serv.AdminCreateRule(c, &domain.Rule{
+		Type:    req.Type,
+		Value:   req.Value,
+		Remark:  req.Remark,
+		Enabled: req.Enabled,
+	})
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "创建规则成功", nil
+}
+
+// AdminUpdateRule 更新规则
+func (h *AntiSpamHandler) AdminUpdateRule(c *gin.Context, req RuleUpdateRequest) (int, string, any) {
+	objId, err := bson.ObjectIDFromHex(req.Id)
+	if err != nil {
+		return 400, "id格式错误", nil
+	}
+	err = h.serv.AdminUpdateRule(c, &domain.Rule{
+		Id:      objId,
+		Type:    req.Type,
+		Value:   req.Value,
+		Remark:  req.Remark,
+		Enabled: req.Enabled,
+	})
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "更新规则成功", nil
+}
+
+// AdminDeleteRule 删除规则
+func (h *AntiSpamHandler) AdminDeleteRule(c *gin.Context, req RuleIdRequest) (int, string, any) {
+	objId, err := bson.ObjectIDFromHex(req.Id)
+	if err != nil {
+		return 400, "id格式错误", nil
+	}
+	err = h.serv.AdminDeleteRule(c, objId)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "删除规则成功", nil
+}
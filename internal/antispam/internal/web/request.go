@@ -0,0 +1,27 @@
+package web
+
+// RuleRequest 创建反垃圾规则请求
+type RuleRequest struct {
+	Type    string `json:"type" binding:"required,oneof=keyword regex ip email link_limit min_submit_time"`
+	Value   string `json:"value" binding:"required"`
+	Remark  string `json:"remark"`
+	Enabled bool   `json:"enabled"`
+}
+
+// RuleUpdateRequest 更新反垃圾规则请求
+type RuleUpdateRequest struct {
+	Id      string `json:"id" binding:"required"`
+	Type    string `json:"type" binding:"required,oneof=keyword regex ip email link_limit min_submit_time"`
+	Value   string `json:"value" binding:"required"`
+	Remark  string `json:"remark"`
+	Enabled bool   `json:"enabled"`
+}
+
+// RuleTypeRequest 按类型查询规则请求
+type RuleTypeRequest struct {
+	Type string `form:"type" binding:"omitempty,oneof=keyword regex ip email link_limit min_submit_time"`
+}
+
+type RuleIdRequest struct {
+	Id string `uri:"id" binding:"required"`
+}
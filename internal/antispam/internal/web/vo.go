@@ -0,0 +1,41 @@
+package web
+
+import (
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/antispam/internal/domain"
+	"github.com/samber/lo"
+)
+
+type RuleVO struct {
+	Id        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Type      string    `json:"type"`
+	Value     string    `json:"value"`
+	Remark    string    `json:"remark"`
+	Enabled   bool      `json:"enabled"`
+}
+
+// SubmitTokenVO 表单令牌
+type SubmitTokenVO struct {
+	Token string `json:"token"`
+}
+
+func (h *AntiSpamHandler) RuleToVO(rule *domain.Rule) *RuleVO {
+	return &RuleVO{
+		Id:        rule.Id.Hex(),
+		CreatedAt: rule.CreatedAt,
+		UpdatedAt: rule.UpdatedAt,
+		Type:      rule.Type,
+		Value:     rule.Value,
+		Remark:    rule.Remark,
+		Enabled:   rule.Enabled,
+	}
+}
+
+func (h *AntiSpamHandler) RuleListToVOList(rules []*domain.Rule) []*RuleVO {
+	return lo.Map(rules, func(rule *domain.Rule, _ int) *RuleVO {
+		return h.RuleToVO(rule)
+	})
+}
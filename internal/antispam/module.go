@@ -0,0 +1,20 @@
+package antispam
+
+import (
+	"github.com/codepzj/Stellux-Server/internal/antispam/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/antispam/internal/service"
+	"github.com/codepzj/Stellux-Server/internal/antispam/internal/web"
+)
+
+type (
+	Handler    = web.AntiSpamHandler
+	Service    = service.IAntiSpamService
+	Checker    = service.Checker
+	Submission = domain.Submission
+	Verdict    = domain.Verdict
+	Rule       = domain.Rule
+	Module     struct {
+		Svc Service
+		Hdl *Handler
+	}
+)
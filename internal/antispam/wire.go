@@ -0,0 +1,26 @@
+//go:build wireinject
+
+package antispam
+
+import (
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/antispam/internal/repository"
+	"github.com/codepzj/Stellux-Server/internal/antispam/internal/repository/dao"
+	"github.com/codepzj/Stellux-Server/internal/antispam/internal/service"
+	"github.com/codepzj/Stellux-Server/internal/antispam/internal/web"
+	"github.com/google/wire"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var AntiSpamProviders = wire.NewSet(web.NewAntiSpamHandler, service.NewAntiSpamService, repository.NewRuleRepository, dao.NewRuleDao, dao.NewUsedTokenDao,
+	wire.Bind(new(service.IAntiSpamService), new(*service.AntiSpamService)),
+	wire.Bind(new(repository.IRuleRepository), new(*repository.RuleRepository)),
+	wire.Bind(new(dao.IRuleDao), new(*dao.RuleDao)),
+	wire.Bind(new(dao.IUsedTokenDao), new(*dao.UsedTokenDao)))
+
+func InitAntiSpamModule(mongoDB *mongo.Database, cfg *conf.Config) *Module {
+	panic(wire.Build(
+		AntiSpamProviders,
+		wire.Struct(new(Module), "Svc", "Hdl"),
+	))
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// 操作类型
+const (
+	ActionCreate     = "create"
+	ActionUpdate     = "update"
+	ActionDelete     = "delete"
+	ActionSoftDelete = "soft_delete" // 移入回收站
+	ActionRestore    = "restore"     // 从回收站恢复
+)
+
+// 审计对象类型
+const (
+	EntityPost            = "post"
+	EntityLabel           = "label"
+	EntityDocument        = "document"
+	EntityDocumentContent = "document_content"
+	EntityFile            = "file"
+	EntityFriend          = "friend"
+	EntityConfig          = "config"
+	EntityUser            = "user"
+)
+
+// Entry 一次修改操作的审计记录
+type Entry struct {
+	ID         bson.ObjectID
+	CreatedAt  time.Time
+	ActorId    string // 操作人, 访客申请或系统操作时为空
+	IP         string
+	Action     string
+	EntityType string
+	EntityId   string
+	Changes    []*Change
+}
+
+// Change 单个字段的变化, 长文本只记录差异
+type Change struct {
+	Field  string
+	Before any
+	After  any
+	Diff   string // 长文本的unified diff, 有值时Before和After为空
+}
+
+// Query 审计查询条件, 零值表示不过滤
+type Query struct {
+	ActorId    string
+	Action     string
+	EntityType string
+	EntityId   string
+	StartTime  time.Time
+	EndTime    time.Time
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/codepzj/Stellux-Server/internal/audit/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/audit/internal/repository/dao"
+	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+	"github.com/samber/lo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type IAuditRepository interface {
+	Create(ctx context.Context, entry *domain.Entry) error
+	GetList(ctx context.Context, query *domain.Query, page *apiwrap.Page) ([]*domain.Entry, int64, error)
+}
+
+var _ IAuditRepository = (*AuditRepository)(nil)
+
+func NewAuditRepository(dao dao.IAuditDao) *AuditRepository {
+	return &AuditRepository{dao: dao}
+}
+
+type AuditRepository struct {
+	dao dao.IAuditDao
+}
+
+func (r *AuditRepository) Create(ctx context.Context, entry *domain.Entry) error {
+	entryDO := &dao.Entry{
+		ActorId:    entry.ActorId,
+		IP:         entry.IP,
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityId:   entry.EntityId,
+		Changes: lo.Map(entry.Changes, func(change *domain.Change, _ int) *dao.Change {
+			return &dao.Change{Field: change.Field, Before: change.Before, After: change.After, Diff: change.Diff}
+		}),
+	}
+	if err := r.dao.Create(ctx, entryDO); err != nil {
+		return err
+	}
+	entry.ID = entryDO.ID
+	entry.CreatedAt = entryDO.CreatedAt
+	return nil
+}
+
+func (r *AuditRepository) GetList(ctx context.Context, query *domain.Query, page *apiwrap.Page) ([]*domain.Entry, int64, error) {
+	filter := bson.D{}
+	if query.ActorId != "" {
+		filter = append(filter, bson.E{Key: "actor_id", Value: query.ActorId})
+	}
+	if query.Action != "" {
+		filter = append(filter, bson.E{Key: "action", Value: query.Action})
+	}
+	if query.EntityType != "" {
+		filter = append(filter, bson.E{Key: "entity_type", Value: query.EntityType})
+	}
+	if query.EntityId != "" {
+		filter = append(filter, bson.E{Key: "entity_id", Value: query.EntityId})
+	}
+	if createdAt := timeRange(query); len(createdAt) > 0 {
+		filter = append(filter, bson.E{Key: "created_at", Value: createdAt})
+	}
+	count, err := r.dao.Count(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	entries, err := r.dao.FindList(ctx, filter, (page.PageNo-1)*page.PageSize, page.PageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	return lo.Map(entries, func(entry *dao.Entry, _ int) *domain.Entry {
+		return r.EntryDOToDomain(entry)
+	}), count, nil
+}
+
+func (r *AuditRepository) EntryDOToDomain(entry *dao.Entry) *domain.Entry {
+	return &domain.Entry{
+		ID:         entry.ID,
+		CreatedAt:  entry.CreatedAt,
+		ActorId:    entry.ActorId,
+		IP:         entry.IP,
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityId:   entry.EntityId,
+		Changes: lo.Map(entry.Changes, func(change *dao.Change, _ int) *domain.Change {
+			return &domain.Change{Field: change.Field, Before: change.Before, After: change.After, Diff: change.Diff}
+		}),
+	}
+}
+
+func timeRange(query *domain.Query) bson.M {
+	createdAt := bson.M{}
+	if !query.StartTime.IsZero() {
+		createdAt["$gte"] = query.StartTime
+	}
+	if !query.EndTime.IsZero() {
+		createdAt["$lte"] = query.EndTime
+	}
+	return createdAt
+}
@@ -0,0 +1,93 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type Entry struct {
+	ID         bson.ObjectID `bson:"_id,omitempty"`
+	CreatedAt  time.Time     `bson:"created_at"`
+	ActorId    string        `bson:"actor_id,omitempty"`
+	IP         string        `bson:"ip,omitempty"`
+	Action     string        `bson:"action"`
+	EntityType string        `bson:"entity_type"`
+	EntityId   string        `bson:"entity_id"`
+	Changes    []*Change     `bson:"changes"`
+}
+
+type Change struct {
+	Field  string `bson:"field"`
+	Before any    `bson:"before,omitempty"`
+	After  any    `bson:"after,omitempty"`
+	Diff   string `bson:"diff,omitempty"`
+}
+
+type IAuditDao interface {
+	Create(ctx context.Context, entry *Entry) error
+	FindList(ctx context.Context, filter bson.D, skip int64, limit int64) ([]*Entry, error)
+	Count(ctx context.Context, filter bson.D) (int64, error)
+}
+
+var _ IAuditDao = (*AuditDao)(nil)
+
+func NewAuditDao(db *mongo.Database) *AuditDao {
+	d := &AuditDao{coll: db.Collection("audit_log")}
+	d.ensureIndexes()
+	return d
+}
+
+type AuditDao struct {
+	coll *mongo.Collection
+}
+
+// ensureIndexes 按时间倒序查看, 按对象或操作人过滤
+func (d *AuditDao) ensureIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := d.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "created_at", Value: -1}}, Options: options.Index().SetName("created_at")},
+		{Keys: bson.D{{Key: "entity_type", Value: 1}, {Key: "entity_id", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("entity_created_at")},
+		{Keys: bson.D{{Key: "actor_id", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("actor_created_at")},
+	})
+	if err != nil {
+		logger.Warn("创建审计日志索引失败",
+			logger.WithError(err),
+		)
+	}
+}
+
+// Create 写入审计记录
+func (d *AuditDao) Create(ctx context.Context, entry *Entry) error {
+	entry.ID = bson.NewObjectID()
+	entry.CreatedAt = time.Now()
+	_, err := d.coll.InsertOne(ctx, entry)
+	return err
+}
+
+// FindList 分页查询审计记录, 按时间倒序
+func (d *AuditDao) FindList(ctx context.Context, filter bson.D, skip int64, limit int64) ([]*Entry, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).SetSkip(skip).SetLimit(limit)
+	cursor, err := d.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*Entry
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Count 统计审计记录数量
+func (d *AuditDao) Count(ctx context.Context, filter bson.D) (int64, error) {
+	return d.coll.CountDocuments(ctx, filter)
+}
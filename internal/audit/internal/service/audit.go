@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/codepzj/Stellux-Server/internal/audit/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/audit/internal/repository"
+	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	maxInlineLength = 256      // 超过该长度的文本按diff或截断记录
+	redactedValue   = "******" // 敏感字段只记录发生了变化
+)
+
+// ignoredFields 每次修改都会变化, 记录下来没有意义
+var ignoredFields = map[string]bool{
+	"UpdatedAt": true,
+}
+
+// sensitiveKeywords 字段名包含这些关键字时不记录原值
+var sensitiveKeywords = []string{"password", "secret", "hash", "token"}
+
+type IAuditService interface {
+	// Record 记录一次成功的修改操作, before和after为修改前后的对象, 新建时before为nil, 删除时after为nil
+	Record(ctx context.Context, action string, entityType string, entityId string, before any, after any)
+	GetList(ctx context.Context, query *domain.Query, page *apiwrap.Page) ([]*domain.Entry, int64, error)
+}
+
+var _ IAuditService = (*AuditService)(nil)
+
+func NewAuditService(repo repository.IAuditRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+type AuditService struct {
+	repo repository.IAuditRepository
+}
+
+// Record 审计失败不影响业务操作, 只记录日志
+func (s *AuditService) Record(ctx context.Context, action string, entityType string, entityId string, before any, after any) {
+	changes, err := diff(before, after)
+	if err != nil {
+		logger.Warn("生成审计差异失败",
+			logger.WithError(err),
+			logger.WithString("entityType", entityType),
+			logger.WithString("entityId", entityId),
+		)
+	}
+	entry := &domain.Entry{
+		Action:     action,
+		EntityType: entityType,
+		EntityId:   entityId,
+		Changes:    changes,
+	}
+	if actorId, ok := ctx.Value("userId").(string); ok {
+		entry.ActorId = actorId
+	}
+	if c, ok := ctx.Value(gin.ContextKey).(*gin.Context); ok && c.Request != nil {
+		entry.IP = c.ClientIP()
+	}
+	if err := s.repo.Create(ctx, entry); err != nil {
+		logger.Error("写入审计日志失败",
+			logger.WithError(err),
+			logger.WithString("action", action),
+			logger.WithString("entityType", entityType),
+			logger.WithString("entityId", entityId),
+		)
+	}
+}
+
+func (s *AuditService) GetList(ctx context.Context, query *domain.Query, page *apiwrap.Page) ([]*domain.Entry, int64, error) {
+	return s.repo.GetList(ctx, query, page)
+}
+
+// diff 比较对象的顶层字段, 只返回发生变化的字段
+func diff(before any, after any) ([]*domain.Change, error) {
+	beforeFields, err := toFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := toFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(beforeFields)+len(afterFields))
+	for key := range beforeFields {
+		keys = append(keys, key)
+	}
+	for key := range afterFields {
+		if _, ok := beforeFields[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	changes := make([]*domain.Change, 0, len(keys))
+	for _, key := range keys {
+		if ignoredFields[key] {
+			continue
+		}
+		oldValue, newValue := beforeFields[key], afterFields[key]
+		if isZero(oldValue) && isZero(newValue) || reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		changes = append(changes, newChange(key, oldValue, newValue))
+	}
+	return changes, nil
+}
+
+func newChange(field string, before any, after any) *domain.Change {
+	if isSensitive(field) {
+		change := &domain.Change{Field: field}
+		if !isZero(before) {
+			change.Before = redactedValue
+		}
+		if !isZero(after) {
+			change.After = redactedValue
+		}
+		return change
+	}
+
+	oldText, oldIsText := before.(string)
+	newText, newIsText := after.(string)
+	if oldIsText && newIsText && oldText != "" && newText != "" && (len(oldText) > maxInlineLength || len(newText) > maxInlineLength) {
+		text, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(oldText),
+			B:        difflib.SplitLines(newText),
+			FromFile: "before",
+			ToFile:   "after",
+			Context:  2,
+		})
+		if err == nil {
+			return &domain.Change{Field: field, Diff: text}
+		}
+	}
+	return &domain.Change{Field: field, Before: truncate(before), After: truncate(after)}
+}
+
+// toFields 通过json序列化把对象转为字段表, 与接口返回的取值方式保持一致
+func toFields(v any) (map[string]any, error) {
+	fields := map[string]any{}
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil() {
+		return fields, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fields, err
+	}
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return map[string]any{}, err
+	}
+	return fields, nil
+}
+
+func isZero(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map:
+		return rv.Len() == 0
+	}
+	return rv.IsZero() || v == "0001-01-01T00:00:00Z" || v == "000000000000000000000000"
+}
+
+func isSensitive(field string) bool {
+	field = strings.ToLower(field)
+	for _, keyword := range sensitiveKeywords {
+		if strings.Contains(field, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func truncate(v any) any {
+	text, ok := v.(string)
+	if !ok || len(text) <= maxInlineLength {
+		return v
+	}
+	for i := maxInlineLength; i > 0; i-- {
+		if utf8.RuneStart(text[i]) {
+			return text[:i] + "..."
+		}
+	}
+	return v
+}
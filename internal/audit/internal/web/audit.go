@@ -0,0 +1,47 @@
+package web
+
+import (
+	"github.com/codepzj/Stellux-Server/internal/audit/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/audit/internal/service"
+	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+	"github.com/codepzj/Stellux-Server/internal/pkg/middleware"
+	"github.com/codepzj/Stellux-Server/internal/pkg/permission"
+	"github.com/gin-gonic/gin"
+)
+
+func NewAuditHandler(serv service.IAuditService) *AuditHandler {
+	return &AuditHandler{
+		serv: serv,
+	}
+}
+
+type AuditHandler struct {
+	serv service.IAuditService
+}
+
+func (h *AuditHandler) RegisterGinRoutes(engine *gin.Engine) {
+	adminGroup := engine.Group("/admin-api/audit")
+	{
+		adminGroup.GET("/list", middleware.RequirePermission(permission.AuditRead), apiwrap.WrapWithQuery(h.AdminGetAuditList)) // 分页查询操作审计日志
+	}
+}
+
+// AdminGetAuditList 按操作人、操作类型、对象和时间范围过滤审计日志
+func (h *AuditHandler) AdminGetAuditList(c *gin.Context, req AuditListRequest) (int, string, any) {
+	if !req.StartTime.IsZero() && !req.EndTime.IsZero() && req.EndTime.Before(req.StartTime) {
+		return 400, "结束时间不能早于开始时间", nil
+	}
+	query := &domain.Query{
+		ActorId:    req.ActorId,
+		Action:     req.Action,
+		EntityType: req.EntityType,
+		EntityId:   req.EntityId,
+		StartTime:  req.StartTime,
+		EndTime:    req.EndTime,
+	}
+	entries, count, err := h.serv.GetList(c, query, &req.Page)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "获取审计日志成功", apiwrap.ToPageVO(req.PageNo, req.PageSize, count, h.EntryDomainToVOList(entries))
+}
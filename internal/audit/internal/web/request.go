@@ -0,0 +1,17 @@
+package web
+
+import (
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+)
+
+type AuditListRequest struct {
+	apiwrap.Page
+	ActorId    string    `form:"actor_id"`
+	Action     string    `form:"action" binding:"omitempty,oneof=create update delete soft_delete restore"`
+	EntityType string    `form:"entity_type"`
+	EntityId   string    `form:"entity_id"`
+	StartTime  time.Time `form:"start_time" time_format:"2006-01-02T15:04:05Z07:00"`
+	EndTime    time.Time `form:"end_time" time_format:"2006-01-02T15:04:05Z07:00"`
+}
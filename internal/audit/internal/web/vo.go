@@ -0,0 +1,43 @@
+package web
+
+import (
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/audit/internal/domain"
+	"github.com/samber/lo"
+)
+
+type AuditEntryVO struct {
+	Id         string           `json:"id"`
+	CreatedAt  time.Time        `json:"created_at"`
+	ActorId    string           `json:"actor_id"`
+	IP         string           `json:"ip"`
+	Action     string           `json:"action"`
+	EntityType string           `json:"entity_type"`
+	EntityId   string           `json:"entity_id"`
+	Changes    []*AuditChangeVO `json:"changes"`
+}
+
+type AuditChangeVO struct {
+	Field  string `json:"field"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+	Diff   string `json:"diff,omitempty"`
+}
+
+func (h *AuditHandler) EntryDomainToVOList(entries []*domain.Entry) []*AuditEntryVO {
+	return lo.Map(entries, func(entry *domain.Entry, _ int) *AuditEntryVO {
+		return &AuditEntryVO{
+			Id:         entry.ID.Hex(),
+			CreatedAt:  entry.CreatedAt,
+			ActorId:    entry.ActorId,
+			IP:         entry.IP,
+			Action:     entry.Action,
+			EntityType: entry.EntityType,
+			EntityId:   entry.EntityId,
+			Changes: lo.Map(entry.Changes, func(change *domain.Change, _ int) *AuditChangeVO {
+				return &AuditChangeVO{Field: change.Field, Before: change.Before, After: change.After, Diff: change.Diff}
+			}),
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package audit
+
+import (
+	"github.com/codepzj/Stellux-Server/internal/audit/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/audit/internal/service"
+	"github.com/codepzj/Stellux-Server/internal/audit/internal/web"
+)
+
+const (
+	ActionCreate     = domain.ActionCreate
+	ActionUpdate     = domain.ActionUpdate
+	ActionDelete     = domain.ActionDelete
+	ActionSoftDelete = domain.ActionSoftDelete
+	ActionRestore    = domain.ActionRestore
+)
+
+const (
+	EntityPost            = domain.EntityPost
+	EntityLabel           = domain.EntityLabel
+	EntityDocument        = domain.EntityDocument
+	EntityDocumentContent = domain.EntityDocumentContent
+	EntityFile            = domain.EntityFile
+	EntityFriend          = domain.EntityFriend
+	EntityConfig          = domain.EntityConfig
+	EntityUser            = domain.EntityUser
+)
+
+type (
+	Handler = web.AuditHandler
+	Service = service.IAuditService
+	Module  struct {
+		Svc Service
+		Hdl *Handler
+	}
+)
@@ -0,0 +1,24 @@
+//go:build wireinject
+
+package audit
+
+import (
+	"github.com/codepzj/Stellux-Server/internal/audit/internal/repository"
+	"github.com/codepzj/Stellux-Server/internal/audit/internal/repository/dao"
+	"github.com/codepzj/Stellux-Server/internal/audit/internal/service"
+	"github.com/codepzj/Stellux-Server/internal/audit/internal/web"
+	"github.com/google/wire"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var AuditProviders = wire.NewSet(web.NewAuditHandler, service.NewAuditService, repository.NewAuditRepository, dao.NewAuditDao,
+	wire.Bind(new(service.IAuditService), new(*service.AuditService)),
+	wire.Bind(new(repository.IAuditRepository), new(*repository.AuditRepository)),
+	wire.Bind(new(dao.IAuditDao), new(*dao.AuditDao)))
+
+func InitAuditModule(mongoDB *mongo.Database) *Module {
+	panic(wire.Build(
+		AuditProviders,
+		wire.Struct(new(Module), "Svc", "Hdl"),
+	))
+}
@@ -6,6 +6,20 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
+// 评论审核状态
+const (
+	CommentStatusPending  = "pending"  // 待审核
+	CommentStatusApproved = "approved" // 已通过
+	CommentStatusRejected = "rejected" // 已拒绝
+	CommentStatusSpam     = "spam"     // 垃圾评论
+)
+
+// 评论审核模式
+const (
+	CommentAuditModeAuto   = "auto"   // 自动通过
+	CommentAuditModeManual = "manual" // 人工审核
+)
+
 type Comment struct {
 	Id        bson.ObjectID
 	CreatedAt time.Time
@@ -20,8 +34,11 @@ type Comment struct {
 	Email     string
 	SiteUrl   string
 	IsAdmin   bool
+	Status    string
+	Ip        string
 }
 
+// CommentShow 前端展示的评论, 不包含邮箱
 type CommentShow struct {
 	Id        bson.ObjectID
 	CreatedAt time.Time
@@ -29,8 +46,11 @@ type CommentShow struct {
 	DeletedAt time.Time
 	Path      string
 	Content   string
+	RootId    bson.ObjectID
+	ParentId  bson.ObjectID
 	Nickname  string
 	Avatar    string
 	SiteUrl   string
 	IsAdmin   bool
+	Children  []*CommentShow // 子评论
 }
@@ -2,19 +2,27 @@ package repository
 
 import (
 	"context"
+	"regexp"
 	"time"
 
 	"github.com/codepzj/Stellux-Server/internal/comment/internal/domain"
 	"github.com/codepzj/Stellux-Server/internal/comment/internal/repository/dao"
+	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
 	"github.com/samber/lo"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 type ICommentRepository interface {
 	Create(ctx context.Context, comment *domain.Comment) error
-	GetListByPath(ctx context.Context, path string) ([]*domain.CommentShow, error)
+	GetByID(ctx context.Context, id bson.ObjectID) (*domain.Comment, error)
+	GetListByPath(ctx context.Context, path string, status string) ([]*domain.CommentShow, error)
+	GetList(ctx context.Context, page *apiwrap.Page, status string) ([]*domain.Comment, int64, error)
+	GetListByRootId(ctx context.Context, rootId bson.ObjectID) ([]*domain.Comment, error)
+	GetListByIds(ctx context.Context, ids []bson.ObjectID) ([]*domain.Comment, error)
 	Update(ctx context.Context, comment *domain.Comment) error
 	Delete(ctx context.Context, id bson.ObjectID) error
+	DeleteMany(ctx context.Context, ids []bson.ObjectID) error
+	UpdateStatusBatch(ctx context.Context, ids []bson.ObjectID, status string) error
 }
 
 var _ ICommentRepository = (*CommentRepository)(nil)
@@ -39,24 +47,77 @@ func (r *CommentRepository) Create(ctx context.Context, comment *domain.Comment)
 		Email:    comment.Email,
 		SiteUrl:  comment.SiteUrl,
 		IsAdmin:  comment.IsAdmin,
+		Status:   comment.Status,
+		Ip:       comment.Ip,
 	})
 }
 
-// GetListByPath 根据路径获取评论列表
-func (r *CommentRepository) GetListByPath(ctx context.Context, path string) ([]*domain.CommentShow, error) {
-	comments, err := r.dao.GetList(ctx, bson.D{{Key: "path", Value: path}})
+// GetByID 根据id获取评论
+func (r *CommentRepository) GetByID(ctx context.Context, id bson.ObjectID) (*domain.Comment, error) {
+	comment, err := r.dao.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.CommentDaoToDomain(comment), nil
+}
+
+// GetListByPath 根据路径和审核状态获取评论列表
+func (r *CommentRepository) GetListByPath(ctx context.Context, path string, status string) ([]*domain.CommentShow, error) {
+	comments, err := r.dao.GetList(ctx, bson.D{{Key: "path", Value: path}, {Key: "status", Value: status}})
 	if err != nil {
 		return nil, err
 	}
 	return r.CommentDaoToShowDomainList(comments), nil
 }
 
+// GetList 分页获取评论列表, status为空时查询全部状态
+func (r *CommentRepository) GetList(ctx context.Context, page *apiwrap.Page, status string) ([]*domain.Comment, int64, error) {
+	filter := bson.D{}
+	if status != "" {
+		filter = append(filter, bson.E{Key: "status", Value: status})
+	}
+	if page.Keyword != "" {
+		// 关键词按字面匹配, 避免正则特殊字符导致查询出错或回溯过多
+		keyword := bson.M{"$regex": regexp.QuoteMeta(page.Keyword), "$options": "i"}
+		filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+			bson.M{"content": keyword},
+			bson.M{"nickname": keyword},
+			bson.M{"email": keyword},
+		}})
+	}
+	skip := (page.PageNo - 1) * page.PageSize
+	comments, count, err := r.dao.GetPage(ctx, filter, skip, page.PageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	return r.CommentDaoToDomainList(comments), count, nil
+}
+
+// GetListByRootId 获取根评论下的所有回复
+func (r *CommentRepository) GetListByRootId(ctx context.Context, rootId bson.ObjectID) ([]*domain.Comment, error) {
+	comments, err := r.dao.GetList(ctx, bson.D{{Key: "root_id", Value: rootId}})
+	if err != nil {
+		return nil, err
+	}
+	return r.CommentDaoToDomainList(comments), nil
+}
+
+// GetListByIds 根据id列表获取评论
+func (r *CommentRepository) GetListByIds(ctx context.Context, ids []bson.ObjectID) ([]*domain.Comment, error) {
+	comments, err := r.dao.GetList(ctx, bson.D{{Key: "_id", Value: bson.M{"$in": ids}}})
+	if err != nil {
+		return nil, err
+	}
+	return r.CommentDaoToDomainList(comments), nil
+}
+
 // Update 更新评论
 func (r *CommentRepository) Update(ctx context.Context, comment *domain.Comment) error {
 	return r.dao.Update(ctx, comment.Id, &dao.Comment{
 		Content:  comment.Content,
 		Nickname: comment.Nickname,
 		Avatar:   comment.Avatar,
+		Email:    comment.Email,
 		SiteUrl:  comment.SiteUrl,
 		IsAdmin:  comment.IsAdmin,
 	})
@@ -67,6 +128,46 @@ func (r *CommentRepository) Delete(ctx context.Context, id bson.ObjectID) error
 	return r.dao.Delete(ctx, id)
 }
 
+// DeleteMany 批量删除评论
+func (r *CommentRepository) DeleteMany(ctx context.Context, ids []bson.ObjectID) error {
+	return r.dao.DeleteMany(ctx, ids)
+}
+
+// UpdateStatusBatch 批量更新评论审核状态
+func (r *CommentRepository) UpdateStatusBatch(ctx context.Context, ids []bson.ObjectID, status string) error {
+	return r.dao.UpdateStatusBatch(ctx, ids, status)
+}
+
+func (r *CommentRepository) CommentDaoToDomainList(comments []*dao.Comment) []*domain.Comment {
+	return lo.Map(comments, func(comment *dao.Comment, _ int) *domain.Comment {
+		return r.CommentDaoToDomain(comment)
+	})
+}
+
+func (r *CommentRepository) CommentDaoToDomain(comment *dao.Comment) *domain.Comment {
+	var deletedAt time.Time
+	if comment.DeletedAt != nil {
+		deletedAt = *comment.DeletedAt
+	}
+	return &domain.Comment{
+		Id:        comment.ID,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+		DeletedAt: deletedAt,
+		Path:      comment.Path,
+		Content:   comment.Content,
+		RootId:    comment.RootId,
+		ParentId:  comment.ParentId,
+		Nickname:  comment.Nickname,
+		Avatar:    comment.Avatar,
+		Email:     comment.Email,
+		SiteUrl:   comment.SiteUrl,
+		IsAdmin:   comment.IsAdmin,
+		Status:    comment.Status,
+		Ip:        comment.Ip,
+	}
+}
+
 func (r *CommentRepository) CommentDaoToShowDomainList(comments []*dao.Comment) []*domain.CommentShow {
 	return lo.Map(comments, func(comment *dao.Comment, _ int) *domain.CommentShow {
 		return r.CommentDaoToShowDomain(comment)
@@ -86,6 +187,8 @@ func (r *CommentRepository) CommentDaoToShowDomain(comment *dao.Comment) *domain
 		DeletedAt: deletedAt,
 		Path:      comment.Path,
 		Content:   comment.Content,
+		RootId:    comment.RootId,
+		ParentId:  comment.ParentId,
 		Nickname:  comment.Nickname,
 		Avatar:    comment.Avatar,
 		SiteUrl:   comment.SiteUrl,
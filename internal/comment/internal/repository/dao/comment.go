@@ -24,13 +24,19 @@ type Comment struct {
 	Email     string        `bson:"email"`     // 邮箱
 	SiteUrl   string        `bson:"site_url"`  // 链接
 	IsAdmin   bool          `bson:"is_admin"`  // 是否管理员
+	Status    string        `bson:"status"`    // 审核状态
+	Ip        string        `bson:"ip"`        // 客户端IP
 }
 
 type ICommentDao interface {
 	Create(ctx context.Context, comment *Comment) error
+	GetByID(ctx context.Context, id bson.ObjectID) (*Comment, error)
 	GetList(ctx context.Context, filter bson.D) ([]*Comment, error)
+	GetPage(ctx context.Context, filter bson.D, skip int64, limit int64) ([]*Comment, int64, error)
 	Update(ctx context.Context, id bson.ObjectID, comment *Comment) error
 	Delete(ctx context.Context, id bson.ObjectID) error
+	DeleteMany(ctx context.Context, ids []bson.ObjectID) error
+	UpdateStatusBatch(ctx context.Context, ids []bson.ObjectID, status string) error
 }
 
 var _ ICommentDao = (*CommentDao)(nil)
@@ -58,6 +64,16 @@ func (d *CommentDao) Create(ctx context.Context, comment *Comment) error {
 	return nil
 }
 
+// GetByID 根据id获取评论
+func (d *CommentDao) GetByID(ctx context.Context, id bson.ObjectID) (*Comment, error) {
+	var comment Comment
+	err := d.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&comment)
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
 // GetList 获取评论列表
 func (d *CommentDao) GetList(ctx context.Context, filter bson.D) ([]*Comment, error) {
 	opts := options.Find().SetSort(bson.M{"created_at": -1})
@@ -74,6 +90,26 @@ func (d *CommentDao) GetList(ctx context.Context, filter bson.D) ([]*Comment, er
 	return comments, nil
 }
 
+// GetPage 分页获取评论列表
+func (d *CommentDao) GetPage(ctx context.Context, filter bson.D, skip int64, limit int64) ([]*Comment, int64, error) {
+	count, err := d.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	opts := options.Find().SetSkip(skip).SetLimit(limit).SetSort(bson.M{"created_at": -1})
+	cursor, err := d.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var comments []*Comment
+	if err = cursor.All(ctx, &comments); err != nil {
+		return nil, 0, err
+	}
+	return comments, count, nil
+}
+
 // Update 更新评论
 func (d *CommentDao) Update(ctx context.Context, id bson.ObjectID, comment *Comment) error {
 	update := bson.M{
@@ -107,3 +143,28 @@ func (d *CommentDao) Delete(ctx context.Context, id bson.ObjectID) error {
 	}
 	return nil
 }
+
+// DeleteMany 批量删除评论
+func (d *CommentDao) DeleteMany(ctx context.Context, ids []bson.ObjectID) error {
+	res, err := d.coll.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return errors.New("删除评论失败")
+	}
+	return nil
+}
+
+// UpdateStatusBatch 批量更新评论审核状态
+func (d *CommentDao) UpdateStatusBatch(ctx context.Context, ids []bson.ObjectID, status string) error {
+	update := bson.M{"$set": bson.M{"status": status, "updated_at": time.Now()}}
+	res, err := d.coll.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("评论不存在")
+	}
+	return nil
+}
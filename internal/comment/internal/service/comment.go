@@ -1,20 +1,412 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/codepzj/Stellux-Server/internal/antispam"
+	"github.com/codepzj/Stellux-Server/internal/comment/internal/domain"
 	"github.com/codepzj/Stellux-Server/internal/comment/internal/repository"
+	"github.com/codepzj/Stellux-Server/internal/config"
+	"github.com/codepzj/Stellux-Server/internal/mail"
+	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 type ICommentService interface {
+	CreateComment(ctx context.Context, comment *domain.Comment, sub *antispam.Submission) error
+	GetCommentTreeByPath(ctx context.Context, path string) ([]*domain.CommentShow, error)
+	AdminUpdateComment(ctx context.Context, comment *domain.Comment) error
+	AdminDeleteComment(ctx context.Context, id bson.ObjectID) error
+	AdminReplyComment(ctx context.Context, comment *domain.Comment) error
+	AdminGetCommentList(ctx context.Context, page *apiwrap.Page, status string) ([]*domain.Comment, int64, error)
+	AdminUpdateCommentStatusBatch(ctx context.Context, ids []bson.ObjectID, status string) error
 }
 
 var _ ICommentService = (*CommentService)(nil)
 
-func NewCommentService(repo repository.ICommentRepository) *CommentService {
+func NewCommentService(repo repository.ICommentRepository, configServ config.Service, spamServ antispam.Service, mailServ mail.Service) *CommentService {
 	return &CommentService{
-		repo: repo,
+		repo:       repo,
+		configServ: configServ,
+		spamServ:   spamServ,
+		mailServ:   mailServ,
 	}
 }
 
 type CommentService struct {
-	repo repository.ICommentRepository
+	repo       repository.ICommentRepository
+	configServ config.Service
+	spamServ   antispam.Service
+	mailServ   mail.Service
+}
+
+// CreateComment 发表评论, 若存在父级评论则自动补全根评论Id, 并根据反垃圾检测和审核模式设置评论状态
+func (s *CommentService) CreateComment(ctx context.Context, comment *domain.Comment, sub *antispam.Submission) error {
+	parent, err := s.fillRootId(ctx, comment)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case comment.IsAdmin:
+		comment.Status = domain.CommentStatusApproved
+	case s.isSpam(ctx, comment, sub):
+		comment.Status = domain.CommentStatusSpam
+	case s.getAuditMode(ctx) == domain.CommentAuditModeAuto:
+		comment.Status = domain.CommentStatusApproved
+	default:
+		comment.Status = domain.CommentStatusPending
+	}
+
+	err = s.repo.Create(ctx, comment)
+	if err != nil {
+		logger.Error("创建评论失败",
+			logger.WithError(err),
+			logger.WithString("path", comment.Path),
+		)
+		return err
+	}
+
+	logger.Info("创建评论成功",
+		logger.WithString("path", comment.Path),
+		logger.WithString("nickname", comment.Nickname),
+		logger.WithString("status", comment.Status),
+	)
+
+	switch comment.Status {
+	case domain.CommentStatusApproved:
+		s.notifyReply(ctx, comment, parent)
+	case domain.CommentStatusPending:
+		s.notifyPending(ctx, comment)
+	}
+	return nil
+}
+
+// GetCommentTreeByPath 获取路径下已审核通过的评论树
+func (s *CommentService) GetCommentTreeByPath(ctx context.Context, path string) ([]*domain.CommentShow, error) {
+	comments, err := s.repo.GetListByPath(ctx, path, domain.CommentStatusApproved)
+	if err != nil {
+		logger.Error("查询评论列表失败",
+			logger.WithError(err),
+			logger.WithString("path", path),
+		)
+		return nil, err
+	}
+	return buildCommentTree(comments), nil
+}
+
+// AdminUpdateComment 管理员编辑评论
+func (s *CommentService) AdminUpdateComment(ctx context.Context, comment *domain.Comment) error {
+	existComment, err := s.repo.GetByID(ctx, comment.Id)
+	if err != nil {
+		logger.Error("查询评论失败",
+			logger.WithError(err),
+			logger.WithString("commentId", comment.Id.Hex()),
+		)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return errors.New("评论不存在")
+		}
+		return err
+	}
+	comment.IsAdmin = existComment.IsAdmin
+	// 未填写邮箱时保留原邮箱, 否则评论者将收不到回复提醒
+	if comment.Email == "" {
+		comment.Email = existComment.Email
+	}
+
+	err = s.repo.Update(ctx, comment)
+	if err != nil {
+		logger.Error("更新评论失败",
+			logger.WithError(err),
+			logger.WithString("commentId", comment.Id.Hex()),
+		)
+		return err
+	}
+
+	logger.Info("更新评论成功",
+		logger.WithString("commentId", comment.Id.Hex()),
+	)
+	return nil
+}
+
+// AdminDeleteComment 管理员删除评论, 同时删除其下所有回复
+func (s *CommentService) AdminDeleteComment(ctx context.Context, id bson.ObjectID) error {
+	comment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		logger.Error("查询评论失败",
+			logger.WithError(err),
+			logger.WithString("commentId", id.Hex()),
+		)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return errors.New("评论不存在")
+		}
+		return err
+	}
+
+	rootId := comment.RootId
+	if rootId.IsZero() {
+		rootId = comment.Id
+	}
+	replies, err := s.repo.GetListByRootId(ctx, rootId)
+	if err != nil {
+		logger.Error("查询回复列表失败",
+			logger.WithError(err),
+			logger.WithString("rootId", rootId.Hex()),
+		)
+		return err
+	}
+
+	ids := collectDescendantIds(comment.Id, replies)
+	err = s.repo.DeleteMany(ctx, ids)
+	if err != nil {
+		logger.Error("删除评论失败",
+			logger.WithError(err),
+			logger.WithString("commentId", id.Hex()),
+		)
+		return err
+	}
+
+	logger.Info("删除评论成功",
+		logger.WithString("commentId", id.Hex()),
+		logger.WithInt("count", len(ids)),
+	)
+	return nil
+}
+
+// AdminReplyComment 管理员回复评论
+func (s *CommentService) AdminReplyComment(ctx context.Context, comment *domain.Comment) error {
+	if comment.ParentId.IsZero() {
+		return errors.New("父级评论不能为空")
+	}
+	comment.IsAdmin = true
+	return s.CreateComment(ctx, comment, nil)
+}
+
+// AdminGetCommentList 管理员分页获取评论列表, 可按审核状态筛选
+func (s *CommentService) AdminGetCommentList(ctx context.Context, page *apiwrap.Page, status string) ([]*domain.Comment, int64, error) {
+	comments, count, err := s.repo.GetList(ctx, page, status)
+	if err != nil {
+		logger.Error("查询评论列表失败",
+			logger.WithError(err),
+			logger.WithString("status", status),
+		)
+		return nil, 0, err
+	}
+	return comments, count, nil
+}
+
+// AdminUpdateCommentStatusBatch 管理员批量更新评论审核状态
+func (s *CommentService) AdminUpdateCommentStatusBatch(ctx context.Context, ids []bson.ObjectID, status string) error {
+	if len(ids) == 0 {
+		return errors.New("评论id列表不能为空")
+	}
+	comments, err := s.repo.GetListByIds(ctx, ids)
+	if err != nil {
+		logger.Error("查询评论列表失败",
+			logger.WithError(err),
+		)
+		return err
+	}
+	err = s.repo.UpdateStatusBatch(ctx, ids, status)
+	if err != nil {
+		logger.Error("批量更新评论状态失败",
+			logger.WithError(err),
+			logger.WithString("status", status),
+		)
+		return err
+	}
+
+	logger.Info("批量更新评论状态成功",
+		logger.WithString("status", status),
+		logger.WithInt("count", len(ids)),
+	)
+
+	// 审核通过后才通知被回复者
+	if status == domain.CommentStatusApproved {
+		for _, comment := range comments {
+			if comment.Status == domain.CommentStatusApproved || comment.ParentId.IsZero() {
+				continue
+			}
+			parent, err := s.repo.GetByID(ctx, comment.ParentId)
+			if err != nil {
+				continue
+			}
+			s.notifyReply(ctx, comment, parent)
+		}
+	}
+	return nil
+}
+
+// notifyReply 邮件通知被回复者, 自己回复自己时不通知
+func (s *CommentService) notifyReply(ctx context.Context, comment *domain.Comment, parent *domain.Comment) {
+	if parent == nil || parent.Email == "" || strings.EqualFold(parent.Email, comment.Email) {
+		return
+	}
+	err := s.mailServ.NotifyCommentReply(ctx, &mail.ReplyNotice{
+		To:             parent.Email,
+		ParentNickname: parent.Nickname,
+		ParentContent:  parent.Content,
+		Nickname:       comment.Nickname,
+		Content:        comment.Content,
+		Path:           comment.Path,
+	})
+	if err != nil {
+		logger.Error("发送评论回复通知失败",
+			logger.WithError(err),
+			logger.WithString("parentId", parent.Id.Hex()),
+		)
+	}
+}
+
+// notifyPending 邮件通知管理员有新评论待审核
+func (s *CommentService) notifyPending(ctx context.Context, comment *domain.Comment) {
+	err := s.mailServ.NotifyCommentPending(ctx, &mail.PendingNotice{
+		Nickname: comment.Nickname,
+		Email:    comment.Email,
+		Content:  comment.Content,
+		Path:     comment.Path,
+	})
+	if err != nil {
+		logger.Error("发送待审核评论通知失败",
+			logger.WithError(err),
+			logger.WithString("path", comment.Path),
+		)
+	}
+}
+
+// isSpam 反垃圾检测, 检测服务异常时放行交由审核模式处理
+func (s *CommentService) isSpam(ctx context.Context, comment *domain.Comment, sub *antispam.Submission) bool {
+	if sub == nil {
+		sub = &antispam.Submission{}
+	}
+	sub.Content = comment.Content
+	sub.Nickname = comment.Nickname
+	sub.Email = comment.Email
+	sub.SiteUrl = comment.SiteUrl
+	sub.Ip = comment.Ip
+
+	verdict, err := s.spamServ.Check(ctx, sub)
+	if err != nil {
+		logger.Error("评论反垃圾检测失败",
+			logger.WithError(err),
+			logger.WithString("path", comment.Path),
+		)
+		return false
+	}
+	return verdict.Spam
+}
+
+// getAuditMode 获取站点评论审核模式, 未配置时默认人工审核
+func (s *CommentService) getAuditMode(ctx context.Context) string {
+	cfg, err := s.configServ.GetConfigByType(ctx, "comment")
+	if err != nil || cfg.Content.CommentAuditMode == "" {
+		return domain.CommentAuditModeManual
+	}
+	return cfg.Content.CommentAuditMode
+}
+
+// fillRootId 根据父级评论补全根评论Id和路径, 返回父级评论
+func (s *CommentService) fillRootId(ctx context.Context, comment *domain.Comment) (*domain.Comment, error) {
+	if comment.ParentId.IsZero() {
+		comment.RootId = bson.ObjectID{}
+		return nil, nil
+	}
+
+	parent, err := s.repo.GetByID(ctx, comment.ParentId)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			logger.Warn("父级评论不存在",
+				logger.WithString("parentId", comment.ParentId.Hex()),
+			)
+			return nil, errors.New("父级评论不存在")
+		}
+		logger.Error("查询父级评论失败",
+			logger.WithError(err),
+			logger.WithString("parentId", comment.ParentId.Hex()),
+		)
+		return nil, err
+	}
+
+	// 访客只能回复已审核通过的评论
+	if !comment.IsAdmin && parent.Status != domain.CommentStatusApproved {
+		logger.Warn("父级评论未通过审核",
+			logger.WithString("parentId", comment.ParentId.Hex()),
+			logger.WithString("status", parent.Status),
+		)
+		return nil, errors.New("父级评论不存在")
+	}
+
+	if comment.Path != "" && comment.Path != parent.Path {
+		logger.Warn("回复路径与父级评论不一致",
+			logger.WithString("path", comment.Path),
+			logger.WithString("parentPath", parent.Path),
+		)
+		return nil, errors.New("回复路径与父级评论不一致")
+	}
+	comment.Path = parent.Path
+
+	if parent.RootId.IsZero() {
+		comment.RootId = parent.Id
+	} else {
+		comment.RootId = parent.RootId
+	}
+	return parent, nil
+}
+
+// buildCommentTree 根据ParentId/RootId构建评论树, 根评论按时间倒序, 回复按时间正序
+func buildCommentTree(comments []*domain.CommentShow) []*domain.CommentShow {
+	nodeMap := make(map[bson.ObjectID]*domain.CommentShow, len(comments))
+	for _, comment := range comments {
+		comment.Children = nil
+		nodeMap[comment.Id] = comment
+	}
+
+	// 按创建时间正序挂载, 保证回复顺序
+	sorted := make([]*domain.CommentShow, len(comments))
+	copy(sorted, comments)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	var roots []*domain.CommentShow
+	for _, comment := range sorted {
+		if comment.ParentId.IsZero() {
+			roots = append(roots, comment)
+			continue
+		}
+		if parent, ok := nodeMap[comment.ParentId]; ok {
+			parent.Children = append(parent.Children, comment)
+			continue
+		}
+		// 父级评论已不存在时挂到根评论下
+		if root, ok := nodeMap[comment.RootId]; ok {
+			root.Children = append(root.Children, comment)
+			continue
+		}
+		roots = append(roots, comment)
+	}
+
+	sort.SliceStable(roots, func(i, j int) bool {
+		return roots[i].CreatedAt.After(roots[j].CreatedAt)
+	})
+	return roots
+}
+
+// collectDescendantIds 收集评论及其所有子孙评论的Id
+func collectDescendantIds(id bson.ObjectID, replies []*domain.Comment) []bson.ObjectID {
+	childrenMap := make(map[bson.ObjectID][]bson.ObjectID)
+	for _, reply := range replies {
+		childrenMap[reply.ParentId] = append(childrenMap[reply.ParentId], reply.Id)
+	}
+
+	ids := []bson.ObjectID{id}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, childrenMap[ids[i]]...)
+	}
+	return ids
 }
@@ -1,8 +1,14 @@
 package web
 
 import (
+	"github.com/codepzj/Stellux-Server/internal/antispam"
+	"github.com/codepzj/Stellux-Server/internal/comment/internal/domain"
 	"github.com/codepzj/Stellux-Server/internal/comment/internal/service"
+	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+	"github.com/codepzj/Stellux-Server/internal/pkg/middleware"
+	"github.com/codepzj/Stellux-Server/internal/pkg/permission"
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 func NewCommentHandler(serv service.ICommentService) *CommentHandler {
@@ -16,5 +22,185 @@ type CommentHandler struct {
 }
 
 func (h *CommentHandler) RegisterGinRoutes(engine *gin.Engine) {
+	commentGroup := engine.Group("/comment")
+	{
+		commentGroup.POST("/create", apiwrap.WrapWithJson(h.CreateComment)) // 发表评论
+		commentGroup.GET("/list", apiwrap.WrapWithQuery(h.GetCommentTree))  // 获取路径下的评论树
+	}
+	adminGroup := engine.Group("/admin-api/comment")
+	{
+		adminGroup.PUT("/edit", middleware.RequirePermission(permission.CommentWrite), apiwrap.WrapWithJson(h.AdminEditComment))                  // 管理员编辑评论
+		adminGroup.DELETE("/delete/:id", middleware.RequirePermission(permission.CommentWrite), apiwrap.WrapWithUri(h.AdminDeleteComment))        // 管理员删除评论
+		adminGroup.POST("/reply", middleware.RequirePermission(permission.CommentWrite), apiwrap.WrapWithJson(h.AdminReplyComment))               // 管理员回复评论
+		adminGroup.GET("/list", middleware.RequirePermission(permission.CommentRead), apiwrap.WrapWithQuery(h.AdminGetCommentList))               // 评论审核队列
+		adminGroup.PUT("/approve/batch", middleware.RequirePermission(permission.CommentWrite), apiwrap.WrapWithJson(h.AdminApproveCommentBatch)) // 批量通过评论
+		adminGroup.PUT("/reject/batch", middleware.RequirePermission(permission.CommentWrite), apiwrap.WrapWithJson(h.AdminRejectCommentBatch))   // 批量拒绝评论
+		adminGroup.PUT("/spam/batch", middleware.RequirePermission(permission.CommentWrite), apiwrap.WrapWithJson(h.AdminSpamCommentBatch))       // 批量标记垃圾评论
+	}
+}
+
+// CreateComment 发表评论
+func (h *CommentHandler) CreateComment(c *gin.Context, req CommentRequest) (int, string, any) {
+	var parentId bson.ObjectID
+	if req.ParentId != "" {
+		objId, err := bson.ObjectIDFromHex(req.ParentId)
+		if err != nil {
+			return 400, "parent_id格式错误", nil
+		}
+		parentId = objId
+	}
+	comment := &domain.Comment{
+		Path:     req.Path,
+		Content:  req.Content,
+		ParentId: parentId,
+		Nickname: req.Nickname,
+		Avatar:   req.Avatar,
+		Email:    req.Email,
+		SiteUrl:  req.SiteUrl,
+		Ip:       c.ClientIP(),
+	}
+	err := h.serv.CreateComment(c, comment, &antispam.Submission{
+		Honeypot: req.Honeypot,
+		Token:    req.Token,
+	})
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	// 垃圾评论同样提示等待审核, 避免暴露检测结果
+	if comment.Status != domain.CommentStatusApproved {
+		return 200, "评论已提交, 等待审核", nil
+	}
+	return 200, "发表评论成功", nil
+}
+
+// GetCommentTree 获取路径下的评论树
+func (h *CommentHandler) GetCommentTree(c *gin.Context, req CommentPathRequest) (int, string, any) {
+	comments, err := h.serv.GetCommentTreeByPath(c, req.Path)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "获取评论列表成功", h.CommentShowListToVOList(comments)
+}
+
+// AdminEditComment 管理员编辑评论
+func (h *CommentHandler) AdminEditComment(c *gin.Context, req CommentEditRequest) (int, string, any) {
+	objId, err := bson.ObjectIDFromHex(req.Id)
+	if err != nil {
+		return 400, "id格式错误", nil
+	}
+	err = h.serv.AdminUpdateComment(c, &domain.Comment{
+		Id:       objId,
+		Content:  req.Content,
+		Nickname: req.Nickname,
+		Avatar:   req.Avatar,
+		Email:    req.Email,
+		SiteUrl:  req.SiteUrl,
+	})
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "编辑评论成功", nil
+}
+
+// AdminDeleteComment 管理员删除评论
+func (h *CommentHandler) AdminDeleteComment(c *gin.Context, req CommentIdRequest) (int, string, any) {
+	objId, err := bson.ObjectIDFromHex(req.Id)
+	if err != nil {
+		return 400, "id格式错误", nil
+	}
+	err = h.serv.AdminDeleteComment(c, objId)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "删除评论成功", nil
+}
+
+// AdminReplyComment 管理员回复评论
+func (h *CommentHandler) AdminReplyComment(c *gin.Context, req CommentReplyRequest) (int, string, any) {
+	parentId, err := bson.ObjectIDFromHex(req.ParentId)
+	if err != nil {
+		return 400, "parent_id格式错误", nil
+	}
+	err = h.serv.AdminReplyComment(c, &domain.Comment{
+		ParentId: parentId,
+		Content:  req.Content,
+		Nickname: req.Nickname,
+		Avatar:   req.Avatar,
+		Email:    req.Email,
+		SiteUrl:  req.SiteUrl,
+	})
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "回复评论成功", nil
+}
 
+// AdminGetCommentList 管理员分页获取评论列表
+func (h *CommentHandler) AdminGetCommentList(c *gin.Context, pageReq CommentPage) (int, string, any) {
+	comments, total, err := h.serv.AdminGetCommentList(c, &apiwrap.Page{
+		PageNo:   pageReq.PageNo,
+		PageSize: pageReq.PageSize,
+		Keyword:  pageReq.Keyword,
+	}, pageReq.Status)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	commentVos := h.CommentListToAdminVOList(comments)
+
+	pageVo := apiwrap.ToPageVO(pageReq.PageNo, pageReq.PageSize, total, commentVos)
+	return 200, "获取评论列表成功", pageVo
+}
+
+// AdminApproveCommentBatch 管理员批量通过评论
+func (h *CommentHandler) AdminApproveCommentBatch(c *gin.Context, commentIDListRequest CommentIDListRequest) (int, string, any) {
+	var objIdList []bson.ObjectID
+	var err error
+	for _, id := range commentIDListRequest.IDList {
+		objId, err := bson.ObjectIDFromHex(id)
+		if err != nil {
+			return 400, "id格式错误", nil
+		}
+		objIdList = append(objIdList, objId)
+	}
+	err = h.serv.AdminUpdateCommentStatusBatch(c, objIdList, domain.CommentStatusApproved)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "批量通过评论成功", nil
+}
+
+// AdminRejectCommentBatch 管理员批量拒绝评论
+func (h *CommentHandler) AdminRejectCommentBatch(c *gin.Context, commentIDListRequest CommentIDListRequest) (int, string, any) {
+	var objIdList []bson.ObjectID
+	var err error
+	for _, id := range commentIDListRequest.IDList {
+		objId, err := bson.ObjectIDFromHex(id)
+		if err != nil {
+			return 400, "id格式错误", nil
+		}
+		objIdList = append(objIdList, objId)
+	}
+	err = h.serv.AdminUpdateCommentStatusBatch(c, objIdList, domain.CommentStatusRejected)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "批量拒绝评论成功", nil
+}
+
+// AdminSpamCommentBatch 管理员批量标记垃圾评论
+func (h *CommentHandler) AdminSpamCommentBatch(c *gin.Context, commentIDListRequest CommentIDListRequest) (int, string, any) {
+	var objIdList []bson.ObjectID
+	var err error
+	for _, id := range commentIDListRequest.IDList {
+		objId, err := bson.ObjectIDFromHex(id)
+		if err != nil {
+			return 400, "id格式错误", nil
+		}
+		objIdList = append(objIdList, objId)
+	}
+	err = h.serv.AdminUpdateCommentStatusBatch(c, objIdList, domain.CommentStatusSpam)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "批量标记垃圾评论成功", nil
 }
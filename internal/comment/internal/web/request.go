@@ -1,3 +1,55 @@
 package web
 
-type CommentRequest struct{}
+// CommentRequest 发表评论请求
+type CommentRequest struct {
+	Path     string `json:"path" binding:"required"`
+	Content  string `json:"content" binding:"required"`
+	ParentId string `json:"parent_id"`
+	Nickname string `json:"nickname" binding:"required"`
+	Avatar   string `json:"avatar"`
+	Email    string `json:"email" binding:"required,email"`
+	SiteUrl  string `json:"site_url"`
+	Honeypot string `json:"honeypot"` // 蜜罐字段, 前端隐藏不填写
+	Token    string `json:"token"`    // 表单令牌
+}
+
+// CommentPathRequest 根据路径查询评论请求
+type CommentPathRequest struct {
+	Path string `form:"path" binding:"required"`
+}
+
+// CommentEditRequest 管理员编辑评论请求
+type CommentEditRequest struct {
+	Id       string `json:"id" binding:"required"`
+	Content  string `json:"content" binding:"required"`
+	Nickname string `json:"nickname" binding:"required"`
+	Avatar   string `json:"avatar"`
+	Email    string `json:"email" binding:"omitempty,email"` // 为空时保留原邮箱
+	SiteUrl  string `json:"site_url"`
+}
+
+// CommentReplyRequest 管理员回复评论请求
+type CommentReplyRequest struct {
+	ParentId string `json:"parent_id" binding:"required"`
+	Content  string `json:"content" binding:"required"`
+	Nickname string `json:"nickname" binding:"required"`
+	Avatar   string `json:"avatar"`
+	Email    string `json:"email" binding:"omitempty,email"`
+	SiteUrl  string `json:"site_url"`
+}
+
+type CommentIdRequest struct {
+	Id string `uri:"id" binding:"required"`
+}
+
+// CommentPage 评论审核队列分页请求
+type CommentPage struct {
+	PageNo   int64  `form:"page_no" binding:"required,gte=1"`
+	PageSize int64  `form:"page_size" binding:"required,gte=1"`
+	Keyword  string `form:"keyword"`
+	Status   string `form:"status" binding:"omitempty,oneof=pending approved rejected spam"`
+}
+
+type CommentIDListRequest struct {
+	IDList []string `json:"id_list" binding:"required"`
+}
@@ -1,3 +1,104 @@
 package web
 
-type CommentVO struct{}
+import (
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/comment/internal/domain"
+	"github.com/samber/lo"
+)
+
+// CommentVO 前端展示的评论
+type CommentVO struct {
+	Id        string       `json:"id"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	Path      string       `json:"path"`
+	Content   string       `json:"content"`
+	RootId    string       `json:"root_id,omitempty"`
+	ParentId  string       `json:"parent_id,omitempty"`
+	Nickname  string       `json:"nickname"`
+	Avatar    string       `json:"avatar"`
+	SiteUrl   string       `json:"site_url"`
+	IsAdmin   bool         `json:"is_admin"`
+	Children  []*CommentVO `json:"children"`
+}
+
+func (h *CommentHandler) CommentShowToVO(comment *domain.CommentShow) *CommentVO {
+	var rootId, parentId string
+	if !comment.RootId.IsZero() {
+		rootId = comment.RootId.Hex()
+	}
+	if !comment.ParentId.IsZero() {
+		parentId = comment.ParentId.Hex()
+	}
+	return &CommentVO{
+		Id:        comment.Id.Hex(),
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+		Path:      comment.Path,
+		Content:   comment.Content,
+		RootId:    rootId,
+		ParentId:  parentId,
+		Nickname:  comment.Nickname,
+		Avatar:    comment.Avatar,
+		SiteUrl:   comment.SiteUrl,
+		IsAdmin:   comment.IsAdmin,
+		Children:  h.CommentShowListToVOList(comment.Children),
+	}
+}
+
+func (h *CommentHandler) CommentShowListToVOList(comments []*domain.CommentShow) []*CommentVO {
+	return lo.Map(comments, func(comment *domain.CommentShow, _ int) *CommentVO {
+		return h.CommentShowToVO(comment)
+	})
+}
+
+// CommentAdminVO 后台管理的评论, 包含邮箱和审核状态
+type CommentAdminVO struct {
+	Id        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Path      string    `json:"path"`
+	Content   string    `json:"content"`
+	RootId    string    `json:"root_id,omitempty"`
+	ParentId  string    `json:"parent_id,omitempty"`
+	Nickname  string    `json:"nickname"`
+	Avatar    string    `json:"avatar"`
+	Email     string    `json:"email"`
+	SiteUrl   string    `json:"site_url"`
+	IsAdmin   bool      `json:"is_admin"`
+	Status    string    `json:"status"`
+	Ip        string    `json:"ip"`
+}
+
+func (h *CommentHandler) CommentToAdminVO(comment *domain.Comment) *CommentAdminVO {
+	var rootId, parentId string
+	if !comment.RootId.IsZero() {
+		rootId = comment.RootId.Hex()
+	}
+	if !comment.ParentId.IsZero() {
+		parentId = comment.ParentId.Hex()
+	}
+	return &CommentAdminVO{
+		Id:        comment.Id.Hex(),
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+		Path:      comment.Path,
+		Content:   comment.Content,
+		RootId:    rootId,
+		ParentId:  parentId,
+		Nickname:  comment.Nickname,
+		Avatar:    comment.Avatar,
+		Email:     comment.Email,
+		SiteUrl:   comment.SiteUrl,
+		IsAdmin:   comment.IsAdmin,
+		Status:    comment.Status,
+		Ip:        comment.Ip,
+	}
+}
+
+func (h *CommentHandler) CommentListToAdminVOList(comments []*domain.Comment) []*CommentAdminVO {
+	return lo.Map(comments, func(comment *domain.Comment, _ int) *CommentAdminVO {
+		return h.CommentToAdminVO(comment)
+	})
+}
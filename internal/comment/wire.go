@@ -3,10 +3,13 @@
 package comment
 
 import (
+	"github.com/codepzj/Stellux-Server/internal/antispam"
 	"github.com/codepzj/Stellux-Server/internal/comment/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/comment/internal/repository/dao"
 	"github.com/codepzj/Stellux-Server/internal/comment/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/comment/internal/web"
+	"github.com/codepzj/Stellux-Server/internal/config"
+	"github.com/codepzj/Stellux-Server/internal/mail"
 	"github.com/google/wire"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
@@ -16,7 +19,7 @@ var CommentProviders = wire.NewSet(web.NewCommentHandler, service.NewCommentServ
 	wire.Bind(new(repository.ICommentRepository), new(*repository.CommentRepository)),
 	wire.Bind(new(dao.ICommentDao), new(*dao.CommentDao)))
 
-func InitCommentModule(mongoDB *mongo.Database) *Module {
+func InitCommentModule(mongoDB *mongo.Database, configServ config.Service, spamServ antispam.Service, mailServ mail.Service) *Module {
 	panic(wire.Build(
 		CommentProviders,
 		wire.Struct(new(Module), "Svc", "Hdl"),
@@ -7,20 +7,23 @@
 package comment
 
 import (
+	"github.com/codepzj/Stellux-Server/internal/antispam"
 	"github.com/codepzj/Stellux-Server/internal/comment/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/comment/internal/repository/dao"
 	"github.com/codepzj/Stellux-Server/internal/comment/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/comment/internal/web"
+	"github.com/codepzj/Stellux-Server/internal/config"
+	"github.com/codepzj/Stellux-Server/internal/mail"
 	"github.com/google/wire"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 // Injectors from wire.go:
 
-func InitCommentModule(mongoDB *mongo.Database) *Module {
+func InitCommentModule(mongoDB *mongo.Database, configServ config.Service, spamServ antispam.Service, mailServ mail.Service) *Module {
 	commentDao := dao.NewCommentDao(mongoDB)
 	commentRepository := repository.NewCommentRepository(commentDao)
-	commentService := service.NewCommentService(commentRepository)
+	commentService := service.NewCommentService(commentRepository, configServ, spamServ, mailServ)
 	commentHandler := web.NewCommentHandler(commentService)
 	module := &Module{
 		Svc: commentService,
@@ -11,7 +11,7 @@ type Config struct {
 	Id        bson.ObjectID `bson:"_id,omitempty"` // 配置ID
 	CreatedAt time.Time     `bson:"created_at"`    // 创建时间
 	UpdatedAt time.Time     `bson:"updated_at"`    // 更新时间
-	Type      string        `bson:"type"`          // 配置类型: home, about, seo
+	Type      string        `bson:"type"`          // 配置类型: home, about, seo, comment
 	Content   Content       `bson:"content"`       // 网站内容配置
 }
 
@@ -52,6 +52,9 @@ type Content struct {
 	OGDescription  string   `bson:"og_description,omitempty"`  // Open Graph描述
 	OGImage        string   `bson:"og_image,omitempty"`        // Open Graph图片
 	TwitterCard    string   `bson:"twitter_card,omitempty"`    // Twitter Card类型
+
+	// 评论配置
+	CommentAuditMode string `bson:"comment_audit_mode,omitempty"` // 评论审核模式: auto, manual
 }
 
 // Repo 开源项目
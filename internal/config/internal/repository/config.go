@@ -31,7 +31,11 @@ type ConfigRepository struct {
 // Create 创建网站配置
 func (r *ConfigRepository) Create(ctx context.Context, config *domain.Config) error {
 	daoConfig := r.ConfigDomainToDao(config)
-	return r.dao.Create(ctx, daoConfig)
+	if err := r.dao.Create(ctx, daoConfig); err != nil {
+		return err
+	}
+	config.Id = daoConfig.ID
+	return nil
 }
 
 // Update 更新网站配置
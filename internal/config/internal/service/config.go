@@ -5,9 +5,11 @@ import (
 	"errors"
 	"time"
 
+	"github.com/codepzj/Stellux-Server/internal/audit"
 	"github.com/codepzj/Stellux-Server/internal/config/internal/domain"
 	"github.com/codepzj/Stellux-Server/internal/config/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/codepzj/Stellux-Server/internal/sitemap"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
@@ -23,14 +25,18 @@ type IConfigService interface {
 
 var _ IConfigService = (*ConfigService)(nil)
 
-func NewConfigService(repo repository.IConfigRepository) *ConfigService {
+func NewConfigService(repo repository.IConfigRepository, sitemapServ sitemap.Service, auditServ audit.Service) *ConfigService {
 	return &ConfigService{
-		repo: repo,
+		repo:        repo,
+		sitemapServ: sitemapServ,
+		auditServ:   auditServ,
 	}
 }
 
 type ConfigService struct {
-	repo repository.IConfigRepository
+	repo        repository.IConfigRepository
+	sitemapServ sitemap.Service
+	auditServ   audit.Service
 }
 
 // CreateConfig 创建网站配置
@@ -54,6 +60,9 @@ func (s *ConfigService) CreateConfig(ctx context.Context, config *domain.Config)
 		return err
 	}
 
+	s.sitemapServ.Invalidate()
+	s.auditServ.Record(ctx, audit.ActionCreate, audit.EntityConfig, config.Id.Hex(), nil, config)
+
 	logger.Info("网站配置创建成功", logger.WithString("type", config.Type))
 	return nil
 }
@@ -87,11 +96,16 @@ func (s *ConfigService) UpdateConfig(ctx context.Context, config *domain.Config)
 
 	config.UpdatedAt = time.Now()
 
+	before, _ := s.repo.GetByID(ctx, config.Id)
 	if err := s.repo.Update(ctx, config); err != nil {
 		logger.Error("更新页面配置失败", logger.WithError(err), logger.WithString("id", config.Id.Hex()))
 		return err
 	}
 
+	s.sitemapServ.Invalidate()
+	after, _ := s.repo.GetByID(ctx, config.Id)
+	s.auditServ.Record(ctx, audit.ActionUpdate, audit.EntityConfig, config.Id.Hex(), before, after)
+
 	logger.Info("页面配置更新成功", logger.WithString("id", config.Id.Hex()))
 	return nil
 }
@@ -132,11 +146,15 @@ func (s *ConfigService) ListConfigs(ctx context.Context) ([]*domain.Config, erro
 func (s *ConfigService) DeleteConfig(ctx context.Context, id bson.ObjectID) error {
 	logger.Info("删除网站配置", logger.WithString("id", id.Hex()))
 
+	before, _ := s.repo.GetByID(ctx, id)
 	if err := s.repo.Delete(ctx, id); err != nil {
 		logger.Error("删除网站配置失败", logger.WithError(err), logger.WithString("id", id.Hex()))
 		return err
 	}
 
+	s.sitemapServ.Invalidate()
+	s.auditServ.Record(ctx, audit.ActionDelete, audit.EntityConfig, id.Hex(), before, nil)
+
 	logger.Info("网站配置删除成功", logger.WithString("id", id.Hex()))
 	return nil
 }
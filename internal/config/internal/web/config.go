@@ -3,6 +3,8 @@ package web
 import (
 	"github.com/codepzj/Stellux-Server/internal/config/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+	"github.com/codepzj/Stellux-Server/internal/pkg/middleware"
+	"github.com/codepzj/Stellux-Server/internal/pkg/permission"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
@@ -22,11 +24,11 @@ func (h *ConfigHandler) RegisterGinRoutes(engine *gin.Engine) {
 	// 管理API
 	adminGroup := engine.Group("/admin-api/config")
 	{
-		adminGroup.POST("create", apiwrap.WrapWithJson(h.AdminCreateConfig))
-		adminGroup.PUT("update", apiwrap.WrapWithJson(h.AdminUpdateConfig))
-		adminGroup.DELETE(":id", apiwrap.WrapWithUri(h.AdminDeleteConfig))
-		adminGroup.GET("list", apiwrap.Wrap(h.AdminListConfigs))
-		adminGroup.GET(":id", apiwrap.WrapWithUri(h.AdminGetConfigByID))
+		adminGroup.POST("create", middleware.RequirePermission(permission.ConfigWrite), apiwrap.WrapWithJson(h.AdminCreateConfig))
+		adminGroup.PUT("update", middleware.RequirePermission(permission.ConfigWrite), apiwrap.WrapWithJson(h.AdminUpdateConfig))
+		adminGroup.DELETE(":id", middleware.RequirePermission(permission.ConfigWrite), apiwrap.WrapWithUri(h.AdminDeleteConfig))
+		adminGroup.GET("list", middleware.RequirePermission(permission.ConfigRead), apiwrap.Wrap(h.AdminListConfigs))
+		adminGroup.GET(":id", middleware.RequirePermission(permission.ConfigRead), apiwrap.WrapWithUri(h.AdminGetConfigByID))
 	}
 
 	// 公开API
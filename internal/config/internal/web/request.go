@@ -2,7 +2,7 @@ package web
 
 // ConfigDto 网站配置DTO
 type ConfigDto struct {
-	Type    string  `json:"type" binding:"required,oneof=home about seo"`
+	Type    string  `json:"type" binding:"required,oneof=home about seo comment"`
 	Content Content `json:"content" binding:"required"`
 }
 
@@ -43,6 +43,9 @@ type Content struct {
 	OGDescription  string   `json:"og_description,omitempty"`
 	OGImage        string   `json:"og_image,omitempty"`
 	TwitterCard    string   `json:"twitter_card,omitempty"`
+
+	// 评论配置
+	CommentAuditMode string `json:"comment_audit_mode,omitempty" binding:"omitempty,oneof=auto manual"`
 }
 
 // Repo 开源项目
@@ -68,7 +71,7 @@ type Timeline struct {
 // ConfigUpdateDto 更新网站配置DTO
 type ConfigUpdateDto struct {
 	ID      string  `json:"id" binding:"required"`
-	Type    string  `json:"type" binding:"required,oneof=home about seo"`
+	Type    string  `json:"type" binding:"required,oneof=home about seo comment"`
 	Content Content `json:"content" binding:"required"`
 }
 
@@ -79,5 +82,5 @@ type ConfigIdRequest struct {
 
 // ConfigTypeRequest 类型请求
 type ConfigTypeRequest struct {
-	Type string `uri:"type" binding:"required,oneof=home about seo"`
+	Type string `uri:"type" binding:"required,oneof=home about seo comment"`
 }
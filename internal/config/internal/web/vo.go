@@ -99,6 +99,7 @@ func (h *ConfigHandler) ContentDtoToDomain(dto Content) domain.Content {
 		OGDescription:     dto.OGDescription,
 		OGImage:           dto.OGImage,
 		TwitterCard:       dto.TwitterCard,
+		CommentAuditMode:  dto.CommentAuditMode,
 	}
 }
 
@@ -170,6 +171,7 @@ func (h *ConfigHandler) ContentToVO(content domain.Content) Content {
 		OGDescription:     content.OGDescription,
 		OGImage:           content.OGImage,
 		TwitterCard:       content.TwitterCard,
+		CommentAuditMode:  content.CommentAuditMode,
 	}
 }
 
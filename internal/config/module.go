@@ -1,10 +1,12 @@
 package config
 
 import (
+	"github.com/codepzj/Stellux-Server/internal/audit"
 	"github.com/codepzj/Stellux-Server/internal/config/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/config/internal/repository/dao"
 	"github.com/codepzj/Stellux-Server/internal/config/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/config/internal/web"
+	"github.com/codepzj/Stellux-Server/internal/sitemap"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
@@ -19,10 +21,10 @@ type (
 	}
 )
 
-func New(db *mongo.Database) *Module {
+func New(db *mongo.Database, sitemapServ sitemap.Service, auditServ audit.Service) *Module {
 	configDao := dao.NewConfigDao(db)
 	configRepository := repository.NewConfigRepository(configDao)
-	configService := service.NewConfigService(configRepository)
+	configService := service.NewConfigService(configRepository, sitemapServ, auditServ)
 	configHandler := web.NewConfigHandler(configService)
 
 	return &Module{
@@ -3,10 +3,12 @@
 package config
 
 import (
+	"github.com/codepzj/Stellux-Server/internal/audit"
 	"github.com/codepzj/Stellux-Server/internal/config/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/config/internal/repository/dao"
 	"github.com/codepzj/Stellux-Server/internal/config/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/config/internal/web"
+	"github.com/codepzj/Stellux-Server/internal/sitemap"
 	"github.com/google/wire"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
@@ -16,7 +18,7 @@ var ConfigProviders = wire.NewSet(web.NewConfigHandler, service.NewConfigService
 	wire.Bind(new(repository.IConfigRepository), new(*repository.ConfigRepository)),
 	wire.Bind(new(dao.IConfigDao), new(*dao.ConfigDao)))
 
-func InitConfigModule(mongoDB *mongo.Database) *Module {
+func InitConfigModule(mongoDB *mongo.Database, sitemapServ sitemap.Service, auditServ audit.Service) *Module {
 	panic(wire.Build(
 		ConfigProviders,
 		wire.Struct(new(Module), "Svc", "Hdl"),
@@ -7,20 +7,22 @@
 package config
 
 import (
+	"github.com/codepzj/Stellux-Server/internal/audit"
 	"github.com/codepzj/Stellux-Server/internal/config/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/config/internal/repository/dao"
 	"github.com/codepzj/Stellux-Server/internal/config/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/config/internal/web"
+	"github.com/codepzj/Stellux-Server/internal/sitemap"
 	"github.com/google/wire"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 // Injectors from wire.go:
 
-func InitConfigModule(mongoDB *mongo.Database) *Module {
+func InitConfigModule(mongoDB *mongo.Database, sitemapServ sitemap.Service, auditServ audit.Service) *Module {
 	configDao := dao.NewConfigDao(mongoDB)
 	configRepository := repository.NewConfigRepository(configDao)
-	configService := service.NewConfigService(configRepository)
+	configService := service.NewConfigService(configRepository, sitemapServ, auditServ)
 	configHandler := web.NewConfigHandler(configService)
 	module := &Module{
 		Svc: configService,
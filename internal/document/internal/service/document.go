@@ -5,9 +5,11 @@ import (
 	"errors"
 	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
 
+	"github.com/codepzj/Stellux-Server/internal/audit"
 	"github.com/codepzj/Stellux-Server/internal/document/internal/domain"
 	"github.com/codepzj/Stellux-Server/internal/document/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/codepzj/Stellux-Server/internal/sitemap"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
@@ -28,14 +30,18 @@ type IDocumentService interface {
 
 var _ IDocumentService = (*DocumentService)(nil)
 
-func NewDocumentService(repo repository.IDocumentRepository) *DocumentService {
+func NewDocumentService(repo repository.IDocumentRepository, sitemapServ sitemap.Service, auditServ audit.Service) *DocumentService {
 	return &DocumentService{
-		repo: repo,
+		repo:        repo,
+		sitemapServ: sitemapServ,
+		auditServ:   auditServ,
 	}
 }
 
 type DocumentService struct {
-	repo repository.IDocumentRepository
+	repo        repository.IDocumentRepository
+	sitemapServ sitemap.Service
+	auditServ   audit.Service
 }
 
 func (s *DocumentService) CreateDocument(ctx context.Context, doc *domain.Document) (bson.ObjectID, error) {
@@ -65,6 +71,9 @@ func (s *DocumentService) CreateDocument(ctx context.Context, doc *domain.Docume
 		return bson.ObjectID{}, err
 	}
 
+	s.sitemapServ.Invalidate()
+	s.recordAudit(ctx, audit.ActionCreate, id, nil)
+
 	logger.Info("创建文档成功",
 		logger.WithString("documentId", id.Hex()),
 		logger.WithString("title", doc.Title),
@@ -115,6 +124,7 @@ func (s *DocumentService) UpdateDocumentById(ctx context.Context, id bson.Object
 		return errors.New("别名已存在")
 	}
 
+	before, _ := s.repo.FindDocumentById(ctx, id)
 	err = s.repo.UpdateDocumentById(ctx, id, doc)
 	if err != nil {
 		logger.Error("更新文档失败",
@@ -124,6 +134,9 @@ func (s *DocumentService) UpdateDocumentById(ctx context.Context, id bson.Object
 		return err
 	}
 
+	s.sitemapServ.Invalidate()
+	s.recordAudit(ctx, audit.ActionUpdate, id, before)
+
 	logger.Info("更新文档成功",
 		logger.WithString("documentId", id.Hex()),
 		logger.WithString("title", doc.Title),
@@ -133,6 +146,7 @@ func (s *DocumentService) UpdateDocumentById(ctx context.Context, id bson.Object
 }
 
 func (s *DocumentService) DeleteDocumentById(ctx context.Context, id bson.ObjectID) error {
+	before, _ := s.repo.FindDocumentById(ctx, id)
 	err := s.repo.DeleteDocumentById(ctx, id)
 	if err != nil {
 		logger.Error("删除文档失败",
@@ -142,6 +156,9 @@ func (s *DocumentService) DeleteDocumentById(ctx context.Context, id bson.Object
 		return err
 	}
 
+	s.sitemapServ.Invalidate()
+	s.recordAudit(ctx, audit.ActionDelete, id, before)
+
 	logger.Info("删除文档成功",
 		logger.WithString("documentId", id.Hex()),
 	)
@@ -150,6 +167,7 @@ func (s *DocumentService) DeleteDocumentById(ctx context.Context, id bson.Object
 }
 
 func (s *DocumentService) SoftDeleteDocumentById(ctx context.Context, id bson.ObjectID) error {
+	before, _ := s.repo.FindDocumentById(ctx, id)
 	err := s.repo.SoftDeleteDocumentById(ctx, id)
 	if err != nil {
 		logger.Error("软删除文档失败",
@@ -159,6 +177,9 @@ func (s *DocumentService) SoftDeleteDocumentById(ctx context.Context, id bson.Ob
 		return err
 	}
 
+	s.sitemapServ.Invalidate()
+	s.recordAudit(ctx, audit.ActionSoftDelete, id, before)
+
 	logger.Info("软删除文档成功",
 		logger.WithString("documentId", id.Hex()),
 	)
@@ -167,6 +188,7 @@ func (s *DocumentService) SoftDeleteDocumentById(ctx context.Context, id bson.Ob
 }
 
 func (s *DocumentService) RestoreDocumentById(ctx context.Context, id bson.ObjectID) error {
+	before, _ := s.repo.FindDocumentById(ctx, id)
 	err := s.repo.RestoreDocumentById(ctx, id)
 	if err != nil {
 		logger.Error("恢复文档失败",
@@ -176,6 +198,9 @@ func (s *DocumentService) RestoreDocumentById(ctx context.Context, id bson.Objec
 		return err
 	}
 
+	s.sitemapServ.Invalidate()
+	s.recordAudit(ctx, audit.ActionRestore, id, before)
+
 	logger.Info("恢复文档成功",
 		logger.WithString("documentId", id.Hex()),
 	)
@@ -276,3 +301,12 @@ func (s *DocumentService) GetAllPublicDocuments(ctx context.Context) ([]*domain.
 
 	return docs, nil
 }
+
+// recordAudit 记录文档修改审计日志, 删除操作不再查询修改后的文档
+func (s *DocumentService) recordAudit(ctx context.Context, action string, id bson.ObjectID, before *domain.Document) {
+	var after *domain.Document
+	if action != audit.ActionDelete {
+		after, _ = s.repo.FindDocumentById(ctx, id)
+	}
+	s.auditServ.Record(ctx, action, audit.EntityDocument, id.Hex(), before, after)
+}
@@ -7,6 +7,7 @@ import (
 	docService "github.com/codepzj/Stellux-Server/internal/document/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
 	"github.com/codepzj/Stellux-Server/internal/pkg/middleware"
+	"github.com/codepzj/Stellux-Server/internal/pkg/permission"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
@@ -24,16 +25,15 @@ type DocumentHandler struct {
 func (h *DocumentHandler) RegisterGinRoutes(engine *gin.Engine) {
 	adminDocumentGroup := engine.Group("/admin-api/document")
 	{
-		adminDocumentGroup.Use(middleware.JWT())
-		adminDocumentGroup.POST("/create", apiwrap.WrapWithJson(h.AdminCreateDocument))       // 管理员创建文档
-		adminDocumentGroup.GET("/find", apiwrap.Wrap(h.AdminFindDocument))                    // 管理员查询特定Id的文档
-		adminDocumentGroup.PUT("/update", apiwrap.WrapWithJson(h.AdminUpdateDocument))        // 管理员更新文档
-		adminDocumentGroup.DELETE("/delete/:id", apiwrap.Wrap(h.AdminDeleteDocument))         // 管理员删除文档
-		adminDocumentGroup.PUT("/soft-delete/:id", apiwrap.Wrap(h.AdminSoftDeleteDocument))   // 管理员软删除文档
-		adminDocumentGroup.PUT("/restore/:id", apiwrap.Wrap(h.AdminRestoreDocument))          // 管理员恢复文档
-		adminDocumentGroup.GET("/find-by-alias", apiwrap.Wrap(h.AdminFindDocumentByAlias))    // 管理员根据别名查询文档
-		adminDocumentGroup.GET("/list", apiwrap.WrapWithQuery(h.AdminGetDocumentList))        // 管理员获取文档列表
-		adminDocumentGroup.GET("/bin-list", apiwrap.WrapWithQuery(h.AdminGetDocumentBinList)) // 管理员获取文档回收箱列表
+		adminDocumentGroup.POST("/create", middleware.RequirePermission(permission.DocumentWrite), apiwrap.WrapWithJson(h.AdminCreateDocument))      // 管理员创建文档
+		adminDocumentGroup.GET("/find", middleware.RequirePermission(permission.DocumentRead), apiwrap.Wrap(h.AdminFindDocument))                    // 管理员查询特定Id的文档
+		adminDocumentGroup.PUT("/update", middleware.RequirePermission(permission.DocumentWrite), apiwrap.WrapWithJson(h.AdminUpdateDocument))       // 管理员更新文档
+		adminDocumentGroup.DELETE("/delete/:id", middleware.RequirePermission(permission.DocumentWrite), apiwrap.Wrap(h.AdminDeleteDocument))        // 管理员删除文档
+		adminDocumentGroup.PUT("/soft-delete/:id", middleware.RequirePermission(permission.DocumentWrite), apiwrap.Wrap(h.AdminSoftDeleteDocument))  // 管理员软删除文档
+		adminDocumentGroup.PUT("/restore/:id", middleware.RequirePermission(permission.DocumentWrite), apiwrap.Wrap(h.AdminRestoreDocument))         // 管理员恢复文档
+		adminDocumentGroup.GET("/find-by-alias", middleware.RequirePermission(permission.DocumentRead), apiwrap.Wrap(h.AdminFindDocumentByAlias))    // 管理员根据别名查询文档
+		adminDocumentGroup.GET("/list", middleware.RequirePermission(permission.DocumentRead), apiwrap.WrapWithQuery(h.AdminGetDocumentList))        // 管理员获取文档列表
+		adminDocumentGroup.GET("/bin-list", middleware.RequirePermission(permission.DocumentRead), apiwrap.WrapWithQuery(h.AdminGetDocumentBinList)) // 管理员获取文档回收箱列表
 	}
 
 	// 公开API
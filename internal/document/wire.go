@@ -3,10 +3,12 @@
 package document
 
 import (
+	"github.com/codepzj/Stellux-Server/internal/audit"
 	"github.com/codepzj/Stellux-Server/internal/document/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/document/internal/repository/dao"
 	"github.com/codepzj/Stellux-Server/internal/document/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/document/internal/web"
+	"github.com/codepzj/Stellux-Server/internal/sitemap"
 	"github.com/google/wire"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
@@ -16,7 +18,7 @@ var DocumentProviders = wire.NewSet(web.NewDocumentHandler, service.NewDocumentS
 	wire.Bind(new(repository.IDocumentRepository), new(*repository.DocumentRepository)),
 	wire.Bind(new(dao.IDocumentDao), new(*dao.DocumentDao)))
 
-func InitDocumentModule(mongoDB *mongo.Database) *Module {
+func InitDocumentModule(mongoDB *mongo.Database, sitemapServ sitemap.Service, auditServ audit.Service) *Module {
 	panic(wire.Build(
 		DocumentProviders,
 		wire.Struct(new(Module), "Svc", "Hdl"),
@@ -7,20 +7,22 @@
 package document
 
 import (
+	"github.com/codepzj/Stellux-Server/internal/audit"
 	"github.com/codepzj/Stellux-Server/internal/document/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/document/internal/repository/dao"
 	"github.com/codepzj/Stellux-Server/internal/document/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/document/internal/web"
+	"github.com/codepzj/Stellux-Server/internal/sitemap"
 	"github.com/google/wire"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 // Injectors from wire.go:
 
-func InitDocumentModule(mongoDB *mongo.Database) *Module {
+func InitDocumentModule(mongoDB *mongo.Database, sitemapServ sitemap.Service, auditServ audit.Service) *Module {
 	documentDao := dao.NewDocumentDao(mongoDB)
 	documentRepository := repository.NewDocumentRepository(documentDao)
-	documentService := service.NewDocumentService(documentRepository)
+	documentService := service.NewDocumentService(documentRepository, sitemapServ, auditServ)
 	documentHandler := web.NewDocumentHandler(documentService)
 	module := &Module{
 		Svc: documentService,
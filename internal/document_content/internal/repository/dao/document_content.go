@@ -41,8 +41,6 @@ type IDocumentContentDao interface {
 	UpdateDocumentContentById(ctx context.Context, id bson.ObjectID, doc DocumentContent) error
 	GetDocumentContentList(ctx context.Context, page *apiwrap.Page, documentId bson.ObjectID) ([]*DocumentContent, int64, error)
 	GetPublicDocumentContentListByDocumentId(ctx context.Context, documentId bson.ObjectID) ([]*DocumentContent, error)
-	SearchDocumentContent(ctx context.Context, keyword string) ([]DocumentContent, error)
-	SearchPublicDocumentContent(ctx context.Context, keyword string) ([]DocumentContent, error)
 	FindPublicDocumentContentById(ctx context.Context, id bson.ObjectID) (DocumentContent, error)
 	FindPublicDocumentContentByParentId(ctx context.Context, parentId bson.ObjectID) ([]DocumentContent, error)
 	FindPublicDocumentContentByDocumentId(ctx context.Context, documentId bson.ObjectID) ([]DocumentContent, error)
@@ -246,54 +244,6 @@ func (d *DocumentContentDao) GetPublicDocumentContentListByDocumentId(ctx contex
 	return documents, nil
 }
 
-// SearchDocumentContent 搜索文档内容
-func (d *DocumentContentDao) SearchDocumentContent(ctx context.Context, keyword string) ([]DocumentContent, error) {
-	filter := bson.M{
-		"is_deleted": false,
-		"$or": []bson.M{
-			{"title": bson.M{"$regex": keyword, "$options": "i"}},
-			{"description": bson.M{"$regex": keyword, "$options": "i"}},
-		},
-	}
-	opts := options.Find().SetSort(bson.D{{Key: "sort", Value: 1}, {Key: "created_at", Value: -1}})
-
-	cursor, err := d.coll.Find(ctx, filter, opts)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var results []DocumentContent
-	if err = cursor.All(ctx, &results); err != nil {
-		return nil, err
-	}
-	return results, nil
-}
-
-// SearchPublicDocumentContent 搜索公开文档内容
-func (d *DocumentContentDao) SearchPublicDocumentContent(ctx context.Context, keyword string) ([]DocumentContent, error) {
-	filter := bson.M{
-		"is_deleted": false,
-		"$or": []bson.M{
-			{"title": bson.M{"$regex": keyword, "$options": "i"}},
-			{"description": bson.M{"$regex": keyword, "$options": "i"}},
-		},
-	}
-	opts := options.Find().SetSort(bson.D{{Key: "sort", Value: 1}, {Key: "created_at", Value: -1}})
-
-	cursor, err := d.coll.Find(ctx, filter, opts)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var results []DocumentContent
-	if err = cursor.All(ctx, &results); err != nil {
-		return nil, err
-	}
-	return results, nil
-}
-
 // FindPublicDocumentContentById 根据id查询公开文档内容
 func (d *DocumentContentDao) FindPublicDocumentContentById(ctx context.Context, id bson.ObjectID) (DocumentContent, error) {
 	filter := bson.M{
@@ -22,8 +22,6 @@ type IDocumentContentRepository interface {
 	UpdateDocumentContentById(ctx context.Context, id bson.ObjectID, doc domain.DocumentContent) error
 	GetDocumentContentList(ctx context.Context, page *apiwrap.Page, documentId bson.ObjectID) ([]domain.DocumentContent, int64, error)
 	GetPublicDocumentContentListByDocumentId(ctx context.Context, documentId bson.ObjectID) ([]domain.DocumentContent, error)
-	SearchDocumentContent(ctx context.Context, keyword string) ([]domain.DocumentContent, error)
-	SearchPublicDocumentContent(ctx context.Context, keyword string) ([]domain.DocumentContent, error)
 	FindPublicDocumentContentById(ctx context.Context, id bson.ObjectID) (domain.DocumentContent, error)
 	FindPublicDocumentContentByParentId(ctx context.Context, parentId bson.ObjectID) ([]domain.DocumentContent, error)
 	FindPublicDocumentContentByDocumentId(ctx context.Context, documentId bson.ObjectID) ([]domain.DocumentContent, error)
@@ -227,62 +225,6 @@ func (r *DocumentContentRepository) GetPublicDocumentContentListByDocumentId(ctx
 	return results, nil
 }
 
-// SearchDocumentContent 搜索文档内容
-func (r *DocumentContentRepository) SearchDocumentContent(ctx context.Context, keyword string) ([]domain.DocumentContent, error) {
-	docs, err := r.dao.SearchDocumentContent(ctx, keyword)
-	if err != nil {
-		return nil, err
-	}
-
-	results := make([]domain.DocumentContent, len(docs))
-	for i, doc := range docs {
-		results[i] = domain.DocumentContent{
-			Id:          doc.ID,
-			CreatedAt:   doc.CreatedAt,
-			UpdatedAt:   doc.UpdatedAt,
-			DeletedAt:   convertDeletedAt(doc.DeletedAt),
-			DocumentId:  doc.DocumentId,
-			Title:       doc.Title,
-			Content:     doc.Content,
-			Description: doc.Description,
-			Alias:       doc.Alias,
-			ParentId:    doc.ParentId,
-			IsDir:       doc.IsDir,
-			Sort:        doc.Sort,
-			IsDeleted:   doc.IsDeleted,
-		}
-	}
-	return results, nil
-}
-
-// SearchPublicDocumentContent 搜索公开文档内容
-func (r *DocumentContentRepository) SearchPublicDocumentContent(ctx context.Context, keyword string) ([]domain.DocumentContent, error) {
-	docs, err := r.dao.SearchPublicDocumentContent(ctx, keyword)
-	if err != nil {
-		return nil, err
-	}
-
-	results := make([]domain.DocumentContent, len(docs))
-	for i, doc := range docs {
-		results[i] = domain.DocumentContent{
-			Id:          doc.ID,
-			CreatedAt:   doc.CreatedAt,
-			UpdatedAt:   doc.UpdatedAt,
-			DeletedAt:   convertDeletedAt(doc.DeletedAt),
-			DocumentId:  doc.DocumentId,
-			Title:       doc.Title,
-			Content:     doc.Content,
-			Description: doc.Description,
-			Alias:       doc.Alias,
-			ParentId:    doc.ParentId,
-			IsDir:       doc.IsDir,
-			Sort:        doc.Sort,
-			IsDeleted:   doc.IsDeleted,
-		}
-	}
-	return results, nil
-}
-
 // FindPublicDocumentContentById 根据id查询公开文档内容
 func (r *DocumentContentRepository) FindPublicDocumentContentById(ctx context.Context, id bson.ObjectID) (domain.DocumentContent, error) {
 	doc, err := r.dao.FindPublicDocumentContentById(ctx, id)
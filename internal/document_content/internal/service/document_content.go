@@ -5,9 +5,13 @@ import (
 	"errors"
 	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
 
+	"github.com/codepzj/Stellux-Server/internal/audit"
 	"github.com/codepzj/Stellux-Server/internal/document_content/internal/domain"
 	"github.com/codepzj/Stellux-Server/internal/document_content/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/codepzj/Stellux-Server/internal/search"
+	"github.com/codepzj/Stellux-Server/internal/sitemap"
+	"github.com/samber/lo"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
@@ -22,8 +26,8 @@ type IDocumentContentService interface {
 	UpdateDocumentContentById(ctx context.Context, id bson.ObjectID, doc domain.DocumentContent) error
 	GetDocumentContentList(ctx context.Context, page *apiwrap.Page, documentId bson.ObjectID) ([]domain.DocumentContent, int64, error)
 	GetPublicDocumentContentListByDocumentId(ctx context.Context, documentId bson.ObjectID) ([]domain.DocumentContent, error)
-	SearchDocumentContent(ctx context.Context, keyword string) ([]domain.DocumentContent, error)
-	SearchPublicDocumentContent(ctx context.Context, keyword string) ([]domain.DocumentContent, error)
+	SearchDocumentContent(ctx context.Context, page *apiwrap.Page) ([]*search.Result, int64, error)
+	SearchPublicDocumentContent(ctx context.Context, page *apiwrap.Page) ([]*search.Result, int64, error)
 	FindPublicDocumentContentById(ctx context.Context, id bson.ObjectID) (domain.DocumentContent, error)
 	FindPublicDocumentContentByParentId(ctx context.Context, parentId bson.ObjectID) ([]domain.DocumentContent, error)
 	FindPublicDocumentContentByDocumentId(ctx context.Context, documentId bson.ObjectID) ([]domain.DocumentContent, error)
@@ -33,14 +37,20 @@ type IDocumentContentService interface {
 
 var _ IDocumentContentService = (*DocumentContentService)(nil)
 
-func NewDocumentContentService(repo repository.IDocumentContentRepository) *DocumentContentService {
+func NewDocumentContentService(repo repository.IDocumentContentRepository, searchServ search.Service, sitemapServ sitemap.Service, auditServ audit.Service) *DocumentContentService {
 	return &DocumentContentService{
-		repo: repo,
+		repo:        repo,
+		searchServ:  searchServ,
+		sitemapServ: sitemapServ,
+		auditServ:   auditServ,
 	}
 }
 
 type DocumentContentService struct {
-	repo repository.IDocumentContentRepository
+	repo        repository.IDocumentContentRepository
+	searchServ  search.Service
+	sitemapServ sitemap.Service
+	auditServ   audit.Service
 }
 
 func (s *DocumentContentService) CreateDocumentContent(ctx context.Context, doc domain.DocumentContent) (bson.ObjectID, error) {
@@ -71,6 +81,10 @@ func (s *DocumentContentService) CreateDocumentContent(ctx context.Context, doc
 		return bson.ObjectID{}, err
 	}
 
+	s.searchServ.SyncDocumentContent(ctx, id)
+	s.sitemapServ.Invalidate()
+	s.recordAudit(ctx, audit.ActionCreate, id, nil)
+
 	logger.Info("创建文档内容成功",
 		logger.WithString("contentId", id.Hex()),
 		logger.WithString("title", doc.Title),
@@ -98,6 +112,7 @@ func (s *DocumentContentService) FindDocumentContentById(ctx context.Context, id
 }
 
 func (s *DocumentContentService) DeleteDocumentContentById(ctx context.Context, id bson.ObjectID) error {
+	before := s.contentSnapshot(ctx, id)
 	err := s.repo.DeleteDocumentContentById(ctx, id)
 	if err != nil {
 		logger.Error("删除文档内容失败",
@@ -107,6 +122,10 @@ func (s *DocumentContentService) DeleteDocumentContentById(ctx context.Context,
 		return err
 	}
 
+	s.searchServ.SyncDocumentContent(ctx, id)
+	s.sitemapServ.Invalidate()
+	s.recordAudit(ctx, audit.ActionDelete, id, before)
+
 	logger.Info("删除文档内容成功",
 		logger.WithString("contentId", id.Hex()),
 	)
@@ -115,6 +134,7 @@ func (s *DocumentContentService) DeleteDocumentContentById(ctx context.Context,
 }
 
 func (s *DocumentContentService) SoftDeleteDocumentContentById(ctx context.Context, id bson.ObjectID) error {
+	before := s.contentSnapshot(ctx, id)
 	err := s.repo.SoftDeleteDocumentContentById(ctx, id)
 	if err != nil {
 		logger.Error("软删除文档内容失败",
@@ -124,6 +144,10 @@ func (s *DocumentContentService) SoftDeleteDocumentContentById(ctx context.Conte
 		return err
 	}
 
+	s.searchServ.SyncDocumentContent(ctx, id)
+	s.sitemapServ.Invalidate()
+	s.recordAudit(ctx, audit.ActionSoftDelete, id, before)
+
 	logger.Info("软删除文档内容成功",
 		logger.WithString("contentId", id.Hex()),
 	)
@@ -132,6 +156,7 @@ func (s *DocumentContentService) SoftDeleteDocumentContentById(ctx context.Conte
 }
 
 func (s *DocumentContentService) RestoreDocumentContentById(ctx context.Context, id bson.ObjectID) error {
+	before := s.contentSnapshot(ctx, id)
 	err := s.repo.RestoreDocumentContentById(ctx, id)
 	if err != nil {
 		logger.Error("恢复文档内容失败",
@@ -141,6 +166,10 @@ func (s *DocumentContentService) RestoreDocumentContentById(ctx context.Context,
 		return err
 	}
 
+	s.searchServ.SyncDocumentContent(ctx, id)
+	s.sitemapServ.Invalidate()
+	s.recordAudit(ctx, audit.ActionRestore, id, before)
+
 	logger.Info("恢复文档内容成功",
 		logger.WithString("contentId", id.Hex()),
 	)
@@ -196,6 +225,7 @@ func (s *DocumentContentService) UpdateDocumentContentById(ctx context.Context,
 
 	// 如果别名不存在,或者别名存在且是当前文档的别名,则更新或者当前文档是目录
 	if len(docContentList) == 0 || (len(docContentList) == 1 && docContentList[0].Id.Hex() == id.Hex()) || doc.IsDir {
+		before := s.contentSnapshot(ctx, id)
 		err = s.repo.UpdateDocumentContentById(ctx, id, doc)
 		if err != nil {
 			logger.Error("更新文档内容失败",
@@ -204,6 +234,9 @@ func (s *DocumentContentService) UpdateDocumentContentById(ctx context.Context,
 			)
 			return err
 		}
+		s.searchServ.SyncDocumentContent(ctx, id)
+		s.sitemapServ.Invalidate()
+		s.recordAudit(ctx, audit.ActionUpdate, id, before)
 		logger.Info("更新文档内容成功",
 			logger.WithString("contentId", id.Hex()),
 			logger.WithString("title", doc.Title),
@@ -253,52 +286,6 @@ func (s *DocumentContentService) GetPublicDocumentContentListByDocumentId(ctx co
 	return contents, nil
 }
 
-func (s *DocumentContentService) SearchDocumentContent(ctx context.Context, keyword string) ([]domain.DocumentContent, error) {
-	logger.Info("搜索文档内容",
-		logger.WithString("method", "SearchDocumentContent"),
-		logger.WithString("keyword", keyword),
-	)
-
-	contents, err := s.repo.SearchDocumentContent(ctx, keyword)
-	if err != nil {
-		logger.Error("搜索文档内容失败",
-			logger.WithError(err),
-			logger.WithString("keyword", keyword),
-		)
-		return nil, err
-	}
-
-	logger.Info("搜索文档内容成功",
-		logger.WithInt("count", len(contents)),
-		logger.WithString("keyword", keyword),
-	)
-
-	return contents, nil
-}
-
-func (s *DocumentContentService) SearchPublicDocumentContent(ctx context.Context, keyword string) ([]domain.DocumentContent, error) {
-	logger.Info("搜索公开文档内容",
-		logger.WithString("method", "SearchPublicDocumentContent"),
-		logger.WithString("keyword", keyword),
-	)
-
-	contents, err := s.repo.SearchPublicDocumentContent(ctx, keyword)
-	if err != nil {
-		logger.Error("搜索公开文档内容失败",
-			logger.WithError(err),
-			logger.WithString("keyword", keyword),
-		)
-		return nil, err
-	}
-
-	logger.Info("搜索公开文档内容成功",
-		logger.WithInt("count", len(contents)),
-		logger.WithString("keyword", keyword),
-	)
-
-	return contents, nil
-}
-
 func (s *DocumentContentService) FindPublicDocumentContentById(ctx context.Context, id bson.ObjectID) (domain.DocumentContent, error) {
 	logger.Info("查询公开文档内容",
 		logger.WithString("method", "FindPublicDocumentContentById"),
@@ -354,6 +341,13 @@ func (s *DocumentContentService) FindPublicDocumentContentByDocumentId(ctx conte
 }
 
 func (s *DocumentContentService) DeleteDocumentContentList(ctx context.Context, ids []string) error {
+	objIds := lo.FilterMap(ids, func(id string, _ int) (bson.ObjectID, bool) {
+		objId, err := bson.ObjectIDFromHex(id)
+		return objId, err == nil
+	})
+	before := lo.Map(objIds, func(id bson.ObjectID, _ int) *domain.DocumentContent {
+		return s.contentSnapshot(ctx, id)
+	})
 	err := s.repo.DeleteDocumentContentList(ctx, ids)
 	if err != nil {
 		logger.Error("批量删除文档内容失败",
@@ -363,6 +357,12 @@ func (s *DocumentContentService) DeleteDocumentContentList(ctx context.Context,
 		return err
 	}
 
+	s.searchServ.SyncDocumentContent(ctx, objIds...)
+	s.sitemapServ.Invalidate()
+	for i, id := range objIds {
+		s.recordAudit(ctx, audit.ActionDelete, id, before[i])
+	}
+
 	logger.Info("批量删除文档内容成功",
 		logger.WithInt("count", len(ids)),
 	)
@@ -388,3 +388,31 @@ func (s *DocumentContentService) FindPublicDocumentContentByRootIdAndAlias(ctx c
 
 	return content, nil
 }
+
+// SearchDocumentContent 全文搜索文档内容, 包含非公开文档
+func (s *DocumentContentService) SearchDocumentContent(ctx context.Context, page *apiwrap.Page) ([]*search.Result, int64, error) {
+	return s.searchServ.Search(ctx, &search.Query{Page: *page, Type: search.TypeDocument})
+}
+
+// SearchPublicDocumentContent 全文搜索公开文档内容
+func (s *DocumentContentService) SearchPublicDocumentContent(ctx context.Context, page *apiwrap.Page) ([]*search.Result, int64, error) {
+	return s.searchServ.Search(ctx, &search.Query{Page: *page, Type: search.TypeDocument, Public: true})
+}
+
+// contentSnapshot 查询文档内容用于审计日志对比, 查询失败时按不存在处理
+func (s *DocumentContentService) contentSnapshot(ctx context.Context, id bson.ObjectID) *domain.DocumentContent {
+	content, err := s.repo.FindDocumentContentById(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return &content
+}
+
+// recordAudit 记录文档内容修改审计日志, 删除操作不再查询修改后的内容
+func (s *DocumentContentService) recordAudit(ctx context.Context, action string, id bson.ObjectID, before *domain.DocumentContent) {
+	var after *domain.DocumentContent
+	if action != audit.ActionDelete {
+		after = s.contentSnapshot(ctx, id)
+	}
+	s.auditServ.Record(ctx, action, audit.EntityDocumentContent, id.Hex(), before, after)
+}
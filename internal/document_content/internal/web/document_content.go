@@ -2,48 +2,52 @@ package web
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/codepzj/Stellux-Server/internal/document_content/internal/domain"
 	"github.com/codepzj/Stellux-Server/internal/document_content/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+	"github.com/codepzj/Stellux-Server/internal/pkg/markdown"
 	"github.com/codepzj/Stellux-Server/internal/pkg/middleware"
+	"github.com/codepzj/Stellux-Server/internal/pkg/permission"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
-func NewDocumentContentHandler(serv service.IDocumentContentService) *DocumentContentHandler {
+func NewDocumentContentHandler(serv service.IDocumentContentService, renderer *markdown.Renderer) *DocumentContentHandler {
 	return &DocumentContentHandler{
-		serv: serv,
+		serv:     serv,
+		renderer: renderer,
 	}
 }
 
 type DocumentContentHandler struct {
-	serv service.IDocumentContentService
+	serv     service.IDocumentContentService
+	renderer *markdown.Renderer
 }
 
 func (h *DocumentContentHandler) RegisterGinRoutes(engine *gin.Engine) {
 	adminDocumentContentGroup := engine.Group("/admin-api/document-content")
 	{
-		adminDocumentContentGroup.Use(middleware.JWT())
-		adminDocumentContentGroup.POST("/create", apiwrap.WrapWithJson(h.CreateDocumentContent))         // 管理员创建文档内容
-		adminDocumentContentGroup.GET("/:id", apiwrap.Wrap(h.FindDocumentContentById))                   // 管理员查询特定Id的文档内容
-		adminDocumentContentGroup.DELETE("/:id", apiwrap.Wrap(h.DeleteDocumentContentById))              // 管理员删除特定Id的文档内容
-		adminDocumentContentGroup.PUT("/soft-delete/:id", apiwrap.Wrap(h.SoftDeleteDocumentContentById)) // 管理员软删除特定Id的文档内容
-		adminDocumentContentGroup.PUT("/restore/:id", apiwrap.Wrap(h.RestoreDocumentContentById))        // 管理员恢复特定Id的文档内容
-		adminDocumentContentGroup.GET("/all/parent-id", apiwrap.Wrap(h.FindDocumentContentByParentId))   // 管理员查询特定父级Id的所有子文档内容
-		adminDocumentContentGroup.GET("/all", apiwrap.Wrap(h.FindDocumentContentByDocumentId))           // 管理员查询特定文档Id的所有子文档内容
-		adminDocumentContentGroup.PUT("/update", apiwrap.WrapWithJson(h.UpdateDocumentContentById))      // 管理员更新特定Id的文档内容
-		adminDocumentContentGroup.GET("/list", apiwrap.WrapWithQuery(h.GetDocumentContentList))          // 管理员获取文档内容列表
-		adminDocumentContentGroup.GET("/search", apiwrap.Wrap(h.SearchDocumentContent))                  // 管理员搜索文档内容
-		adminDocumentContentGroup.POST("/delete-list", apiwrap.Wrap(h.DeleteDocumentContentList))        // 管理员批量删除文档内容
+		adminDocumentContentGroup.POST("/create", middleware.RequirePermission(permission.DocumentWrite), apiwrap.WrapWithJson(h.CreateDocumentContent))         // 管理员创建文档内容
+		adminDocumentContentGroup.GET("/:id", middleware.RequirePermission(permission.DocumentRead), apiwrap.Wrap(h.FindDocumentContentById))                    // 管理员查询特定Id的文档内容
+		adminDocumentContentGroup.DELETE("/:id", middleware.RequirePermission(permission.DocumentWrite), apiwrap.Wrap(h.DeleteDocumentContentById))              // 管理员删除特定Id的文档内容
+		adminDocumentContentGroup.PUT("/soft-delete/:id", middleware.RequirePermission(permission.DocumentWrite), apiwrap.Wrap(h.SoftDeleteDocumentContentById)) // 管理员软删除特定Id的文档内容
+		adminDocumentContentGroup.PUT("/restore/:id", middleware.RequirePermission(permission.DocumentWrite), apiwrap.Wrap(h.RestoreDocumentContentById))        // 管理员恢复特定Id的文档内容
+		adminDocumentContentGroup.GET("/all/parent-id", middleware.RequirePermission(permission.DocumentRead), apiwrap.Wrap(h.FindDocumentContentByParentId))    // 管理员查询特定父级Id的所有子文档内容
+		adminDocumentContentGroup.GET("/all", middleware.RequirePermission(permission.DocumentRead), apiwrap.Wrap(h.FindDocumentContentByDocumentId))            // 管理员查询特定文档Id的所有子文档内容
+		adminDocumentContentGroup.PUT("/update", middleware.RequirePermission(permission.DocumentWrite), apiwrap.WrapWithJson(h.UpdateDocumentContentById))      // 管理员更新特定Id的文档内容
+		adminDocumentContentGroup.GET("/list", middleware.RequirePermission(permission.DocumentRead), apiwrap.WrapWithQuery(h.GetDocumentContentList))           // 管理员获取文档内容列表
+		adminDocumentContentGroup.GET("/search", middleware.RequirePermission(permission.DocumentRead), apiwrap.WrapWithQuery(h.SearchDocumentContent))          // 管理员搜索文档内容
+		adminDocumentContentGroup.POST("/delete-list", middleware.RequirePermission(permission.DocumentWrite), apiwrap.Wrap(h.DeleteDocumentContentList))        // 管理员批量删除文档内容
 	}
 
 	// 公开API
 	documentContentGroup := engine.Group("/document-content")
 	{
-		documentContentGroup.GET("/:id", apiwrap.Wrap(h.FindPublicDocumentContentById))         // 公开查询特定Id的文档内容
-		documentContentGroup.GET("/all", apiwrap.Wrap(h.FindPublicDocumentContentByDocumentId)) // 公开查询特定文档Id的所有子文档内容
-		// documentContentGroup.GET("/search", apiwrap.Wrap(h.SearchPublicDocumentContent))                          // 公开搜索文档内容
+		documentContentGroup.GET("/:id", apiwrap.Wrap(h.FindPublicDocumentContentById))                           // 公开查询特定Id的文档内容
+		documentContentGroup.GET("/all", apiwrap.Wrap(h.FindPublicDocumentContentByDocumentId))                   // 公开查询特定文档Id的所有子文档内容
+		documentContentGroup.GET("/search", apiwrap.WrapWithQuery(h.SearchPublicDocumentContent))                 // 公开搜索文档内容
 		documentContentGroup.GET("/by-root-and-alias", apiwrap.Wrap(h.FindPublicDocumentContentByRootIdAndAlias)) // 公开根据根文档ID和别名查询文档内容
 	}
 }
@@ -88,7 +92,9 @@ func (h *DocumentContentHandler) FindDocumentContentById(c *gin.Context) (int, s
 	if err != nil {
 		return 500, err.Error(), nil
 	}
-	return 200, fmt.Sprintf("查询文档内容成功, 文档Id:%s", documentId), h.DocumentContentDomainToVO(doc)
+	vo := h.DocumentContentDomainToVO(doc)
+	vo.SetRendered(h.render(c, doc.Content))
+	return 200, fmt.Sprintf("查询文档内容成功, 文档Id:%s", documentId), vo
 }
 
 // DeleteDocumentContentById 管理员删除特定Id的文档内容
@@ -233,18 +239,24 @@ func (h *DocumentContentHandler) GetDocumentContentList(c *gin.Context, page api
 	return 200, "获取文档内容列表成功", apiwrap.ToPageVO(page.PageNo, page.PageSize, count, docsVOPtr)
 }
 
-// SearchDocumentContent 管理员搜索文档内容
-func (h *DocumentContentHandler) SearchDocumentContent(c *gin.Context) (int, string, any) {
-	keyword := c.Query("keyword")
-	if keyword == "" {
-		return 400, "keyword不能为空", nil
+// SearchDocumentContent 管理员全文搜索文档内容
+func (h *DocumentContentHandler) SearchDocumentContent(c *gin.Context, req SearchDocumentContentRequest) (int, string, any) {
+	page := req.ToPage()
+	results, total, err := h.serv.SearchDocumentContent(c, page)
+	if err != nil {
+		return 500, err.Error(), nil
 	}
+	return 200, "搜索文档内容成功", apiwrap.ToPageVO(page.PageNo, page.PageSize, total, h.SearchResultToVOList(results))
+}
 
-	docs, err := h.serv.SearchDocumentContent(c, keyword)
+// SearchPublicDocumentContent 公开全文搜索文档内容
+func (h *DocumentContentHandler) SearchPublicDocumentContent(c *gin.Context, req SearchDocumentContentRequest) (int, string, any) {
+	page := req.ToPage()
+	results, total, err := h.serv.SearchPublicDocumentContent(c, page)
 	if err != nil {
 		return 500, err.Error(), nil
 	}
-	return 200, "搜索文档内容成功", h.DocumentContentDomainToVOList(docs)
+	return 200, "搜索文档内容成功", apiwrap.ToPageVO(page.PageNo, page.PageSize, total, h.SearchResultToVOList(results))
 }
 
 // FindPublicDocumentContentById 公开查询特定Id的文档内容
@@ -263,7 +275,9 @@ func (h *DocumentContentHandler) FindPublicDocumentContentById(c *gin.Context) (
 	if err != nil {
 		return 500, err.Error(), nil
 	}
-	return 200, fmt.Sprintf("查询文档内容成功, 文档Id:%s", documentId), h.DocumentContentDomainToVO(doc)
+	vo := h.DocumentContentDomainToVO(doc)
+	vo.SetRendered(h.render(c, doc.Content))
+	return 200, fmt.Sprintf("查询文档内容成功, 文档Id:%s", documentId), vo
 }
 
 // FindPublicDocumentContentByParentId 公开根据父级Id查询所有子文档内容
@@ -337,7 +351,9 @@ func (h *DocumentContentHandler) FindPublicDocumentContentByRootIdAndAlias(c *gi
 		return 400, "未找到指定别名的文档", nil
 	}
 
-	return 200, fmt.Sprintf("查询文档内容成功, 根文档ID:%s, 别名:%s", documentId, alias), h.DocumentContentDomainToVO(targetDoc)
+	vo := h.DocumentContentDomainToVO(targetDoc)
+	vo.SetRendered(h.render(c, targetDoc.Content))
+	return 200, fmt.Sprintf("查询文档内容成功, 根文档ID:%s, 别名:%s", documentId, alias), vo
 }
 
 // DocumentContentDomainToVO 将domain对象转换为VO
@@ -382,3 +398,11 @@ func (h *DocumentContentHandler) DeleteDocumentContentList(c *gin.Context) (int,
 	}
 	return 200, "批量删除成功", nil
 }
+
+// render 请求携带render=true时渲染Markdown正文, 否则返回nil
+func (h *DocumentContentHandler) render(c *gin.Context, content string) *markdown.Rendered {
+	if ok, _ := strconv.ParseBool(c.Query("render")); !ok {
+		return nil
+	}
+	return h.renderer.Render(content)
+}
@@ -1,5 +1,7 @@
 package web
 
+import "github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+
 // 创建文档内容请求
 type CreateDocumentContentRequest struct {
 	DocumentId  string `json:"document_id" binding:"required"`
@@ -24,3 +26,21 @@ type UpdateDocumentContentRequest struct {
 	IsDir       bool   `json:"is_dir"`
 	Sort        int    `json:"sort"`
 }
+
+// 搜索文档内容请求, 未传分页参数时默认第一页每页10条
+type SearchDocumentContentRequest struct {
+	Keyword  string `form:"keyword" binding:"required"`
+	PageNo   int64  `form:"page_no" binding:"omitempty,gte=1"`
+	PageSize int64  `form:"page_size" binding:"omitempty,gte=1,lte=50"`
+}
+
+func (r SearchDocumentContentRequest) ToPage() *apiwrap.Page {
+	page := &apiwrap.Page{PageNo: r.PageNo, PageSize: r.PageSize, Keyword: r.Keyword}
+	if page.PageNo == 0 {
+		page.PageNo = 1
+	}
+	if page.PageSize == 0 {
+		page.PageSize = 10
+	}
+	return page
+}
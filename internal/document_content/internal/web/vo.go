@@ -1,6 +1,11 @@
 package web
 
-import "time"
+import (
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/markdown"
+	"github.com/codepzj/Stellux-Server/internal/search"
+)
 
 type DocumentContentVO struct {
 	Id          string    `json:"id"`
@@ -14,4 +19,59 @@ type DocumentContentVO struct {
 	ParentId    string    `json:"parent_id"`
 	IsDir       bool      `json:"is_dir"`
 	Sort        int       `json:"sort"`
+
+	// 以下字段仅在请求携带render=true时返回
+	Html        string              `json:"html,omitempty"`
+	Toc         []*markdown.TocItem `json:"toc,omitempty"`
+	WordCount   int                 `json:"word_count,omitempty"`
+	ReadingTime int                 `json:"reading_time,omitempty"`
+}
+
+// SetRendered 填充渲染结果, rendered为nil时不做处理
+func (vo *DocumentContentVO) SetRendered(rendered *markdown.Rendered) {
+	if rendered == nil {
+		return
+	}
+	vo.Html = rendered.HTML
+	vo.Toc = rendered.Toc
+	vo.WordCount = rendered.WordCount
+	vo.ReadingTime = rendered.ReadingTime
+}
+
+// DocumentContentSearchVO 文档内容搜索结果, 标题和摘要中的命中词以mark标签高亮
+type DocumentContentSearchVO struct {
+	Id             string    `json:"id"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	DocumentId     string    `json:"document_id"`
+	DocumentTitle  string    `json:"document_title"`
+	DocumentAlias  string    `json:"document_alias"`
+	Title          string    `json:"title"`
+	TitleHighlight string    `json:"title_highlight"`
+	Description    string    `json:"description"`
+	Snippet        string    `json:"snippet"`
+	Alias          string    `json:"alias"`
+	Score          float64   `json:"score"`
+}
+
+// SearchResultToVOList 将搜索结果转换为VO列表
+func (h *DocumentContentHandler) SearchResultToVOList(results []*search.Result) []*DocumentContentSearchVO {
+	vos := make([]*DocumentContentSearchVO, len(results))
+	for i, result := range results {
+		vos[i] = &DocumentContentSearchVO{
+			Id:             result.Id.Hex(),
+			CreatedAt:      result.CreatedAt,
+			UpdatedAt:      result.UpdatedAt,
+			DocumentId:     result.DocumentId.Hex(),
+			DocumentTitle:  result.DocumentTitle,
+			DocumentAlias:  result.DocumentAlias,
+			Title:          result.Title,
+			TitleHighlight: result.TitleHighlight,
+			Description:    result.Description,
+			Snippet:        result.Snippet,
+			Alias:          result.Alias,
+			Score:          result.Score,
+		}
+	}
+	return vos
 }
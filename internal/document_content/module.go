@@ -1,6 +1,7 @@
 package document_content
 
 import (
+	"github.com/codepzj/Stellux-Server/internal/document_content/internal/domain"
 	"github.com/codepzj/Stellux-Server/internal/document_content/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/document_content/internal/web"
 )
@@ -8,6 +9,7 @@ import (
 type (
 	Handler = web.DocumentContentHandler
 	Service = service.IDocumentContentService
+	Domain  = domain.DocumentContent
 	Module  struct {
 		Svc Service
 		Hdl *Handler
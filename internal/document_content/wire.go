@@ -3,10 +3,14 @@
 package document_content
 
 import (
+	"github.com/codepzj/Stellux-Server/internal/audit"
 	"github.com/codepzj/Stellux-Server/internal/document_content/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/document_content/internal/repository/dao"
 	"github.com/codepzj/Stellux-Server/internal/document_content/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/document_content/internal/web"
+	"github.com/codepzj/Stellux-Server/internal/pkg/markdown"
+	"github.com/codepzj/Stellux-Server/internal/search"
+	"github.com/codepzj/Stellux-Server/internal/sitemap"
 	"github.com/google/wire"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
@@ -16,7 +20,7 @@ var DocumentContentProviders = wire.NewSet(web.NewDocumentContentHandler, servic
 	wire.Bind(new(repository.IDocumentContentRepository), new(*repository.DocumentContentRepository)),
 	wire.Bind(new(dao.IDocumentContentDao), new(*dao.DocumentContentDao)))
 
-func InitDocumentContentModule(mongoDB *mongo.Database) *Module {
+func InitDocumentContentModule(mongoDB *mongo.Database, searchServ search.Service, sitemapServ sitemap.Service, renderer *markdown.Renderer, auditServ audit.Service) *Module {
 	panic(wire.Build(
 		DocumentContentProviders,
 		wire.Struct(new(Module), "Svc", "Hdl"),
@@ -7,21 +7,25 @@
 package document_content
 
 import (
+	"github.com/codepzj/Stellux-Server/internal/audit"
 	"github.com/codepzj/Stellux-Server/internal/document_content/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/document_content/internal/repository/dao"
 	"github.com/codepzj/Stellux-Server/internal/document_content/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/document_content/internal/web"
+	"github.com/codepzj/Stellux-Server/internal/pkg/markdown"
+	"github.com/codepzj/Stellux-Server/internal/search"
+	"github.com/codepzj/Stellux-Server/internal/sitemap"
 	"github.com/google/wire"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 // Injectors from wire.go:
 
-func InitDocumentContentModule(mongoDB *mongo.Database) *Module {
+func InitDocumentContentModule(mongoDB *mongo.Database, searchServ search.Service, sitemapServ sitemap.Service, renderer *markdown.Renderer, auditServ audit.Service) *Module {
 	documentContentDao := dao.NewDocumentContentDao(mongoDB)
 	documentContentRepository := repository.NewDocumentContentRepository(documentContentDao)
-	documentContentService := service.NewDocumentContentService(documentContentRepository)
-	documentContentHandler := web.NewDocumentContentHandler(documentContentService)
+	documentContentService := service.NewDocumentContentService(documentContentRepository, searchServ, sitemapServ, auditServ)
+	documentContentHandler := web.NewDocumentContentHandler(documentContentService, renderer)
 	module := &Module{
 		Svc: documentContentService,
 		Hdl: documentContentHandler,
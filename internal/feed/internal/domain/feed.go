@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// Feed 订阅源, 与输出格式无关
+type Feed struct {
+	Title       string    // 标题
+	Description string    // 描述
+	Link        string    // 站点或页面地址
+	Author      string    // 作者
+	Language    string    // 语言
+	UpdatedAt   time.Time // 最近更新时间, 取条目中最晚的更新时间
+	Items       []*Item   // 条目, 按发布时间倒序
+}
+
+// Item 订阅条目
+type Item struct {
+	Id          string    // 全局唯一ID, 不随站点地址变化
+	Title       string    // 标题
+	Link        string    // 页面地址
+	Summary     string    // 摘要
+	Content     string    // 正文
+	Author      string    // 作者
+	Categories  []string  // 分类和标签名称
+	Image       string    // 缩略图
+	PublishedAt time.Time // 发布时间
+	UpdatedAt   time.Time // 更新时间
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/config"
+	"github.com/codepzj/Stellux-Server/internal/document"
+	"github.com/codepzj/Stellux-Server/internal/document_content"
+	"github.com/codepzj/Stellux-Server/internal/feed/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/label"
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/codepzj/Stellux-Server/internal/post"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const (
+	maxFeedItems = 20 // 每个订阅源最多输出的条目数
+	feedLanguage = "zh-CN"
+)
+
+var ErrFeedNotFound = errors.New("订阅源不存在")
+
+type IFeedService interface {
+	GetSiteFeed(ctx context.Context) (*domain.Feed, error)
+	GetCategoryFeed(ctx context.Context, name string) (*domain.Feed, error)
+	GetTagFeed(ctx context.Context, name string) (*domain.Feed, error)
+	GetDocumentFeed(ctx context.Context, alias string) (*domain.Feed, error)
+}
+
+var _ IFeedService = (*FeedService)(nil)
+
+func NewFeedService(postServ post.Service, labelServ label.Service, documentServ document.Service, documentContentServ document_content.Service, configServ config.Service) *FeedService {
+	return &FeedService{
+		postServ:            postServ,
+		labelServ:           labelServ,
+		documentServ:        documentServ,
+		documentContentServ: documentContentServ,
+		configServ:          configServ,
+	}
+}
+
+type FeedService struct {
+	postServ            post.Service
+	labelServ           label.Service
+	documentServ        document.Service
+	documentContentServ document_content.Service
+	configServ          config.Service
+}
+
+// GetSiteFeed 全站最新发布文章
+func (s *FeedService) GetSiteFeed(ctx context.Context) (*domain.Feed, error) {
+	feed := s.siteFeed(ctx)
+	if err := s.fillPostItems(ctx, feed, func(*post.Detail) bool { return true }); err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// GetCategoryFeed 分类下最新发布文章
+func (s *FeedService) GetCategoryFeed(ctx context.Context, name string) (*domain.Feed, error) {
+	if err := s.checkLabel(ctx, "category", name); err != nil {
+		return nil, err
+	}
+	feed := s.siteFeed(ctx)
+	feed.Title = feed.Title + " - " + name
+	err := s.fillPostItems(ctx, feed, func(p *post.Detail) bool {
+		return p.Category.Name == name
+	})
+	if err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// GetTagFeed 标签下最新发布文章
+func (s *FeedService) GetTagFeed(ctx context.Context, name string) (*domain.Feed, error) {
+	if err := s.checkLabel(ctx, "tag", name); err != nil {
+		return nil, err
+	}
+	feed := s.siteFeed(ctx)
+	feed.Title = feed.Title + " - " + name
+	err := s.fillPostItems(ctx, feed, func(p *post.Detail) bool {
+		return slices.ContainsFunc(p.Tags, func(tag label.Domain) bool {
+			return tag.Name == name
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// GetDocumentFeed 公开文档下最新创建的页面
+func (s *FeedService) GetDocumentFeed(ctx context.Context, alias string) (*domain.Feed, error) {
+	doc, err := s.documentServ.FindDocumentByAlias(ctx, alias)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrFeedNotFound
+		}
+		logger.Error("查询文档失败",
+			logger.WithError(err),
+			logger.WithString("alias", alias),
+		)
+		return nil, err
+	}
+	if !doc.IsPublic || doc.IsDeleted {
+		return nil, ErrFeedNotFound
+	}
+
+	contents, err := s.documentContentServ.GetPublicDocumentContentListByDocumentId(ctx, doc.Id)
+	if err != nil {
+		logger.Error("查询文档内容失败",
+			logger.WithError(err),
+			logger.WithString("documentId", doc.Id.Hex()),
+		)
+		return nil, err
+	}
+
+	feed := s.siteFeed(ctx)
+	feed.Title = feed.Title + " - " + doc.Title
+	if doc.Description != "" {
+		feed.Description = doc.Description
+	}
+	feed.Link = feed.Link + "/document/" + url.PathEscape(doc.Alias)
+
+	// 只输出页面, 目录节点不单独订阅
+	contents = slices.DeleteFunc(contents, func(content document_content.Domain) bool {
+		return content.IsDir
+	})
+	slices.SortFunc(contents, func(a, b document_content.Domain) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	for _, content := range contents[:min(maxFeedItems, len(contents))] {
+		feed.Items = append(feed.Items, &domain.Item{
+			Id:          "urn:stellux:document:" + content.Id.Hex(),
+			Title:       content.Title,
+			Link:        feed.Link + "/" + url.PathEscape(contentAlias(content)),
+			Summary:     content.Description,
+			Content:     content.Content,
+			Author:      feed.Author,
+			Categories:  []string{doc.Title},
+			PublishedAt: content.CreatedAt,
+			UpdatedAt:   content.UpdatedAt,
+		})
+		feed.UpdatedAt = latest(feed.UpdatedAt, content.UpdatedAt)
+	}
+	return feed, nil
+}
+
+// siteFeed 从seo配置读取站点信息, 配置缺失时使用空值, 不影响订阅输出
+func (s *FeedService) siteFeed(ctx context.Context) *domain.Feed {
+	feed := &domain.Feed{Language: feedLanguage}
+	cfg, err := s.configServ.GetConfigByType(ctx, "seo")
+	if err != nil {
+		logger.Warn("获取seo配置失败, 订阅源将不包含站点信息",
+			logger.WithError(err),
+		)
+		return feed
+	}
+	feed.Title = firstNonEmpty(cfg.Content.SEOTitle, cfg.Content.Title)
+	feed.Description = firstNonEmpty(cfg.Content.SEODescription, cfg.Content.Description)
+	feed.Author = cfg.Content.SEOAuthor
+	feed.Link = strings.TrimRight(cfg.Content.CanonicalURL, "/")
+	return feed
+}
+
+func (s *FeedService) fillPostItems(ctx context.Context, feed *domain.Feed, match func(*post.Detail) bool) error {
+	posts, err := s.postServ.GetAllPublishPost(ctx)
+	if err != nil {
+		return err
+	}
+	posts = slices.DeleteFunc(posts, func(p *post.Detail) bool {
+		return !match(p)
+	})
+	// 订阅源按发布时间排序, 不受置顶影响
+	slices.SortFunc(posts, func(a, b *post.Detail) int {
+		return postPublishedAt(b).Compare(postPublishedAt(a))
+	})
+	for _, p := range posts[:min(maxFeedItems, len(posts))] {
+		categories := []string{}
+		if p.Category.Name != "" {
+			categories = append(categories, p.Category.Name)
+		}
+		for _, tag := range p.Tags {
+			categories = append(categories, tag.Name)
+		}
+		feed.Items = append(feed.Items, &domain.Item{
+			Id:          "urn:stellux:post:" + p.Id.Hex(),
+			Title:       p.Title,
+			Link:        feed.Link + "/post/" + url.PathEscape(postAlias(p)),
+			Summary:     p.Description,
+			Content:     p.Content,
+			Author:      firstNonEmpty(p.Author, feed.Author),
+			Categories:  categories,
+			Image:       p.Thumbnail,
+			PublishedAt: postPublishedAt(p),
+			UpdatedAt:   p.UpdatedAt,
+		})
+		feed.UpdatedAt = latest(feed.UpdatedAt, p.UpdatedAt)
+	}
+	return nil
+}
+
+// checkLabel 标签或分类不存在时返回ErrFeedNotFound
+func (s *FeedService) checkLabel(ctx context.Context, labelType string, name string) error {
+	labels, err := s.labelServ.GetAllLabelsByType(ctx, labelType)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(labels, func(l *label.Domain) bool { return l.Name == name }) {
+		return ErrFeedNotFound
+	}
+	return nil
+}
+
+func postAlias(p *post.Detail) string {
+	if p.Alias != "" {
+		return p.Alias
+	}
+	return p.Id.Hex()
+}
+
+// postPublishedAt 文章首次发布的时间, 记录发布时间之前的旧文章以创建时间代替
+func postPublishedAt(p *post.Detail) time.Time {
+	if !p.PublishedAt.IsZero() {
+		return p.PublishedAt
+	}
+	return p.CreatedAt
+}
+
+func contentAlias(content document_content.Domain) string {
+	if content.Alias != "" {
+		return content.Alias
+	}
+	return content.Id.Hex()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
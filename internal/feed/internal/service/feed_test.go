@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/feed/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/post"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// fakePostService 只实现订阅源用到的GetAllPublishPost
+type fakePostService struct {
+	post.Service
+	posts []*post.Detail
+}
+
+func (f *fakePostService) GetAllPublishPost(context.Context) ([]*post.Detail, error) {
+	return f.posts, nil
+}
+
+func TestFillPostItemsPublishedAt(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// 草稿创建于1月1日, 定时于1月10日发布, 发布后publish_at已被清除
+	scheduled := &post.Detail{Id: bson.NewObjectID(), Title: "scheduled", CreatedAt: base, PublishedAt: base.AddDate(0, 0, 9)}
+	// 1月5日创建并立即发布
+	immediate := &post.Detail{Id: bson.NewObjectID(), Title: "immediate", CreatedAt: base.AddDate(0, 0, 4), PublishedAt: base.AddDate(0, 0, 4)}
+	// 记录发布时间之前的旧文章, 以创建时间代替
+	legacy := &post.Detail{Id: bson.NewObjectID(), Title: "legacy", CreatedAt: base.AddDate(0, 0, 6)}
+
+	s := &FeedService{postServ: &fakePostService{posts: []*post.Detail{immediate, legacy, scheduled}}}
+	feed := &domain.Feed{}
+	if err := s.fillPostItems(context.Background(), feed, func(*post.Detail) bool { return true }); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		title string
+		want  time.Time
+	}{
+		{title: "scheduled", want: base.AddDate(0, 0, 9)},
+		{title: "legacy", want: base.AddDate(0, 0, 6)},
+		{title: "immediate", want: base.AddDate(0, 0, 4)},
+	}
+	if len(feed.Items) != len(tests) {
+		t.Fatalf("len(Items) = %d, want %d", len(feed.Items), len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			item := feed.Items[i]
+			if item.Title != tt.title {
+				t.Errorf("Items[%d].Title = %s, want %s", i, item.Title, tt.title)
+			}
+			if !item.PublishedAt.Equal(tt.want) {
+				t.Errorf("Items[%d].PublishedAt = %v, want %v", i, item.PublishedAt, tt.want)
+			}
+		})
+	}
+}
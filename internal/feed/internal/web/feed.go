@@ -0,0 +1,107 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/codepzj/Stellux-Server/internal/feed/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/feed/internal/service"
+	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+func NewFeedHandler(serv service.IFeedService) *FeedHandler {
+	return &FeedHandler{
+		serv: serv,
+	}
+}
+
+type FeedHandler struct {
+	serv service.IFeedService
+}
+
+// feedFormat 订阅输出格式
+type feedFormat struct {
+	file        string
+	contentType string
+	render      func(feed *domain.Feed, selfURL string) ([]byte, error)
+}
+
+var feedFormats = []feedFormat{
+	{file: "rss.xml", contentType: "application/rss+xml; charset=utf-8", render: RenderRSS},
+	{file: "atom.xml", contentType: "application/atom+xml; charset=utf-8", render: RenderAtom},
+	{file: "feed.json", contentType: "application/feed+json; charset=utf-8", render: RenderJSONFeed},
+}
+
+func (h *FeedHandler) RegisterGinRoutes(engine *gin.Engine) {
+	rssFormat, atomFormat, jsonFormat := feedFormats[0], feedFormats[1], feedFormats[2]
+	engine.GET("/feed.json", h.serve(h.SiteFeed, jsonFormat)) // 全站JSON Feed
+	feedGroup := engine.Group("/feed")
+	{
+		feedGroup.GET("/rss.xml", h.serve(h.SiteFeed, rssFormat))   // 全站RSS
+		feedGroup.GET("/atom.xml", h.serve(h.SiteFeed, atomFormat)) // 全站Atom
+		for _, format := range feedFormats {
+			feedGroup.GET("/category/:name/"+format.file, h.serve(h.CategoryFeed, format))  // 分类订阅
+			feedGroup.GET("/tag/:name/"+format.file, h.serve(h.TagFeed, format))            // 标签订阅
+			feedGroup.GET("/document/:alias/"+format.file, h.serve(h.DocumentFeed, format)) // 文档新页面订阅
+		}
+	}
+}
+
+// SiteFeed 全站最新文章
+func (h *FeedHandler) SiteFeed(c *gin.Context) (*domain.Feed, error) {
+	return h.serv.GetSiteFeed(c)
+}
+
+// CategoryFeed 分类下最新文章
+func (h *FeedHandler) CategoryFeed(c *gin.Context) (*domain.Feed, error) {
+	return h.serv.GetCategoryFeed(c, c.Param("name"))
+}
+
+// TagFeed 标签下最新文章
+func (h *FeedHandler) TagFeed(c *gin.Context) (*domain.Feed, error) {
+	return h.serv.GetTagFeed(c, c.Param("name"))
+}
+
+// DocumentFeed 文档下最新页面
+func (h *FeedHandler) DocumentFeed(c *gin.Context) (*domain.Feed, error) {
+	return h.serv.GetDocumentFeed(c, c.Param("alias"))
+}
+
+// serve 订阅阅读器不解析统一响应结构, 直接输出对应格式并使用HTTP状态码
+func (h *FeedHandler) serve(load func(c *gin.Context) (*domain.Feed, error), format feedFormat) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		feed, err := load(c)
+		if err != nil {
+			if errors.Is(err, service.ErrFeedNotFound) {
+				c.String(http.StatusNotFound, err.Error())
+				return
+			}
+			c.String(http.StatusInternalServerError, "获取订阅源失败")
+			return
+		}
+		body, err := format.render(feed, requestURL(c))
+		if err != nil {
+			logger.Error("生成订阅源失败",
+				logger.WithError(err),
+				logger.WithString("path", c.Request.URL.Path),
+			)
+			c.String(http.StatusInternalServerError, "生成订阅源失败")
+			return
+		}
+		apiwrap.Conditional(c, format.contentType, body, feed.UpdatedAt)
+	}
+}
+
+// requestURL 当前请求的完整地址, 用作订阅源的self链接
+func requestURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host + c.Request.URL.Path
+}
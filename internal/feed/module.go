@@ -0,0 +1,15 @@
+package feed
+
+import (
+	"github.com/codepzj/Stellux-Server/internal/feed/internal/service"
+	"github.com/codepzj/Stellux-Server/internal/feed/internal/web"
+)
+
+type (
+	Handler = web.FeedHandler
+	Service = service.IFeedService
+	Module  struct {
+		Svc Service
+		Hdl *Handler
+	}
+)
@@ -0,0 +1,24 @@
+//go:build wireinject
+
+package feed
+
+import (
+	"github.com/codepzj/Stellux-Server/internal/config"
+	"github.com/codepzj/Stellux-Server/internal/document"
+	"github.com/codepzj/Stellux-Server/internal/document_content"
+	"github.com/codepzj/Stellux-Server/internal/feed/internal/service"
+	"github.com/codepzj/Stellux-Server/internal/feed/internal/web"
+	"github.com/codepzj/Stellux-Server/internal/label"
+	"github.com/codepzj/Stellux-Server/internal/post"
+	"github.com/google/wire"
+)
+
+var FeedProviders = wire.NewSet(web.NewFeedHandler, service.NewFeedService,
+	wire.Bind(new(service.IFeedService), new(*service.FeedService)))
+
+func InitFeedModule(postServ post.Service, labelServ label.Service, documentServ document.Service, documentContentServ document_content.Service, configServ config.Service) *Module {
+	panic(wire.Build(
+		FeedProviders,
+		wire.Struct(new(Module), "Svc", "Hdl"),
+	))
+}
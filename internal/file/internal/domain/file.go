@@ -1,10 +1,137 @@
 package domain
 
-import "go.mongodb.org/mongo-driver/v2/bson"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
 
 type File struct {
 	ID       bson.ObjectID
 	FileName string
 	Url      string
-	Dst      string
+	Key      string // 文件在存储中的key
+	Width    int    // 图片宽度, 非图片为0
+	Height   int    // 图片高度, 非图片为0
+	Variants []*Variant
+	Size     int64  // 文件大小(字节)
+	MimeType string // 按文件内容识别的MIME类型
+	Sha256   string // 文件内容的SHA-256, 旧版本的文件为空
+
+	Folder      string          // 所在的虚拟目录, eg: /posts/2024, 根目录为/
+	Alt         string          // 图片替代文本
+	Caption     string          // 图片说明
+	Tags        []string        // 文件标签
+	AlbumIdList []bson.ObjectID // 所属相册, 一个文件可以属于多个相册
+	CreatedAt   time.Time
+
+	UnreferencedAt *time.Time // 垃圾回收时首次发现未被引用的时间, 被引用时为空
+}
+
+// RootFolder 根目录, 旧版本的文件没有目录, 视为在根目录下
+const RootFolder = "/"
+
+// Query 文件查询条件, 零值表示不过滤
+type Query struct {
+	Keyword   string   // 匹配文件名、替代文本和说明, 不区分大小写
+	Tags      []string // 包含全部标签
+	MimeType  string   // 精确匹配, 以/*结尾时按前缀匹配, eg: image/*
+	Folder    string   // 只查询该目录下的文件, 不包括子目录
+	AlbumId   bson.ObjectID
+	StartTime time.Time // 上传时间范围
+	EndTime   time.Time
+}
+
+// FileMeta 可编辑的文件描述信息
+type FileMeta struct {
+	Alt     string
+	Caption string
+	Tags    []string
+}
+
+// FolderCount 目录及其下的文件数
+type FolderCount struct {
+	Folder string
+	Count  int
+}
+
+// TagCount 标签及使用该标签的文件数
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+// Album 相册, 用于把不同目录下的文件组织在一起
+type Album struct {
+	ID          bson.ObjectID
+	Name        string
+	Description string
+	FileCount   int // 相册中的文件数, 仅列表返回
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Variant 上传时生成的缩略图
+type Variant struct {
+	Width  int
+	Height int
+	Format string
+	Key    string
+	Url    string
+}
+
+// MigrateResult 文件在存储之间迁移的结果
+type MigrateResult struct {
+	Total    int // 文件记录总数
+	Migrated int // 成功迁移的文件数
+	Missing  int // 源存储和目标存储中都找不到的文件数
+	Failed   int // 迁移失败的文件数
+}
+
+// 引用文件的内容类型
+const (
+	SourcePost            = "post"
+	SourcePostRevision    = "post_revision"
+	SourceDocument        = "document"
+	SourceDocumentContent = "document_content"
+	SourceFriend          = "friend"
+	SourceConfig          = "config"
+	SourceUser            = "user"
+	SourceComment         = "comment"
+)
+
+// ContentField 可能包含文件地址的内容字段
+type ContentField struct {
+	SourceType string // 内容类型
+	SourceId   string // 内容Id, 历史版本为文章Id, 网站配置为配置类型
+	Title      string // 内容标题
+	Field      string // 字段名
+	Text       string // 字段内容
+}
+
+// Reference 文件被引用的位置
+type Reference struct {
+	SourceType string
+	SourceId   string
+	Title      string
+	Field      string
+}
+
+// GCResult 一次垃圾回收的结果
+type GCResult struct {
+	Total        int // 文件记录总数
+	Referenced   int // 被引用的文件数
+	Unreferenced int // 未被引用的文件数, 包括本次删除的
+	Deleted      int // 超过宽限期被删除的文件数
+	Failed       int // 删除失败的文件数
+}
+
+// Upload 断点续传会话
+type Upload struct {
+	ID        string
+	FileName  string
+	Length    int64 // 文件总大小
+	Offset    int64 // 已接收的大小
+	CreatedAt time.Time
+	ExpiresAt time.Time // 超过该时间未继续上传的会话会被清理
 }
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/codepzj/Stellux-Server/internal/file/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/file/internal/repository/dao"
+	"github.com/samber/lo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (r *FileRepository) CreateAlbum(ctx context.Context, album *domain.Album) error {
+	albumDO := &dao.Album{Name: album.Name, Description: album.Description}
+	if err := r.albumDao.Create(ctx, albumDO); err != nil {
+		return err
+	}
+	album.ID, album.CreatedAt, album.UpdatedAt = albumDO.ID, albumDO.CreatedAt, albumDO.UpdatedAt
+	return nil
+}
+
+func (r *FileRepository) UpdateAlbum(ctx context.Context, album *domain.Album) error {
+	return r.albumDao.Update(ctx, album.ID, album.Name, album.Description)
+}
+
+// DeleteAlbum 删除相册并把文件移出相册, 文件本身不删除
+func (r *FileRepository) DeleteAlbum(ctx context.Context, id bson.ObjectID) error {
+	if err := r.albumDao.Delete(ctx, id); err != nil {
+		return err
+	}
+	_, err := r.dao.RemoveFromAlbum(ctx, nil, id)
+	return err
+}
+
+func (r *FileRepository) GetAlbum(ctx context.Context, id bson.ObjectID) (*domain.Album, error) {
+	album, err := r.albumDao.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.AlbumDaoToDomain(album), nil
+}
+
+// GetAlbumList 获取全部相册及其中的文件数
+func (r *FileRepository) GetAlbumList(ctx context.Context) ([]*domain.Album, error) {
+	albums, err := r.albumDao.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	albumCounts, err := r.dao.GetAlbumCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts := lo.SliceToMap(albumCounts, func(albumCount *dao.AlbumCount) (bson.ObjectID, int) {
+		return albumCount.AlbumId, albumCount.Count
+	})
+	return lo.Map(albums, func(album *dao.Album, _ int) *domain.Album {
+		albumDomain := r.AlbumDaoToDomain(album)
+		albumDomain.FileCount = counts[album.ID]
+		return albumDomain
+	}), nil
+}
+
+func (r *FileRepository) AddToAlbum(ctx context.Context, idList []bson.ObjectID, albumId bson.ObjectID) (int64, error) {
+	return r.dao.AddToAlbum(ctx, idList, albumId)
+}
+
+func (r *FileRepository) RemoveFromAlbum(ctx context.Context, idList []bson.ObjectID, albumId bson.ObjectID) (int64, error) {
+	return r.dao.RemoveFromAlbum(ctx, idList, albumId)
+}
+
+func (r *FileRepository) AlbumDaoToDomain(album *dao.Album) *domain.Album {
+	return &domain.Album{
+		ID:          album.ID,
+		Name:        album.Name,
+		Description: album.Description,
+		CreatedAt:   album.CreatedAt,
+		UpdatedAt:   album.UpdatedAt,
+	}
+}
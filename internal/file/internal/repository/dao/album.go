@@ -0,0 +1,116 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type Album struct {
+	ID          bson.ObjectID `bson:"_id,omitempty"`
+	CreatedAt   time.Time     `bson:"created_at"`
+	UpdatedAt   time.Time     `bson:"updated_at"`
+	Name        string        `bson:"name"`
+	Description string        `bson:"description,omitempty"`
+}
+
+type IAlbumDao interface {
+	Create(ctx context.Context, album *Album) error
+	Update(ctx context.Context, id bson.ObjectID, name string, description string) error
+	Delete(ctx context.Context, id bson.ObjectID) error
+	Get(ctx context.Context, id bson.ObjectID) (*Album, error)
+	GetAll(ctx context.Context) ([]*Album, error)
+}
+
+var _ IAlbumDao = (*AlbumDao)(nil)
+
+func NewAlbumDao(db *mongo.Database) *AlbumDao {
+	d := &AlbumDao{coll: db.Collection("file_album")}
+	d.ensureIndexes()
+	return d
+}
+
+type AlbumDao struct {
+	coll *mongo.Collection
+}
+
+// ensureIndexes 相册名称唯一
+func (d *AlbumDao) ensureIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := d.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetName("name").SetUnique(true),
+	})
+	if err != nil {
+		logger.Warn("创建相册索引失败",
+			logger.WithError(err),
+		)
+	}
+}
+
+func (d *AlbumDao) Create(ctx context.Context, album *Album) error {
+	album.ID = bson.NewObjectID()
+	album.CreatedAt = time.Now()
+	album.UpdatedAt = time.Now()
+	_, err := d.coll.InsertOne(ctx, album)
+	return err
+}
+
+func (d *AlbumDao) Update(ctx context.Context, id bson.ObjectID, name string, description string) error {
+	update := bson.M{
+		"$set": bson.M{
+			"name":        name,
+			"description": description,
+			"updated_at":  time.Now(),
+		},
+	}
+	updateResult, err := d.coll.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if updateResult.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (d *AlbumDao) Delete(ctx context.Context, id bson.ObjectID) error {
+	deleteResult, err := d.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if deleteResult.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (d *AlbumDao) Get(ctx context.Context, id bson.ObjectID) (*Album, error) {
+	var album Album
+	err := d.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&album)
+	if err != nil {
+		return nil, err
+	}
+	return &album, nil
+}
+
+// GetAll 获取全部相册, 按名称排序
+func (d *AlbumDao) GetAll(ctx context.Context) ([]*Album, error) {
+	cursor, err := d.coll.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var albums []*Album
+	if err = cursor.All(ctx, &albums); err != nil {
+		return nil, err
+	}
+	return albums, nil
+}
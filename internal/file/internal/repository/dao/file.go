@@ -5,6 +5,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -17,14 +18,67 @@ type File struct {
 	DeletedAt *time.Time    `bson:"deleted_at,omitempty"`
 	FileName  string        `bson:"file_name"`
 	Url       string        `bson:"url"`
-	Dst       string        `bson:"dst"`
+	Key       string        `bson:"key,omitempty"`
+	Dst       string        `bson:"dst,omitempty"` // 旧版本保存的本地路径, 没有key时由此推断
+	Width     int           `bson:"width,omitempty"`
+	Height    int           `bson:"height,omitempty"`
+	Variants  []*Variant    `bson:"variants,omitempty"`
+	Size      int64         `bson:"size,omitempty"`
+	MimeType  string        `bson:"mime_type,omitempty"`
+	Sha256    string        `bson:"sha256,omitempty"` // 文件内容的SHA-256, 用于去重
+
+	Folder      string          `bson:"folder,omitempty"` // 虚拟目录, 为空时在根目录下
+	Alt         string          `bson:"alt,omitempty"`
+	Caption     string          `bson:"caption,omitempty"`
+	Tags        []string        `bson:"tags,omitempty"`
+	AlbumIdList []bson.ObjectID `bson:"album_ids,omitempty"`
+
+	UnreferencedAt *time.Time `bson:"unreferenced_at,omitempty"` // 垃圾回收时首次发现未被引用的时间
+}
+
+type Variant struct {
+	Width  int    `bson:"width"`
+	Height int    `bson:"height"`
+	Format string `bson:"format"`
+	Key    string `bson:"key"`
+	Url    string `bson:"url"`
+}
+
+// FolderCount 目录下的文件数
+type FolderCount struct {
+	Folder string `bson:"_id"`
+	Count  int    `bson:"count"`
+}
+
+// TagCount 使用标签的文件数
+type TagCount struct {
+	Tag   string `bson:"_id"`
+	Count int    `bson:"count"`
+}
+
+// AlbumCount 相册中的文件数
+type AlbumCount struct {
+	AlbumId bson.ObjectID `bson:"_id"`
+	Count   int           `bson:"count"`
 }
 
 type IFileDao interface {
 	Create(ctx context.Context, file *File) error
 	Get(ctx context.Context, id bson.ObjectID) (*File, error)
-	GetList(ctx context.Context, skip int64, limit int64) ([]*File, int64, error)
+	GetBySha256(ctx context.Context, sha256 string) (*File, error)
+	GetList(ctx context.Context, filter bson.D, skip int64, limit int64) ([]*File, int64, error)
 	GetListByIDList(ctx context.Context, idList []bson.ObjectID) ([]*File, error)
+	GetUnreferencedList(ctx context.Context, skip int64, limit int64) ([]*File, int64, error)
+	UpdateLocation(ctx context.Context, id bson.ObjectID, key string, url string, variants []*Variant) error
+	SetUnreferenced(ctx context.Context, idList []bson.ObjectID, unreferencedAt *time.Time) error
+	UpdateMeta(ctx context.Context, id bson.ObjectID, alt string, caption string, tags []string) error
+	Rename(ctx context.Context, id bson.ObjectID, fileName string) error
+	Move(ctx context.Context, idList []bson.ObjectID, folder string) (int64, error)
+	AddToAlbum(ctx context.Context, idList []bson.ObjectID, albumId bson.ObjectID) (int64, error)
+	RemoveFromAlbum(ctx context.Context, idList []bson.ObjectID, albumId bson.ObjectID) (int64, error)
+	GetFolderCounts(ctx context.Context) ([]*FolderCount, error)
+	GetTagCounts(ctx context.Context) ([]*TagCount, error)
+	GetAlbumCounts(ctx context.Context) ([]*AlbumCount, error)
 
 	Delete(ctx context.Context, id bson.ObjectID) error
 	DeleteMany(ctx context.Context, idList []bson.ObjectID) error
@@ -33,13 +87,39 @@ type IFileDao interface {
 var _ IFileDao = (*FileDao)(nil)
 
 func NewFileDao(db *mongo.Database) *FileDao {
-	return &FileDao{coll: db.Collection("file")}
+	d := &FileDao{coll: db.Collection("file")}
+	d.ensureIndexes()
+	return d
 }
 
 type FileDao struct {
 	coll *mongo.Collection
 }
 
+// ensureIndexes 按内容哈希去重, 旧版本的文件没有哈希, 不参与唯一约束
+// 媒体库按目录、标签和相册筛选, 按上传时间倒序
+func (d *FileDao) ensureIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := d.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "sha256", Value: 1}},
+			Options: options.Index().SetName("sha256").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"sha256": bson.M{"$type": "string"}}),
+		},
+		{Keys: bson.D{{Key: "folder", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("folder_created_at")},
+		{Keys: bson.D{{Key: "tags", Value: 1}}, Options: options.Index().SetName("tags")},
+		{Keys: bson.D{{Key: "album_ids", Value: 1}}, Options: options.Index().SetName("album_ids")},
+		{Keys: bson.D{{Key: "created_at", Value: -1}}, Options: options.Index().SetName("created_at")},
+	})
+	if err != nil {
+		logger.Warn("创建文件索引失败",
+			logger.WithError(err),
+		)
+	}
+}
+
 func (d *FileDao) Create(ctx context.Context, file *File) error {
 	file.ID = bson.NewObjectID()
 	file.CreatedAt = time.Now()
@@ -63,13 +143,24 @@ func (d *FileDao) Get(ctx context.Context, id bson.ObjectID) (*File, error) {
 	return &file, nil
 }
 
-func (d *FileDao) GetList(ctx context.Context, skip int64, limit int64) ([]*File, int64, error) {
-	count, err := d.coll.CountDocuments(ctx, bson.M{})
+// GetBySha256 根据内容哈希获取文件
+func (d *FileDao) GetBySha256(ctx context.Context, sha256 string) (*File, error) {
+	var file File
+	err := d.coll.FindOne(ctx, bson.M{"sha256": sha256}).Decode(&file)
+	if err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// GetList 按条件分页查询文件, 最新上传的在前
+func (d *FileDao) GetList(ctx context.Context, filter bson.D, skip int64, limit int64) ([]*File, int64, error) {
+	count, err := d.coll.CountDocuments(ctx, filter)
 	if err != nil {
 		return nil, 0, err
 	}
-	opts := options.Find().SetSkip(skip).SetLimit(limit).SetSort(bson.M{"created_at": -1})
-	cursor, err := d.coll.Find(ctx, bson.M{}, opts)
+	opts := options.Find().SetSkip(skip).SetLimit(limit).SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := d.coll.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -96,6 +187,193 @@ func (d *FileDao) GetListByIDList(ctx context.Context, idList []bson.ObjectID) (
 	return files, nil
 }
 
+// GetUnreferencedList 分页查询未被引用的文件, 最早发现的在前
+func (d *FileDao) GetUnreferencedList(ctx context.Context, skip int64, limit int64) ([]*File, int64, error) {
+	filter := bson.M{"unreferenced_at": bson.M{"$ne": nil}}
+	count, err := d.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	opts := options.Find().SetSkip(skip).SetLimit(limit).SetSort(bson.D{{Key: "unreferenced_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := d.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var files []*File
+	if err = cursor.All(ctx, &files); err != nil {
+		return nil, 0, err
+	}
+	return files, count, nil
+}
+
+// SetUnreferenced 标记文件未被引用的时间, unreferencedAt为nil时清除标记
+func (d *FileDao) SetUnreferenced(ctx context.Context, idList []bson.ObjectID, unreferencedAt *time.Time) error {
+	update := bson.M{"$unset": bson.M{"unreferenced_at": ""}}
+	if unreferencedAt != nil {
+		update = bson.M{"$set": bson.M{"unreferenced_at": unreferencedAt}}
+	}
+	_, err := d.coll.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": idList}}, update)
+	return err
+}
+
+// UpdateLocation 更新文件及缩略图在存储中的key和访问地址
+func (d *FileDao) UpdateLocation(ctx context.Context, id bson.ObjectID, key string, url string, variants []*Variant) error {
+	update := bson.M{
+		"$set": bson.M{
+			"key":        key,
+			"url":        url,
+			"variants":   variants,
+			"updated_at": time.Now(),
+		},
+	}
+	updateResult, err := d.coll.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if updateResult.MatchedCount == 0 {
+		return errors.New("更新文件失败")
+	}
+	return nil
+}
+
+// UpdateMeta 更新替代文本、说明和标签
+func (d *FileDao) UpdateMeta(ctx context.Context, id bson.ObjectID, alt string, caption string, tags []string) error {
+	if tags == nil {
+		tags = []string{}
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"alt":        alt,
+			"caption":    caption,
+			"tags":       tags,
+			"updated_at": time.Now(),
+		},
+	}
+	updateResult, err := d.coll.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if updateResult.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// Rename 修改文件名, 只修改展示的名称, 存储中的key和访问地址不变
+func (d *FileDao) Rename(ctx context.Context, id bson.ObjectID, fileName string) error {
+	update := bson.M{
+		"$set": bson.M{
+			"file_name":  fileName,
+			"updated_at": time.Now(),
+		},
+	}
+	updateResult, err := d.coll.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if updateResult.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// Move 批量移动文件到目录, 返回匹配到的文件数
+func (d *FileDao) Move(ctx context.Context, idList []bson.ObjectID, folder string) (int64, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"folder":     folder,
+			"updated_at": time.Now(),
+		},
+	}
+	updateResult, err := d.coll.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": idList}}, update)
+	if err != nil {
+		return 0, err
+	}
+	return updateResult.MatchedCount, nil
+}
+
+// AddToAlbum 批量把文件加入相册, 已在相册中的文件不重复添加, 返回匹配到的文件数
+func (d *FileDao) AddToAlbum(ctx context.Context, idList []bson.ObjectID, albumId bson.ObjectID) (int64, error) {
+	updateResult, err := d.coll.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": idList}}, bson.M{"$addToSet": bson.M{"album_ids": albumId}})
+	if err != nil {
+		return 0, err
+	}
+	return updateResult.MatchedCount, nil
+}
+
+// RemoveFromAlbum 批量把文件移出相册, idList为空时移出相册中的全部文件
+func (d *FileDao) RemoveFromAlbum(ctx context.Context, idList []bson.ObjectID, albumId bson.ObjectID) (int64, error) {
+	filter := bson.M{"album_ids": albumId}
+	if len(idList) > 0 {
+		filter["_id"] = bson.M{"$in": idList}
+	}
+	updateResult, err := d.coll.UpdateMany(ctx, filter, bson.M{"$pull": bson.M{"album_ids": albumId}})
+	if err != nil {
+		return 0, err
+	}
+	return updateResult.ModifiedCount, nil
+}
+
+// GetFolderCounts 获取所有目录及其下的文件数, 没有目录的旧文件计入根目录
+func (d *FileDao) GetFolderCounts(ctx context.Context) ([]*FolderCount, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$folder", "/"}}}},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+	var folderCounts []*FolderCount
+	if err := d.aggregate(ctx, pipeline, &folderCounts); err != nil {
+		return nil, err
+	}
+	return folderCounts, nil
+}
+
+// GetTagCounts 获取所有标签及使用该标签的文件数, 使用多的在前
+func (d *FileDao) GetTagCounts(ctx context.Context) ([]*TagCount, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$tags"},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+	var tagCounts []*TagCount
+	if err := d.aggregate(ctx, pipeline, &tagCounts); err != nil {
+		return nil, err
+	}
+	return tagCounts, nil
+}
+
+// GetAlbumCounts 获取每个相册中的文件数, 空相册不返回
+func (d *FileDao) GetAlbumCounts(ctx context.Context) ([]*AlbumCount, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$unwind", Value: "$album_ids"}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$album_ids"},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+	}
+	var albumCounts []*AlbumCount
+	if err := d.aggregate(ctx, pipeline, &albumCounts); err != nil {
+		return nil, err
+	}
+	return albumCounts, nil
+}
+
+func (d *FileDao) aggregate(ctx context.Context, pipeline mongo.Pipeline, results any) error {
+	cursor, err := d.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	return cursor.All(ctx, results)
+}
+
 func (d *FileDao) Delete(ctx context.Context, id bson.ObjectID) error {
 	deleteResult, err := d.coll.DeleteOne(ctx, bson.M{"_id": id})
 	if err != nil {
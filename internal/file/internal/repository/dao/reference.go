@@ -0,0 +1,170 @@
+package dao
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type PostRef struct {
+	ID        bson.ObjectID `bson:"_id"`
+	Title     string        `bson:"title"`
+	Content   string        `bson:"content"`
+	Thumbnail string        `bson:"thumbnail"`
+}
+
+type PostRevisionRef struct {
+	PostID    bson.ObjectID `bson:"post_id"`
+	Title     string        `bson:"title"`
+	Content   string        `bson:"content"`
+	Thumbnail string        `bson:"thumbnail"`
+}
+
+type DocumentRef struct {
+	ID        bson.ObjectID `bson:"_id"`
+	Title     string        `bson:"title"`
+	Thumbnail string        `bson:"thumbnail"`
+}
+
+type DocumentContentRef struct {
+	ID      bson.ObjectID `bson:"_id"`
+	Title   string        `bson:"title"`
+	Content string        `bson:"content"`
+}
+
+type FriendRef struct {
+	ID        bson.ObjectID `bson:"_id"`
+	Name      string        `bson:"name"`
+	AvatarUrl string        `bson:"avatar_url"`
+}
+
+type UserRef struct {
+	ID       bson.ObjectID `bson:"_id"`
+	Nickname string        `bson:"nickname"`
+	Avatar   string        `bson:"avatar"`
+}
+
+type CommentRef struct {
+	ID       bson.ObjectID `bson:"_id"`
+	Nickname string        `bson:"nickname"`
+	Content  string        `bson:"content"`
+	Avatar   string        `bson:"avatar"`
+}
+
+type ConfigRef struct {
+	Type    string `bson:"type"`
+	Content struct {
+		Avatar  string `bson:"avatar"`
+		OGImage string `bson:"og_image"`
+	} `bson:"content"`
+}
+
+// IReferenceDao 查询其他模块中可能引用文件的内容, 包括已软删除的内容, 恢复后仍需使用文件
+type IReferenceDao interface {
+	FindPosts(ctx context.Context, pattern string) ([]*PostRef, error)
+	FindPostRevisions(ctx context.Context, pattern string) ([]*PostRevisionRef, error)
+	FindDocuments(ctx context.Context, pattern string) ([]*DocumentRef, error)
+	FindDocumentContents(ctx context.Context, pattern string) ([]*DocumentContentRef, error)
+	FindFriends(ctx context.Context, pattern string) ([]*FriendRef, error)
+	FindConfigs(ctx context.Context, pattern string) ([]*ConfigRef, error)
+	FindUsers(ctx context.Context, pattern string) ([]*UserRef, error)
+	FindComments(ctx context.Context, pattern string) ([]*CommentRef, error)
+}
+
+var _ IReferenceDao = (*ReferenceDao)(nil)
+
+func NewReferenceDao(db *mongo.Database) *ReferenceDao {
+	return &ReferenceDao{
+		postColl:            db.Collection("post"),
+		postRevisionColl:    db.Collection("post_revision"),
+		documentColl:        db.Collection("document"),
+		documentContentColl: db.Collection("document_content"),
+		friendColl:          db.Collection("friend"),
+		configColl:          db.Collection("config"),
+		userColl:            db.Collection("user"),
+		commentColl:         db.Collection("comment"),
+	}
+}
+
+type ReferenceDao struct {
+	postColl            *mongo.Collection
+	postRevisionColl    *mongo.Collection
+	documentColl        *mongo.Collection
+	documentContentColl *mongo.Collection
+	friendColl          *mongo.Collection
+	configColl          *mongo.Collection
+	userColl            *mongo.Collection
+	commentColl         *mongo.Collection
+}
+
+// FindPosts 查询正文或缩略图匹配pattern的文章
+func (d *ReferenceDao) FindPosts(ctx context.Context, pattern string) ([]*PostRef, error) {
+	opts := options.Find().SetProjection(bson.M{"title": 1, "content": 1, "thumbnail": 1})
+	return find[PostRef](ctx, d.postColl, regexFilter(pattern, "content", "thumbnail"), opts)
+}
+
+// FindPostRevisions 查询正文或缩略图匹配pattern的文章历史版本
+func (d *ReferenceDao) FindPostRevisions(ctx context.Context, pattern string) ([]*PostRevisionRef, error) {
+	opts := options.Find().SetProjection(bson.M{"post_id": 1, "title": 1, "content": 1, "thumbnail": 1})
+	return find[PostRevisionRef](ctx, d.postRevisionColl, regexFilter(pattern, "content", "thumbnail"), opts)
+}
+
+// FindDocuments 查询缩略图匹配pattern的文档
+func (d *ReferenceDao) FindDocuments(ctx context.Context, pattern string) ([]*DocumentRef, error) {
+	opts := options.Find().SetProjection(bson.M{"title": 1, "thumbnail": 1})
+	return find[DocumentRef](ctx, d.documentColl, regexFilter(pattern, "thumbnail"), opts)
+}
+
+// FindDocumentContents 查询正文匹配pattern的文档内容
+func (d *ReferenceDao) FindDocumentContents(ctx context.Context, pattern string) ([]*DocumentContentRef, error) {
+	opts := options.Find().SetProjection(bson.M{"title": 1, "content": 1})
+	return find[DocumentContentRef](ctx, d.documentContentColl, regexFilter(pattern, "content"), opts)
+}
+
+// FindFriends 查询头像匹配pattern的友链
+func (d *ReferenceDao) FindFriends(ctx context.Context, pattern string) ([]*FriendRef, error) {
+	opts := options.Find().SetProjection(bson.M{"name": 1, "avatar_url": 1})
+	return find[FriendRef](ctx, d.friendColl, regexFilter(pattern, "avatar_url"), opts)
+}
+
+// FindConfigs 查询头像或Open Graph图片匹配pattern的网站配置
+func (d *ReferenceDao) FindConfigs(ctx context.Context, pattern string) ([]*ConfigRef, error) {
+	opts := options.Find().SetProjection(bson.M{"type": 1, "content.avatar": 1, "content.og_image": 1})
+	return find[ConfigRef](ctx, d.configColl, regexFilter(pattern, "content.avatar", "content.og_image"), opts)
+}
+
+// FindUsers 查询头像匹配pattern的用户
+func (d *ReferenceDao) FindUsers(ctx context.Context, pattern string) ([]*UserRef, error) {
+	opts := options.Find().SetProjection(bson.M{"nickname": 1, "avatar": 1})
+	return find[UserRef](ctx, d.userColl, regexFilter(pattern, "avatar"), opts)
+}
+
+// FindComments 查询头像或正文匹配pattern的评论
+func (d *ReferenceDao) FindComments(ctx context.Context, pattern string) ([]*CommentRef, error) {
+	opts := options.Find().SetProjection(bson.M{"nickname": 1, "content": 1, "avatar": 1})
+	return find[CommentRef](ctx, d.commentColl, regexFilter(pattern, "avatar", "content"), opts)
+}
+
+func regexFilter(pattern string, fields ...string) bson.M {
+	conditions := make(bson.A, 0, len(fields))
+	for _, field := range fields {
+		conditions = append(conditions, bson.M{field: bson.Regex{Pattern: pattern}})
+	}
+	return bson.M{"$or": conditions}
+}
+
+func find[T any](ctx context.Context, coll *mongo.Collection, filter any, opts *options.FindOptionsBuilder) ([]*T, error) {
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var list []*T
+	if err = cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -2,6 +2,10 @@ package repository
 
 import (
 	"context"
+	"path"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/codepzj/Stellux-Server/internal/file/internal/domain"
 	"github.com/codepzj/Stellux-Server/internal/file/internal/repository/dao"
@@ -13,24 +17,48 @@ import (
 type IFileRepository interface {
 	Create(ctx context.Context, file *domain.File) error
 	Get(ctx context.Context, id bson.ObjectID) (*domain.File, error)
-	GetList(ctx context.Context, page *apiwrap.Page) ([]*domain.File, int64, error)
+	GetBySha256(ctx context.Context, sha256 string) (*domain.File, error)
+	GetList(ctx context.Context, query *domain.Query, page *apiwrap.Page) ([]*domain.File, int64, error)
 	GetListByIDList(ctx context.Context, idList []bson.ObjectID) ([]*domain.File, error)
+	GetUnreferencedList(ctx context.Context, page *apiwrap.Page) ([]*domain.File, int64, error)
+	UpdateLocation(ctx context.Context, file *domain.File) error
+	SetUnreferenced(ctx context.Context, idList []bson.ObjectID, unreferencedAt *time.Time) error
+	UpdateMeta(ctx context.Context, id bson.ObjectID, meta *domain.FileMeta) error
+	Rename(ctx context.Context, id bson.ObjectID, fileName string) error
+	Move(ctx context.Context, idList []bson.ObjectID, folder string) (int64, error)
+	GetFolderCounts(ctx context.Context) ([]*domain.FolderCount, error)
+	GetTagCounts(ctx context.Context) ([]*domain.TagCount, error)
+	CreateAlbum(ctx context.Context, album *domain.Album) error
+	UpdateAlbum(ctx context.Context, album *domain.Album) error
+	DeleteAlbum(ctx context.Context, id bson.ObjectID) error
+	GetAlbum(ctx context.Context, id bson.ObjectID) (*domain.Album, error)
+	GetAlbumList(ctx context.Context) ([]*domain.Album, error)
+	AddToAlbum(ctx context.Context, idList []bson.ObjectID, albumId bson.ObjectID) (int64, error)
+	RemoveFromAlbum(ctx context.Context, idList []bson.ObjectID, albumId bson.ObjectID) (int64, error)
+	FindContentFields(ctx context.Context, pattern string) ([]*domain.ContentField, error)
 	Delete(ctx context.Context, id bson.ObjectID) error
 	DeleteMany(ctx context.Context, idList []bson.ObjectID) error
 }
 
 var _ IFileRepository = (*FileRepository)(nil)
 
-func NewFileRepository(dao dao.IFileDao) *FileRepository {
-	return &FileRepository{dao: dao}
+func NewFileRepository(dao dao.IFileDao, referenceDao dao.IReferenceDao, albumDao dao.IAlbumDao) *FileRepository {
+	return &FileRepository{dao: dao, referenceDao: referenceDao, albumDao: albumDao}
 }
 
 type FileRepository struct {
-	dao dao.IFileDao
+	dao          dao.IFileDao
+	referenceDao dao.IReferenceDao
+	albumDao     dao.IAlbumDao
 }
 
 func (r *FileRepository) Create(ctx context.Context, file *domain.File) error {
-	return r.dao.Create(ctx, r.FileDomainToDao(file))
+	fileDO := r.FileDomainToDao(file)
+	if err := r.dao.Create(ctx, fileDO); err != nil {
+		return err
+	}
+	file.ID = fileDO.ID
+	return nil
 }
 
 func (r *FileRepository) Get(ctx context.Context, id bson.ObjectID) (*domain.File, error) {
@@ -41,16 +69,65 @@ func (r *FileRepository) Get(ctx context.Context, id bson.ObjectID) (*domain.Fil
 	return r.FileDaoToDomain(file), nil
 }
 
-func (r *FileRepository) GetList(ctx context.Context, page *apiwrap.Page) ([]*domain.File, int64, error) {
+func (r *FileRepository) GetBySha256(ctx context.Context, sha256 string) (*domain.File, error) {
+	file, err := r.dao.GetBySha256(ctx, sha256)
+	if err != nil {
+		return nil, err
+	}
+	return r.FileDaoToDomain(file), nil
+}
+
+func (r *FileRepository) GetList(ctx context.Context, query *domain.Query, page *apiwrap.Page) ([]*domain.File, int64, error) {
 	limit := page.PageSize
 	skip := (page.PageNo - 1) * page.PageSize
-	files, count, err := r.dao.GetList(ctx, skip, limit)
+	files, count, err := r.dao.GetList(ctx, fileFilter(query), skip, limit)
 	if err != nil {
 		return nil, 0, err
 	}
 	return r.FileDaoToDomainList(files), count, nil
 }
 
+// fileFilter 将查询条件转换为mongo过滤条件
+func fileFilter(query *domain.Query) bson.D {
+	filter := bson.D{}
+	if query.Keyword != "" {
+		keyword := bson.M{"$regex": regexp.QuoteMeta(query.Keyword), "$options": "i"}
+		filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+			bson.M{"file_name": keyword},
+			bson.M{"alt": keyword},
+			bson.M{"caption": keyword},
+		}})
+	}
+	if len(query.Tags) > 0 {
+		filter = append(filter, bson.E{Key: "tags", Value: bson.M{"$all": query.Tags}})
+	}
+	if prefix, ok := strings.CutSuffix(query.MimeType, "/*"); ok {
+		filter = append(filter, bson.E{Key: "mime_type", Value: bson.M{"$regex": "^" + regexp.QuoteMeta(prefix+"/")}})
+	} else if query.MimeType != "" {
+		filter = append(filter, bson.E{Key: "mime_type", Value: query.MimeType})
+	}
+	if query.Folder == domain.RootFolder {
+		// 旧版本的文件没有目录字段
+		filter = append(filter, bson.E{Key: "folder", Value: bson.M{"$in": bson.A{domain.RootFolder, nil}}})
+	} else if query.Folder != "" {
+		filter = append(filter, bson.E{Key: "folder", Value: query.Folder})
+	}
+	if !query.AlbumId.IsZero() {
+		filter = append(filter, bson.E{Key: "album_ids", Value: query.AlbumId})
+	}
+	createdAt := bson.M{}
+	if !query.StartTime.IsZero() {
+		createdAt["$gte"] = query.StartTime
+	}
+	if !query.EndTime.IsZero() {
+		createdAt["$lte"] = query.EndTime
+	}
+	if len(createdAt) > 0 {
+		filter = append(filter, bson.E{Key: "created_at", Value: createdAt})
+	}
+	return filter
+}
+
 func (r *FileRepository) Delete(ctx context.Context, id bson.ObjectID) error {
 	return r.dao.Delete(ctx, id)
 }
@@ -67,11 +144,74 @@ func (r *FileRepository) GetListByIDList(ctx context.Context, idList []bson.Obje
 	return r.FileDaoToDomainList(files), nil
 }
 
+func (r *FileRepository) GetUnreferencedList(ctx context.Context, page *apiwrap.Page) ([]*domain.File, int64, error) {
+	files, count, err := r.dao.GetUnreferencedList(ctx, (page.PageNo-1)*page.PageSize, page.PageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	return r.FileDaoToDomainList(files), count, nil
+}
+
+func (r *FileRepository) SetUnreferenced(ctx context.Context, idList []bson.ObjectID, unreferencedAt *time.Time) error {
+	if len(idList) == 0 {
+		return nil
+	}
+	return r.dao.SetUnreferenced(ctx, idList, unreferencedAt)
+}
+
+func (r *FileRepository) UpdateMeta(ctx context.Context, id bson.ObjectID, meta *domain.FileMeta) error {
+	return r.dao.UpdateMeta(ctx, id, meta.Alt, meta.Caption, meta.Tags)
+}
+
+func (r *FileRepository) Rename(ctx context.Context, id bson.ObjectID, fileName string) error {
+	return r.dao.Rename(ctx, id, fileName)
+}
+
+func (r *FileRepository) Move(ctx context.Context, idList []bson.ObjectID, folder string) (int64, error) {
+	return r.dao.Move(ctx, idList, folder)
+}
+
+func (r *FileRepository) GetFolderCounts(ctx context.Context) ([]*domain.FolderCount, error) {
+	folderCounts, err := r.dao.GetFolderCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(folderCounts, func(folderCount *dao.FolderCount, _ int) *domain.FolderCount {
+		return &domain.FolderCount{Folder: folderCount.Folder, Count: folderCount.Count}
+	}), nil
+}
+
+func (r *FileRepository) GetTagCounts(ctx context.Context) ([]*domain.TagCount, error) {
+	tagCounts, err := r.dao.GetTagCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(tagCounts, func(tagCount *dao.TagCount, _ int) *domain.TagCount {
+		return &domain.TagCount{Tag: tagCount.Tag, Count: tagCount.Count}
+	}), nil
+}
+
+func (r *FileRepository) UpdateLocation(ctx context.Context, file *domain.File) error {
+	return r.dao.UpdateLocation(ctx, file.ID, file.Key, file.Url, r.VariantDomainToDaoList(file.Variants))
+}
+
 func (r *FileRepository) FileDomainToDao(file *domain.File) *dao.File {
 	return &dao.File{
 		FileName: file.FileName,
 		Url:      file.Url,
-		Dst:      file.Dst,
+		Key:      file.Key,
+		Width:    file.Width,
+		Height:   file.Height,
+		Variants: r.VariantDomainToDaoList(file.Variants),
+		Size:     file.Size,
+		MimeType: file.MimeType,
+		Sha256:   file.Sha256,
+
+		Folder:      file.Folder,
+		Alt:         file.Alt,
+		Caption:     file.Caption,
+		Tags:        file.Tags,
+		AlbumIdList: file.AlbumIdList,
 	}
 }
 
@@ -86,12 +226,51 @@ func (r *FileRepository) FileDaoToDomain(file *dao.File) *domain.File {
 		ID:       file.ID,
 		FileName: file.FileName,
 		Url:      file.Url,
-		Dst:      file.Dst,
+		Key:      fileKey(file),
+		Width:    file.Width,
+		Height:   file.Height,
+		Variants: lo.Map(file.Variants, func(variant *dao.Variant, _ int) *domain.Variant {
+			return &domain.Variant{Width: variant.Width, Height: variant.Height, Format: variant.Format, Key: variant.Key, Url: variant.Url}
+		}),
+		Size:     file.Size,
+		MimeType: file.MimeType,
+		Sha256:   file.Sha256,
+
+		Folder:      fileFolder(file),
+		Alt:         file.Alt,
+		Caption:     file.Caption,
+		Tags:        file.Tags,
+		AlbumIdList: file.AlbumIdList,
+		CreatedAt:   file.CreatedAt,
+
+		UnreferencedAt: file.UnreferencedAt,
 	}
 }
 
+func (r *FileRepository) VariantDomainToDaoList(variants []*domain.Variant) []*dao.Variant {
+	return lo.Map(variants, func(variant *domain.Variant, _ int) *dao.Variant {
+		return &dao.Variant{Width: variant.Width, Height: variant.Height, Format: variant.Format, Key: variant.Key, Url: variant.Url}
+	})
+}
+
 func (r *FileRepository) FileDaoToDomainList(files []*dao.File) []*domain.File {
 	return lo.Map(files, func(file *dao.File, _ int) *domain.File {
 		return r.FileDaoToDomain(file)
 	})
 }
+
+// fileKey 旧版本只保存了本地路径static/images/xxx, 文件名即为key
+func fileKey(file *dao.File) string {
+	if file.Key != "" || file.Dst == "" {
+		return file.Key
+	}
+	return path.Base(file.Dst)
+}
+
+// fileFolder 旧版本的文件没有目录, 在根目录下
+func fileFolder(file *dao.File) string {
+	if file.Folder == "" {
+		return domain.RootFolder
+	}
+	return file.Folder
+}
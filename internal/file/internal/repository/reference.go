@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/codepzj/Stellux-Server/internal/file/internal/domain"
+)
+
+// FindContentFields 查询内容匹配pattern的字段, 每个非空字段对应一条记录
+func (r *FileRepository) FindContentFields(ctx context.Context, pattern string) ([]*domain.ContentField, error) {
+	var fields []*domain.ContentField
+	add := func(sourceType string, sourceId string, title string, field string, text string) {
+		if text == "" {
+			return
+		}
+		fields = append(fields, &domain.ContentField{SourceType: sourceType, SourceId: sourceId, Title: title, Field: field, Text: text})
+	}
+
+	posts, err := r.referenceDao.FindPosts(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, post := range posts {
+		add(domain.SourcePost, post.ID.Hex(), post.Title, "content", post.Content)
+		add(domain.SourcePost, post.ID.Hex(), post.Title, "thumbnail", post.Thumbnail)
+	}
+
+	revisions, err := r.referenceDao.FindPostRevisions(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, revision := range revisions {
+		add(domain.SourcePostRevision, revision.PostID.Hex(), revision.Title, "content", revision.Content)
+		add(domain.SourcePostRevision, revision.PostID.Hex(), revision.Title, "thumbnail", revision.Thumbnail)
+	}
+
+	documents, err := r.referenceDao.FindDocuments(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, document := range documents {
+		add(domain.SourceDocument, document.ID.Hex(), document.Title, "thumbnail", document.Thumbnail)
+	}
+
+	documentContents, err := r.referenceDao.FindDocumentContents(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, documentContent := range documentContents {
+		add(domain.SourceDocumentContent, documentContent.ID.Hex(), documentContent.Title, "content", documentContent.Content)
+	}
+
+	friends, err := r.referenceDao.FindFriends(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, friend := range friends {
+		add(domain.SourceFriend, friend.ID.Hex(), friend.Name, "avatar_url", friend.AvatarUrl)
+	}
+
+	configs, err := r.referenceDao.FindConfigs(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, config := range configs {
+		add(domain.SourceConfig, config.Type, config.Type, "avatar", config.Content.Avatar)
+		add(domain.SourceConfig, config.Type, config.Type, "og_image", config.Content.OGImage)
+	}
+
+	users, err := r.referenceDao.FindUsers(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		add(domain.SourceUser, user.ID.Hex(), user.Nickname, "avatar", user.Avatar)
+	}
+
+	comments, err := r.referenceDao.FindComments(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, comment := range comments {
+		add(domain.SourceComment, comment.ID.Hex(), comment.Nickname, "avatar", comment.Avatar)
+		add(domain.SourceComment, comment.ID.Hex(), comment.Nickname, "content", comment.Content)
+	}
+	return fields, nil
+}
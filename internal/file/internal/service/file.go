@@ -1,94 +1,276 @@
 package service
 
 import (
+	"bytes"
+	"context"
+	"io"
 	"mime/multipart"
-	"os"
-	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/audit"
 	"github.com/codepzj/Stellux-Server/internal/file/internal/domain"
 	"github.com/codepzj/Stellux-Server/internal/file/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
 	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/codepzj/Stellux-Server/internal/pkg/storage"
 	"github.com/codepzj/Stellux-Server/internal/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
+const migratePageSize = 100 // 迁移时每批处理的文件数
+
 type IFileService interface {
-	UploadFile(ctx *gin.Context, file *multipart.FileHeader) error
-	QueryFileList(ctx *gin.Context, page *apiwrap.Page) ([]*domain.File, int64, error)
-	DeleteFiles(ctx *gin.Context, idList []string) error
+	UploadFile(ctx *gin.Context, file *multipart.FileHeader) (*domain.File, error)
+	QueryFileList(ctx *gin.Context, query *domain.Query, page *apiwrap.Page) ([]*domain.File, int64, error)
+	UpdateFileMeta(ctx context.Context, id bson.ObjectID, meta *domain.FileMeta) error
+	RenameFile(ctx context.Context, id bson.ObjectID, fileName string) error
+	MoveFiles(ctx context.Context, idList []bson.ObjectID, folder string) error
+	GetFolders(ctx context.Context) ([]*domain.FolderCount, error)
+	GetTags(ctx context.Context) ([]*domain.TagCount, error)
+	CreateAlbum(ctx context.Context, album *domain.Album) error
+	UpdateAlbum(ctx context.Context, album *domain.Album) error
+	DeleteAlbum(ctx context.Context, id bson.ObjectID) error
+	GetAlbumList(ctx context.Context) ([]*domain.Album, error)
+	AddToAlbum(ctx context.Context, albumId bson.ObjectID, idList []bson.ObjectID) error
+	RemoveFromAlbum(ctx context.Context, albumId bson.ObjectID, idList []bson.ObjectID) error
+	DeleteFiles(ctx *gin.Context, idList []string, force bool) (map[bson.ObjectID][]*domain.Reference, error)
+	GetReferences(ctx context.Context, files []*domain.File) (map[bson.ObjectID][]*domain.Reference, error)
+	QueryUnreferencedList(ctx context.Context, page *apiwrap.Page) ([]*domain.File, int64, error)
+	RunGC(ctx context.Context, dryRun bool) (*domain.GCResult, error)
+	StartGC(ctx context.Context)
+	CreateUpload(ctx context.Context, fileName string, length int64) (*domain.Upload, error)
+	GetUpload(ctx context.Context, id string) (*domain.Upload, error)
+	WriteUpload(ctx context.Context, id string, offset int64, r io.Reader) (*domain.Upload, *domain.File, error)
+	DeleteUpload(ctx context.Context, id string) error
+	StartUploadCleaner(ctx context.Context)
+	OpenFile(ctx context.Context, key string) (*storage.Object, error)
+	ResizeImage(ctx context.Context, key string, width int, format string) (*storage.Object, error)
+	MigrateFiles(ctx context.Context, from storage.Storage, to storage.Storage, keepSource bool) (*domain.MigrateResult, error)
 }
 
 var _ IFileService = (*FileService)(nil)
 
-func NewFileService(repo repository.IFileRepository) *FileService {
+func NewFileService(repo repository.IFileRepository, auditServ audit.Service, store storage.Storage, cfg *conf.Config) *FileService {
+	variantWidths := defaultVariantWidths
+	if len(cfg.Image.VariantWidths) > 0 {
+		variantWidths = slices.Sorted(slices.Values(cfg.Image.VariantWidths))
+	}
+	maxWidth := cfg.Image.MaxWidth
+	if maxWidth <= 0 {
+		maxWidth = defaultMaxWidth
+	}
+	cacheDir := cfg.Image.CacheDir
+	if cacheDir == "" {
+		cacheDir = defaultCacheDir
+	}
+	limits := uploadLimits(cfg.Upload.Rules)
+	var maxUploadSize int64
+	for _, maxSize := range limits {
+		if maxSize <= 0 {
+			maxUploadSize = 0
+			break
+		}
+		maxUploadSize = max(maxUploadSize, maxSize)
+	}
+	gcInterval := defaultGCInterval
+	if cfg.FileGC.IntervalHours != 0 {
+		gcInterval = time.Duration(cfg.FileGC.IntervalHours) * time.Hour
+	}
+	gcGrace := defaultGCGrace
+	if cfg.FileGC.GraceDays > 0 {
+		gcGrace = time.Duration(cfg.FileGC.GraceDays) * 24 * time.Hour
+	}
+	resumableDir := cfg.Upload.ResumableDir
+	if resumableDir == "" {
+		resumableDir = defaultResumableDir
+	}
+	resumableExpire := defaultResumableExpire
+	if cfg.Upload.ResumableExpireHours > 0 {
+		resumableExpire = time.Duration(cfg.Upload.ResumableExpireHours) * time.Hour
+	}
 	return &FileService{
-		repo: repo,
+		repo:          repo,
+		auditServ:     auditServ,
+		store:         store,
+		variantWidths: variantWidths,
+		maxWidth:      maxWidth,
+		cacheDir:      cacheDir,
+		uploadLimits:  limits,
+		maxUploadSize: maxUploadSize,
+		gcInterval:    gcInterval,
+		gcGrace:       gcGrace,
+		gcDryRun:      cfg.FileGC.DryRun == nil || *cfg.FileGC.DryRun,
+
+		resumableDir:    resumableDir,
+		resumableExpire: resumableExpire,
 	}
 }
 
 type FileService struct {
-	repo repository.IFileRepository
+	repo          repository.IFileRepository
+	auditServ     audit.Service
+	store         storage.Storage
+	variantWidths []int            // 上传时生成的缩略图宽度, 升序
+	maxWidth      int              // 按需缩放允许的最大宽度
+	cacheDir      string           // 按需缩放结果的缓存目录
+	uploadLimits  map[string]int64 // 允许上传的MIME类型及大小限制, 0为不限制
+	maxUploadSize int64            // 所有类型中最大的大小限制, 识别类型前先按此拒绝, 0为不限制
+	gcInterval    time.Duration    // 垃圾回收间隔, 小于等于0时不自动回收
+	gcGrace       time.Duration    // 未被引用的文件保留时长
+	gcDryRun      bool             // 垃圾回收只标记不删除
+
+	resumableDir    string        // 断点续传的临时目录
+	resumableExpire time.Duration // 断点续传会话最后一次写入后的保留时长
+	uploadLocksMu   sync.Mutex
+	uploadLocks     map[string]*uploadLock // 会话Id -> 会话锁
 }
 
-func (s *FileService) UploadFile(ctx *gin.Context, file *multipart.FileHeader) error {
-	err := os.MkdirAll("static/images", os.ModePerm)
+// UploadFile 校验并保存上传的文件, 内容相同的文件已存在时直接返回已有记录
+func (s *FileService) UploadFile(ctx *gin.Context, file *multipart.FileHeader) (*domain.File, error) {
+	src, err := file.Open()
 	if err != nil {
-		logger.Error("创建目录失败",
+		logger.Error("读取上传文件失败",
 			logger.WithError(err),
+			logger.WithString("filename", file.Filename),
 		)
-		return err
+		return nil, err
+	}
+	defer src.Close()
+	return s.saveFile(ctx, file.Filename, src, file.Size)
+}
+
+// saveFile 校验并保存文件, 普通上传和断点续传共用
+// 只有图片会整体读入内存用于清除元数据和生成缩略图, 其他文件边读边计算哈希和保存
+func (s *FileService) saveFile(ctx context.Context, fileName string, src io.ReadSeeker, size int64) (*domain.File, error) {
+	if s.maxUploadSize > 0 && size > s.maxUploadSize {
+		return nil, ErrFileTooLarge
 	}
 
-	// 获取domain文件信息
+	// 校验类型和大小
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(src, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		logger.Error("读取上传文件失败",
+			logger.WithError(err),
+			logger.WithString("filename", fileName),
+		)
+		return nil, err
+	}
+	mimeType := detectMimeType(head[:n])
+	if err = s.checkUpload(mimeType, size); err != nil {
+		logger.Warn("上传文件校验失败",
+			logger.WithError(err),
+			logger.WithString("filename", fileName),
+		)
+		return nil, err
+	}
+
+	var data []byte
+	if strings.HasPrefix(mimeType, "image/") {
+		if data, err = readAllFrom(src); err != nil {
+			logger.Error("读取上传文件失败",
+				logger.WithError(err),
+				logger.WithString("filename", fileName),
+			)
+			return nil, err
+		}
+		if data, err = sanitizeUpload(mimeType, data); err != nil {
+			logger.Warn("清除文件元数据失败",
+				logger.WithError(err),
+				logger.WithString("filename", fileName),
+			)
+			return nil, err
+		}
+		src, size = bytes.NewReader(data), int64(len(data))
+	}
+
+	// 按处理后的内容去重, 元数据清除的结果是确定的, 相同的原文件得到相同的哈希
+	hash, err := hashFrom(src)
+	if err != nil {
+		logger.Error("读取上传文件失败",
+			logger.WithError(err),
+			logger.WithString("filename", fileName),
+		)
+		return nil, err
+	}
+	existing, err := s.repo.GetBySha256(ctx, hash)
+	if err == nil {
+		logger.Info("文件已存在, 复用已有记录",
+			logger.WithString("filename", fileName),
+			logger.WithString("url", existing.Url),
+		)
+		return existing, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		logger.Error("查询文件失败",
+			logger.WithError(err),
+			logger.WithString("sha256", hash),
+		)
+		return nil, err
+	}
 
-	fileName := file.Filename
 	// 生成新的文件名
 	timestamp := time.Now().Unix()
-	newFileName := strconv.FormatInt(timestamp, 10) + utils.RandString(10) + filepath.Ext(fileName)
-	networkPath := "/images/" + newFileName
-	filePath := "static/images/" + newFileName
+	key := strconv.FormatInt(timestamp, 10) + utils.RandString(10) + extByMimeType(mimeType, fileName)
 	uploadFile := &domain.File{
 		FileName: fileName,
-		Url:      networkPath,
-		Dst:      filePath,
+		Url:      s.store.URL(key),
+		Key:      key,
+		Size:     size,
+		MimeType: mimeType,
+		Sha256:   hash,
 	}
 
 	// 保存文件
-	os.MkdirAll(filepath.Dir(uploadFile.Dst), 0755)
-	err = ctx.SaveUploadedFile(file, uploadFile.Dst)
+	if _, err = src.Seek(0, io.SeekStart); err == nil {
+		err = s.store.Put(ctx, key, src, size, mimeType)
+	}
 	if err != nil {
 		logger.Error("保存文件失败",
 			logger.WithError(err),
 			logger.WithString("filename", uploadFile.FileName),
 		)
-		return errors.Wrapf(err, "保存文件失败: %s", uploadFile.FileName)
+		return nil, errors.Wrapf(err, "保存文件失败: %s", uploadFile.FileName)
+	}
+	if data != nil {
+		uploadFile.Width, uploadFile.Height, uploadFile.Variants = s.generateVariants(ctx, key, data)
 	}
 
-	// 5. 存入数据库
+	// 存入数据库
 	err = s.repo.Create(ctx, uploadFile)
 	if err != nil {
+		s.removeStored(ctx, uploadFile)
+		if mongo.IsDuplicateKeyError(err) {
+			// 相同内容的文件同时上传, 使用先保存的记录
+			return s.repo.GetBySha256(ctx, hash)
+		}
 		logger.Error("存入数据库失败",
 			logger.WithError(err),
 			logger.WithString("filename", uploadFile.FileName),
 		)
-		return err
+		return nil, err
 	}
+	s.auditServ.Record(ctx, audit.ActionCreate, audit.EntityFile, uploadFile.ID.Hex(), nil, uploadFile)
 
 	logger.Info("上传文件成功",
 		logger.WithString("filename", fileName),
-		logger.WithString("url", networkPath),
+		logger.WithString("url", uploadFile.Url),
 	)
 
-	return nil
+	return uploadFile, nil
 }
 
-func (s *FileService) QueryFileList(ctx *gin.Context, page *apiwrap.Page) ([]*domain.File, int64, error) {
-	files, total, err := s.repo.GetList(ctx, page)
+// QueryFileList 按目录、相册、标签、类型、上传时间和关键字分页查询文件
+func (s *FileService) QueryFileList(ctx *gin.Context, query *domain.Query, page *apiwrap.Page) ([]*domain.File, int64, error) {
+	files, total, err := s.repo.GetList(ctx, query, page)
 	if err != nil {
 		logger.Error("查询文件列表失败",
 			logger.WithError(err),
@@ -98,7 +280,8 @@ func (s *FileService) QueryFileList(ctx *gin.Context, page *apiwrap.Page) ([]*do
 	return files, total, nil
 }
 
-func (s *FileService) DeleteFiles(ctx *gin.Context, idList []string) error {
+// DeleteFiles 批量删除文件, 有文件被引用时不删除并返回引用位置, force为true时仍然删除
+func (s *FileService) DeleteFiles(ctx *gin.Context, idList []string, force bool) (map[bson.ObjectID][]*domain.Reference, error) {
 	var objIdList []bson.ObjectID
 	for _, id := range idList {
 		objId, err := bson.ObjectIDFromHex(id)
@@ -107,7 +290,7 @@ func (s *FileService) DeleteFiles(ctx *gin.Context, idList []string) error {
 				logger.WithError(err),
 				logger.WithString("id", id),
 			)
-			return err
+			return nil, err
 		}
 		objIdList = append(objIdList, objId)
 	}
@@ -117,17 +300,24 @@ func (s *FileService) DeleteFiles(ctx *gin.Context, idList []string) error {
 		logger.Error("查询文件列表失败",
 			logger.WithError(err),
 		)
-		return err
+		return nil, err
 	}
 
-	for _, file := range files {
-		err := os.Remove(file.Dst)
-		if err != nil {
-			logger.Warn("删除物理文件失败",
-				logger.WithError(err),
-				logger.WithString("path", file.Dst),
-			)
+	references, err := s.GetReferences(ctx, files)
+	if err != nil {
+		return nil, err
+	}
+	if len(references) > 0 {
+		if !force {
+			return references, ErrFileReferenced
 		}
+		logger.Warn("强制删除被引用的文件",
+			logger.WithInt("count", len(references)),
+		)
+	}
+
+	for _, file := range files {
+		s.removeStored(ctx, file)
 	}
 
 	err = s.repo.DeleteMany(ctx, objIdList)
@@ -135,12 +325,157 @@ func (s *FileService) DeleteFiles(ctx *gin.Context, idList []string) error {
 		logger.Error("删除文件记录失败",
 			logger.WithError(err),
 		)
-		return err
+		return nil, err
+	}
+	for _, file := range files {
+		s.auditServ.Record(ctx, audit.ActionDelete, audit.EntityFile, file.ID.Hex(), file, nil)
 	}
 
 	logger.Info("批量删除文件成功",
 		logger.WithInt("count", len(idList)),
 	)
 
+	return nil, nil
+}
+
+// removeStored 删除原文件、缩略图和缩放缓存, 失败只记录日志
+func (s *FileService) removeStored(ctx context.Context, file *domain.File) {
+	for _, key := range storedKeys(file) {
+		if err := s.store.Delete(ctx, key); err != nil {
+			logger.Warn("删除存储文件失败",
+				logger.WithError(err),
+				logger.WithString("key", key),
+			)
+		}
+	}
+	s.removeCache(file.Key)
+}
+
+// OpenFile 读取存储中的文件, 用于/images路径的访问
+func (s *FileService) OpenFile(ctx context.Context, key string) (*storage.Object, error) {
+	return s.store.Get(ctx, key)
+}
+
+// MigrateFiles 将全部文件从from复制到to并更新访问地址, keepSource为false时删除源文件
+// 已迁移过的文件在源存储中不存在时只更新访问地址, 因此中断后可以重复执行
+func (s *FileService) MigrateFiles(ctx context.Context, from storage.Storage, to storage.Storage, keepSource bool) (*domain.MigrateResult, error) {
+	result := &domain.MigrateResult{}
+	page := &apiwrap.Page{PageNo: 1, PageSize: migratePageSize}
+	for {
+		files, total, err := s.repo.GetList(ctx, &domain.Query{}, page)
+		if err != nil {
+			logger.Error("查询文件列表失败",
+				logger.WithError(err),
+			)
+			return result, err
+		}
+		result.Total = int(total)
+
+		for _, file := range files {
+			err := s.migrateFile(ctx, file, from, to, keepSource)
+			switch {
+			case errors.Is(err, storage.ErrNotFound):
+				result.Missing++
+				logger.Warn("迁移文件不存在",
+					logger.WithString("fileId", file.ID.Hex()),
+					logger.WithString("key", file.Key),
+				)
+			case err != nil:
+				result.Failed++
+				logger.Error("迁移文件失败",
+					logger.WithError(err),
+					logger.WithString("fileId", file.ID.Hex()),
+					logger.WithString("key", file.Key),
+				)
+			default:
+				result.Migrated++
+			}
+		}
+
+		if int64(len(files)) < page.PageSize {
+			break
+		}
+		page.PageNo++
+	}
+
+	logger.Info("迁移文件完成",
+		logger.WithInt("total", result.Total),
+		logger.WithInt("migrated", result.Migrated),
+		logger.WithInt("missing", result.Missing),
+		logger.WithInt("failed", result.Failed),
+	)
+	return result, nil
+}
+
+// migrateFile 复制单个文件并更新记录, 先更新记录再删除源文件, 避免记录指向不存在的文件
+func (s *FileService) migrateFile(ctx context.Context, file *domain.File, from storage.Storage, to storage.Storage, keepSource bool) error {
+	if file.Key == "" {
+		return storage.ErrNotFound
+	}
+
+	object, err := from.Get(ctx, file.Key)
+	if errors.Is(err, storage.ErrNotFound) {
+		// 上次迁移已复制但未更新记录
+		if object, err = to.Get(ctx, file.Key); err != nil {
+			return err
+		}
+		object.Close()
+		keepSource = true
+	} else if err != nil {
+		return err
+	} else {
+		err = to.Put(ctx, file.Key, object, object.Size, object.ContentType)
+		object.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	// 缩略图缺失不影响原图的迁移
+	variants := make([]*domain.Variant, 0, len(file.Variants))
+	for _, variant := range file.Variants {
+		if err = copyObject(ctx, from, to, variant.Key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+		migrated := *variant
+		migrated.Url = to.URL(variant.Key)
+		variants = append(variants, &migrated)
+	}
+
+	updatedFile := *file
+	updatedFile.Url = to.URL(file.Key)
+	updatedFile.Variants = variants
+	if err = s.repo.UpdateLocation(ctx, &updatedFile); err != nil {
+		return err
+	}
+	s.auditServ.Record(ctx, audit.ActionUpdate, audit.EntityFile, file.ID.Hex(), file, &updatedFile)
+
+	if !keepSource {
+		for _, key := range storedKeys(file) {
+			if err = from.Delete(ctx, key); err != nil {
+				logger.Warn("删除源文件失败",
+					logger.WithError(err),
+					logger.WithString("key", key),
+				)
+			}
+		}
+	}
 	return nil
 }
+
+// copyObject 复制单个对象, 源对象不存在但目标已存在时视为已复制
+func copyObject(ctx context.Context, from storage.Storage, to storage.Storage, key string) error {
+	object, err := from.Get(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		if object, err = to.Get(ctx, key); err != nil {
+			return err
+		}
+		object.Close()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+	return to.Put(ctx, key, object, object.Size, object.ContentType)
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/codepzj/Stellux-Server/internal/file/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/pkg/imaging"
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/codepzj/Stellux-Server/internal/pkg/storage"
+)
+
+const (
+	defaultMaxWidth = 2560
+	defaultCacheDir = "data/image_cache"
+	widthStep       = 32 // 按需缩放的宽度向上取整到该值的倍数, 限制缓存文件数量
+)
+
+var defaultVariantWidths = []int{320, 768, 1280}
+
+// generateVariants 生成并保存缩略图, 非图片或处理失败时只返回能读取到的尺寸, 不影响上传
+func (s *FileService) generateVariants(ctx context.Context, key string, data []byte) (int, int, []*domain.Variant) {
+	cfg, err := imaging.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, nil
+	}
+	if cfg.Format == imaging.FormatGIF || len(s.variantWidths) == 0 || cfg.Width <= s.variantWidths[0] {
+		return cfg.Width, cfg.Height, nil
+	}
+
+	img, format, err := imaging.Decode(data)
+	if err != nil {
+		logger.Warn("解码图片失败",
+			logger.WithError(err),
+			logger.WithString("key", key),
+		)
+		return cfg.Width, cfg.Height, nil
+	}
+
+	var variants []*domain.Variant
+	for _, width := range s.variantWidths {
+		if width >= cfg.Width {
+			break
+		}
+		resized := imaging.Resize(img, width)
+		for _, variantFormat := range imaging.VariantFormats(format, img) {
+			variant, err := s.saveVariant(ctx, key, resized, variantFormat)
+			if err != nil {
+				logger.Warn("生成缩略图失败",
+					logger.WithError(err),
+					logger.WithString("key", key),
+					logger.WithInt("width", width),
+					logger.WithString("format", variantFormat),
+				)
+				continue
+			}
+			variants = append(variants, variant)
+		}
+	}
+	return cfg.Width, cfg.Height, variants
+}
+
+func (s *FileService) saveVariant(ctx context.Context, key string, img image.Image, format string) (*domain.Variant, error) {
+	var buf bytes.Buffer
+	if err := imaging.Encode(&buf, img, format); err != nil {
+		return nil, err
+	}
+	bounds := img.Bounds()
+	variantKey := strings.TrimSuffix(key, path.Ext(key)) + "_" + strconv.Itoa(bounds.Dx()) + "w" + imaging.Ext(format)
+	if err := s.store.Put(ctx, variantKey, &buf, int64(buf.Len()), imaging.ContentType(format)); err != nil {
+		return nil, err
+	}
+	return &domain.Variant{
+		Width:  bounds.Dx(),
+		Height: bounds.Dy(),
+		Format: format,
+		Key:    variantKey,
+		Url:    s.store.URL(variantKey),
+	}, nil
+}
+
+// ResizeImage 按需缩放图片并转换格式, 结果缓存在本地磁盘, width为0时保持原尺寸, format为空时保持原格式
+func (s *FileService) ResizeImage(ctx context.Context, key string, width int, format string) (*storage.Object, error) {
+	if format != "" && !imaging.IsFormat(format) {
+		return nil, imaging.ErrUnsupportedFormat
+	}
+	if width > 0 {
+		width = min((width+widthStep-1)/widthStep*widthStep, s.maxWidth)
+	}
+
+	cachePath, err := s.cachePath(key, width, format)
+	if err != nil {
+		return nil, err
+	}
+	if object, err := openCache(cachePath); err == nil {
+		return object, nil
+	}
+
+	source, err := s.store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(source)
+	source.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	img, sourceFormat, err := imaging.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		format = sourceFormat
+		if format == imaging.FormatGIF && width > 0 {
+			// 缩放后的GIF只保留第一帧且色彩损失严重
+			format = imaging.FormatPNG
+		}
+	}
+
+	var buf bytes.Buffer
+	if format == sourceFormat && (width == 0 || width >= img.Bounds().Dx()) {
+		// 尺寸和格式都不变时直接使用原图, 避免重复压缩
+		buf.Write(data)
+	} else if err = imaging.Encode(&buf, imaging.Resize(img, width), format); err != nil {
+		return nil, err
+	}
+
+	if err = writeCache(cachePath, buf.Bytes()); err != nil {
+		logger.Warn("写入图片缓存失败",
+			logger.WithError(err),
+			logger.WithString("path", cachePath),
+		)
+		return &storage.Object{
+			ReadCloser:  io.NopCloser(bytes.NewReader(buf.Bytes())),
+			Size:        int64(buf.Len()),
+			ContentType: imaging.ContentType(format),
+		}, nil
+	}
+	return openCache(cachePath)
+}
+
+// cachePath 缓存文件路径, 由key、宽度和格式决定
+func (s *FileService) cachePath(key string, width int, format string) (string, error) {
+	key = strings.TrimPrefix(path.Clean("/"+key), "/")
+	if key == "" || key == "." {
+		return "", storage.ErrInvalidKey
+	}
+	if format == "" {
+		format = "auto"
+	}
+	return filepath.Join(s.cacheDir, filepath.FromSlash(key)) + "@w" + strconv.Itoa(width) + "." + format, nil
+}
+
+// removeCache 删除文件的全部缩放缓存
+func (s *FileService) removeCache(key string) {
+	prefix, err := s.cachePath(key, 0, "")
+	if err != nil {
+		return
+	}
+	dir, name := filepath.Split(strings.TrimSuffix(prefix, "0.auto"))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), name) {
+			os.Remove(filepath.Join(dir, entry.Name()))
+		}
+	}
+}
+
+// openCache 打开缓存文件, 不设置Content-Type, 由响应时根据内容识别
+func openCache(cachePath string) (*storage.Object, error) {
+	file, err := os.Open(cachePath)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &storage.Object{ReadCloser: file, Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// writeCache 先写临时文件再重命名, 并发请求同一缓存时不会读到不完整的文件
+func writeCache(cachePath string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(cachePath), ".cache-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), cachePath)
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/codepzj/Stellux-Server/internal/audit"
+	"github.com/codepzj/Stellux-Server/internal/file/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var (
+	ErrFileNotFound  = errors.New("文件不存在")
+	ErrAlbumNotFound = errors.New("相册不存在")
+	ErrAlbumExists   = errors.New("相册名称已存在")
+)
+
+// NormalizeFolder 规范化虚拟目录, 以/开头且不以/结尾, 空字符串为根目录
+func NormalizeFolder(folder string) string {
+	return path.Clean("/" + strings.TrimSpace(folder))
+}
+
+// normalizeTags 去除标签首尾空白, 去掉空标签和重复标签
+func normalizeTags(tags []string) []string {
+	tags = lo.Map(tags, func(tag string, _ int) string {
+		return strings.TrimSpace(tag)
+	})
+	return lo.Uniq(lo.Compact(tags))
+}
+
+// UpdateFileMeta 更新文件的替代文本、说明和标签
+func (s *FileService) UpdateFileMeta(ctx context.Context, id bson.ObjectID, meta *domain.FileMeta) error {
+	before, err := s.getFile(ctx, id)
+	if err != nil {
+		return err
+	}
+	meta.Tags = normalizeTags(meta.Tags)
+	if err = s.repo.UpdateMeta(ctx, id, meta); err != nil {
+		logger.Error("更新文件信息失败",
+			logger.WithError(err),
+			logger.WithString("fileId", id.Hex()),
+		)
+		return err
+	}
+	after := *before
+	after.Alt, after.Caption, after.Tags = meta.Alt, meta.Caption, meta.Tags
+	s.auditServ.Record(ctx, audit.ActionUpdate, audit.EntityFile, id.Hex(), before, &after)
+	return nil
+}
+
+// RenameFile 修改文件名, 访问地址不变, 已引用该文件的内容不受影响
+func (s *FileService) RenameFile(ctx context.Context, id bson.ObjectID, fileName string) error {
+	before, err := s.getFile(ctx, id)
+	if err != nil {
+		return err
+	}
+	fileName = strings.TrimSpace(fileName)
+	if err = s.repo.Rename(ctx, id, fileName); err != nil {
+		logger.Error("重命名文件失败",
+			logger.WithError(err),
+			logger.WithString("fileId", id.Hex()),
+		)
+		return err
+	}
+	after := *before
+	after.FileName = fileName
+	s.auditServ.Record(ctx, audit.ActionUpdate, audit.EntityFile, id.Hex(), before, &after)
+	return nil
+}
+
+// MoveFiles 批量移动文件到虚拟目录, 目录不需要预先创建
+func (s *FileService) MoveFiles(ctx context.Context, idList []bson.ObjectID, folder string) error {
+	files, err := s.getFiles(ctx, idList)
+	if err != nil {
+		return err
+	}
+	folder = NormalizeFolder(folder)
+	if _, err = s.repo.Move(ctx, idList, folder); err != nil {
+		logger.Error("移动文件失败",
+			logger.WithError(err),
+			logger.WithString("folder", folder),
+		)
+		return err
+	}
+	for _, file := range files {
+		if file.Folder == folder {
+			continue
+		}
+		after := *file
+		after.Folder = folder
+		s.auditServ.Record(ctx, audit.ActionUpdate, audit.EntityFile, file.ID.Hex(), file, &after)
+	}
+
+	logger.Info("移动文件成功",
+		logger.WithInt("count", len(files)),
+		logger.WithString("folder", folder),
+	)
+	return nil
+}
+
+// GetFolders 获取所有虚拟目录及其下的文件数
+func (s *FileService) GetFolders(ctx context.Context) ([]*domain.FolderCount, error) {
+	folders, err := s.repo.GetFolderCounts(ctx)
+	if err != nil {
+		logger.Error("查询文件目录失败",
+			logger.WithError(err),
+		)
+		return nil, err
+	}
+	return folders, nil
+}
+
+// GetTags 获取所有文件标签及使用次数
+func (s *FileService) GetTags(ctx context.Context) ([]*domain.TagCount, error) {
+	tags, err := s.repo.GetTagCounts(ctx)
+	if err != nil {
+		logger.Error("查询文件标签失败",
+			logger.WithError(err),
+		)
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (s *FileService) CreateAlbum(ctx context.Context, album *domain.Album) error {
+	album.Name = strings.TrimSpace(album.Name)
+	err := s.repo.CreateAlbum(ctx, album)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrAlbumExists
+	}
+	if err != nil {
+		logger.Error("创建相册失败",
+			logger.WithError(err),
+			logger.WithString("name", album.Name),
+		)
+		return err
+	}
+	return nil
+}
+
+func (s *FileService) UpdateAlbum(ctx context.Context, album *domain.Album) error {
+	album.Name = strings.TrimSpace(album.Name)
+	err := s.repo.UpdateAlbum(ctx, album)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrAlbumExists
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrAlbumNotFound
+	}
+	if err != nil {
+		logger.Error("更新相册失败",
+			logger.WithError(err),
+			logger.WithString("albumId", album.ID.Hex()),
+		)
+		return err
+	}
+	return nil
+}
+
+// DeleteAlbum 删除相册, 相册中的文件保留
+func (s *FileService) DeleteAlbum(ctx context.Context, id bson.ObjectID) error {
+	err := s.repo.DeleteAlbum(ctx, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrAlbumNotFound
+	}
+	if err != nil {
+		logger.Error("删除相册失败",
+			logger.WithError(err),
+			logger.WithString("albumId", id.Hex()),
+		)
+		return err
+	}
+	return nil
+}
+
+// GetAlbumList 获取全部相册及其中的文件数
+func (s *FileService) GetAlbumList(ctx context.Context) ([]*domain.Album, error) {
+	albums, err := s.repo.GetAlbumList(ctx)
+	if err != nil {
+		logger.Error("查询相册列表失败",
+			logger.WithError(err),
+		)
+		return nil, err
+	}
+	return albums, nil
+}
+
+// AddToAlbum 批量把文件加入相册, 已在相册中的文件忽略
+func (s *FileService) AddToAlbum(ctx context.Context, albumId bson.ObjectID, idList []bson.ObjectID) error {
+	if err := s.checkAlbum(ctx, albumId); err != nil {
+		return err
+	}
+	if _, err := s.getFiles(ctx, idList); err != nil {
+		return err
+	}
+	if _, err := s.repo.AddToAlbum(ctx, idList, albumId); err != nil {
+		logger.Error("加入相册失败",
+			logger.WithError(err),
+			logger.WithString("albumId", albumId.Hex()),
+		)
+		return err
+	}
+	return nil
+}
+
+// RemoveFromAlbum 批量把文件移出相册, 不在相册中的文件忽略
+func (s *FileService) RemoveFromAlbum(ctx context.Context, albumId bson.ObjectID, idList []bson.ObjectID) error {
+	if err := s.checkAlbum(ctx, albumId); err != nil {
+		return err
+	}
+	if _, err := s.repo.RemoveFromAlbum(ctx, idList, albumId); err != nil {
+		logger.Error("移出相册失败",
+			logger.WithError(err),
+			logger.WithString("albumId", albumId.Hex()),
+		)
+		return err
+	}
+	return nil
+}
+
+func (s *FileService) checkAlbum(ctx context.Context, albumId bson.ObjectID) error {
+	_, err := s.repo.GetAlbum(ctx, albumId)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrAlbumNotFound
+	}
+	if err != nil {
+		logger.Error("查询相册失败",
+			logger.WithError(err),
+			logger.WithString("albumId", albumId.Hex()),
+		)
+		return err
+	}
+	return nil
+}
+
+// getFiles 批量获取文件, 有文件不存在时返回ErrFileNotFound
+func (s *FileService) getFiles(ctx context.Context, idList []bson.ObjectID) ([]*domain.File, error) {
+	files, err := s.repo.GetListByIDList(ctx, idList)
+	if err != nil {
+		logger.Error("查询文件列表失败",
+			logger.WithError(err),
+		)
+		return nil, err
+	}
+	if len(files) != len(lo.Uniq(idList)) {
+		return nil, ErrFileNotFound
+	}
+	return files, nil
+}
+
+func (s *FileService) getFile(ctx context.Context, id bson.ObjectID) (*domain.File, error) {
+	file, err := s.repo.Get(ctx, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrFileNotFound
+	}
+	if err != nil {
+		logger.Error("查询文件失败",
+			logger.WithError(err),
+			logger.WithString("fileId", id.Hex()),
+		)
+		return nil, err
+	}
+	return file, nil
+}
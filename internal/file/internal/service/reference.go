@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/audit"
+	"github.com/codepzj/Stellux-Server/internal/file/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	defaultGCInterval = 24 * time.Hour
+	defaultGCGrace    = 7 * 24 * time.Hour
+	gcPageSize        = 500 // 垃圾回收时每批读取的文件数
+)
+
+var ErrFileReferenced = errors.New("文件正在被使用")
+
+// urlSegmentPattern 内容中地址的路径片段, 查询参数和锚点不属于key
+// 文件key不含/, 按最后的路径片段匹配key, 不依赖访问地址的前缀:
+// 本地存储的/images/<key>、对象存储的PUBLIC_URL/<PREFIX>/<key>以及迁移存储前的旧地址都能识别
+var urlSegmentPattern = regexp.MustCompile(`/([^\s"'()<>\[\]?#\\/]+)`)
+
+// referencedKeys 提取文本中可能引用的文件key, 包含地址中的所有路径片段, 由调用方与已有的key比对
+func referencedKeys(text string) []string {
+	matches := urlSegmentPattern.FindAllStringSubmatch(text, -1)
+	keys := make([]string, 0, len(matches))
+	for _, match := range matches {
+		// 句末的标点不属于地址
+		if key := strings.TrimRight(match[1], ".,;:!"); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// storedKeys 文件原图和缩略图的key, 引用其中任意一个都算引用了该文件
+func storedKeys(file *domain.File) []string {
+	keys := []string{file.Key}
+	for _, variant := range file.Variants {
+		keys = append(keys, variant.Key)
+	}
+	return keys
+}
+
+// GetReferences 查询文件被引用的位置, 没有被引用的文件不在结果中
+func (s *FileService) GetReferences(ctx context.Context, files []*domain.File) (map[bson.ObjectID][]*domain.Reference, error) {
+	fileIds := make(map[string]bson.ObjectID)
+	prefixes := make([]string, 0, len(files))
+	for _, file := range files {
+		if file.Key == "" {
+			continue
+		}
+		for _, key := range storedKeys(file) {
+			fileIds[key] = file.ID
+		}
+		// 缩略图的key以原图key去掉扩展名为前缀, 先按前缀查询再精确匹配
+		prefixes = append(prefixes, regexp.QuoteMeta(strings.TrimSuffix(file.Key, path.Ext(file.Key))))
+	}
+
+	references := make(map[bson.ObjectID][]*domain.Reference)
+	if len(prefixes) == 0 {
+		return references, nil
+	}
+	fields, err := s.repo.FindContentFields(ctx, "/(?:"+strings.Join(prefixes, "|")+")")
+	if err != nil {
+		logger.Error("查询文件引用失败",
+			logger.WithError(err),
+		)
+		return nil, err
+	}
+	for _, field := range fields {
+		seen := make(map[bson.ObjectID]bool)
+		for _, key := range referencedKeys(field.Text) {
+			id, ok := fileIds[key]
+			if !ok || seen[id] {
+				continue
+			}
+			seen[id] = true
+			references[id] = append(references[id], &domain.Reference{
+				SourceType: field.SourceType,
+				SourceId:   field.SourceId,
+				Title:      field.Title,
+				Field:      field.Field,
+			})
+		}
+	}
+	return references, nil
+}
+
+// QueryUnreferencedList 分页查询垃圾回收标记为未被引用的文件
+func (s *FileService) QueryUnreferencedList(ctx context.Context, page *apiwrap.Page) ([]*domain.File, int64, error) {
+	files, total, err := s.repo.GetUnreferencedList(ctx, page)
+	if err != nil {
+		logger.Error("查询未被引用的文件失败",
+			logger.WithError(err),
+		)
+		return nil, 0, err
+	}
+	return files, total, nil
+}
+
+// StartGC 启动垃圾回收协程, 按间隔回收未被引用的文件, ctx取消后退出
+func (s *FileService) StartGC(ctx context.Context) {
+	if s.gcInterval <= 0 {
+		logger.Info("文件垃圾回收未开启")
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.gcInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if _, err := s.RunGC(ctx, false); err != nil {
+				logger.Error("文件垃圾回收失败",
+					logger.WithError(err),
+				)
+			}
+		}
+	}()
+	logger.Info("文件垃圾回收协程已启动")
+}
+
+// RunGC 扫描全部文件的引用: 被引用的清除标记, 首次发现未被引用的记录时间, 未被引用超过宽限期的删除
+// dryRun或配置了DRY_RUN时只标记不删除
+func (s *FileService) RunGC(ctx context.Context, dryRun bool) (*domain.GCResult, error) {
+	// 先扫描引用再读取文件, 扫描期间上传的文件最多被标记, 不会被删除
+	fields, err := s.repo.FindContentFields(ctx, "/")
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[string]bool)
+	for _, field := range fields {
+		for _, key := range referencedKeys(field.Text) {
+			used[key] = true
+		}
+	}
+
+	now := time.Now()
+	result := &domain.GCResult{}
+	var referenced, unmarked []bson.ObjectID
+	var expired []*domain.File
+	page := &apiwrap.Page{PageNo: 1, PageSize: gcPageSize}
+	for {
+		files, total, err := s.repo.GetList(ctx, &domain.Query{}, page)
+		if err != nil {
+			return nil, err
+		}
+		result.Total = int(total)
+
+		for _, file := range files {
+			if slices.ContainsFunc(storedKeys(file), func(key string) bool { return used[key] }) {
+				result.Referenced++
+				if file.UnreferencedAt != nil {
+					referenced = append(referenced, file.ID)
+				}
+				continue
+			}
+			result.Unreferenced++
+			if file.UnreferencedAt == nil {
+				unmarked = append(unmarked, file.ID)
+			} else if now.Sub(*file.UnreferencedAt) >= s.gcGrace {
+				expired = append(expired, file)
+			}
+		}
+
+		if int64(len(files)) < page.PageSize {
+			break
+		}
+		page.PageNo++
+	}
+
+	if err = s.repo.SetUnreferenced(ctx, referenced, nil); err != nil {
+		return nil, err
+	}
+	if err = s.repo.SetUnreferenced(ctx, unmarked, &now); err != nil {
+		return nil, err
+	}
+	if !dryRun && !s.gcDryRun {
+		for _, file := range expired {
+			if err := s.repo.Delete(ctx, file.ID); err != nil {
+				result.Failed++
+				logger.Error("删除未被引用的文件失败",
+					logger.WithError(err),
+					logger.WithString("fileId", file.ID.Hex()),
+				)
+				continue
+			}
+			s.removeStored(ctx, file)
+			s.auditServ.Record(ctx, audit.ActionDelete, audit.EntityFile, file.ID.Hex(), file, nil)
+			result.Deleted++
+		}
+	}
+
+	logger.Info("文件垃圾回收完成",
+		logger.WithInt("total", result.Total),
+		logger.WithInt("referenced", result.Referenced),
+		logger.WithInt("unreferenced", result.Unreferenced),
+		logger.WithInt("expired", len(expired)),
+		logger.WithInt("deleted", result.Deleted),
+		logger.WithInt("failed", result.Failed),
+	)
+	return result, nil
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestReferencedKeys(t *testing.T) {
+	const key = "1700000000abcdefghij.png"
+	tests := []struct {
+		name string
+		text string
+		want bool
+	}{
+		{name: "本地存储", text: "![](/images/" + key + ")", want: true},
+		{name: "本地存储带域名", text: `<img src="https://blog.example.com/images/` + key + `">`, want: true},
+		{name: "缩放参数", text: "![](/images/" + key + "?w=640&fmt=webp)", want: true},
+		{name: "S3公开地址无前缀", text: "![](https://cdn.example.com/" + key + ")", want: true},
+		{name: "S3公开地址带前缀", text: "![](https://cdn.example.com/blog/uploads/" + key + ")", want: true},
+		{name: "协议相对地址", text: `<img src="//cdn.example.com/` + key + `"/>`, want: true},
+		{name: "句末标点", text: "封面 https://cdn.example.com/" + key + ".", want: true},
+		{name: "锚点", text: "https://cdn.example.com/" + key + "#top", want: true},
+		{name: "其他文件", text: "![](https://cdn.example.com/1700000000abcdefghij.jpg)", want: false},
+		{name: "key作为其他片段的一部分", text: "https://cdn.example.com/x" + key, want: false},
+		{name: "没有地址", text: key, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := referencedKeys(tt.text)
+			if got := slices.Contains(keys, key); got != tt.want {
+				t.Errorf("referencedKeys(%q) = %v, want contains %s: %v", tt.text, keys, key, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,347 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/file/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/pkg/imaging"
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultResumableDir    = "data/uploads"
+	defaultResumableExpire = 24 * time.Hour
+	resumableCleanInterval = time.Hour
+
+	uploadInfoExt = ".info"
+	uploadDataExt = ".bin"
+)
+
+var (
+	ErrUploadNotFound       = errors.New("上传会话不存在或已过期")
+	ErrUploadOffsetMismatch = errors.New("上传偏移量与已接收的大小不一致")
+	ErrUploadExceedsLength  = errors.New("上传内容超出声明的文件大小")
+)
+
+// 断点续传的会话保存在本地目录, 同一会话的写入只在进程内串行, 因此只支持单实例部署
+// 多实例部署时需由负载均衡把/admin-api/file/uploads的请求固定转发到同一实例
+
+// uploadLock 会话锁, 记录持有和等待的数量, 没有人使用时才从表中删除,
+// 否则等待旧锁的请求和新请求会拿到两把不同的锁
+type uploadLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// uploadInfo 会话信息, 与数据文件一起保存在临时目录, 已接收的大小即数据文件的大小
+type uploadInfo struct {
+	ID        string    `json:"id"`
+	FileName  string    `json:"file_name"`
+	Length    int64     `json:"length"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateUpload 创建断点续传会话
+func (s *FileService) CreateUpload(ctx context.Context, fileName string, length int64) (*domain.Upload, error) {
+	if s.maxUploadSize > 0 && length > s.maxUploadSize {
+		return nil, ErrFileTooLarge
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	info := &uploadInfo{ID: hex.EncodeToString(id), FileName: fileName, Length: length, CreatedAt: time.Now()}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(s.resumableDir, 0755); err != nil {
+		logger.Error("创建断点续传目录失败",
+			logger.WithError(err),
+			logger.WithString("dir", s.resumableDir),
+		)
+		return nil, err
+	}
+	if err = os.WriteFile(s.uploadPath(info.ID, uploadDataExt), nil, 0644); err == nil {
+		err = os.WriteFile(s.uploadPath(info.ID, uploadInfoExt), data, 0644)
+	}
+	if err != nil {
+		logger.Error("创建断点续传会话失败",
+			logger.WithError(err),
+			logger.WithString("filename", fileName),
+		)
+		s.removeUpload(info.ID)
+		return nil, err
+	}
+
+	logger.Info("创建断点续传会话",
+		logger.WithString("uploadId", info.ID),
+		logger.WithString("filename", fileName),
+		logger.WithAny("length", length),
+	)
+	return &domain.Upload{
+		ID:        info.ID,
+		FileName:  info.FileName,
+		Length:    info.Length,
+		CreatedAt: info.CreatedAt,
+		ExpiresAt: info.CreatedAt.Add(s.resumableExpire),
+	}, nil
+}
+
+// GetUpload 查询会话的上传进度
+func (s *FileService) GetUpload(ctx context.Context, id string) (*domain.Upload, error) {
+	if !isUploadID(id) {
+		return nil, ErrUploadNotFound
+	}
+	data, err := os.ReadFile(s.uploadPath(id, uploadInfoExt))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrUploadNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var info uploadInfo
+	if err = json.Unmarshal(data, &info); err != nil {
+		return nil, err
+	}
+	stat, err := os.Stat(s.uploadPath(id, uploadDataExt))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrUploadNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	upload := &domain.Upload{
+		ID:        info.ID,
+		FileName:  info.FileName,
+		Length:    info.Length,
+		Offset:    stat.Size(),
+		CreatedAt: info.CreatedAt,
+		ExpiresAt: stat.ModTime().Add(s.resumableExpire),
+	}
+	if time.Now().After(upload.ExpiresAt) {
+		return nil, ErrUploadNotFound
+	}
+	return upload, nil
+}
+
+// WriteUpload 从offset处追加数据, 接收完整后保存为文件并删除会话, 此时返回保存的文件
+// 连接中断时已写入的数据保留, 客户端查询进度后从新的偏移量继续上传
+func (s *FileService) WriteUpload(ctx context.Context, id string, offset int64, r io.Reader) (*domain.Upload, *domain.File, error) {
+	unlock := s.lockUpload(id)
+	defer unlock()
+
+	upload, err := s.GetUpload(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if offset != upload.Offset {
+		return upload, nil, ErrUploadOffsetMismatch
+	}
+
+	if upload.Offset < upload.Length {
+		written, err := s.appendUpload(upload, r)
+		upload.Offset += written
+		upload.ExpiresAt = time.Now().Add(s.resumableExpire)
+		if err != nil {
+			if !errors.Is(err, ErrUploadExceedsLength) {
+				logger.Warn("接收上传数据中断",
+					logger.WithError(err),
+					logger.WithString("uploadId", id),
+					logger.WithAny("offset", upload.Offset),
+				)
+			}
+			return upload, nil, err
+		}
+
+		// 收到文件头后尽早校验类型, 避免上传完才发现不允许上传
+		if threshold := min(sniffLen, upload.Length); upload.Offset-written < threshold && upload.Offset >= threshold {
+			if err = s.checkUploadHead(upload); err != nil {
+				s.removeUpload(id)
+				return nil, nil, err
+			}
+		}
+		if upload.Offset < upload.Length {
+			return upload, nil, nil
+		}
+	}
+
+	file, err := s.finishUpload(ctx, upload)
+	if err != nil {
+		return upload, nil, err
+	}
+	return upload, file, nil
+}
+
+// DeleteUpload 取消上传并删除会话
+func (s *FileService) DeleteUpload(ctx context.Context, id string) error {
+	unlock := s.lockUpload(id)
+	defer unlock()
+
+	if _, err := s.GetUpload(ctx, id); err != nil {
+		return err
+	}
+	s.removeUpload(id)
+	return nil
+}
+
+// StartUploadCleaner 启动协程定期清理过期的断点续传会话, ctx取消后退出
+func (s *FileService) StartUploadCleaner(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(resumableCleanInterval)
+		defer ticker.Stop()
+		for {
+			s.cleanExpiredUploads(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	logger.Info("断点续传清理协程已启动")
+}
+
+func (s *FileService) cleanExpiredUploads(ctx context.Context) {
+	entries, err := os.ReadDir(s.resumableDir)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Error("读取断点续传目录失败",
+				logger.WithError(err),
+			)
+		}
+		return
+	}
+
+	count := 0
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), uploadInfoExt)
+		if !ok || !isUploadID(id) {
+			continue
+		}
+		unlock := s.lockUpload(id)
+		if _, err := s.GetUpload(ctx, id); errors.Is(err, ErrUploadNotFound) {
+			s.removeUpload(id)
+			count++
+		}
+		unlock()
+	}
+	if count > 0 {
+		logger.Info("清理过期的断点续传会话",
+			logger.WithInt("count", count),
+		)
+	}
+}
+
+// appendUpload 追加数据, 超出声明大小的部分不写入
+func (s *FileService) appendUpload(upload *domain.Upload, r io.Reader) (int64, error) {
+	dataFile, err := os.OpenFile(s.uploadPath(upload.ID, uploadDataExt), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return 0, err
+	}
+	remaining := upload.Length - upload.Offset
+	written, err := io.Copy(dataFile, io.LimitReader(r, remaining))
+	if closeErr := dataFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && written == remaining {
+		if n, _ := r.Read(make([]byte, 1)); n > 0 {
+			err = ErrUploadExceedsLength
+		}
+	}
+	return written, err
+}
+
+// checkUploadHead 按已接收的文件头校验类型和大小
+func (s *FileService) checkUploadHead(upload *domain.Upload) error {
+	dataFile, err := os.Open(s.uploadPath(upload.ID, uploadDataExt))
+	if err != nil {
+		return err
+	}
+	defer dataFile.Close()
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(dataFile, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+	return s.checkUpload(detectMimeType(head[:n]), upload.Length)
+}
+
+// finishUpload 将接收完整的数据保存为文件, 校验不通过时删除会话, 其他错误保留会话以便重试
+func (s *FileService) finishUpload(ctx context.Context, upload *domain.Upload) (*domain.File, error) {
+	dataFile, err := os.Open(s.uploadPath(upload.ID, uploadDataExt))
+	if err != nil {
+		return nil, err
+	}
+	file, err := s.saveFile(ctx, upload.FileName, dataFile, upload.Length)
+	dataFile.Close()
+	if err != nil {
+		if errors.Is(err, ErrFileTypeNotAllowed) || errors.Is(err, ErrFileTooLarge) || errors.Is(err, imaging.ErrInvalidJPEG) {
+			s.removeUpload(upload.ID)
+		}
+		return nil, err
+	}
+	s.removeUpload(upload.ID)
+	return file, nil
+}
+
+// lockUpload 同一会话的写入串行执行
+func (s *FileService) lockUpload(id string) func() {
+	s.uploadLocksMu.Lock()
+	if s.uploadLocks == nil {
+		s.uploadLocks = make(map[string]*uploadLock)
+	}
+	lock, ok := s.uploadLocks[id]
+	if !ok {
+		lock = &uploadLock{}
+		s.uploadLocks[id] = lock
+	}
+	lock.refs++
+	s.uploadLocksMu.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+		s.uploadLocksMu.Lock()
+		if lock.refs--; lock.refs == 0 {
+			delete(s.uploadLocks, id)
+		}
+		s.uploadLocksMu.Unlock()
+	}
+}
+
+func (s *FileService) removeUpload(id string) {
+	for _, ext := range []string{uploadInfoExt, uploadDataExt} {
+		if err := os.Remove(s.uploadPath(id, ext)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logger.Warn("删除断点续传文件失败",
+				logger.WithError(err),
+				logger.WithString("uploadId", id),
+			)
+		}
+	}
+}
+
+func (s *FileService) uploadPath(id string, ext string) string {
+	return filepath.Join(s.resumableDir, id+ext)
+}
+
+// isUploadID 会话Id为32位十六进制, 校验后才能拼接路径
+func isUploadID(id string) bool {
+	if len(id) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
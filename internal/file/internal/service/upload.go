@@ -0,0 +1,114 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/pkg/imaging"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrFileTypeNotAllowed = errors.New("不允许上传该类型的文件")
+	ErrFileTooLarge       = errors.New("文件大小超出限制")
+)
+
+const sniffLen = 512 // 识别类型读取的文件头长度, 与http.DetectContentType一致
+
+var defaultUploadRules = []conf.UploadRule{
+	{MimeTypes: []string{"image/jpeg", "image/png", "image/gif", "image/webp"}, MaxSize: 10 << 20},
+}
+
+// mimeExts 常见类型使用固定的扩展名, mime.ExtensionsByType的结果依赖系统配置
+var mimeExts = map[string]string{
+	"image/jpeg":      imaging.Ext(imaging.FormatJPEG),
+	"image/png":       imaging.Ext(imaging.FormatPNG),
+	"image/gif":       imaging.Ext(imaging.FormatGIF),
+	"image/webp":      imaging.Ext(imaging.FormatWebP),
+	"application/pdf": ".pdf",
+}
+
+// uploadLimits 将上传规则展开为MIME类型到大小限制的映射
+func uploadLimits(rules []conf.UploadRule) map[string]int64 {
+	if len(rules) == 0 {
+		rules = defaultUploadRules
+	}
+	limits := make(map[string]int64)
+	for _, rule := range rules {
+		for _, mimeType := range rule.MimeTypes {
+			limits[strings.ToLower(strings.TrimSpace(mimeType))] = rule.MaxSize
+		}
+	}
+	return limits
+}
+
+// checkUpload 校验文件类型和大小
+func (s *FileService) checkUpload(mimeType string, size int64) error {
+	maxSize, ok := s.uploadLimits[mimeType]
+	if !ok {
+		return errors.Wrapf(ErrFileTypeNotAllowed, "%s", mimeType)
+	}
+	if maxSize > 0 && size > maxSize {
+		return errors.Wrapf(ErrFileTooLarge, "%s最大%dKB", mimeType, maxSize>>10)
+	}
+	return nil
+}
+
+// detectMimeType 按文件内容识别MIME类型, 不信任扩展名和客户端提供的Content-Type
+func detectMimeType(data []byte) string {
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil {
+		return "application/octet-stream"
+	}
+	return mediaType
+}
+
+// extByMimeType 保存时使用的扩展名, 原文件名的扩展名与类型相符时沿用
+func extByMimeType(mimeType string, fileName string) string {
+	if ext, ok := mimeExts[mimeType]; ok {
+		return ext
+	}
+	exts, _ := mime.ExtensionsByType(mimeType)
+	if ext := strings.ToLower(filepath.Ext(fileName)); slices.Contains(exts, ext) {
+		return ext
+	}
+	if len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
+
+// sanitizeUpload 删除文件中的隐私信息, 目前只处理JPEG的EXIF(含GPS)等元数据
+func sanitizeUpload(mimeType string, data []byte) ([]byte, error) {
+	if mimeType != "image/jpeg" {
+		return data, nil
+	}
+	return imaging.StripJPEGMetadata(data)
+}
+
+// readAllFrom 从头读取全部内容
+func readAllFrom(src io.ReadSeeker) ([]byte, error) {
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(src)
+}
+
+// hashFrom 从头计算内容的SHA-256
+func hashFrom(src io.ReadSeeker) (string, error) {
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, src); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
@@ -1,9 +1,21 @@
 package web
 
 import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/codepzj/Stellux-Server/internal/file/internal/domain"
 	"github.com/codepzj/Stellux-Server/internal/file/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+	"github.com/codepzj/Stellux-Server/internal/pkg/imaging"
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/codepzj/Stellux-Server/internal/pkg/middleware"
+	"github.com/codepzj/Stellux-Server/internal/pkg/permission"
+	"github.com/codepzj/Stellux-Server/internal/pkg/storage"
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 func NewFileHandler(serv service.IFileService) *FileHandler {
@@ -17,18 +29,92 @@ type FileHandler struct {
 }
 
 func (h *FileHandler) RegisterGinRoutes(engine *gin.Engine) {
-	engine.Static("/images", "./static/images")
+	engine.GET("/images/*key", h.ServeFile)
+	engine.HEAD("/images/*key", h.ServeFile)
 	fileGroup := engine.Group("/file")
 	{
 		fileGroup.GET("/list", apiwrap.WrapWithQuery(h.QueryFileList))
 	}
 	fileAdminGroup := engine.Group("/admin-api/file")
 	{
-		fileAdminGroup.POST("/upload", apiwrap.Wrap(h.UploadFile))
-		fileAdminGroup.DELETE("/delete", apiwrap.WrapWithJson(h.DeleteFiles))
+		fileAdminGroup.GET("/list", middleware.RequirePermission(permission.FileWrite), apiwrap.WrapWithQuery(h.QueryAdminFileList))
+		fileAdminGroup.GET("/unreferenced", middleware.RequirePermission(permission.FileWrite), apiwrap.WrapWithQuery(h.QueryUnreferencedList))
+		fileAdminGroup.POST("/gc", middleware.RequirePermission(permission.FileWrite), apiwrap.WrapWithQuery(h.RunGC))
+		fileAdminGroup.POST("/upload", middleware.RequirePermission(permission.FileWrite), apiwrap.Wrap(h.UploadFile))
+		fileAdminGroup.DELETE("/delete", middleware.RequirePermission(permission.FileWrite), apiwrap.WrapWithJson(h.DeleteFiles))
+		fileAdminGroup.PUT("/meta", middleware.RequirePermission(permission.FileWrite), apiwrap.WrapWithJson(h.UpdateFileMeta))
+		fileAdminGroup.PUT("/rename", middleware.RequirePermission(permission.FileWrite), apiwrap.WrapWithJson(h.RenameFile))
+		fileAdminGroup.PUT("/move", middleware.RequirePermission(permission.FileWrite), apiwrap.WrapWithJson(h.MoveFiles))
+		fileAdminGroup.GET("/folders", middleware.RequirePermission(permission.FileWrite), apiwrap.Wrap(h.QueryFolders))
+		fileAdminGroup.GET("/tags", middleware.RequirePermission(permission.FileWrite), apiwrap.Wrap(h.QueryTags))
+	}
+	albumAdminGroup := engine.Group("/admin-api/file/album", middleware.RequirePermission(permission.FileWrite))
+	{
+		albumAdminGroup.GET("/list", apiwrap.Wrap(h.QueryAlbumList))
+		albumAdminGroup.POST("/create", apiwrap.WrapWithJson(h.CreateAlbum))
+		albumAdminGroup.PUT("/edit", apiwrap.WrapWithJson(h.UpdateAlbum))
+		albumAdminGroup.DELETE("/delete/:id", apiwrap.Wrap(h.DeleteAlbum))
+		albumAdminGroup.POST("/files", apiwrap.WrapWithJson(h.AddToAlbum))
+		albumAdminGroup.DELETE("/files", apiwrap.WrapWithJson(h.RemoveFromAlbum))
+	}
+	// 断点续传, tus协议
+	uploadGroup := engine.Group("/admin-api/file/uploads", middleware.RequirePermission(permission.FileWrite), h.TusResumable)
+	{
+		uploadGroup.POST("", h.CreateUpload)
+		uploadGroup.HEAD("/:id", h.GetUpload)
+		uploadGroup.PATCH("/:id", h.WriteUpload)
+		uploadGroup.DELETE("/:id", h.DeleteUpload)
 	}
 }
 
+// ServeFile 从文件存储读取文件, 本地存储和未配置公开地址的对象存储都通过该路径访问
+// 带w或fmt参数时按需缩放图片并转换格式, eg: /images/xxx.png?w=640&fmt=webp
+func (h *FileHandler) ServeFile(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	var (
+		object *storage.Object
+		err    error
+	)
+	if c.Query("w") != "" || c.Query("fmt") != "" {
+		var query ImageQuery
+		if err = c.ShouldBindQuery(&query); err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		if query.Fmt == "jpg" {
+			query.Fmt = imaging.FormatJPEG
+		}
+		object, err = h.serv.ResizeImage(c, key, query.W, query.Fmt)
+	} else {
+		object, err = h.serv.OpenFile(c, key)
+	}
+	if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, imaging.ErrUnsupportedFormat) || errors.Is(err, imaging.ErrTooLarge) {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		logger.Error("读取文件失败",
+			logger.WithError(err),
+			logger.WithString("key", c.Param("key")),
+		)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	defer object.Close()
+
+	c.Header("Content-Type", object.ContentType)
+	if seeker, ok := object.ReadCloser.(io.ReadSeeker); ok {
+		// 支持Range和条件请求
+		http.ServeContent(c.Writer, c.Request, "", object.ModTime, seeker)
+		return
+	}
+	c.DataFromReader(http.StatusOK, object.Size, object.ContentType, object, nil)
+}
+
 func (h *FileHandler) UploadFile(c *gin.Context) (int, string, any) {
 	file, err := c.FormFile("file")
 	if err != nil {
@@ -37,23 +123,100 @@ func (h *FileHandler) UploadFile(c *gin.Context) (int, string, any) {
 	if file == nil {
 		return 400, "未找到上传的文件", nil
 	}
-	err = h.serv.UploadFile(c, file)
+	uploadFile, err := h.serv.UploadFile(c, file)
+	if errors.Is(err, service.ErrFileTypeNotAllowed) || errors.Is(err, imaging.ErrInvalidJPEG) {
+		return 400, err.Error(), nil
+	}
+	if errors.Is(err, service.ErrFileTooLarge) {
+		return 413, err.Error(), nil
+	}
 	if err != nil {
 		return 500, err.Error(), nil
 	}
-	return 200, "文件上传成功", nil
+	return 200, "文件上传成功", h.FileDomainToVO(uploadFile)
 }
 
-func (h *FileHandler) QueryFileList(c *gin.Context, page *apiwrap.Page) (int, string, any) {
-	files, count, err := h.serv.QueryFileList(c, page)
+func (h *FileHandler) QueryFileList(c *gin.Context, req *FileListRequest) (int, string, any) {
+	query, err := h.toQuery(req)
+	if err != nil {
+		return 400, err.Error(), nil
+	}
+	files, count, err := h.serv.QueryFileList(c, query, &req.Page)
 	if err != nil {
 		return 500, err.Error(), nil
 	}
-	return 200, "文件列表查询成功", apiwrap.ToPageVO(page.PageNo, page.PageSize, count, h.FileDomainToVOList(files))
+	return 200, "文件列表查询成功", apiwrap.ToPageVO(req.PageNo, req.PageSize, count, h.FileDomainToVOList(files))
+}
+
+// QueryAdminFileList 文件列表, 包含每个文件被引用的位置
+func (h *FileHandler) QueryAdminFileList(c *gin.Context, req *FileListRequest) (int, string, any) {
+	query, err := h.toQuery(req)
+	if err != nil {
+		return 400, err.Error(), nil
+	}
+	files, count, err := h.serv.QueryFileList(c, query, &req.Page)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	references, err := h.serv.GetReferences(c, files)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	fileVOs := h.FileDomainToVOList(files)
+	for i, file := range files {
+		fileVOs[i].References = h.ReferenceDomainToVOList(references[file.ID])
+	}
+	return 200, "文件列表查询成功", apiwrap.ToPageVO(req.PageNo, req.PageSize, count, fileVOs)
+}
+
+// toQuery 校验并转换文件列表查询条件
+func (h *FileHandler) toQuery(req *FileListRequest) (*domain.Query, error) {
+	if !req.StartTime.IsZero() && !req.EndTime.IsZero() && req.EndTime.Before(req.StartTime) {
+		return nil, errors.New("结束时间不能早于开始时间")
+	}
+	query := &domain.Query{
+		Keyword:   req.Keyword,
+		Tags:      req.Tags,
+		MimeType:  req.MimeType,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+	}
+	if req.Folder != "" {
+		query.Folder = service.NormalizeFolder(req.Folder)
+	}
+	if req.AlbumId != "" {
+		albumId, err := bson.ObjectIDFromHex(req.AlbumId)
+		if err != nil {
+			return nil, errors.New("相册id格式错误")
+		}
+		query.AlbumId = albumId
+	}
+	return query, nil
+}
+
+// QueryUnreferencedList 垃圾回收标记为未被引用的文件, 超过宽限期后会被删除
+func (h *FileHandler) QueryUnreferencedList(c *gin.Context, page *apiwrap.Page) (int, string, any) {
+	files, count, err := h.serv.QueryUnreferencedList(c, page)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "未被引用的文件查询成功", apiwrap.ToPageVO(page.PageNo, page.PageSize, count, h.FileDomainToVOList(files))
+}
+
+// RunGC 立即执行一次垃圾回收
+func (h *FileHandler) RunGC(c *gin.Context, gcRequest *GCRequest) (int, string, any) {
+	result, err := h.serv.RunGC(c, gcRequest.DryRun)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "垃圾回收完成", h.GCResultDomainToVO(result)
 }
 
 func (h *FileHandler) DeleteFiles(c *gin.Context, deleteFilesRequest *DeleteFilesRequest) (int, string, any) {
-	err := h.serv.DeleteFiles(c, deleteFilesRequest.IDList)
+	references, err := h.serv.DeleteFiles(c, deleteFilesRequest.IDList, deleteFilesRequest.Force)
+	if errors.Is(err, service.ErrFileReferenced) {
+		return 409, "文件正在被使用, 确认后可强制删除", h.FileReferencesToVOList(references)
+	}
 	if err != nil {
 		return 500, err.Error(), nil
 	}
@@ -0,0 +1,188 @@
+package web
+
+import (
+	"errors"
+
+	"github.com/codepzj/Stellux-Server/internal/file/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/file/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// UpdateFileMeta 修改文件的替代文本、说明和标签
+func (h *FileHandler) UpdateFileMeta(c *gin.Context, req *UpdateFileMetaRequest) (int, string, any) {
+	id, err := bson.ObjectIDFromHex(req.ID)
+	if err != nil {
+		return 400, "id格式错误", nil
+	}
+	err = h.serv.UpdateFileMeta(c, id, &domain.FileMeta{
+		Alt:     req.Alt,
+		Caption: req.Caption,
+		Tags:    req.Tags,
+	})
+	if errors.Is(err, service.ErrFileNotFound) {
+		return 404, err.Error(), nil
+	}
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "文件信息更新成功", nil
+}
+
+// RenameFile 修改文件名, 访问地址不变
+func (h *FileHandler) RenameFile(c *gin.Context, req *RenameFileRequest) (int, string, any) {
+	id, err := bson.ObjectIDFromHex(req.ID)
+	if err != nil {
+		return 400, "id格式错误", nil
+	}
+	err = h.serv.RenameFile(c, id, req.FileName)
+	if errors.Is(err, service.ErrFileNotFound) {
+		return 404, err.Error(), nil
+	}
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "文件重命名成功", nil
+}
+
+// MoveFiles 批量移动文件到目录
+func (h *FileHandler) MoveFiles(c *gin.Context, req *MoveFilesRequest) (int, string, any) {
+	idList, err := toObjectIDList(req.IDList)
+	if err != nil {
+		return 400, "id格式错误", nil
+	}
+	err = h.serv.MoveFiles(c, idList, req.Folder)
+	if errors.Is(err, service.ErrFileNotFound) {
+		return 404, err.Error(), nil
+	}
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "文件移动成功", nil
+}
+
+// QueryFolders 所有目录及其下的文件数
+func (h *FileHandler) QueryFolders(c *gin.Context) (int, string, any) {
+	folders, err := h.serv.GetFolders(c)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "文件目录查询成功", h.FolderDomainToVOList(folders)
+}
+
+// QueryTags 所有文件标签及使用次数
+func (h *FileHandler) QueryTags(c *gin.Context) (int, string, any) {
+	tags, err := h.serv.GetTags(c)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "文件标签查询成功", h.TagDomainToVOList(tags)
+}
+
+func (h *FileHandler) QueryAlbumList(c *gin.Context) (int, string, any) {
+	albums, err := h.serv.GetAlbumList(c)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "相册列表查询成功", h.AlbumDomainToVOList(albums)
+}
+
+func (h *FileHandler) CreateAlbum(c *gin.Context, req *AlbumRequest) (int, string, any) {
+	album := &domain.Album{Name: req.Name, Description: req.Description}
+	err := h.serv.CreateAlbum(c, album)
+	if errors.Is(err, service.ErrAlbumExists) {
+		return 409, err.Error(), nil
+	}
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "相册创建成功", h.AlbumDomainToVO(album)
+}
+
+func (h *FileHandler) UpdateAlbum(c *gin.Context, req *AlbumRequest) (int, string, any) {
+	id, err := bson.ObjectIDFromHex(req.ID)
+	if err != nil {
+		return 400, "id格式错误", nil
+	}
+	err = h.serv.UpdateAlbum(c, &domain.Album{ID: id, Name: req.Name, Description: req.Description})
+	if errors.Is(err, service.ErrAlbumExists) {
+		return 409, err.Error(), nil
+	}
+	if errors.Is(err, service.ErrAlbumNotFound) {
+		return 404, err.Error(), nil
+	}
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "相册更新成功", nil
+}
+
+// DeleteAlbum 删除相册, 相册中的文件保留
+func (h *FileHandler) DeleteAlbum(c *gin.Context) (int, string, any) {
+	id, err := bson.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		return 400, "id格式错误", nil
+	}
+	err = h.serv.DeleteAlbum(c, id)
+	if errors.Is(err, service.ErrAlbumNotFound) {
+		return 404, err.Error(), nil
+	}
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "相册删除成功", nil
+}
+
+func (h *FileHandler) AddToAlbum(c *gin.Context, req *AlbumFilesRequest) (int, string, any) {
+	albumId, idList, err := toAlbumFiles(req)
+	if err != nil {
+		return 400, "id格式错误", nil
+	}
+	err = h.serv.AddToAlbum(c, albumId, idList)
+	if errors.Is(err, service.ErrAlbumNotFound) || errors.Is(err, service.ErrFileNotFound) {
+		return 404, err.Error(), nil
+	}
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "加入相册成功", nil
+}
+
+func (h *FileHandler) RemoveFromAlbum(c *gin.Context, req *AlbumFilesRequest) (int, string, any) {
+	albumId, idList, err := toAlbumFiles(req)
+	if err != nil {
+		return 400, "id格式错误", nil
+	}
+	err = h.serv.RemoveFromAlbum(c, albumId, idList)
+	if errors.Is(err, service.ErrAlbumNotFound) {
+		return 404, err.Error(), nil
+	}
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "移出相册成功", nil
+}
+
+func toAlbumFiles(req *AlbumFilesRequest) (bson.ObjectID, []bson.ObjectID, error) {
+	albumId, err := bson.ObjectIDFromHex(req.AlbumId)
+	if err != nil {
+		return bson.ObjectID{}, nil, err
+	}
+	idList, err := toObjectIDList(req.IDList)
+	if err != nil {
+		return bson.ObjectID{}, nil, err
+	}
+	return albumId, idList, nil
+}
+
+func toObjectIDList(idList []string) ([]bson.ObjectID, error) {
+	objIdList := make([]bson.ObjectID, 0, len(idList))
+	for _, id := range idList {
+		objId, err := bson.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, err
+		}
+		objIdList = append(objIdList, objId)
+	}
+	return objIdList, nil
+}
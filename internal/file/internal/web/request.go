@@ -1,5 +1,60 @@
 package web
 
+import (
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+)
+
+// FileListRequest 文件列表查询条件, keyword匹配文件名、替代文本和说明
+type FileListRequest struct {
+	apiwrap.Page
+	Tags      []string  `form:"tag"`       // 可重复, 匹配包含全部标签的文件
+	MimeType  string    `form:"mime_type"` // eg: image/png, image/*
+	Folder    string    `form:"folder"`    // 只查询该目录下的文件, 不包括子目录
+	AlbumId   string    `form:"album_id"`
+	StartTime time.Time `form:"start_time" time_format:"2006-01-02T15:04:05Z07:00"`
+	EndTime   time.Time `form:"end_time" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type UpdateFileMetaRequest struct {
+	ID      string   `json:"id" binding:"required"`
+	Alt     string   `json:"alt" binding:"max=500"`
+	Caption string   `json:"caption" binding:"max=2000"`
+	Tags    []string `json:"tags" binding:"max=50,dive,max=50"`
+}
+
+type RenameFileRequest struct {
+	ID       string `json:"id" binding:"required"`
+	FileName string `json:"file_name" binding:"required,max=255"`
+}
+
+type MoveFilesRequest struct {
+	IDList []string `json:"id_list" binding:"required,min=1"`
+	Folder string   `json:"folder" binding:"max=255"` // 目标目录, 为空时移动到根目录
+}
+
+type AlbumRequest struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=500"`
+}
+
+type AlbumFilesRequest struct {
+	AlbumId string   `json:"album_id" binding:"required"`
+	IDList  []string `json:"id_list" binding:"required,min=1"`
+}
+
 type DeleteFilesRequest struct {
 	IDList []string `json:"id_list" binding:"required"`
+	Force  bool     `json:"force"` // 文件被引用时仍然删除
+}
+
+type GCRequest struct {
+	DryRun bool `form:"dry_run"` // 只标记不删除
+}
+
+type ImageQuery struct {
+	W   int    `form:"w" binding:"omitempty,min=1"`
+	Fmt string `form:"fmt" binding:"omitempty,oneof=jpeg jpg png gif webp"`
 }
@@ -0,0 +1,156 @@
+package web
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/codepzj/Stellux-Server/internal/file/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/file/internal/service"
+	"github.com/codepzj/Stellux-Server/internal/pkg/imaging"
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// 断点续传兼容tus 1.0.0协议(https://tus.io/protocols/resumable-upload), 支持creation、expiration、termination扩展
+const (
+	tusVersion         = "1.0.0"
+	tusExtensions      = "creation,expiration,termination"
+	offsetContentType  = "application/offset+octet-stream"
+	defaultUploadName  = "upload"
+	uploadFileIdHeader = "X-File-Id"  // 上传完成后保存的文件Id
+	uploadFileURL      = "X-File-Url" // 上传完成后保存的文件地址
+)
+
+// TusResumable 校验协议版本并返回协议信息, OPTIONS请求由跨域中间件处理, 因此每个响应都带上版本和扩展
+func (h *FileHandler) TusResumable(c *gin.Context) {
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Tus-Version", tusVersion)
+	c.Header("Tus-Extension", tusExtensions)
+	if c.GetHeader("Tus-Resumable") != tusVersion {
+		c.AbortWithStatus(http.StatusPreconditionFailed)
+		return
+	}
+	c.Next()
+}
+
+// CreateUpload 创建上传会话, Upload-Metadata中的filename作为文件名
+func (h *FileHandler) CreateUpload(c *gin.Context) {
+	if c.GetHeader("Upload-Defer-Length") != "" {
+		c.String(http.StatusBadRequest, "不支持Upload-Defer-Length")
+		return
+	}
+	length, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil || length < 0 {
+		c.String(http.StatusBadRequest, "Upload-Length无效")
+		return
+	}
+
+	upload, err := h.serv.CreateUpload(c, uploadFileName(c.GetHeader("Upload-Metadata")), length)
+	if errors.Is(err, service.ErrFileTooLarge) {
+		c.String(http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Header("Location", path.Join(c.Request.URL.Path, upload.ID))
+	c.Header("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	c.Status(http.StatusCreated)
+}
+
+// GetUpload 查询上传进度
+func (h *FileHandler) GetUpload(c *gin.Context) {
+	upload, err := h.serv.GetUpload(c, c.Param("id"))
+	if errors.Is(err, service.ErrUploadNotFound) {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logger.Error("查询上传进度失败",
+			logger.WithError(err),
+			logger.WithString("uploadId", c.Param("id")),
+		)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	setUploadHeaders(c, upload)
+	c.Header("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+}
+
+// WriteUpload 接收一段数据, 上传完成时通过X-File-Id和X-File-Url返回保存的文件
+func (h *FileHandler) WriteUpload(c *gin.Context) {
+	if c.ContentType() != offsetContentType {
+		c.Status(http.StatusUnsupportedMediaType)
+		return
+	}
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		c.String(http.StatusBadRequest, "Upload-Offset无效")
+		return
+	}
+
+	upload, file, err := h.serv.WriteUpload(c, c.Param("id"), offset, c.Request.Body)
+	switch {
+	case errors.Is(err, service.ErrUploadNotFound):
+		c.Status(http.StatusNotFound)
+	case errors.Is(err, service.ErrUploadOffsetMismatch):
+		c.String(http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrUploadExceedsLength), errors.Is(err, imaging.ErrInvalidJPEG):
+		c.String(http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrFileTooLarge):
+		c.String(http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, service.ErrFileTypeNotAllowed):
+		c.String(http.StatusUnsupportedMediaType, err.Error())
+	case err != nil:
+		c.Status(http.StatusInternalServerError)
+	default:
+		setUploadHeaders(c, upload)
+		if file != nil {
+			c.Header(uploadFileIdHeader, file.ID.Hex())
+			c.Header(uploadFileURL, file.Url)
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// DeleteUpload 取消上传
+func (h *FileHandler) DeleteUpload(c *gin.Context) {
+	err := h.serv.DeleteUpload(c, c.Param("id"))
+	if errors.Is(err, service.ErrUploadNotFound) {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func setUploadHeaders(c *gin.Context, upload *domain.Upload) {
+	c.Header("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	c.Header("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+}
+
+// uploadFileName 从Upload-Metadata中读取文件名, 格式为逗号分隔的"键 base64值"
+func uploadFileName(metadata string) string {
+	for _, pair := range strings.Split(metadata, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key != "filename" && key != "name" {
+			continue
+		}
+		name, err := base64.StdEncoding.DecodeString(value)
+		if err != nil || len(name) == 0 {
+			break
+		}
+		return path.Base(strings.ReplaceAll(string(name), "\\", "/"))
+	}
+	return defaultUploadName
+}
@@ -1,14 +1,79 @@
 package web
 
 import (
+	"time"
+
 	"github.com/codepzj/Stellux-Server/internal/file/internal/domain"
 	"github.com/samber/lo"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 type FileVO struct {
-	Id       string `json:"id"`
-	FileName string `json:"file_name"`
-	Url      string `json:"url"`
+	Id       string       `json:"id"`
+	FileName string       `json:"file_name"`
+	Url      string       `json:"url"`
+	Width    int          `json:"width"`
+	Height   int          `json:"height"`
+	Variants []*VariantVO `json:"variants"`
+	Size     int64        `json:"size"`
+	MimeType string       `json:"mime_type"`
+	Sha256   string       `json:"sha256"`
+
+	Folder      string    `json:"folder"`
+	Alt         string    `json:"alt"`
+	Caption     string    `json:"caption"`
+	Tags        []string  `json:"tags"`
+	AlbumIdList []string  `json:"album_ids"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	UnreferencedAt *time.Time     `json:"unreferenced_at,omitempty"` // 垃圾回收首次发现未被引用的时间
+	References     []*ReferenceVO `json:"references,omitempty"`      // 被引用的位置, 仅管理端列表返回
+}
+
+type FolderVO struct {
+	Folder string `json:"folder"`
+	Count  int    `json:"count"`
+}
+
+type TagVO struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+type AlbumVO struct {
+	Id          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	FileCount   int       `json:"file_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type ReferenceVO struct {
+	SourceType string `json:"source_type"` // post, post_revision, document, document_content, friend, config
+	SourceId   string `json:"source_id"`   // 历史版本为文章Id, 网站配置为配置类型
+	Title      string `json:"title"`
+	Field      string `json:"field"`
+}
+
+type FileReferencesVO struct {
+	Id         string         `json:"id"`
+	References []*ReferenceVO `json:"references"`
+}
+
+type GCResultVO struct {
+	Total        int `json:"total"`
+	Referenced   int `json:"referenced"`
+	Unreferenced int `json:"unreferenced"`
+	Deleted      int `json:"deleted"`
+	Failed       int `json:"failed"`
+}
+
+type VariantVO struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Format string `json:"format"`
+	Url    string `json:"url"`
 }
 
 func (h *FileHandler) FileDomainToVO(file *domain.File) *FileVO {
@@ -16,6 +81,30 @@ func (h *FileHandler) FileDomainToVO(file *domain.File) *FileVO {
 		Id:       file.ID.Hex(),
 		FileName: file.FileName,
 		Url:      file.Url,
+		Width:    file.Width,
+		Height:   file.Height,
+		Variants: lo.Map(file.Variants, func(variant *domain.Variant, _ int) *VariantVO {
+			return &VariantVO{
+				Width:  variant.Width,
+				Height: variant.Height,
+				Format: variant.Format,
+				Url:    variant.Url,
+			}
+		}),
+		Size:     file.Size,
+		MimeType: file.MimeType,
+		Sha256:   file.Sha256,
+
+		Folder:  file.Folder,
+		Alt:     file.Alt,
+		Caption: file.Caption,
+		Tags:    file.Tags,
+		AlbumIdList: lo.Map(file.AlbumIdList, func(albumId bson.ObjectID, _ int) string {
+			return albumId.Hex()
+		}),
+		CreatedAt: file.CreatedAt,
+
+		UnreferencedAt: file.UnreferencedAt,
 	}
 }
 
@@ -24,3 +113,62 @@ func (h *FileHandler) FileDomainToVOList(files []*domain.File) []*FileVO {
 		return h.FileDomainToVO(file)
 	})
 }
+
+func (h *FileHandler) FolderDomainToVOList(folders []*domain.FolderCount) []*FolderVO {
+	return lo.Map(folders, func(folder *domain.FolderCount, _ int) *FolderVO {
+		return &FolderVO{Folder: folder.Folder, Count: folder.Count}
+	})
+}
+
+func (h *FileHandler) TagDomainToVOList(tags []*domain.TagCount) []*TagVO {
+	return lo.Map(tags, func(tag *domain.TagCount, _ int) *TagVO {
+		return &TagVO{Tag: tag.Tag, Count: tag.Count}
+	})
+}
+
+func (h *FileHandler) AlbumDomainToVO(album *domain.Album) *AlbumVO {
+	return &AlbumVO{
+		Id:          album.ID.Hex(),
+		Name:        album.Name,
+		Description: album.Description,
+		FileCount:   album.FileCount,
+		CreatedAt:   album.CreatedAt,
+		UpdatedAt:   album.UpdatedAt,
+	}
+}
+
+func (h *FileHandler) AlbumDomainToVOList(albums []*domain.Album) []*AlbumVO {
+	return lo.Map(albums, func(album *domain.Album, _ int) *AlbumVO {
+		return h.AlbumDomainToVO(album)
+	})
+}
+
+func (h *FileHandler) ReferenceDomainToVOList(references []*domain.Reference) []*ReferenceVO {
+	return lo.Map(references, func(reference *domain.Reference, _ int) *ReferenceVO {
+		return &ReferenceVO{
+			SourceType: reference.SourceType,
+			SourceId:   reference.SourceId,
+			Title:      reference.Title,
+			Field:      reference.Field,
+		}
+	})
+}
+
+func (h *FileHandler) FileReferencesToVOList(references map[bson.ObjectID][]*domain.Reference) []*FileReferencesVO {
+	return lo.MapToSlice(references, func(id bson.ObjectID, fileReferences []*domain.Reference) *FileReferencesVO {
+		return &FileReferencesVO{
+			Id:         id.Hex(),
+			References: h.ReferenceDomainToVOList(fileReferences),
+		}
+	})
+}
+
+func (h *FileHandler) GCResultDomainToVO(result *domain.GCResult) *GCResultVO {
+	return &GCResultVO{
+		Total:        result.Total,
+		Referenced:   result.Referenced,
+		Unreferenced: result.Unreferenced,
+		Deleted:      result.Deleted,
+		Failed:       result.Failed,
+	}
+}
@@ -3,20 +3,25 @@
 package file
 
 import (
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/audit"
 	"github.com/codepzj/Stellux-Server/internal/file/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/file/internal/repository/dao"
 	"github.com/codepzj/Stellux-Server/internal/file/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/file/internal/web"
+	"github.com/codepzj/Stellux-Server/internal/pkg/storage"
 	"github.com/google/wire"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
-var FileProviders = wire.NewSet(web.NewFileHandler, service.NewFileService, repository.NewFileRepository, dao.NewFileDao,
+var FileProviders = wire.NewSet(web.NewFileHandler, service.NewFileService, repository.NewFileRepository, dao.NewFileDao, dao.NewReferenceDao, dao.NewAlbumDao,
 	wire.Bind(new(service.IFileService), new(*service.FileService)),
 	wire.Bind(new(repository.IFileRepository), new(*repository.FileRepository)),
-	wire.Bind(new(dao.IFileDao), new(*dao.FileDao)))
+	wire.Bind(new(dao.IFileDao), new(*dao.FileDao)),
+	wire.Bind(new(dao.IReferenceDao), new(*dao.ReferenceDao)),
+	wire.Bind(new(dao.IAlbumDao), new(*dao.AlbumDao)))
 
-func InitFileModule(mongoDB *mongo.Database) *Module {
+func InitFileModule(mongoDB *mongo.Database, auditServ audit.Service, store storage.Storage, cfg *conf.Config) *Module {
 	panic(wire.Build(
 		FileProviders,
 		wire.Struct(new(Module), "Svc", "Hdl"),
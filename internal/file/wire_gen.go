@@ -7,20 +7,25 @@
 package file
 
 import (
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/audit"
 	"github.com/codepzj/Stellux-Server/internal/file/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/file/internal/repository/dao"
 	"github.com/codepzj/Stellux-Server/internal/file/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/file/internal/web"
+	"github.com/codepzj/Stellux-Server/internal/pkg/storage"
 	"github.com/google/wire"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 // Injectors from wire.go:
 
-func InitFileModule(mongoDB *mongo.Database) *Module {
+func InitFileModule(mongoDB *mongo.Database, auditServ audit.Service, store storage.Storage, cfg *conf.Config) *Module {
 	fileDao := dao.NewFileDao(mongoDB)
-	fileRepository := repository.NewFileRepository(fileDao)
-	fileService := service.NewFileService(fileRepository)
+	referenceDao := dao.NewReferenceDao(mongoDB)
+	albumDao := dao.NewAlbumDao(mongoDB)
+	fileRepository := repository.NewFileRepository(fileDao, referenceDao, albumDao)
+	fileService := service.NewFileService(fileRepository, auditServ, store, cfg)
 	fileHandler := web.NewFileHandler(fileService)
 	module := &Module{
 		Svc: fileService,
@@ -31,4 +36,4 @@ func InitFileModule(mongoDB *mongo.Database) *Module {
 
 // wire.go:
 
-var FileProviders = wire.NewSet(web.NewFileHandler, service.NewFileService, repository.NewFileRepository, dao.NewFileDao, wire.Bind(new(service.IFileService), new(*service.FileService)), wire.Bind(new(repository.IFileRepository), new(*repository.FileRepository)), wire.Bind(new(dao.IFileDao), new(*dao.FileDao)))
+var FileProviders = wire.NewSet(web.NewFileHandler, service.NewFileService, repository.NewFileRepository, dao.NewFileDao, dao.NewReferenceDao, dao.NewAlbumDao, wire.Bind(new(service.IFileService), new(*service.FileService)), wire.Bind(new(repository.IFileRepository), new(*repository.FileRepository)), wire.Bind(new(dao.IFileDao), new(*dao.FileDao)), wire.Bind(new(dao.IReferenceDao), new(*dao.ReferenceDao)), wire.Bind(new(dao.IAlbumDao), new(*dao.AlbumDao)))
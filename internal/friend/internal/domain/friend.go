@@ -12,4 +12,5 @@ type Friend struct {
 	WebsiteType int    // 网站类型
 	AvatarUrl   string // 头像地址
 	IsActive    bool   // 是否激活
+	IsSpam      bool   // 是否为垃圾申请
 }
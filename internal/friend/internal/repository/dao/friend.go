@@ -20,10 +20,12 @@ type Friend struct {
 	AvatarUrl   string        `bson:"avatar_url"`
 	WebsiteType int           `bson:"website_type"`
 	IsActive    bool          `bson:"is_active"`
+	IsSpam      bool          `bson:"is_spam"`
 }
 
 type IFriendDao interface {
 	Create(ctx context.Context, friend *Friend) error
+	FindByID(ctx context.Context, id bson.ObjectID) (*Friend, error)
 	FindAll(ctx context.Context) ([]*Friend, error)
 	FindAllActive(ctx context.Context) ([]*Friend, error)
 	ExistsBySiteUrl(ctx context.Context, siteUrl string) (bool, error)
@@ -56,6 +58,15 @@ func (d *FriendDao) Create(ctx context.Context, friend *Friend) error {
 	return nil
 }
 
+// FindByID 根据id查询友链
+func (d *FriendDao) FindByID(ctx context.Context, id bson.ObjectID) (*Friend, error) {
+	var friend Friend
+	if err := d.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&friend); err != nil {
+		return nil, err
+	}
+	return &friend, nil
+}
+
 func (d *FriendDao) FindAll(ctx context.Context) ([]*Friend, error) {
 	cursor, err := d.coll.Find(ctx, bson.M{})
 	if err != nil {
@@ -116,6 +127,7 @@ func (d *FriendDao) Update(ctx context.Context, id bson.ObjectID, friend *Friend
 			"avatar_url":   friend.AvatarUrl,
 			"website_type": friend.WebsiteType,
 			"is_active":    friend.IsActive,
+			"is_spam":      friend.IsSpam,
 			"updated_at":   time.Now(),
 		},
 	}
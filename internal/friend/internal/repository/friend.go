@@ -14,6 +14,7 @@ type IFriendRepository interface {
 	ExistsBySiteUrl(ctx context.Context, siteUrl string) (bool, error)
 	ExistsBySiteUrlExceptID(ctx context.Context, siteUrl string, excludeID bson.ObjectID) (bool, error)
 	FindAllActive(ctx context.Context) ([]*domain.Friend, error)
+	FindByID(ctx context.Context, id bson.ObjectID) (*domain.Friend, error)
 	FindAll(ctx context.Context) ([]*domain.Friend, error)
 	Update(ctx context.Context, id bson.ObjectID, friend *domain.Friend) error
 	Delete(ctx context.Context, id bson.ObjectID) error
@@ -30,7 +31,12 @@ type FriendRepository struct {
 }
 
 func (r *FriendRepository) Create(ctx context.Context, friend *domain.Friend) error {
-	return r.dao.Create(ctx, r.FriendDomainToDao(friend))
+	friendDO := r.FriendDomainToDao(friend)
+	if err := r.dao.Create(ctx, friendDO); err != nil {
+		return err
+	}
+	friend.ID = friendDO.ID
+	return nil
 }
 
 func (r *FriendRepository) ExistsBySiteUrl(ctx context.Context, siteUrl string) (bool, error) {
@@ -50,6 +56,15 @@ func (r *FriendRepository) FindAllActive(ctx context.Context) ([]*domain.Friend,
 	return r.FriendDaoToDomainList(friends), nil
 }
 
+// FindByID 根据id查询友链
+func (r *FriendRepository) FindByID(ctx context.Context, id bson.ObjectID) (*domain.Friend, error) {
+	friend, err := r.dao.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.FriendDaoToDomain(friend), nil
+}
+
 // FindAll 查询所有友链
 func (r *FriendRepository) FindAll(ctx context.Context) ([]*domain.Friend, error) {
 	friends, err := r.dao.FindAll(ctx)
@@ -75,6 +90,7 @@ func (r *FriendRepository) FriendDomainToDao(friend *domain.Friend) *dao.Friend
 		AvatarUrl:   friend.AvatarUrl,
 		WebsiteType: friend.WebsiteType,
 		IsActive:    friend.IsActive,
+		IsSpam:      friend.IsSpam,
 	}
 }
 
@@ -87,6 +103,7 @@ func (r *FriendRepository) FriendDaoToDomain(friend *dao.Friend) *domain.Friend
 		AvatarUrl:   friend.AvatarUrl,
 		WebsiteType: friend.WebsiteType,
 		IsActive:    friend.IsActive,
+		IsSpam:      friend.IsSpam,
 	}
 }
 
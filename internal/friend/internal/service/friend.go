@@ -5,6 +5,8 @@ import (
 	"errors"
 	"regexp"
 
+	"github.com/codepzj/Stellux-Server/internal/antispam"
+	"github.com/codepzj/Stellux-Server/internal/audit"
 	"github.com/codepzj/Stellux-Server/internal/friend/internal/domain"
 	"github.com/codepzj/Stellux-Server/internal/friend/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
@@ -13,6 +15,7 @@ import (
 
 type IFriendService interface {
 	CreateFriend(ctx context.Context, friend *domain.Friend) error
+	ApplyFriend(ctx context.Context, friend *domain.Friend, sub *antispam.Submission) error
 	FindFriendList(ctx context.Context) ([]*domain.Friend, error)
 	FindAllFriends(ctx context.Context) ([]*domain.Friend, error)
 	UpdateFriend(ctx context.Context, id bson.ObjectID, friend *domain.Friend) error
@@ -21,14 +24,18 @@ type IFriendService interface {
 
 var _ IFriendService = (*FriendService)(nil)
 
-func NewFriendService(repo repository.IFriendRepository) *FriendService {
+func NewFriendService(repo repository.IFriendRepository, spamServ antispam.Service, auditServ audit.Service) *FriendService {
 	return &FriendService{
-		repo: repo,
+		repo:      repo,
+		spamServ:  spamServ,
+		auditServ: auditServ,
 	}
 }
 
 type FriendService struct {
-	repo repository.IFriendRepository
+	repo      repository.IFriendRepository
+	spamServ  antispam.Service
+	auditServ audit.Service
 }
 
 // validateFriend 使用正则校验URL等字段
@@ -85,6 +92,7 @@ func (s *FriendService) CreateFriend(ctx context.Context, friend *domain.Friend)
 		)
 		return err
 	}
+	s.auditServ.Record(ctx, audit.ActionCreate, audit.EntityFriend, friend.ID.Hex(), nil, friend)
 
 	logger.Info("创建友链成功",
 		logger.WithString("name", friend.Name),
@@ -94,6 +102,26 @@ func (s *FriendService) CreateFriend(ctx context.Context, friend *domain.Friend)
 	return nil
 }
 
+// ApplyFriend 访客申请友链, 申请默认不激活, 命中反垃圾规则时标记为垃圾申请
+func (s *FriendService) ApplyFriend(ctx context.Context, friend *domain.Friend, sub *antispam.Submission) error {
+	sub.Content = friend.Description
+	sub.Nickname = friend.Name
+	sub.SiteUrl = friend.SiteUrl
+
+	verdict, err := s.spamServ.Check(ctx, sub)
+	if err != nil {
+		logger.Error("友链申请反垃圾检测失败",
+			logger.WithError(err),
+			logger.WithString("siteUrl", friend.SiteUrl),
+		)
+	} else if verdict.Spam {
+		friend.IsSpam = true
+	}
+	friend.IsActive = false
+
+	return s.CreateFriend(ctx, friend)
+}
+
 // FindFriendList 查询友链列表
 func (s *FriendService) FindFriendList(ctx context.Context) ([]*domain.Friend, error) {
 	logger.Info("查询活跃友链列表",
@@ -155,6 +183,7 @@ func (s *FriendService) UpdateFriend(ctx context.Context, id bson.ObjectID, frie
 		return errors.New("站点URL已存在")
 	}
 
+	before, _ := s.repo.FindByID(ctx, id)
 	err = s.repo.Update(ctx, id, friend)
 	if err != nil {
 		logger.Error("更新友链失败",
@@ -163,6 +192,8 @@ func (s *FriendService) UpdateFriend(ctx context.Context, id bson.ObjectID, frie
 		)
 		return err
 	}
+	after, _ := s.repo.FindByID(ctx, id)
+	s.auditServ.Record(ctx, audit.ActionUpdate, audit.EntityFriend, id.Hex(), before, after)
 
 	logger.Info("更新友链成功",
 		logger.WithString("friendId", id.Hex()),
@@ -174,6 +205,7 @@ func (s *FriendService) UpdateFriend(ctx context.Context, id bson.ObjectID, frie
 
 // DeleteFriend 删除好友
 func (s *FriendService) DeleteFriend(ctx context.Context, id bson.ObjectID) error {
+	before, _ := s.repo.FindByID(ctx, id)
 	err := s.repo.Delete(ctx, id)
 	if err != nil {
 		logger.Error("删除友链失败",
@@ -182,6 +214,7 @@ func (s *FriendService) DeleteFriend(ctx context.Context, id bson.ObjectID) erro
 		)
 		return err
 	}
+	s.auditServ.Record(ctx, audit.ActionDelete, audit.EntityFriend, id.Hex(), before, nil)
 
 	logger.Info("删除友链成功",
 		logger.WithString("friendId", id.Hex()),
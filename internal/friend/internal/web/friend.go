@@ -1,9 +1,12 @@
 package web
 
 import (
+	"github.com/codepzj/Stellux-Server/internal/antispam"
 	"github.com/codepzj/Stellux-Server/internal/friend/internal/domain"
 	"github.com/codepzj/Stellux-Server/internal/friend/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+	"github.com/codepzj/Stellux-Server/internal/pkg/middleware"
+	"github.com/codepzj/Stellux-Server/internal/pkg/permission"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
@@ -22,13 +25,14 @@ func (h *FriendHandler) RegisterGinRoutes(engine *gin.Engine) {
 	friendGroup := engine.Group("/friend")
 	{
 		friendGroup.GET("/list", apiwrap.Wrap(h.FindFriendList))
+		friendGroup.POST("/apply", apiwrap.WrapWithJson(h.ApplyFriend))
 	}
 	adminGroup := engine.Group("/admin-api")
 	{
-		adminGroup.POST("/friend/create", apiwrap.WrapWithJson(h.CreateFriend))
-		adminGroup.GET("/friend/all", apiwrap.Wrap(h.FindAllFriends))
-		adminGroup.PUT("/friend/update", apiwrap.WrapWithJson(h.UpdateFriend))
-		adminGroup.DELETE("/friend/delete/:id", apiwrap.Wrap(h.DeleteFriend))
+		adminGroup.POST("/friend/create", middleware.RequirePermission(permission.FriendWrite), apiwrap.WrapWithJson(h.CreateFriend))
+		adminGroup.GET("/friend/all", middleware.RequirePermission(permission.FriendRead), apiwrap.Wrap(h.FindAllFriends))
+		adminGroup.PUT("/friend/update", middleware.RequirePermission(permission.FriendWrite), apiwrap.WrapWithJson(h.UpdateFriend))
+		adminGroup.DELETE("/friend/delete/:id", middleware.RequirePermission(permission.FriendWrite), apiwrap.Wrap(h.DeleteFriend))
 	}
 
 }
@@ -58,6 +62,25 @@ func (h *FriendHandler) CreateFriend(c *gin.Context, friend *FriendRequest) (int
 	return 200, "操作成功", nil
 }
 
+// ApplyFriend 申请友链
+func (h *FriendHandler) ApplyFriend(c *gin.Context, friend *FriendApplyRequest) (int, string, any) {
+	err := h.serv.ApplyFriend(c, &domain.Friend{
+		Name:        friend.Name,
+		Description: friend.Description,
+		SiteUrl:     friend.SiteUrl,
+		AvatarUrl:   friend.AvatarUrl,
+		WebsiteType: friend.WebsiteType,
+	}, &antispam.Submission{
+		Ip:       c.ClientIP(),
+		Honeypot: friend.Honeypot,
+		Token:    friend.Token,
+	})
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "友链申请已提交, 等待审核", nil
+}
+
 // FindAllFriends 获取所有友链
 func (h *FriendHandler) FindAllFriends(c *gin.Context) (int, string, any) {
 	friends, err := h.serv.FindAllFriends(c)
@@ -80,6 +103,7 @@ func (h *FriendHandler) UpdateFriend(c *gin.Context, friend *FriendUpdateRequest
 		AvatarUrl:   friend.AvatarUrl,
 		WebsiteType: friend.WebsiteType,
 		IsActive:    friend.IsActive,
+		IsSpam:      friend.IsSpam,
 	})
 	if err != nil {
 		return 500, err.Error(), nil
@@ -15,6 +15,17 @@ type FriendRequest struct {
 	WebsiteType int    `json:"website_type" binding:"min=0,max=3"`
 }
 
+// FriendApplyRequest 访客申请友链请求
+type FriendApplyRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description" binding:"required"`
+	SiteUrl     string `json:"site_url" binding:"required"`
+	AvatarUrl   string `json:"avatar_url" binding:"required"`
+	WebsiteType int    `json:"website_type" binding:"min=0,max=3"`
+	Honeypot    string `json:"honeypot"` // 蜜罐字段, 前端隐藏不填写
+	Token       string `json:"token"`    // 表单令牌
+}
+
 type FriendUpdateRequest struct {
 	ID          string `json:"id" binding:"required"`
 	Name        string `json:"name" binding:"required"`
@@ -23,4 +34,5 @@ type FriendUpdateRequest struct {
 	AvatarUrl   string `json:"avatar_url" binding:"required"`
 	WebsiteType int    `json:"website_type" binding:"min=0,max=3"`
 	IsActive    bool   `json:"is_active"`
+	IsSpam      bool   `json:"is_spam"`
 }
@@ -13,6 +13,7 @@ type FriendVO struct {
 	AvatarUrl   string `json:"avatar_url"`
 	WebsiteType int    `json:"website_type"`
 	IsActive    bool   `json:"is_active"`
+	IsSpam      bool   `json:"is_spam"`
 }
 
 // FriendShowVO 友链展示VO
@@ -34,6 +35,7 @@ func FriendDomainToVO(friend *domain.Friend) *FriendVO {
 		AvatarUrl:   friend.AvatarUrl,
 		WebsiteType: friend.WebsiteType,
 		IsActive:    friend.IsActive,
+		IsSpam:      friend.IsSpam,
 	}
 }
 
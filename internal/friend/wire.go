@@ -3,6 +3,8 @@
 package friend
 
 import (
+	"github.com/codepzj/Stellux-Server/internal/antispam"
+	"github.com/codepzj/Stellux-Server/internal/audit"
 	"github.com/codepzj/Stellux-Server/internal/friend/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/friend/internal/repository/dao"
 	"github.com/codepzj/Stellux-Server/internal/friend/internal/service"
@@ -16,7 +18,7 @@ var FriendProviders = wire.NewSet(web.NewFriendHandler, service.NewFriendService
 	wire.Bind(new(repository.IFriendRepository), new(*repository.FriendRepository)),
 	wire.Bind(new(dao.IFriendDao), new(*dao.FriendDao)))
 
-func InitFriendModule(mongoDB *mongo.Database) *Module {
+func InitFriendModule(mongoDB *mongo.Database, spamServ antispam.Service, auditServ audit.Service) *Module {
 	panic(wire.Build(
 		FriendProviders,
 		wire.Struct(new(Module), "Svc", "Hdl"),
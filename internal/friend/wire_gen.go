@@ -7,6 +7,8 @@
 package friend
 
 import (
+	"github.com/codepzj/Stellux-Server/internal/antispam"
+	"github.com/codepzj/Stellux-Server/internal/audit"
 	"github.com/codepzj/Stellux-Server/internal/friend/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/friend/internal/repository/dao"
 	"github.com/codepzj/Stellux-Server/internal/friend/internal/service"
@@ -17,10 +19,10 @@ import (
 
 // Injectors from wire.go:
 
-func InitFriendModule(mongoDB *mongo.Database) *Module {
+func InitFriendModule(mongoDB *mongo.Database, spamServ antispam.Service, auditServ audit.Service) *Module {
 	friendDao := dao.NewFriendDao(mongoDB)
 	friendRepository := repository.NewFriendRepository(friendDao)
-	friendService := service.NewFriendService(friendRepository)
+	friendService := service.NewFriendService(friendRepository, spamServ, auditServ)
 	friendHandler := web.NewFriendHandler(friendService)
 	module := &Module{
 		Svc: friendService,
@@ -0,0 +1,15 @@
+package infra
+
+import (
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/pkg/limiter"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// NewLimiterStore 根据配置选择失败记录存储, 多实例部署时使用MongoDB共享记录
+func NewLimiterStore(cfg *conf.Config, db *mongo.Database) limiter.Store {
+	if cfg.Login.Store == "mongo" {
+		return limiter.NewMongoStore(db, "rate_limit")
+	}
+	return limiter.NewMemoryStore()
+}
@@ -0,0 +1,14 @@
+package infra
+
+import (
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/pkg/mailer"
+)
+
+// NewMailer 根据配置选择邮件发送方式, 未配置SMTP时使用本地日志发送
+func NewMailer(cfg *conf.Config) mailer.Mailer {
+	if cfg.Mail.Driver == "smtp" && cfg.Mail.Host != "" {
+		return mailer.NewSMTPMailer(cfg.Mail.Host, cfg.Mail.Port, cfg.Mail.Username, cfg.Mail.Password, cfg.Mail.From, cfg.Mail.FromName)
+	}
+	return mailer.NewLogMailer(cfg.Mail.LogDir, cfg.Mail.From, cfg.Mail.FromName)
+}
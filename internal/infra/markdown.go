@@ -0,0 +1,11 @@
+package infra
+
+import (
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/pkg/markdown"
+)
+
+// NewMarkdownRenderer 文章和文档内容共用一个渲染器, 共享渲染缓存
+func NewMarkdownRenderer(cfg *conf.Config) *markdown.Renderer {
+	return markdown.NewRenderer(cfg.Markdown.CacheSize)
+}
@@ -0,0 +1,48 @@
+package infra
+
+import (
+	"fmt"
+
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/pkg/storage"
+	"github.com/pkg/errors"
+)
+
+const (
+	storageBaseURL  = "/images"       // 由服务端提供的文件访问路径
+	storageLocalDir = "static/images" // 默认本地存储目录
+)
+
+// NewStorage 根据配置选择文件存储
+func NewStorage(cfg *conf.Config) storage.Storage {
+	store, err := NewStorageByDriver(cfg, cfg.Storage.Driver)
+	if err != nil {
+		panic(errors.Wrap(err, "初始化文件存储失败"))
+	}
+	return store
+}
+
+// NewStorageByDriver 按指定方式创建文件存储, 迁移文件时需要同时创建两种存储
+func NewStorageByDriver(cfg *conf.Config, driver string) (storage.Storage, error) {
+	switch driver {
+	case "", "local":
+		dir := cfg.Storage.LocalDir
+		if dir == "" {
+			dir = storageLocalDir
+		}
+		return storage.NewLocalStorage(dir, storageBaseURL), nil
+	case "s3":
+		return storage.NewS3Storage(storage.S3Options{
+			Endpoint:  cfg.Storage.S3.Endpoint,
+			AccessKey: cfg.Storage.S3.AccessKey,
+			SecretKey: cfg.Storage.S3.SecretKey,
+			Bucket:    cfg.Storage.S3.Bucket,
+			Region:    cfg.Storage.S3.Region,
+			UseSSL:    cfg.Storage.S3.UseSSL,
+			Prefix:    cfg.Storage.S3.Prefix,
+			PublicURL: cfg.Storage.S3.PublicURL,
+		}, storageBaseURL)
+	default:
+		return nil, fmt.Errorf("不支持的文件存储方式: %s", driver)
+	}
+}
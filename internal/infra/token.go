@@ -0,0 +1,40 @@
+package infra
+
+import (
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/pkg/token"
+	"github.com/pkg/errors"
+)
+
+// defaultKid 未配置密钥列表时JWT_SECRET对应的kid
+const defaultKid = "default"
+
+// NewTokenManager 根据配置加载令牌签名密钥, 未配置密钥列表时使用JWT_SECRET
+func NewTokenManager(cfg *conf.Config) *token.Manager {
+	var keys []*token.Key
+	if len(cfg.Auth.Keys) == 0 {
+		key, err := token.NewHMACKey(defaultKid, cfg.Server.JwtSecret)
+		if err != nil {
+			panic(errors.Wrap(err, "JWT密钥未配置"))
+		}
+		keys = append(keys, key)
+	}
+	for _, k := range cfg.Auth.Keys {
+		var key *token.Key
+		var err error
+		if k.Alg == token.AlgHS256 {
+			key, err = token.NewHMACKey(k.Kid, k.Secret)
+		} else {
+			key, err = token.LoadKey(k.Kid, k.Alg, k.PrivateKeyFile, k.PublicKeyFile)
+		}
+		if err != nil {
+			panic(errors.Wrap(err, "加载JWT密钥失败"))
+		}
+		keys = append(keys, key)
+	}
+	manager, err := token.NewManager(cfg.Auth.ActiveKid, keys...)
+	if err != nil {
+		panic(errors.Wrap(err, "初始化JWT密钥失败"))
+	}
+	return manager
+}
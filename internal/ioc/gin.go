@@ -1,24 +1,41 @@
 package ioc
 
 import (
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/antispam"
+	"github.com/codepzj/Stellux-Server/internal/audit"
+	"github.com/codepzj/Stellux-Server/internal/comment"
 	"github.com/codepzj/Stellux-Server/internal/config"
 	"github.com/codepzj/Stellux-Server/internal/document"
 	"github.com/codepzj/Stellux-Server/internal/document_content"
+	"github.com/codepzj/Stellux-Server/internal/feed"
 	"github.com/codepzj/Stellux-Server/internal/file"
 	"github.com/codepzj/Stellux-Server/internal/friend"
 	"github.com/codepzj/Stellux-Server/internal/label"
+	"github.com/codepzj/Stellux-Server/internal/mail"
+	"github.com/codepzj/Stellux-Server/internal/pkg/token"
 	"github.com/codepzj/Stellux-Server/internal/post"
+	"github.com/codepzj/Stellux-Server/internal/rbac"
+	"github.com/codepzj/Stellux-Server/internal/search"
+	"github.com/codepzj/Stellux-Server/internal/sitemap"
 	"github.com/codepzj/Stellux-Server/internal/user"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
 )
 
 // NewGin 初始化gin服务器
-func NewGin(userHdl *user.Handler, postHdl *post.Handler, labelHdl *label.Handler, fileHdl *file.Handler, documentHdl *document.Handler, documentContentHdl *document_content.Handler, friendHdl *friend.Handler, configHdl *config.Handler, middleware []gin.HandlerFunc) *gin.Engine {
+func NewGin(userHdl *user.Handler, postHdl *post.Handler, labelHdl *label.Handler, fileHdl *file.Handler, documentHdl *document.Handler, documentContentHdl *document_content.Handler, friendHdl *friend.Handler, configHdl *config.Handler, commentHdl *comment.Handler, antispamHdl *antispam.Handler, mailHdl *mail.Handler, searchHdl *search.Handler, feedHdl *feed.Handler, sitemapHdl *sitemap.Handler, rbacHdl *rbac.Handler, auditHdl *audit.Handler, tokenManager *token.Manager, sessionServ user.SessionService, personalTokenServ user.PersonalTokenService, middleware []gin.HandlerFunc, cfg *conf.Config) *gin.Engine {
 	router := gin.Default()
+	// 默认信任所有代理, 客户端可以伪造X-Forwarded-For绕过按IP的限流
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		panic(errors.Wrap(err, "信任的代理地址配置错误"))
+	}
 
-	// 中间件
+	// 中间件, 鉴权策略必须在注册路由之前挂载
+	policy := newRoutePolicy(adminPrefix, publicAdminRoutes)
 	router.Use(middleware...)
+	policy.install(router, tokenManager, sessionServ, personalTokenServ)
 
 	// 初始化路由
 	{
@@ -27,6 +44,11 @@ func NewGin(userHdl *user.Handler, postHdl *post.Handler, labelHdl *label.Handle
 				"msg": "stellux后端服务正常运行中",
 			})
 		})
+		// 公开非对称密钥的公钥, 供其他服务验证访问令牌
+		router.GET("/.well-known/jwks.json", func(c *gin.Context) {
+			c.Header("Cache-Control", "public, max-age=300")
+			c.JSON(200, tokenManager.JWKS())
+		})
 		userHdl.RegisterGinRoutes(router)
 		postHdl.RegisterGinRoutes(router)
 		labelHdl.RegisterGinRoutes(router)
@@ -35,7 +57,20 @@ func NewGin(userHdl *user.Handler, postHdl *post.Handler, labelHdl *label.Handle
 		documentContentHdl.RegisterGinRoutes(router)
 		friendHdl.RegisterGinRoutes(router)
 		configHdl.RegisterGinRoutes(router)
+		commentHdl.RegisterGinRoutes(router)
+		antispamHdl.RegisterGinRoutes(router)
+		mailHdl.RegisterGinRoutes(router)
+		searchHdl.RegisterGinRoutes(router)
+		feedHdl.RegisterGinRoutes(router)
+		sitemapHdl.RegisterGinRoutes(router)
+		rbacHdl.RegisterGinRoutes(router)
+		auditHdl.RegisterGinRoutes(router)
+	}
+
+	if err := policy.verify(router.Routes()); err != nil {
+		panic(err)
 	}
+	policy.dump(router.Routes())
 
 	return router
 }
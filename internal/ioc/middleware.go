@@ -2,14 +2,16 @@ package ioc
 
 import (
 	"github.com/codepzj/Stellux-Server/internal/pkg/middleware"
+	"github.com/codepzj/Stellux-Server/internal/rbac"
 
 	"github.com/gin-gonic/gin"
 )
 
-func InitMiddleWare() []gin.HandlerFunc {
+func InitMiddleWare(rbacServ rbac.Service) []gin.HandlerFunc {
 	return []gin.HandlerFunc{
 		gin.Recovery(),
 		middleware.Cors(),
 		middleware.CacheControlMiddleware(),
+		middleware.Authorization(rbacServ),
 	}
 }
@@ -0,0 +1,107 @@
+package ioc
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/middleware"
+	"github.com/codepzj/Stellux-Server/internal/pkg/token"
+
+	"github.com/gin-gonic/gin"
+)
+
+// adminPrefix 后台接口前缀, 其下的路由默认需要登录
+const adminPrefix = "/admin-api"
+
+// publicAdminRoutes 显式放行的后台路由, 无需登录即可访问, 格式为"METHOD 路径", 如"GET /admin-api/xxx/:id"
+var publicAdminRoutes = []string{}
+
+const (
+	authPublic = "public"
+	authJWT    = "jwt"
+)
+
+// routePolicy 集中的路由鉴权策略
+type routePolicy struct {
+	prefix    string
+	public    map[string]bool
+	installed bool            // 鉴权中间件是否已挂载
+	before    map[string]bool // 挂载鉴权中间件前已注册的路由, 这些路由不经过鉴权中间件
+}
+
+func newRoutePolicy(prefix string, publicRoutes []string) *routePolicy {
+	public := make(map[string]bool, len(publicRoutes))
+	for _, route := range publicRoutes {
+		public[route] = true
+	}
+	return &routePolicy{prefix: prefix, public: public}
+}
+
+// requirement 返回路由的鉴权要求, path为gin注册的完整路由
+func (p *routePolicy) requirement(method, path string) string {
+	if !p.isAdmin(path) || p.public[method+" "+path] {
+		return authPublic
+	}
+	return authJWT
+}
+
+func (p *routePolicy) isAdmin(path string) bool {
+	return path == p.prefix || strings.HasPrefix(path, p.prefix+"/")
+}
+
+// install 将鉴权中间件挂载为全局中间件, gin的全局中间件只对之后注册的路由生效, 因此记录此前已注册的路由
+func (p *routePolicy) install(engine *gin.Engine, tokenManager *token.Manager, checker middleware.SessionChecker, verifier middleware.PersonalTokenVerifier) {
+	p.installed = true
+	p.before = make(map[string]bool)
+	for _, route := range engine.Routes() {
+		p.before[route.Method+" "+route.Path] = true
+	}
+	engine.Use(p.authenticate(tokenManager, checker, verifier))
+}
+
+// authenticate 按匹配到的路由执行鉴权
+func (p *routePolicy) authenticate(tokenManager *token.Manager, checker middleware.SessionChecker, verifier middleware.PersonalTokenVerifier) gin.HandlerFunc {
+	jwt := middleware.JWT(tokenManager, checker, verifier)
+	return func(c *gin.Context) {
+		if p.requirement(c.Request.Method, c.FullPath()) == authJWT {
+			jwt(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+// verify 校验路由表, 需要登录的后台路由必须在鉴权中间件挂载之后注册, 显式放行的路由必须是已注册的后台路由
+func (p *routePolicy) verify(routes gin.RoutesInfo) error {
+	registered := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		registered[route.Method+" "+route.Path] = true
+		if p.requirement(route.Method, route.Path) == authJWT && (!p.installed || p.before[route.Method+" "+route.Path]) {
+			return fmt.Errorf("后台路由未受保护: %s %s", route.Method, route.Path)
+		}
+	}
+	for route := range p.public {
+		method, path, _ := strings.Cut(route, " ")
+		if !p.isAdmin(path) {
+			return fmt.Errorf("放行路由不在%s下: %s", p.prefix, route)
+		}
+		if !registered[method+" "+path] {
+			return fmt.Errorf("放行路由未注册: %s", route)
+		}
+	}
+	return nil
+}
+
+// dump 打印路由表及各路由的鉴权要求
+func (p *routePolicy) dump(routes gin.RoutesInfo) {
+	routes = slices.Clone(routes)
+	slices.SortFunc(routes, func(a, b gin.RouteInfo) int {
+		return strings.Compare(a.Path+" "+a.Method, b.Path+" "+b.Method)
+	})
+	log.Printf("路由表, 共%d条:", len(routes))
+	for _, route := range routes {
+		log.Printf("%-7s %-60s %s", route.Method, route.Path, p.requirement(route.Method, route.Path))
+	}
+}
@@ -0,0 +1,99 @@
+package ioc
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRoutePolicyRequirement(t *testing.T) {
+	policy := newRoutePolicy(adminPrefix, []string{"GET /admin-api/public/:id"})
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{method: "GET", path: "/post/list", want: authPublic},
+		{method: "GET", path: "/admin-api", want: authJWT},
+		{method: "POST", path: "/admin-api/post/create", want: authJWT},
+		{method: "GET", path: "/admin-api/public/:id", want: authPublic},
+		{method: "DELETE", path: "/admin-api/public/:id", want: authJWT},
+		{method: "GET", path: "/admin-apix/post", want: authPublic},
+	}
+	for _, tt := range tests {
+		if got := policy.requirement(tt.method, tt.path); got != tt.want {
+			t.Errorf("requirement(%s %s) = %s, want %s", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRoutePolicyVerify(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := func(c *gin.Context) {}
+	tests := []struct {
+		name    string
+		public  []string
+		setup   func(engine *gin.Engine, policy *routePolicy)
+		wantErr bool
+	}{
+		{
+			name: "后台路由在鉴权中间件之后注册",
+			setup: func(engine *gin.Engine, policy *routePolicy) {
+				engine.GET("/post/list", handler)
+				policy.install(engine, nil, nil, nil)
+				engine.GET("/admin-api/post/list", handler)
+			},
+		},
+		{
+			name: "后台路由在鉴权中间件之前注册",
+			setup: func(engine *gin.Engine, policy *routePolicy) {
+				engine.GET("/admin-api/post/list", handler)
+				policy.install(engine, nil, nil, nil)
+				engine.GET("/admin-api/label/list", handler)
+			},
+			wantErr: true,
+		},
+		{
+			name: "未挂载鉴权中间件",
+			setup: func(engine *gin.Engine, policy *routePolicy) {
+				engine.GET("/admin-api/post/list", handler)
+			},
+			wantErr: true,
+		},
+		{
+			name:   "放行路由在鉴权中间件之前注册",
+			public: []string{"GET /admin-api/ping"},
+			setup: func(engine *gin.Engine, policy *routePolicy) {
+				engine.GET("/admin-api/ping", handler)
+				policy.install(engine, nil, nil, nil)
+			},
+		},
+		{
+			name:   "放行路由未注册",
+			public: []string{"GET /admin-api/ping"},
+			setup: func(engine *gin.Engine, policy *routePolicy) {
+				policy.install(engine, nil, nil, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:   "放行路由不在后台前缀下",
+			public: []string{"GET /ping"},
+			setup: func(engine *gin.Engine, policy *routePolicy) {
+				policy.install(engine, nil, nil, nil)
+				engine.GET("/ping", handler)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+			policy := newRoutePolicy(adminPrefix, tt.public)
+			tt.setup(engine, policy)
+			if err := policy.verify(engine.Routes()); (err != nil) != tt.wantErr {
+				t.Errorf("verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
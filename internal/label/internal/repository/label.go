@@ -32,7 +32,12 @@ type LabelRepository struct {
 }
 
 func (r *LabelRepository) CreateLabel(ctx context.Context, label *domain.Label) error {
-	return r.dao.CreateLabel(ctx, r.LabelDomainToLabelDO(label))
+	labelDO := r.LabelDomainToLabelDO(label)
+	if err := r.dao.CreateLabel(ctx, labelDO); err != nil {
+		return err
+	}
+	label.Id = labelDO.ID
+	return nil
 }
 
 func (r *LabelRepository) UpdateLabel(ctx context.Context, id string, label *domain.Label) error {
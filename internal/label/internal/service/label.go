@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 
+	"github.com/codepzj/Stellux-Server/internal/audit"
 	"github.com/codepzj/Stellux-Server/internal/label/internal/domain"
 	"github.com/codepzj/Stellux-Server/internal/label/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/codepzj/Stellux-Server/internal/sitemap"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
@@ -23,14 +25,18 @@ type ILabelService interface {
 
 var _ ILabelService = (*LabelService)(nil)
 
-func NewLabelService(repo repository.ILabelRepository) *LabelService {
+func NewLabelService(repo repository.ILabelRepository, sitemapServ sitemap.Service, auditServ audit.Service) *LabelService {
 	return &LabelService{
-		repo: repo,
+		repo:        repo,
+		sitemapServ: sitemapServ,
+		auditServ:   auditServ,
 	}
 }
 
 type LabelService struct {
-	repo repository.ILabelRepository
+	repo        repository.ILabelRepository
+	sitemapServ sitemap.Service
+	auditServ   audit.Service
 }
 
 // CreateLabel 创建标签
@@ -47,6 +53,8 @@ func (s *LabelService) CreateLabel(ctx context.Context, label *domain.Label) err
 				)
 				return err
 			}
+			s.sitemapServ.Invalidate()
+			s.auditServ.Record(ctx, audit.ActionCreate, audit.EntityLabel, label.Id.Hex(), nil, label)
 			logger.Info("创建标签成功",
 				logger.WithString("name", label.Name),
 				logger.WithString("type", label.LabelType),
@@ -83,6 +91,9 @@ func (s *LabelService) CreateLabel(ctx context.Context, label *domain.Label) err
 		return err
 	}
 
+	s.sitemapServ.Invalidate()
+	s.auditServ.Record(ctx, audit.ActionCreate, audit.EntityLabel, label.Id.Hex(), nil, label)
+
 	logger.Info("创建标签成功",
 		logger.WithString("name", label.Name),
 		logger.WithString("type", label.LabelType),
@@ -93,6 +104,7 @@ func (s *LabelService) CreateLabel(ctx context.Context, label *domain.Label) err
 
 // UpdateLabel 更新标签
 func (s *LabelService) UpdateLabel(ctx context.Context, id string, label *domain.Label) error {
+	before, _ := s.repo.GetLabelById(ctx, id)
 	// 检查标签是否存在
 	existLabel, err := s.repo.GetLabelByName(ctx, label.Name)
 	if err != nil {
@@ -105,6 +117,8 @@ func (s *LabelService) UpdateLabel(ctx context.Context, id string, label *domain
 				)
 				return err
 			}
+			s.sitemapServ.Invalidate()
+			s.recordUpdateAudit(ctx, id, before)
 			logger.Info("更新标签成功",
 				logger.WithString("labelId", id),
 				logger.WithString("name", label.Name),
@@ -141,6 +155,9 @@ func (s *LabelService) UpdateLabel(ctx context.Context, id string, label *domain
 		return err
 	}
 
+	s.sitemapServ.Invalidate()
+	s.recordUpdateAudit(ctx, id, before)
+
 	logger.Info("更新标签成功",
 		logger.WithString("labelId", id),
 		logger.WithString("name", label.Name),
@@ -151,6 +168,7 @@ func (s *LabelService) UpdateLabel(ctx context.Context, id string, label *domain
 
 // DeleteLabel 删除标签
 func (s *LabelService) DeleteLabel(ctx context.Context, id string) error {
+	before, _ := s.repo.GetLabelById(ctx, id)
 	err := s.repo.DeleteLabel(ctx, id)
 	if err != nil {
 		logger.Error("删除标签失败",
@@ -160,6 +178,9 @@ func (s *LabelService) DeleteLabel(ctx context.Context, id string) error {
 		return err
 	}
 
+	s.sitemapServ.Invalidate()
+	s.auditServ.Record(ctx, audit.ActionDelete, audit.EntityLabel, id, before, nil)
+
 	logger.Info("删除标签成功",
 		logger.WithString("labelId", id),
 	)
@@ -266,3 +287,9 @@ func (s *LabelService) GetAllTagsLabelWithCount(ctx context.Context) ([]*domain.
 
 	return labels, nil
 }
+
+// recordUpdateAudit 查询更新后的标签并记录审计日志
+func (s *LabelService) recordUpdateAudit(ctx context.Context, id string, before *domain.Label) {
+	after, _ := s.repo.GetLabelById(ctx, id)
+	s.auditServ.Record(ctx, audit.ActionUpdate, audit.EntityLabel, id, before, after)
+}
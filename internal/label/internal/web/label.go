@@ -4,6 +4,8 @@ import (
 	"github.com/codepzj/Stellux-Server/internal/label/internal/domain"
 	"github.com/codepzj/Stellux-Server/internal/label/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+	"github.com/codepzj/Stellux-Server/internal/pkg/middleware"
+	"github.com/codepzj/Stellux-Server/internal/pkg/permission"
 	"github.com/gin-gonic/gin"
 	"github.com/samber/lo"
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -30,9 +32,9 @@ func (h *LabelHandler) RegisterGinRoutes(engine *gin.Engine) {
 	}
 	adminGroup := engine.Group("/admin-api/label")
 	{
-		adminGroup.POST("/create", apiwrap.WrapWithJson(h.AdminCreate)) // 创建标签
-		adminGroup.PUT("/edit", apiwrap.WrapWithJson(h.AdminUpdate))    // 更新标签
-		adminGroup.DELETE("/delete/:id", apiwrap.Wrap(h.AdminDelete))   // 删除标签
+		adminGroup.POST("/create", middleware.RequirePermission(permission.LabelWrite), apiwrap.WrapWithJson(h.AdminCreate)) // 创建标签
+		adminGroup.PUT("/edit", middleware.RequirePermission(permission.LabelWrite), apiwrap.WrapWithJson(h.AdminUpdate))    // 更新标签
+		adminGroup.DELETE("/delete/:id", middleware.RequirePermission(permission.LabelWrite), apiwrap.Wrap(h.AdminDelete))   // 删除标签
 	}
 }
 
@@ -3,10 +3,12 @@
 package label
 
 import (
+	"github.com/codepzj/Stellux-Server/internal/audit"
 	"github.com/codepzj/Stellux-Server/internal/label/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/label/internal/repository/dao"
 	"github.com/codepzj/Stellux-Server/internal/label/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/label/internal/web"
+	"github.com/codepzj/Stellux-Server/internal/sitemap"
 	"github.com/google/wire"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
@@ -16,7 +18,7 @@ var LabelProviders = wire.NewSet(web.NewLabelHandler, service.NewLabelService, r
 	wire.Bind(new(repository.ILabelRepository), new(*repository.LabelRepository)),
 	wire.Bind(new(dao.ILabelDao), new(*dao.LabelDao)))
 
-func InitLabelModule(mongoDB *mongo.Database) *Module {
+func InitLabelModule(mongoDB *mongo.Database, sitemapServ sitemap.Service, auditServ audit.Service) *Module {
 	panic(wire.Build(
 		LabelProviders,
 		wire.Struct(new(Module), "Svc", "Hdl"),
@@ -7,20 +7,22 @@
 package label
 
 import (
+	"github.com/codepzj/Stellux-Server/internal/audit"
 	"github.com/codepzj/Stellux-Server/internal/label/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/label/internal/repository/dao"
 	"github.com/codepzj/Stellux-Server/internal/label/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/label/internal/web"
+	"github.com/codepzj/Stellux-Server/internal/sitemap"
 	"github.com/google/wire"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 // Injectors from wire.go:
 
-func InitLabelModule(mongoDB *mongo.Database) *Module {
+func InitLabelModule(mongoDB *mongo.Database, sitemapServ sitemap.Service, auditServ audit.Service) *Module {
 	labelDao := dao.NewLabelDao(mongoDB)
 	labelRepository := repository.NewLabelRepository(labelDao)
-	labelService := service.NewLabelService(labelRepository)
+	labelService := service.NewLabelService(labelRepository, sitemapServ, auditServ)
 	labelHandler := web.NewLabelHandler(labelService)
 	module := &Module{
 		Svc: labelService,
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// 发件箱邮件状态
+const (
+	MailStatusPending = "pending" // 待发送
+	MailStatusSending = "sending" // 发送中
+	MailStatusSent    = "sent"    // 已发送
+	MailStatusFailed  = "failed"  // 重试耗尽, 发送失败
+)
+
+// Mail 发件箱中的邮件
+type Mail struct {
+	Id          bson.ObjectID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	To          string
+	Subject     string
+	Text        string
+	Html        string
+	Status      string
+	Attempts    int
+	LastError   string
+	NextRetryAt time.Time
+	SentAt      time.Time
+}
+
+// ReplyNotice 评论被回复通知
+type ReplyNotice struct {
+	To             string // 被回复者邮箱
+	ParentNickname string // 被回复者昵称
+	ParentContent  string // 被回复的评论内容
+	Nickname       string // 回复者昵称
+	Content        string // 回复内容
+	Path           string // 评论所在路径
+}
+
+// PendingNotice 新评论待审核通知
+type PendingNotice struct {
+	Nickname string // 评论者昵称
+	Email    string // 评论者邮箱
+	Content  string // 评论内容
+	Path     string // 评论所在路径
+}
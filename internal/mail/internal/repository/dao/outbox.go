@@ -0,0 +1,111 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type Outbox struct {
+	ID          bson.ObjectID `bson:"_id,omitempty"`
+	CreatedAt   time.Time     `bson:"created_at"`
+	UpdatedAt   time.Time     `bson:"updated_at"`
+	To          string        `bson:"to"`                // 收件人
+	Subject     string        `bson:"subject"`           // 主题
+	Text        string        `bson:"text"`              // 纯文本正文
+	Html        string        `bson:"html"`              // HTML正文
+	Status      string        `bson:"status"`            // 发送状态
+	Attempts    int           `bson:"attempts"`          // 已尝试次数
+	LastError   string        `bson:"last_error"`        // 最近一次失败原因
+	NextRetryAt time.Time     `bson:"next_retry_at"`     // 下次发送时间
+	SentAt      *time.Time    `bson:"sent_at,omitempty"` // 发送成功时间
+}
+
+type IOutboxDao interface {
+	Create(ctx context.Context, outbox *Outbox) error
+	ClaimNext(ctx context.Context, now time.Time) (*Outbox, error)
+	MarkSent(ctx context.Context, id bson.ObjectID) error
+	MarkFailed(ctx context.Context, id bson.ObjectID, status string, lastError string, nextRetryAt time.Time) error
+	ResetStale(ctx context.Context, before time.Time) (int64, error)
+}
+
+var _ IOutboxDao = (*OutboxDao)(nil)
+
+func NewOutboxDao(db *mongo.Database) *OutboxDao {
+	return &OutboxDao{coll: db.Collection("mail_outbox")}
+}
+
+type OutboxDao struct {
+	coll *mongo.Collection
+}
+
+// Create 写入发件箱
+func (d *OutboxDao) Create(ctx context.Context, outbox *Outbox) error {
+	outbox.ID = bson.NewObjectID()
+	outbox.CreatedAt = time.Now()
+	outbox.UpdatedAt = time.Now()
+	res, err := d.coll.InsertOne(ctx, outbox)
+	if err != nil {
+		return err
+	}
+	if res.InsertedID == nil {
+		return errors.New("写入发件箱失败")
+	}
+	return nil
+}
+
+// ClaimNext 领取一封到期的待发送邮件并标记为发送中, 没有待发送邮件时返回 mongo.ErrNoDocuments
+func (d *OutboxDao) ClaimNext(ctx context.Context, now time.Time) (*Outbox, error) {
+	filter := bson.M{
+		"status":        "pending",
+		"next_retry_at": bson.M{"$lte": now},
+	}
+	update := bson.M{
+		"$set": bson.M{"status": "sending", "updated_at": now},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.M{"next_retry_at": 1}).
+		SetReturnDocument(options.After)
+
+	var outbox Outbox
+	if err := d.coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&outbox); err != nil {
+		return nil, err
+	}
+	return &outbox, nil
+}
+
+// MarkSent 标记为发送成功
+func (d *OutboxDao) MarkSent(ctx context.Context, id bson.ObjectID) error {
+	now := time.Now()
+	update := bson.M{"$set": bson.M{"status": "sent", "sent_at": now, "last_error": "", "updated_at": now}}
+	_, err := d.coll.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+// MarkFailed 记录发送失败, status为pending时在nextRetryAt后重试
+func (d *OutboxDao) MarkFailed(ctx context.Context, id bson.ObjectID, status string, lastError string, nextRetryAt time.Time) error {
+	update := bson.M{"$set": bson.M{
+		"status":        status,
+		"last_error":    lastError,
+		"next_retry_at": nextRetryAt,
+		"updated_at":    time.Now(),
+	}}
+	_, err := d.coll.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+// ResetStale 将长时间停留在发送中的邮件重置为待发送, 用于进程异常退出后的恢复
+func (d *OutboxDao) ResetStale(ctx context.Context, before time.Time) (int64, error) {
+	filter := bson.M{"status": "sending", "updated_at": bson.M{"$lt": before}}
+	update := bson.M{"$set": bson.M{"status": "pending", "updated_at": time.Now()}}
+	res, err := d.coll.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
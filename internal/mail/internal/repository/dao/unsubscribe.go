@@ -0,0 +1,47 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type Unsubscribe struct {
+	ID        bson.ObjectID `bson:"_id,omitempty"`
+	CreatedAt time.Time     `bson:"created_at"`
+	Email     string        `bson:"email"` // 退订邮箱
+}
+
+type IUnsubscribeDao interface {
+	Create(ctx context.Context, email string) error
+	Exists(ctx context.Context, email string) (bool, error)
+}
+
+var _ IUnsubscribeDao = (*UnsubscribeDao)(nil)
+
+func NewUnsubscribeDao(db *mongo.Database) *UnsubscribeDao {
+	return &UnsubscribeDao{coll: db.Collection("mail_unsubscribe")}
+}
+
+type UnsubscribeDao struct {
+	coll *mongo.Collection
+}
+
+// Create 记录退订邮箱, 重复退订时不做处理
+func (d *UnsubscribeDao) Create(ctx context.Context, email string) error {
+	update := bson.M{"$setOnInsert": bson.M{"_id": bson.NewObjectID(), "email": email, "created_at": time.Now()}}
+	_, err := d.coll.UpdateOne(ctx, bson.M{"email": email}, update, options.UpdateOne().SetUpsert(true))
+	return err
+}
+
+// Exists 判断邮箱是否已退订
+func (d *UnsubscribeDao) Exists(ctx context.Context, email string) (bool, error) {
+	count, err := d.coll.CountDocuments(ctx, bson.M{"email": email})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
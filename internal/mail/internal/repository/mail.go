@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/mail/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/mail/internal/repository/dao"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type IMailRepository interface {
+	Create(ctx context.Context, mail *domain.Mail) error
+	ClaimNext(ctx context.Context) (*domain.Mail, error)
+	MarkSent(ctx context.Context, id bson.ObjectID) error
+	MarkFailed(ctx context.Context, mail *domain.Mail) error
+	ResetStale(ctx context.Context, before time.Time) (int64, error)
+	Unsubscribe(ctx context.Context, email string) error
+	IsUnsubscribed(ctx context.Context, email string) (bool, error)
+}
+
+var _ IMailRepository = (*MailRepository)(nil)
+
+func NewMailRepository(outboxDao dao.IOutboxDao, unsubscribeDao dao.IUnsubscribeDao) *MailRepository {
+	return &MailRepository{outboxDao: outboxDao, unsubscribeDao: unsubscribeDao}
+}
+
+type MailRepository struct {
+	outboxDao      dao.IOutboxDao
+	unsubscribeDao dao.IUnsubscribeDao
+}
+
+// Create 写入发件箱
+func (r *MailRepository) Create(ctx context.Context, mail *domain.Mail) error {
+	return r.outboxDao.Create(ctx, &dao.Outbox{
+		To:          mail.To,
+		Subject:     mail.Subject,
+		Text:        mail.Text,
+		Html:        mail.Html,
+		Status:      mail.Status,
+		NextRetryAt: mail.NextRetryAt,
+	})
+}
+
+// ClaimNext 领取一封到期的待发送邮件
+func (r *MailRepository) ClaimNext(ctx context.Context) (*domain.Mail, error) {
+	outbox, err := r.outboxDao.ClaimNext(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return r.OutboxDaoToDomain(outbox), nil
+}
+
+// MarkSent 标记为发送成功
+func (r *MailRepository) MarkSent(ctx context.Context, id bson.ObjectID) error {
+	return r.outboxDao.MarkSent(ctx, id)
+}
+
+// MarkFailed 记录发送失败
+func (r *MailRepository) MarkFailed(ctx context.Context, mail *domain.Mail) error {
+	return r.outboxDao.MarkFailed(ctx, mail.Id, mail.Status, mail.LastError, mail.NextRetryAt)
+}
+
+// ResetStale 重置长时间停留在发送中的邮件
+func (r *MailRepository) ResetStale(ctx context.Context, before time.Time) (int64, error) {
+	return r.outboxDao.ResetStale(ctx, before)
+}
+
+// Unsubscribe 记录退订邮箱
+func (r *MailRepository) Unsubscribe(ctx context.Context, email string) error {
+	return r.unsubscribeDao.Create(ctx, strings.ToLower(email))
+}
+
+// IsUnsubscribed 判断邮箱是否已退订
+func (r *MailRepository) IsUnsubscribed(ctx context.Context, email string) (bool, error) {
+	return r.unsubscribeDao.Exists(ctx, strings.ToLower(email))
+}
+
+func (r *MailRepository) OutboxDaoToDomain(outbox *dao.Outbox) *domain.Mail {
+	var sentAt time.Time
+	if outbox.SentAt != nil {
+		sentAt = *outbox.SentAt
+	}
+	return &domain.Mail{
+		Id:          outbox.ID,
+		CreatedAt:   outbox.CreatedAt,
+		UpdatedAt:   outbox.UpdatedAt,
+		To:          outbox.To,
+		Subject:     outbox.Subject,
+		Text:        outbox.Text,
+		Html:        outbox.Html,
+		Status:      outbox.Status,
+		Attempts:    outbox.Attempts,
+		LastError:   outbox.LastError,
+		NextRetryAt: outbox.NextRetryAt,
+		SentAt:      sentAt,
+	}
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/mail/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/mail/internal/repository"
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/codepzj/Stellux-Server/internal/pkg/mailer"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const (
+	outboxPollInterval = 10 * time.Second // 发件箱轮询间隔
+	outboxSendTimeout  = 30 * time.Second // 单封邮件发送超时
+	outboxStaleAfter   = 10 * time.Minute // 发送中状态超过该时间视为进程异常退出
+	maxRetryBackoff    = time.Hour        // 重试最长间隔
+	defaultMaxRetry    = 5
+)
+
+type IMailService interface {
+	Enqueue(ctx context.Context, to string, subject string, tpl string, data map[string]any) error
+	NotifyCommentReply(ctx context.Context, notice *domain.ReplyNotice) error
+	NotifyCommentPending(ctx context.Context, notice *domain.PendingNotice) error
+	Unsubscribe(ctx context.Context, token string) error
+	StartOutboxWorker(ctx context.Context)
+}
+
+var _ IMailService = (*MailService)(nil)
+
+func NewMailService(repo repository.IMailRepository, m mailer.Mailer, cfg *conf.Config) *MailService {
+	// 退订令牌不过期, 密钥为空时任何人都可以伪造令牌
+	if cfg.Mail.Secret == "" {
+		panic(errors.New("未配置Mail.SECRET, 无法签发退订令牌"))
+	}
+	maxRetry := cfg.Mail.MaxRetry
+	if maxRetry <= 0 {
+		maxRetry = defaultMaxRetry
+	}
+	return &MailService{
+		repo:     repo,
+		mailer:   m,
+		cfg:      cfg.Mail,
+		maxRetry: maxRetry,
+		token:    NewUnsubscribeToken(cfg.Mail.Secret),
+	}
+}
+
+type MailService struct {
+	repo     repository.IMailRepository
+	mailer   mailer.Mailer
+	cfg      conf.Mail
+	maxRetry int
+	token    *UnsubscribeToken
+}
+
+// Enqueue 渲染模板并写入发件箱, 收件人已退订时跳过
+func (s *MailService) Enqueue(ctx context.Context, to string, subject string, tpl string, data map[string]any) error {
+	if to == "" {
+		return errors.New("收件人不能为空")
+	}
+	unsubscribed, err := s.repo.IsUnsubscribed(ctx, to)
+	if err != nil {
+		logger.Error("查询退订状态失败",
+			logger.WithError(err),
+			logger.WithString("to", to),
+		)
+		return err
+	}
+	if unsubscribed {
+		logger.Info("收件人已退订, 跳过发送",
+			logger.WithString("to", to),
+			logger.WithString("template", tpl),
+		)
+		return nil
+	}
+
+	data["UnsubscribeURL"] = s.unsubscribeURL(to)
+	text, html, err := renderTemplate(tpl, data)
+	if err != nil {
+		logger.Error("渲染邮件模板失败",
+			logger.WithError(err),
+			logger.WithString("template", tpl),
+		)
+		return err
+	}
+
+	err = s.repo.Create(ctx, &domain.Mail{
+		To:          to,
+		Subject:     subject,
+		Text:        text,
+		Html:        html,
+		Status:      domain.MailStatusPending,
+		NextRetryAt: time.Now(),
+	})
+	if err != nil {
+		logger.Error("写入发件箱失败",
+			logger.WithError(err),
+			logger.WithString("to", to),
+		)
+		return err
+	}
+	return nil
+}
+
+// NotifyCommentReply 通知被回复的评论者
+func (s *MailService) NotifyCommentReply(ctx context.Context, notice *domain.ReplyNotice) error {
+	return s.Enqueue(ctx, notice.To, "你的评论收到了新回复", "comment_reply", map[string]any{
+		"ParentNickname": notice.ParentNickname,
+		"ParentContent":  notice.ParentContent,
+		"Nickname":       notice.Nickname,
+		"Content":        notice.Content,
+		"Path":           notice.Path,
+		"Link":           s.siteLink(notice.Path),
+	})
+}
+
+// NotifyCommentPending 通知管理员有新评论待审核, 未配置管理员邮箱时跳过
+func (s *MailService) NotifyCommentPending(ctx context.Context, notice *domain.PendingNotice) error {
+	if s.cfg.AdminEmail == "" {
+		return nil
+	}
+	return s.Enqueue(ctx, s.cfg.AdminEmail, "有新的评论等待审核", "comment_pending", map[string]any{
+		"Nickname": notice.Nickname,
+		"Email":    notice.Email,
+		"Content":  notice.Content,
+		"Path":     notice.Path,
+		"Link":     s.siteLink(notice.Path),
+	})
+}
+
+// Unsubscribe 根据退订令牌退订邮件通知
+func (s *MailService) Unsubscribe(ctx context.Context, token string) error {
+	email, err := s.token.Parse(token)
+	if err != nil {
+		logger.Warn("退订令牌校验失败",
+			logger.WithError(err),
+		)
+		return err
+	}
+	if err := s.repo.Unsubscribe(ctx, email); err != nil {
+		logger.Error("退订失败",
+			logger.WithError(err),
+			logger.WithString("email", email),
+		)
+		return err
+	}
+
+	logger.Info("退订成功",
+		logger.WithString("email", email),
+	)
+	return nil
+}
+
+// StartOutboxWorker 启动发件箱投递协程, 按间隔轮询到期邮件并发送, ctx取消后退出
+func (s *MailService) StartOutboxWorker(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(outboxPollInterval)
+		defer ticker.Stop()
+		for {
+			s.resetStale(ctx)
+			s.drainOutbox(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	logger.Info("邮件发件箱投递协程已启动")
+}
+
+// drainOutbox 发送所有到期的邮件
+func (s *MailService) drainOutbox(ctx context.Context) {
+	for ctx.Err() == nil {
+		mail, err := s.repo.ClaimNext(ctx)
+		if err != nil {
+			if !errors.Is(err, mongo.ErrNoDocuments) {
+				logger.Error("领取待发送邮件失败",
+					logger.WithError(err),
+				)
+			}
+			return
+		}
+		s.deliver(ctx, mail)
+	}
+}
+
+// deliver 发送单封邮件, 失败时按指数退避安排重试
+func (s *MailService) deliver(ctx context.Context, mail *domain.Mail) {
+	sendCtx, cancel := context.WithTimeout(ctx, outboxSendTimeout)
+	defer cancel()
+
+	err := s.mailer.Send(sendCtx, &mailer.Message{
+		To:      mail.To,
+		Subject: mail.Subject,
+		Text:    mail.Text,
+		Html:    mail.Html,
+	})
+	if err == nil {
+		if err := s.repo.MarkSent(ctx, mail.Id); err != nil {
+			logger.Error("更新邮件状态失败",
+				logger.WithError(err),
+				logger.WithString("mailId", mail.Id.Hex()),
+			)
+		}
+		logger.Info("邮件发送成功",
+			logger.WithString("to", mail.To),
+			logger.WithString("subject", mail.Subject),
+		)
+		return
+	}
+
+	mail.LastError = err.Error()
+	if mail.Attempts >= s.maxRetry {
+		mail.Status = domain.MailStatusFailed
+	} else {
+		mail.Status = domain.MailStatusPending
+		mail.NextRetryAt = time.Now().Add(retryBackoff(mail.Attempts))
+	}
+	logger.Warn("邮件发送失败",
+		logger.WithError(err),
+		logger.WithString("to", mail.To),
+		logger.WithInt("attempts", mail.Attempts),
+		logger.WithString("status", mail.Status),
+	)
+	if err := s.repo.MarkFailed(ctx, mail); err != nil {
+		logger.Error("更新邮件状态失败",
+			logger.WithError(err),
+			logger.WithString("mailId", mail.Id.Hex()),
+		)
+	}
+}
+
+// resetStale 恢复进程异常退出时遗留的发送中邮件
+func (s *MailService) resetStale(ctx context.Context) {
+	count, err := s.repo.ResetStale(ctx, time.Now().Add(-outboxStaleAfter))
+	if err != nil {
+		logger.Error("重置发送中邮件失败",
+			logger.WithError(err),
+		)
+		return
+	}
+	if count > 0 {
+		logger.Warn("已重置发送中邮件",
+			logger.WithInt("count", int(count)),
+		)
+	}
+}
+
+// retryBackoff 第n次失败后的重试间隔, 1分钟起按2倍递增
+func retryBackoff(attempts int) time.Duration {
+	if attempts <= 0 || attempts > 7 {
+		return maxRetryBackoff
+	}
+	return min(time.Minute<<(attempts-1), maxRetryBackoff)
+}
+
+func (s *MailService) siteLink(path string) string {
+	return strings.TrimRight(s.cfg.SiteURL, "/") + path
+}
+
+func (s *MailService) unsubscribeURL(email string) string {
+	return strings.TrimRight(s.cfg.ServerURL, "/") + "/mail/unsubscribe?token=" + url.QueryEscape(s.token.Issue(email))
+}
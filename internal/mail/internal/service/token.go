@@ -0,0 +1,52 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// UnsubscribeToken 签发和校验退订令牌, 令牌中包含邮箱和签名, 不会过期
+type UnsubscribeToken struct {
+	key []byte
+}
+
+// unsubscribeKeyLabel 由配置的密钥派生退订令牌专用的签名密钥, 避免与其他用途共用同一密钥
+const unsubscribeKeyLabel = "mail-unsubscribe"
+
+func NewUnsubscribeToken(secret string) *UnsubscribeToken {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsubscribeKeyLabel))
+	return &UnsubscribeToken{key: mac.Sum(nil)}
+}
+
+// Issue 签发令牌, 格式为 base64(邮箱).base64(签名)
+func (t *UnsubscribeToken) Issue(email string) string {
+	payload := []byte(strings.ToLower(email))
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(t.sign(payload))
+}
+
+// Parse 校验令牌并返回邮箱
+func (t *UnsubscribeToken) Parse(token string) (string, error) {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return "", errors.New("退订令牌格式错误")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || len(payload) == 0 {
+		return "", errors.New("退订令牌格式错误")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(sig, t.sign(payload)) {
+		return "", errors.New("退订令牌无效")
+	}
+	return string(payload), nil
+}
+
+func (t *UnsubscribeToken) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, t.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
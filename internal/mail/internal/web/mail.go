@@ -0,0 +1,33 @@
+package web
+
+import (
+	"github.com/codepzj/Stellux-Server/internal/mail/internal/service"
+	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+	"github.com/gin-gonic/gin"
+)
+
+func NewMailHandler(serv service.IMailService) *MailHandler {
+	return &MailHandler{
+		serv: serv,
+	}
+}
+
+type MailHandler struct {
+	serv service.IMailService
+}
+
+func (h *MailHandler) RegisterGinRoutes(engine *gin.Engine) {
+	mailGroup := engine.Group("/mail")
+	{
+		mailGroup.GET("/unsubscribe", apiwrap.WrapWithQuery(h.Unsubscribe)) // 退订邮件通知
+	}
+}
+
+// Unsubscribe 退订邮件通知
+func (h *MailHandler) Unsubscribe(c *gin.Context, req UnsubscribeRequest) (int, string, any) {
+	err := h.serv.Unsubscribe(c, req.Token)
+	if err != nil {
+		return 400, err.Error(), nil
+	}
+	return 200, "退订成功", nil
+}
@@ -0,0 +1,6 @@
+package web
+
+// UnsubscribeRequest 退订请求
+type UnsubscribeRequest struct {
+	Token string `form:"token" binding:"required"`
+}
@@ -0,0 +1,18 @@
+package mail
+
+import (
+	"github.com/codepzj/Stellux-Server/internal/mail/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/mail/internal/service"
+	"github.com/codepzj/Stellux-Server/internal/mail/internal/web"
+)
+
+type (
+	Handler       = web.MailHandler
+	Service       = service.IMailService
+	ReplyNotice   = domain.ReplyNotice
+	PendingNotice = domain.PendingNotice
+	Module        struct {
+		Svc Service
+		Hdl *Handler
+	}
+)
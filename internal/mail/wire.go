@@ -0,0 +1,27 @@
+//go:build wireinject
+
+package mail
+
+import (
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/mail/internal/repository"
+	"github.com/codepzj/Stellux-Server/internal/mail/internal/repository/dao"
+	"github.com/codepzj/Stellux-Server/internal/mail/internal/service"
+	"github.com/codepzj/Stellux-Server/internal/mail/internal/web"
+	"github.com/codepzj/Stellux-Server/internal/pkg/mailer"
+	"github.com/google/wire"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var MailProviders = wire.NewSet(web.NewMailHandler, service.NewMailService, repository.NewMailRepository, dao.NewOutboxDao, dao.NewUnsubscribeDao,
+	wire.Bind(new(service.IMailService), new(*service.MailService)),
+	wire.Bind(new(repository.IMailRepository), new(*repository.MailRepository)),
+	wire.Bind(new(dao.IOutboxDao), new(*dao.OutboxDao)),
+	wire.Bind(new(dao.IUnsubscribeDao), new(*dao.UnsubscribeDao)))
+
+func InitMailModule(mongoDB *mongo.Database, m mailer.Mailer, cfg *conf.Config) *Module {
+	panic(wire.Build(
+		MailProviders,
+		wire.Struct(new(Module), "Svc", "Hdl"),
+	))
+}
@@ -0,0 +1,145 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// 支持的图片格式, 与image.Decode返回的格式名一致
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatGIF  = "gif"
+	FormatWebP = "webp"
+)
+
+const (
+	jpegQuality = 82
+	MaxPixels   = 40_000_000 // 解码前校验像素数, 防止超大图片耗尽内存
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("不支持的图片格式")
+	ErrTooLarge          = errors.New("图片尺寸过大")
+)
+
+// Config 图片的格式和尺寸
+type Config struct {
+	Format string
+	Width  int
+	Height int
+}
+
+// DecodeConfig 只读取图片头部信息, 非图片返回ErrUnsupportedFormat
+func DecodeConfig(r io.Reader) (*Config, error) {
+	cfg, format, err := image.DecodeConfig(r)
+	if errors.Is(err, image.ErrFormat) {
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Config{Format: format, Width: cfg.Width, Height: cfg.Height}, nil
+}
+
+// Decode 解码图片, 先读取头部校验像素数再完整解码
+func Decode(data []byte) (image.Image, string, error) {
+	cfg, err := DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return nil, "", ErrTooLarge
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if errors.Is(err, image.ErrFormat) {
+		return nil, "", ErrUnsupportedFormat
+	}
+	return img, format, err
+}
+
+// Resize 按宽度等比缩放, 不放大图片
+func Resize(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	if width <= 0 || width >= bounds.Dx() {
+		return img
+	}
+	height := max(1, bounds.Dy()*width/bounds.Dx())
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// Encode 按指定格式编码图片, WebP使用纯Go的无损编码器
+func Encode(w io.Writer, img image.Image, format string) error {
+	switch format {
+	case FormatJPEG:
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+	case FormatPNG:
+		return (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(w, img)
+	case FormatGIF:
+		return gif.Encode(w, img, nil)
+	case FormatWebP:
+		return nativewebp.Encode(w, img, nil)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
+
+// IsOpaque 图片是否不含透明像素
+func IsOpaque(img image.Image) bool {
+	if opaque, ok := img.(interface{ Opaque() bool }); ok {
+		return opaque.Opaque()
+	}
+	return false
+}
+
+// VariantFormats 生成缩略图使用的格式
+// 纯Go的WebP编码器只支持无损压缩, 对照片效果不如JPEG, 因此只为PNG这类无损图片额外生成WebP
+func VariantFormats(format string, img image.Image) []string {
+	switch format {
+	case FormatJPEG:
+		return []string{FormatJPEG}
+	case FormatPNG:
+		return []string{FormatPNG, FormatWebP}
+	case FormatWebP:
+		if IsOpaque(img) {
+			return []string{FormatJPEG}
+		}
+		return []string{FormatPNG, FormatWebP}
+	default:
+		// GIF可能是动图, 缩放后会丢失动画
+		return nil
+	}
+}
+
+// ContentType 格式对应的MIME类型
+func ContentType(format string) string {
+	return "image/" + format
+}
+
+// Ext 格式对应的扩展名
+func Ext(format string) string {
+	if format == FormatJPEG {
+		return ".jpg"
+	}
+	return "." + format
+}
+
+// IsFormat 是否为支持输出的格式
+func IsFormat(format string) bool {
+	switch format {
+	case FormatJPEG, FormatPNG, FormatGIF, FormatWebP:
+		return true
+	}
+	return false
+}
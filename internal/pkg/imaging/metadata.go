@@ -0,0 +1,139 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	markerSOI  = 0xD8
+	markerEOI  = 0xD9
+	markerSOS  = 0xDA
+	markerAPP0 = 0xE0
+	markerAPP1 = 0xE1 // EXIF、XMP
+	markerIPTC = 0xED // APP13, Photoshop IPTC
+	markerCOM  = 0xFE
+
+	tagOrientation = 0x0112
+)
+
+var ErrInvalidJPEG = errors.New("无效的JPEG文件")
+
+var exifHeader = []byte("Exif\x00\x00")
+
+// StripJPEGMetadata 删除JPEG中的EXIF(含GPS)、XMP、IPTC和注释, 不重新编码图像数据
+// 方向信息会影响显示, 删除EXIF后单独写回只含方向的EXIF段; ICC色彩配置(APP2)和Adobe(APP14)段保留
+func StripJPEGMetadata(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != markerSOI {
+		return nil, ErrInvalidJPEG
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, 0xFF, markerSOI)
+	insertAt := len(out) // 方向段写在SOI或紧随其后的JFIF段之后
+	orientation := 0
+	for pos := 2; ; {
+		if pos+2 > len(data) || data[pos] != 0xFF {
+			return nil, ErrInvalidJPEG
+		}
+		marker := data[pos+1]
+		switch {
+		case marker == 0xFF:
+			// 填充字节
+			pos++
+			continue
+		case marker == 0x01 || (marker >= 0xD0 && marker <= markerSOI):
+			// 没有长度的标记
+			out = append(out, 0xFF, marker)
+			pos += 2
+			continue
+		case marker == markerEOI:
+			return append(out, data[pos:]...), nil
+		}
+
+		if pos+4 > len(data) {
+			return nil, ErrInvalidJPEG
+		}
+		end := pos + 2 + int(binary.BigEndian.Uint16(data[pos+2:]))
+		if end < pos+4 || end > len(data) {
+			return nil, ErrInvalidJPEG
+		}
+		segment := data[pos:end]
+		switch marker {
+		case markerSOS:
+			// 之后为图像数据, 原样保留
+			if orientation > 1 {
+				out = append(out[:insertAt], append(orientationSegment(orientation), out[insertAt:]...)...)
+			}
+			return append(out, data[pos:]...), nil
+		case markerAPP1:
+			if o := exifOrientation(segment[4:]); o > 0 {
+				orientation = o
+			}
+		case markerIPTC, markerCOM:
+		default:
+			out = append(out, segment...)
+			if marker == markerAPP0 && insertAt == 2 {
+				insertAt = len(out)
+			}
+		}
+		pos = end
+	}
+}
+
+// exifOrientation 读取EXIF中IFD0的方向, 没有或无法解析时返回0
+func exifOrientation(payload []byte) int {
+	if !bytes.HasPrefix(payload, exifHeader) {
+		return 0
+	}
+	tiff := payload[len(exifHeader):]
+	if len(tiff) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 0
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for i := range count {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) == tagOrientation {
+			if value := int(order.Uint16(tiff[entry+8:])); value >= 1 && value <= 8 {
+				return value
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// orientationSegment 只包含方向的APP1段
+func orientationSegment(orientation int) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{0xFF, markerAPP1, 0, 34})
+	buf.Write(exifHeader)
+	buf.WriteString("MM\x00\x2a")                                // 大端TIFF头
+	binary.Write(&buf, binary.BigEndian, uint32(8))              // IFD0偏移
+	binary.Write(&buf, binary.BigEndian, uint16(1))              // 条目数
+	binary.Write(&buf, binary.BigEndian, uint16(tagOrientation)) // 标签
+	binary.Write(&buf, binary.BigEndian, uint16(3))              // SHORT类型
+	binary.Write(&buf, binary.BigEndian, uint32(1))              // 数量
+	binary.Write(&buf, binary.BigEndian, uint16(orientation))    // 值, 不足4字节左对齐
+	binary.Write(&buf, binary.BigEndian, uint16(0))
+	binary.Write(&buf, binary.BigEndian, uint32(0)) // 没有下一个IFD
+	return buf.Bytes()
+}
@@ -0,0 +1,207 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+var (
+	gpsMarker = []byte("GPS-LATITUDE-31.2304")
+	iccMarker = []byte("ICC_PROFILE\x00\x01\x01stellux-icc")
+)
+
+// segment 构造带长度的JPEG段
+func segment(marker byte, payload []byte) []byte {
+	b := []byte{0xFF, marker, 0, 0}
+	binary.BigEndian.PutUint16(b[2:], uint16(len(payload)+2))
+	return append(b, payload...)
+}
+
+// exifPayload 构造包含方向和GPS IFD的EXIF, orientation为0时不写方向
+func exifPayload(order binary.ByteOrder, orientation int) []byte {
+	var buf bytes.Buffer
+	buf.Write(exifHeader)
+	if order == binary.LittleEndian {
+		buf.WriteString("II\x2a\x00")
+	} else {
+		buf.WriteString("MM\x00\x2a")
+	}
+	entries := [][3]uint32{{0x8825, 4, 38}} // GPS IFD指针
+	if orientation > 0 {
+		entries = append([][3]uint32{{tagOrientation, 3, uint32(orientation)}}, entries...)
+	}
+	binary.Write(&buf, order, uint32(8))
+	binary.Write(&buf, order, uint16(len(entries)))
+	for _, e := range entries {
+		binary.Write(&buf, order, uint16(e[0]))
+		binary.Write(&buf, order, uint16(e[1]))
+		binary.Write(&buf, order, uint32(1))
+		if e[1] == 3 {
+			binary.Write(&buf, order, uint16(e[2]))
+			binary.Write(&buf, order, uint16(0))
+		} else {
+			binary.Write(&buf, order, e[2])
+		}
+	}
+	binary.Write(&buf, order, uint32(0))
+	buf.Write(gpsMarker)
+	return buf.Bytes()
+}
+
+// testJPEG 编码一张小图, 在SOI后插入指定的段
+func testJPEG(t *testing.T, segments ...[]byte) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for x := range 8 {
+		img.Set(x, x, color.RGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+	data := []byte{0xFF, markerSOI}
+	for _, s := range segments {
+		data = append(data, s...)
+	}
+	return append(data, encoded[2:]...)
+}
+
+// app1Orientations 返回输出中所有APP1段解析出的方向
+func app1Orientations(data []byte) []int {
+	var orientations []int
+	for pos := 2; pos+4 <= len(data) && data[pos] == 0xFF && data[pos+1] != markerSOS; {
+		end := pos + 2 + int(binary.BigEndian.Uint16(data[pos+2:]))
+		if data[pos+1] == markerAPP1 {
+			orientations = append(orientations, exifOrientation(data[pos+4:end]))
+		}
+		pos = end
+	}
+	return orientations
+}
+
+func TestStripJPEGMetadata(t *testing.T) {
+	jfif := segment(markerAPP0, []byte("JFIF\x00\x01\x01\x00\x00\x01\x00\x01\x00\x00"))
+	icc := segment(0xE2, iccMarker)
+	xmp := segment(markerAPP1, []byte("http://ns.adobe.com/xap/1.0/\x00<x:xmpmeta>GPS-LATITUDE-31.2304</x:xmpmeta>"))
+	iptc := segment(markerIPTC, []byte("Photoshop 3.0\x00secret-caption"))
+	comment := segment(markerCOM, []byte("secret-comment"))
+
+	tests := []struct {
+		name            string
+		data            []byte
+		wantOrientation []int    // 输出中APP1段的方向, 删除后只应剩下写回的方向段
+		wantKept        [][]byte // 输出中应保留的内容
+	}{
+		{
+			name:            "删除EXIF中的GPS, 保留方向",
+			data:            testJPEG(t, jfif, segment(markerAPP1, exifPayload(binary.BigEndian, 6)), icc),
+			wantOrientation: []int{6},
+			wantKept:        [][]byte{jfif, icc},
+		},
+		{
+			name:            "小端EXIF",
+			data:            testJPEG(t, segment(markerAPP1, exifPayload(binary.LittleEndian, 8)), icc),
+			wantOrientation: []int{8},
+			wantKept:        [][]byte{icc},
+		},
+		{
+			name:     "方向为正常时不写回EXIF",
+			data:     testJPEG(t, jfif, segment(markerAPP1, exifPayload(binary.BigEndian, 1))),
+			wantKept: [][]byte{jfif},
+		},
+		{
+			name: "没有方向的EXIF",
+			data: testJPEG(t, segment(markerAPP1, exifPayload(binary.BigEndian, 0))),
+		},
+		{
+			name:            "删除XMP、IPTC和注释",
+			data:            testJPEG(t, jfif, xmp, segment(markerAPP1, exifPayload(binary.BigEndian, 3)), iptc, comment, icc),
+			wantOrientation: []int{3},
+			wantKept:        [][]byte{jfif, icc},
+		},
+		{
+			name:     "没有元数据",
+			data:     testJPEG(t, jfif),
+			wantKept: [][]byte{jfif},
+		},
+		{
+			name: "EXIF的IFD偏移越界",
+			data: testJPEG(t, segment(markerAPP1, append(append([]byte{}, exifHeader...), "MM\x00\x2a\xff\xff\xff\xff"...))),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := StripJPEGMetadata(tt.data)
+			if err != nil {
+				t.Fatalf("StripJPEGMetadata() error = %v", err)
+			}
+			for _, removed := range [][]byte{gpsMarker, []byte("secret-caption"), []byte("secret-comment")} {
+				if bytes.Contains(got, removed) {
+					t.Errorf("输出中仍包含 %q", removed)
+				}
+			}
+			for _, kept := range tt.wantKept {
+				if !bytes.Contains(got, kept) {
+					t.Errorf("输出中缺少 %q", kept)
+				}
+			}
+			orientations := app1Orientations(got)
+			if len(orientations) != len(tt.wantOrientation) || (len(orientations) > 0 && orientations[0] != tt.wantOrientation[0]) {
+				t.Errorf("APP1方向 = %v, want %v", orientations, tt.wantOrientation)
+			}
+			// 方向段写在SOI或JFIF段之后
+			if len(tt.wantOrientation) > 0 {
+				insertAt := 2
+				if bytes.HasPrefix(got[2:], jfif) {
+					insertAt += len(jfif)
+				}
+				if got[insertAt] != 0xFF || got[insertAt+1] != markerAPP1 {
+					t.Errorf("方向段位置错误, offset %d 处为 % x", insertAt, got[insertAt:insertAt+2])
+				}
+			}
+			if _, err := jpeg.Decode(bytes.NewReader(got)); err != nil {
+				t.Errorf("输出无法解码: %v", err)
+			}
+		})
+	}
+}
+
+func TestStripJPEGMetadataInvalid(t *testing.T) {
+	valid := testJPEG(t, segment(markerAPP1, exifPayload(binary.BigEndian, 6)))
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "空数据", data: nil},
+		{name: "不是JPEG", data: []byte("\x89PNG\r\n\x1a\n")},
+		{name: "只有SOI", data: []byte{0xFF, markerSOI}},
+		{name: "SOI后不是标记", data: []byte{0xFF, markerSOI, 0x00, 0x00}},
+		{name: "长度字段被截断", data: []byte{0xFF, markerSOI, 0xFF, markerAPP1, 0x00}},
+		{name: "长度小于2", data: []byte{0xFF, markerSOI, 0xFF, markerAPP1, 0x00, 0x01, 0x00, 0x00}},
+		{name: "长度为0", data: []byte{0xFF, markerSOI, 0xFF, markerAPP0, 0x00, 0x00, 0xFF, markerEOI}},
+		{name: "长度超出文件", data: []byte{0xFF, markerSOI, 0xFF, markerAPP1, 0xFF, 0xFF, 'E', 'x'}},
+		{name: "SOS的长度超出文件", data: []byte{0xFF, markerSOI, 0xFF, markerSOS, 0x00, 0x10}},
+		{name: "没有SOS和EOI", data: append([]byte{0xFF, markerSOI}, segment(markerAPP0, []byte("JFIF\x00"))...)},
+		{name: "只有填充字节", data: []byte{0xFF, markerSOI, 0xFF, 0xFF, 0xFF}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := StripJPEGMetadata(tt.data); !errors.Is(err, ErrInvalidJPEG) {
+				t.Errorf("StripJPEGMetadata() error = %v, want ErrInvalidJPEG", err)
+			}
+		})
+	}
+
+	// 任意位置截断都只能返回错误或结果, 不能panic
+	t.Run("截断", func(t *testing.T) {
+		for i := range len(valid) {
+			_, _ = StripJPEGMetadata(valid[:i])
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package limiter
+
+import (
+	"context"
+	"time"
+)
+
+// Policy 失败限制策略
+// 前FreeAttempts次失败不受限制, 之后每次尝试前需等待BaseDelay*2^(n-FreeAttempts), 最长MaxDelay
+// 连续失败达到MaxFailures次后锁定LockDuration, 最近一次失败超过Window后失败次数清零
+type Policy struct {
+	FreeAttempts int
+	MaxFailures  int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	LockDuration time.Duration
+	Window       time.Duration
+}
+
+// Limiter 按键统计连续失败次数, 实现指数退避和临时锁定
+type Limiter struct {
+	store  Store
+	policy Policy
+}
+
+func New(store Store, policy Policy) *Limiter {
+	return &Limiter{store: store, policy: policy}
+}
+
+// Check 返回下一次尝试前还需等待的时长, 为0时允许尝试, locked表示处于锁定中
+func (l *Limiter) Check(ctx context.Context, key string) (wait time.Duration, locked bool, err error) {
+	record, err := l.store.Get(ctx, key)
+	if err != nil || record == nil {
+		return 0, false, err
+	}
+	now := time.Now()
+	if record.Locked(now) {
+		return record.LockedUntil.Sub(now), true, nil
+	}
+	if wait = record.LastFailureAt.Add(l.delay(record.Failures)).Sub(now); wait > 0 {
+		return wait, false, nil
+	}
+	return 0, false, nil
+}
+
+// Fail 记录一次失败, 达到最大失败次数时锁定
+func (l *Limiter) Fail(ctx context.Context, key string) (*Record, error) {
+	now := time.Now()
+	record, err := l.store.Incr(ctx, key, now, l.policy.Window)
+	if err != nil {
+		return nil, err
+	}
+	if l.policy.MaxFailures > 0 && record.Failures >= l.policy.MaxFailures && !record.Locked(now) {
+		record.LockedUntil = now.Add(l.policy.LockDuration)
+		if err = l.store.Lock(ctx, key, record.LockedUntil); err != nil {
+			return nil, err
+		}
+	}
+	return record, nil
+}
+
+// Reset 清空失败记录
+func (l *Limiter) Reset(ctx context.Context, key string) error {
+	return l.store.Delete(ctx, key)
+}
+
+// delay 连续失败failures次后下一次尝试前需等待的时长
+func (l *Limiter) delay(failures int) time.Duration {
+	n := failures - l.policy.FreeAttempts
+	if n <= 0 || l.policy.BaseDelay <= 0 {
+		return 0
+	}
+	delay := l.policy.BaseDelay
+	for i := 1; i < n; i++ {
+		if l.policy.MaxDelay > 0 && delay >= l.policy.MaxDelay {
+			break
+		}
+		delay *= 2
+	}
+	if l.policy.MaxDelay > 0 {
+		delay = min(delay, l.policy.MaxDelay)
+	}
+	return delay
+}
@@ -0,0 +1,35 @@
+package limiter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiterDelay(t *testing.T) {
+	policy := Policy{FreeAttempts: 3, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	tests := []struct {
+		name     string
+		policy   Policy
+		failures int
+		want     time.Duration
+	}{
+		{name: "没有失败", policy: policy, failures: 0, want: 0},
+		{name: "免等待次数内", policy: policy, failures: 3, want: 0},
+		{name: "超出一次", policy: policy, failures: 4, want: time.Second},
+		{name: "超出两次", policy: policy, failures: 5, want: 2 * time.Second},
+		{name: "超出四次", policy: policy, failures: 7, want: 8 * time.Second},
+		{name: "达到上限", policy: policy, failures: 8, want: 10 * time.Second},
+		{name: "失败次数很大时不溢出", policy: policy, failures: 1000, want: 10 * time.Second},
+		{name: "没有免等待次数", policy: Policy{BaseDelay: time.Second}, failures: 1, want: time.Second},
+		{name: "没有上限", policy: Policy{BaseDelay: time.Second}, failures: 6, want: 32 * time.Second},
+		{name: "未设置基础等待", policy: Policy{MaxDelay: time.Minute}, failures: 10, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(NewMemoryStore(), tt.policy)
+			if got := l.delay(tt.failures); got != tt.want {
+				t.Errorf("delay(%d) = %v, want %v", tt.failures, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package limiter
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+var _ Store = (*MemoryStore)(nil)
+
+// MemoryStore 进程内存储, 重启后记录丢失, 仅适用于单实例部署
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]*Record
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]*Record)}
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record := s.get(key, time.Now())
+	if record == nil {
+		return nil, nil
+	}
+	copied := *record
+	return &copied, nil
+}
+
+func (s *MemoryStore) Incr(ctx context.Context, key string, now time.Time, window time.Duration) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record := s.get(key, now)
+	if record == nil {
+		record = &Record{Key: key}
+		s.records[key] = record
+	}
+	record.Failures++
+	record.LastFailureAt = now
+	record.ExpiresAt = later(record.ExpiresAt, now.Add(window))
+	copied := *record
+	return &copied, nil
+}
+
+func (s *MemoryStore) Lock(ctx context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record := s.get(key, time.Now())
+	if record == nil {
+		record = &Record{Key: key}
+		s.records[key] = record
+	}
+	record.LockedUntil = until
+	record.ExpiresAt = later(record.ExpiresAt, until)
+	return nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+func (s *MemoryStore) List(ctx context.Context) ([]*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	records := make([]*Record, 0, len(s.records))
+	for key := range s.records {
+		if record := s.get(key, now); record != nil {
+			copied := *record
+			records = append(records, &copied)
+		}
+	}
+	slices.SortFunc(records, func(a, b *Record) int {
+		return b.LastFailureAt.Compare(a.LastFailureAt)
+	})
+	return records, nil
+}
+
+// get 获取未过期的记录, 顺带清理已过期的记录, 调用方需持有锁
+func (s *MemoryStore) get(key string, now time.Time) *Record {
+	record, ok := s.records[key]
+	if !ok {
+		return nil
+	}
+	if !record.ExpiresAt.After(now) {
+		delete(s.records, key)
+		return nil
+	}
+	return record
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,129 @@
+package limiter
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var _ Store = (*MongoStore)(nil)
+
+type mongoRecord struct {
+	Key           string     `bson:"_id"`
+	Failures      int        `bson:"failures"`
+	LastFailureAt time.Time  `bson:"last_failure_at"`
+	LockedUntil   *time.Time `bson:"locked_until,omitempty"`
+	ExpiresAt     time.Time  `bson:"expires_at"`
+}
+
+// MongoStore 基于MongoDB的共享存储, 多实例部署时共用失败记录, 过期记录由TTL索引清理
+type MongoStore struct {
+	coll *mongo.Collection
+}
+
+func NewMongoStore(db *mongo.Database, collection string) *MongoStore {
+	s := &MongoStore{coll: db.Collection(collection)}
+	s.ensureIndexes()
+	return s
+}
+
+func (s *MongoStore) ensureIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := s.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		logger.Warn("创建限流记录索引失败",
+			logger.WithError(err),
+		)
+	}
+}
+
+func (s *MongoStore) Get(ctx context.Context, key string) (*Record, error) {
+	var record mongoRecord
+	err := s.coll.FindOne(ctx, bson.M{"_id": key, "expires_at": bson.M{"$gt": time.Now()}}).Decode(&record)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return record.toRecord(), nil
+}
+
+// Incr 使用聚合管道更新, 在同一次写操作中判断记录是否过期, 保证多实例并发时计数准确
+func (s *MongoStore) Incr(ctx context.Context, key string, now time.Time, window time.Duration) (*Record, error) {
+	alive := bson.M{"$gt": bson.A{"$expires_at", now}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"failures":        bson.M{"$cond": bson.A{alive, bson.M{"$add": bson.A{"$failures", 1}}, 1}},
+			"locked_until":    bson.M{"$cond": bson.A{alive, "$locked_until", "$$REMOVE"}},
+			"last_failure_at": now,
+			"expires_at": bson.M{"$cond": bson.A{
+				alive,
+				bson.M{"$max": bson.A{"$expires_at", now.Add(window)}},
+				now.Add(window),
+			}},
+		}}},
+	}
+	var record mongoRecord
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	if err := s.coll.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&record); err != nil {
+		return nil, err
+	}
+	return record.toRecord(), nil
+}
+
+func (s *MongoStore) Lock(ctx context.Context, key string, until time.Time) error {
+	update := bson.M{
+		"$set": bson.M{"locked_until": until},
+		"$max": bson.M{"expires_at": until},
+	}
+	_, err := s.coll.UpdateOne(ctx, bson.M{"_id": key}, update, options.UpdateOne().SetUpsert(true))
+	return err
+}
+
+func (s *MongoStore) Delete(ctx context.Context, key string) error {
+	_, err := s.coll.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
+
+func (s *MongoStore) List(ctx context.Context) ([]*Record, error) {
+	opts := options.Find().SetSort(bson.M{"last_failure_at": -1})
+	cursor, err := s.coll.Find(ctx, bson.M{"expires_at": bson.M{"$gt": time.Now()}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var records []*mongoRecord
+	if err = cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+	result := make([]*Record, 0, len(records))
+	for _, record := range records {
+		result = append(result, record.toRecord())
+	}
+	return result, nil
+}
+
+func (r *mongoRecord) toRecord() *Record {
+	record := &Record{
+		Key:           r.Key,
+		Failures:      r.Failures,
+		LastFailureAt: r.LastFailureAt,
+		ExpiresAt:     r.ExpiresAt,
+	}
+	if r.LockedUntil != nil {
+		record.LockedUntil = *r.LockedUntil
+	}
+	return record
+}
@@ -0,0 +1,34 @@
+package limiter
+
+import (
+	"context"
+	"time"
+)
+
+// Record 某个键的连续失败记录
+type Record struct {
+	Key           string
+	Failures      int       // 窗口内连续失败次数
+	LastFailureAt time.Time // 最近一次失败时间
+	LockedUntil   time.Time // 锁定截止时间, 零值表示未锁定
+	ExpiresAt     time.Time // 记录过期时间, 过期后失败次数重新计算
+}
+
+// Locked 在now时刻是否处于锁定中
+func (r *Record) Locked(now time.Time) bool {
+	return r.LockedUntil.After(now)
+}
+
+// Store 失败记录存储, 单实例部署可使用内存存储, 多实例部署需使用共享存储
+type Store interface {
+	// Get 获取未过期的记录, 不存在时返回nil
+	Get(ctx context.Context, key string) (*Record, error)
+	// Incr 原子地增加一次失败, 记录已过期时从1开始计数, 记录至少保留到now+window
+	Incr(ctx context.Context, key string, now time.Time, window time.Duration) (*Record, error)
+	// Lock 锁定到until, 记录至少保留到锁定结束
+	Lock(ctx context.Context, key string, until time.Time) error
+	// Delete 删除记录, 解除锁定并清空失败次数
+	Delete(ctx context.Context, key string) error
+	// List 获取全部未过期的记录, 按最近失败时间倒序
+	List(ctx context.Context) ([]*Record, error)
+}
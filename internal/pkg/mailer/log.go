@@ -0,0 +1,47 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+)
+
+var _ Mailer = (*LogMailer)(nil)
+
+// LogMailer 将邮件写入本地文件并打印日志, 用于本地开发调试
+type LogMailer struct {
+	dir  string
+	from mail.Address
+}
+
+func NewLogMailer(dir, from, fromName string) *LogMailer {
+	return &LogMailer{
+		dir:  dir,
+		from: mail.Address{Name: fromName, Address: from},
+	}
+}
+
+func (m *LogMailer) Send(_ context.Context, msg *Message) error {
+	logger.Info("发送邮件",
+		logger.WithString("to", msg.To),
+		logger.WithString("subject", msg.Subject),
+	)
+	if m.dir == "" {
+		return nil
+	}
+
+	data, err := buildMIME(m.from, msg)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.dir, os.ModePerm); err != nil {
+		return err
+	}
+	filename := filepath.Join(m.dir, fmt.Sprintf("%s.eml", time.Now().Format("20060102150405.000000000")))
+	return os.WriteFile(filename, data, 0o644)
+}
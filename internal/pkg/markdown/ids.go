@@ -0,0 +1,57 @@
+package markdown
+
+import (
+	"bytes"
+	"strconv"
+	"unicode"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+)
+
+// ids 标题锚点生成器, 保留中文等非ASCII字母, 同名标题依次追加-1、-2
+// 锚点只由标题文字和出现顺序决定, 内容不变时锚点不变
+type ids struct {
+	used map[string]struct{}
+}
+
+var _ parser.IDs = (*ids)(nil)
+
+func newIDs() *ids {
+	return &ids{used: map[string]struct{}{}}
+}
+
+func (s *ids) Generate(value []byte, kind ast.NodeKind) []byte {
+	var buf bytes.Buffer
+	dash := false
+	for _, r := range string(bytes.ToLower(bytes.TrimSpace(value))) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			buf.WriteRune(r)
+			dash = false
+		case unicode.IsSpace(r) || r == '-':
+			if buf.Len() > 0 && !dash {
+				buf.WriteByte('-')
+				dash = true
+			}
+		}
+	}
+	id := string(bytes.TrimRight(buf.Bytes(), "-"))
+	if id == "" {
+		id = "section"
+	}
+
+	unique := id
+	for i := 1; ; i++ {
+		if _, ok := s.used[unique]; !ok {
+			break
+		}
+		unique = id + "-" + strconv.Itoa(i)
+	}
+	s.used[unique] = struct{}{}
+	return []byte(unique)
+}
+
+func (s *ids) Put(value []byte) {
+	s.used[string(value)] = struct{}{}
+}
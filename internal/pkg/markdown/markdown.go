@@ -0,0 +1,238 @@
+package markdown
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"math"
+	"regexp"
+	"sync"
+	"unicode"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+const (
+	defaultCacheSize = 512
+	cjkPerMinute     = 400 // 中日韩文字每分钟阅读字数
+	wordsPerMinute   = 200 // 其他语言每分钟阅读单词数
+)
+
+// Rendered 渲染结果
+type Rendered struct {
+	HTML        string     // 净化后的HTML
+	Toc         []*TocItem // 目录树
+	WordCount   int        // 字数, 中日韩文字按字计, 其他按单词计
+	ReadingTime int        // 预计阅读分钟数
+}
+
+// TocItem 目录项, Id与HTML中标题的id属性一致
+type TocItem struct {
+	Level    int        `json:"level"`
+	Id       string     `json:"id"`
+	Title    string     `json:"title"`
+	Children []*TocItem `json:"children,omitempty"`
+}
+
+// Renderer 基于CommonMark和GFM扩展的Markdown渲染器, 按内容摘要缓存渲染结果
+type Renderer struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+	strict *bluemonday.Policy
+
+	mu       sync.Mutex
+	capacity int
+	entries  map[[sha256.Size]byte]*list.Element
+	lru      *list.List
+}
+
+type cacheEntry struct {
+	key      [sha256.Size]byte
+	rendered *Rendered
+}
+
+// NewRenderer cacheSize为缓存的渲染结果数量, 小于等于0时使用默认值
+func NewRenderer(cacheSize int) *Renderer {
+	if cacheSize <= 0 {
+		cacheSize = defaultCacheSize
+	}
+	return &Renderer{
+		md: goldmark.New(
+			goldmark.WithExtensions(extension.GFM),
+			goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+			// 原始HTML交给bluemonday净化
+			goldmark.WithRendererOptions(html.WithUnsafe()),
+		),
+		policy:   newPolicy(),
+		strict:   bluemonday.StrictPolicy(),
+		capacity: cacheSize,
+		entries:  map[[sha256.Size]byte]*list.Element{},
+		lru:      list.New(),
+	}
+}
+
+// newPolicy 在UGC策略基础上保留标题锚点、代码语言和任务列表
+func newPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("id").OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	policy.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+	return policy
+}
+
+// Render 渲染Markdown, 相同内容直接返回缓存结果, 返回值不可修改
+func (r *Renderer) Render(source string) *Rendered {
+	key := sha256.Sum256([]byte(source))
+	if rendered, ok := r.get(key); ok {
+		return rendered
+	}
+	rendered := r.render([]byte(source))
+	r.put(key, rendered)
+	return rendered
+}
+
+func (r *Renderer) render(source []byte) *Rendered {
+	ctx := parser.NewContext(parser.WithIDs(newIDs()))
+	doc := r.md.Parser().Parse(text.NewReader(source), parser.WithContext(ctx))
+
+	var buf bytes.Buffer
+	if err := r.md.Renderer().Render(&buf, source, doc); err != nil {
+		// 渲染器只写入内存, 出错时按空内容处理
+		buf.Reset()
+	}
+	sanitized := r.policy.SanitizeBytes(buf.Bytes())
+	wordCount := countWords(r.strict.SanitizeBytes(sanitized))
+	return &Rendered{
+		HTML:        string(sanitized),
+		Toc:         buildToc(doc, source),
+		WordCount:   wordCount.total(),
+		ReadingTime: wordCount.minutes(),
+	}
+}
+
+// buildToc 按标题层级生成目录树, 跳级的标题挂在最近的上级标题下
+func buildToc(doc ast.Node, source []byte) []*TocItem {
+	var root []*TocItem
+	var stack []*TocItem
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		item := &TocItem{Level: heading.Level, Title: nodeText(heading, source)}
+		if id, ok := heading.AttributeString("id"); ok {
+			if b, ok := id.([]byte); ok {
+				item.Id = string(b)
+			}
+		}
+		for len(stack) > 0 && stack[len(stack)-1].Level >= item.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			root = append(root, item)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, item)
+		}
+		stack = append(stack, item)
+		return ast.WalkSkipChildren, nil
+	})
+	return root
+}
+
+// nodeText 提取节点下的纯文本
+func nodeText(n ast.Node, source []byte) string {
+	var buf bytes.Buffer
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := c.(type) {
+		case *ast.Text:
+			buf.Write(node.Value(source))
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				buf.WriteByte(' ')
+			}
+		case *ast.String:
+			buf.Write(node.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return buf.String()
+}
+
+type wordCount struct {
+	cjk   int
+	words int
+}
+
+func (w wordCount) total() int {
+	return w.cjk + w.words
+}
+
+func (w wordCount) minutes() int {
+	if w.total() == 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(w.cjk)/cjkPerMinute + float64(w.words)/wordsPerMinute))
+}
+
+// countWords 中日韩文字逐字计数, 其余字母数字按连续片段计为一个单词
+func countWords(plain []byte) wordCount {
+	var count wordCount
+	inWord := false
+	for _, r := range string(plain) {
+		switch {
+		case isCJK(r):
+			count.cjk++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				count.words++
+				inWord = true
+			}
+		case r == '\'' || r == '-':
+			// 单词内的撇号和连字符不拆分
+		default:
+			inWord = false
+		}
+	}
+	return count
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+func (r *Renderer) get(key [sha256.Size]byte) (*Rendered, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	elem, ok := r.entries[key]
+	if !ok {
+		return nil, false
+	}
+	r.lru.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).rendered, true
+}
+
+func (r *Renderer) put(key [sha256.Size]byte, rendered *Rendered) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if elem, ok := r.entries[key]; ok {
+		r.lru.MoveToFront(elem)
+		return
+	}
+	r.entries[key] = r.lru.PushFront(&cacheEntry{key: key, rendered: rendered})
+	for r.lru.Len() > r.capacity {
+		oldest := r.lru.Back()
+		r.lru.Remove(oldest)
+		delete(r.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
@@ -14,8 +14,10 @@ func Cors() gin.HandlerFunc {
 		log.Println("requestURI", requestURI, "method", method)
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, X-Requested-With, X-Extra-Header, Content-Type, Accept, Authorization")
-		c.Header("Access-Control-Expose-Headers", "Content-Length, Access-Control-Allow-Origin, Access-Control-Allow-Headers, Cache-Control, Content-Language, Content-Type")
+		c.Header("Access-Control-Allow-Headers", "Origin, X-Requested-With, X-Extra-Header, Content-Type, Accept, Authorization, "+
+			"Tus-Resumable, Upload-Length, Upload-Offset, Upload-Metadata, Upload-Defer-Length")
+		c.Header("Access-Control-Expose-Headers", "Content-Length, Access-Control-Allow-Origin, Access-Control-Allow-Headers, Cache-Control, Content-Language, Content-Type, "+
+			"Location, Tus-Resumable, Tus-Version, Tus-Extension, Upload-Length, Upload-Offset, Upload-Expires, X-File-Id, X-File-Url")
 		c.Header("Access-Control-Allow-Credentials", "true") // 允许携带cookie
 		c.Header("Access-Control-Max-Age", "86400")
 		c.Set("content-type", "application/json")
@@ -1,30 +1,98 @@
 package middleware
 
 import (
+	"context"
 	"strings"
 
 	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
-	"github.com/codepzj/Stellux-Server/internal/pkg/utils"
+	"github.com/codepzj/Stellux-Server/internal/pkg/token"
 
 	"github.com/gin-gonic/gin"
 )
 
-func JWT() gin.HandlerFunc {
+// SessionChecker 判断登录会话是否仍然有效, 由user模块实现
+type SessionChecker interface {
+	IsSessionActive(ctx context.Context, sessionId string) (bool, error)
+}
+
+// PersonalTokenVerifier 校验个人访问令牌, 返回所属用户和授权范围, 由user模块实现
+type PersonalTokenVerifier interface {
+	VerifyPersonalToken(ctx context.Context, plain string, ip string) (userId string, scopes []string, ok bool, err error)
+}
+
+// scopesKey 个人访问令牌的授权范围, 只有使用个人访问令牌的请求才会设置
+const scopesKey = "scopes"
+
+// JWT 校验Authorization头中的访问令牌, 同时接受登录签发的JWT和个人访问令牌
+func JWT(tokenManager *token.Manager, checker SessionChecker, verifier PersonalTokenVerifier) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		access_token := ctx.Request.Header.Get("Authorization")
-		logger.Debug("用户携带的token", logger.WithString("access_token", access_token))
 		// 若非GET请求的token为空
 		if access_token == "" || !strings.HasPrefix(access_token, "Bearer ") {
 			ctx.AbortWithStatusJSON(200, gin.H{"code": 401, "error": "未携带access_token"})
 			return
 		}
-		claims, err := utils.ParseToken(strings.TrimPrefix(access_token, "Bearer "))
+		raw := strings.TrimPrefix(access_token, "Bearer ")
+		if token.IsPersonalToken(raw) {
+			personalToken(ctx, verifier, raw)
+			return
+		}
+		claims, err := tokenManager.ParseToken(raw)
 		if err != nil {
 			logger.Error("解析token失败", logger.WithError(err))
 			ctx.AbortWithStatusJSON(200, gin.H{"code": 401, "error": "access_token已过期"})
 			return
 		}
+		// 不属于任何会话的旧令牌或会话已吊销的令牌一律拒绝
+		if claims.SessionId == "" {
+			ctx.AbortWithStatusJSON(200, gin.H{"code": 401, "error": "access_token已失效, 请重新登录"})
+			return
+		}
+		active, err := checker.IsSessionActive(ctx, claims.SessionId)
+		if err != nil {
+			logger.Error("查询会话状态失败", logger.WithError(err), logger.WithString("sessionId", claims.SessionId))
+			ctx.AbortWithStatusJSON(200, gin.H{"code": 500, "error": "查询会话状态失败"})
+			return
+		}
+		if !active {
+			ctx.AbortWithStatusJSON(200, gin.H{"code": 401, "error": "登录已失效, 请重新登录"})
+			return
+		}
 		ctx.Set("userId", claims.ID)
+		ctx.Set("sessionId", claims.SessionId)
+		ctx.Next()
+	}
+}
+
+func personalToken(ctx *gin.Context, verifier PersonalTokenVerifier, raw string) {
+	userId, scopes, ok, err := verifier.VerifyPersonalToken(ctx, raw, ctx.ClientIP())
+	if err != nil {
+		logger.Error("校验个人访问令牌失败", logger.WithError(err))
+		ctx.AbortWithStatusJSON(200, gin.H{"code": 500, "error": "校验访问令牌失败"})
+		return
+	}
+	if !ok {
+		ctx.AbortWithStatusJSON(200, gin.H{"code": 401, "error": "访问令牌无效或已过期"})
+		return
+	}
+	ctx.Set("userId", userId)
+	ctx.Set(scopesKey, scopes)
+	ctx.Next()
+}
+
+// IsPersonalToken 当前请求是否使用个人访问令牌认证
+func IsPersonalToken(ctx *gin.Context) bool {
+	_, exists := ctx.Get(scopesKey)
+	return exists
+}
+
+// RequireSession 只允许登录会话访问, 用于修改密码、管理令牌等账号安全相关接口
+func RequireSession() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if IsPersonalToken(ctx) {
+			ctx.AbortWithStatusJSON(200, gin.H{"code": 403, "error": "该接口不支持个人访问令牌, 请登录后操作"})
+			return
+		}
 		ctx.Next()
 	}
 }
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"slices"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const authorizerKey = "authorizer"
+
+// Authorizer 判断用户是否拥有指定权限, 由rbac模块实现
+type Authorizer interface {
+	Authorize(ctx context.Context, userId string, permission string) (bool, error)
+}
+
+// Authorization 将鉴权器注入请求上下文, 供RequirePermission和HasPermission使用
+func Authorization(authorizer Authorizer) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set(authorizerKey, authorizer)
+		ctx.Next()
+	}
+}
+
+// RequirePermission 校验当前登录用户是否拥有指定权限, 需在JWT之后使用
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetString("userId") == "" {
+			ctx.AbortWithStatusJSON(200, gin.H{"code": 401, "error": "未携带access_token"})
+			return
+		}
+		ok, err := HasPermission(ctx, permission)
+		if err != nil {
+			logger.Error("权限校验失败",
+				logger.WithError(err),
+				logger.WithString("userId", ctx.GetString("userId")),
+				logger.WithString("permission", permission),
+			)
+			ctx.AbortWithStatusJSON(200, gin.H{"code": 500, "error": "权限校验失败"})
+			return
+		}
+		if !ok {
+			logger.Warn("用户没有权限",
+				logger.WithString("userId", ctx.GetString("userId")),
+				logger.WithString("permission", permission),
+				logger.WithString("path", ctx.FullPath()),
+			)
+			ctx.AbortWithStatusJSON(200, gin.H{"code": 403, "error": "没有权限: " + permission})
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// HasPermission 判断当前登录用户是否拥有指定权限, 未注入鉴权器时一律拒绝
+// 使用个人访问令牌时, 权限同时受用户角色和令牌授权范围限制
+func HasPermission(ctx *gin.Context, permission string) (bool, error) {
+	userId := ctx.GetString("userId")
+	value, exists := ctx.Get(authorizerKey)
+	if !exists || userId == "" {
+		return false, nil
+	}
+	authorizer, ok := value.(Authorizer)
+	if !ok {
+		return false, nil
+	}
+	if scopes, exists := ctx.Get(scopesKey); exists && !slices.Contains(scopes.([]string), permission) {
+		return false, nil
+	}
+	return authorizer.Authorize(ctx, userId, permission)
+}
@@ -0,0 +1,124 @@
+package permission
+
+import "slices"
+
+// 内置角色Id, 与User.RoleId对应
+const (
+	RoleAdmin  = 0 // 管理员, 拥有全部权限且不可修改
+	RoleEditor = 1 // 编辑, 管理全部内容
+	RoleAuthor = 2 // 作者, 只能编辑自己的文章
+	RoleViewer = 3 // 访客, 只读
+)
+
+// 权限码, 格式为"资源:操作", 按后台路由分组划分
+const (
+	UserRead      = "user:read"
+	UserWrite     = "user:write"
+	RoleManage    = "role:manage"
+	PostRead      = "post:read"
+	PostWrite     = "post:write"
+	PostManage    = "post:manage"
+	LabelWrite    = "label:write"
+	FileWrite     = "file:write"
+	DocumentRead  = "document:read"
+	DocumentWrite = "document:write"
+	FriendRead    = "friend:read"
+	FriendWrite   = "friend:write"
+	ConfigRead    = "config:read"
+	ConfigWrite   = "config:write"
+	CommentRead   = "comment:read"
+	CommentWrite  = "comment:write"
+	AntiSpamRead  = "antispam:read"
+	AntiSpamWrite = "antispam:write"
+	SearchRead    = "search:read"
+	SearchWrite   = "search:write"
+	AuditRead     = "audit:read"
+)
+
+// Definition 权限定义
+type Definition struct {
+	Code        string
+	Group       string
+	Description string
+}
+
+// Definitions 全部权限, 角色只能分配其中的权限码
+var Definitions = []Definition{
+	{Code: UserRead, Group: "user", Description: "查看用户列表"},
+	{Code: UserWrite, Group: "user", Description: "创建、更新、删除用户及分配角色"},
+	{Code: RoleManage, Group: "role", Description: "管理角色和权限"},
+	{Code: PostRead, Group: "post", Description: "查看草稿、回收站和历史版本"},
+	{Code: PostWrite, Group: "post", Description: "创建文章, 编辑和删除自己的文章"},
+	{Code: PostManage, Group: "post", Description: "编辑和删除所有人的文章"},
+	{Code: LabelWrite, Group: "label", Description: "创建、更新、删除分类和标签"},
+	{Code: FileWrite, Group: "file", Description: "上传和删除文件"},
+	{Code: DocumentRead, Group: "document", Description: "查看文档和文档内容"},
+	{Code: DocumentWrite, Group: "document", Description: "创建、更新、删除文档和文档内容"},
+	{Code: FriendRead, Group: "friend", Description: "查看友链"},
+	{Code: FriendWrite, Group: "friend", Description: "创建、更新、删除友链"},
+	{Code: ConfigRead, Group: "config", Description: "查看站点配置"},
+	{Code: ConfigWrite, Group: "config", Description: "创建、更新、删除站点配置"},
+	{Code: CommentRead, Group: "comment", Description: "查看评论审核队列"},
+	{Code: CommentWrite, Group: "comment", Description: "审核、编辑、回复和删除评论"},
+	{Code: AntiSpamRead, Group: "antispam", Description: "查看反垃圾规则"},
+	{Code: AntiSpamWrite, Group: "antispam", Description: "创建、更新、删除反垃圾规则"},
+	{Code: SearchRead, Group: "search", Description: "后台全文搜索"},
+	{Code: SearchWrite, Group: "search", Description: "重建全文索引"},
+	{Code: AuditRead, Group: "audit", Description: "查看操作审计日志"},
+}
+
+// Codes 返回全部权限码
+func Codes() []string {
+	codes := make([]string, 0, len(Definitions))
+	for _, definition := range Definitions {
+		codes = append(codes, definition.Code)
+	}
+	return codes
+}
+
+// IsValid 判断权限码是否存在
+func IsValid(code string) bool {
+	return slices.ContainsFunc(Definitions, func(definition Definition) bool {
+		return definition.Code == code
+	})
+}
+
+// Builtin 内置角色及其默认权限, 管理员始终拥有全部权限
+var Builtin = []BuiltinRole{
+	{
+		RoleId:      RoleAdmin,
+		Name:        "管理员",
+		Description: "拥有全部权限",
+		Permissions: Codes(),
+	},
+	{
+		RoleId:      RoleEditor,
+		Name:        "编辑",
+		Description: "管理全部内容, 不能管理用户和角色",
+		Permissions: []string{
+			PostRead, PostWrite, PostManage, LabelWrite, FileWrite,
+			DocumentRead, DocumentWrite, FriendRead, FriendWrite, ConfigRead,
+			CommentRead, CommentWrite, AntiSpamRead, SearchRead, SearchWrite,
+		},
+	},
+	{
+		RoleId:      RoleAuthor,
+		Name:        "作者",
+		Description: "撰写文章, 只能编辑自己的文章",
+		Permissions: []string{PostRead, PostWrite, FileWrite, DocumentRead, SearchRead},
+	},
+	{
+		RoleId:      RoleViewer,
+		Name:        "访客",
+		Description: "只读访问后台",
+		Permissions: []string{PostRead, DocumentRead, FriendRead, ConfigRead, CommentRead, SearchRead},
+	},
+}
+
+// BuiltinRole 内置角色
+type BuiltinRole struct {
+	RoleId      int
+	Name        string
+	Description string
+	Permissions []string
+}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Options S3兼容存储的连接参数
+type S3Options struct {
+	Endpoint  string // 服务地址, 不带协议, 如"127.0.0.1:9000"
+	AccessKey string
+	SecretKey string
+	Bucket    string
+	Region    string
+	UseSSL    bool
+	Prefix    string // 对象key前缀, 多个站点共用一个bucket时区分
+	PublicURL string // 对象的公开访问地址, 如CDN域名, 为空时通过baseURL由服务端转发
+}
+
+var _ Storage = (*S3Storage)(nil)
+
+// NewS3Storage S3兼容存储, 支持AWS S3、MinIO及各云厂商的S3兼容接口, bucket不存在时自动创建
+func NewS3Storage(opts S3Options, baseURL string) (*S3Storage, error) {
+	client, err := minio.New(opts.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(opts.AccessKey, opts.SecretKey, ""),
+		Secure: opts.UseSSL,
+		Region: opts.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	exists, err := client.BucketExists(ctx, opts.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if err = client.MakeBucket(ctx, opts.Bucket, minio.MakeBucketOptions{Region: opts.Region}); err != nil {
+			return nil, err
+		}
+	}
+
+	return &S3Storage{
+		client:    client,
+		bucket:    opts.Bucket,
+		prefix:    opts.Prefix,
+		publicURL: opts.PublicURL,
+		baseURL:   baseURL,
+	}, nil
+}
+
+type S3Storage struct {
+	client    *minio.Client
+	bucket    string
+	prefix    string
+	publicURL string
+	baseURL   string
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	objectKey, err := s.objectKey(key)
+	if err != nil {
+		return err
+	}
+	if contentType == "" {
+		contentType = contentTypeByKey(key)
+	}
+	_, err = s.client.PutObject(ctx, s.bucket, objectKey, r, size, minio.PutObjectOptions{ContentType: contentType})
+	return err
+}
+
+// Get GetObject不会立即请求, 通过Stat确认对象存在并获取元信息
+func (s *S3Storage) Get(ctx context.Context, key string) (*Object, error) {
+	objectKey, err := s.objectKey(key)
+	if err != nil {
+		return nil, err
+	}
+	object, err := s.client.GetObject(ctx, s.bucket, objectKey, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, s.convertError(err)
+	}
+	info, err := object.Stat()
+	if err != nil {
+		object.Close()
+		return nil, s.convertError(err)
+	}
+	return &Object{
+		ReadCloser:  object,
+		Size:        info.Size,
+		ContentType: info.ContentType,
+		ModTime:     info.LastModified,
+	}, nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	objectKey, err := s.objectKey(key)
+	if err != nil {
+		return err
+	}
+	return s.client.RemoveObject(ctx, s.bucket, objectKey, minio.RemoveObjectOptions{})
+}
+
+// URL 配置了公开地址时直接访问对象存储, 否则由服务端转发
+func (s *S3Storage) URL(key string) string {
+	key, _ = cleanKey(key)
+	if s.publicURL == "" {
+		return joinURL(s.baseURL, key)
+	}
+	return joinURL(s.publicURL, path.Join(s.prefix, key))
+}
+
+func (s *S3Storage) objectKey(key string) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(s.prefix, key), nil
+}
+
+func (s *S3Storage) convertError(err error) error {
+	if minio.ToErrorResponse(err).StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"path"
+	"strings"
+	"time"
+)
+
+// ErrNotFound 文件不存在
+var ErrNotFound = errors.New("文件不存在")
+
+// ErrInvalidKey 文件key为空或试图访问存储目录之外的路径
+var ErrInvalidKey = errors.New("文件key不合法")
+
+// Object 读取到的文件, 使用完毕后需要Close
+type Object struct {
+	io.ReadCloser
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Storage 文件存储, key为存储内的相对路径, 如"1700000000abc.png"
+type Storage interface {
+	// Put 写入文件, size未知时传-1, 相同key会被覆盖
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get 读取文件, 不存在时返回ErrNotFound
+	Get(ctx context.Context, key string) (*Object, error)
+	// Delete 删除文件, 文件不存在时不返回错误
+	Delete(ctx context.Context, key string) error
+	// URL 文件的访问地址
+	URL(key string) string
+}
+
+// cleanKey 规范化key, 去掉开头的斜杠并拒绝跳出存储目录的路径
+func cleanKey(key string) (string, error) {
+	key = strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(key, "\\", "/")), "/")
+	if key == "" || key == "." {
+		return "", ErrInvalidKey
+	}
+	return key, nil
+}
+
+// contentTypeByKey 根据扩展名推断文件类型
+func contentTypeByKey(key string) string {
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// joinURL 拼接访问地址, 避免重复的斜杠
+func joinURL(baseURL string, key string) string {
+	return strings.TrimRight(baseURL, "/") + "/" + key
+}
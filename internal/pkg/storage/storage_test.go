@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCleanKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		want    string
+		wantErr error
+	}{
+		{name: "普通key", key: "a.png", want: "a.png"},
+		{name: "开头的斜杠", key: "/a.png", want: "a.png"},
+		{name: "多级目录", key: "2024/01/a.png", want: "2024/01/a.png"},
+		{name: "重复的斜杠", key: "2024//01/./a.png", want: "2024/01/a.png"},
+		{name: "反斜杠", key: `2024\01\a.png`, want: "2024/01/a.png"},
+		{name: "跳出存储目录", key: "../../etc/passwd", want: "etc/passwd"},
+		{name: "反斜杠跳出存储目录", key: `..\..\etc\passwd`, want: "etc/passwd"},
+		{name: "中间跳出存储目录", key: "a/../../b.png", want: "b.png"},
+		{name: "空key", key: "", wantErr: ErrInvalidKey},
+		{name: "只有斜杠", key: "/", wantErr: ErrInvalidKey},
+		{name: "上级目录", key: "..", wantErr: ErrInvalidKey},
+		{name: "当前目录", key: "./", wantErr: ErrInvalidKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cleanKey(tt.key)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("cleanKey(%q) error = %v, want %v", tt.key, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("cleanKey(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package token
+
+import (
+	"encoding/base64"
+	"math/big"
+)
+
+// JWKSet JWKS文档, 供其他服务验证令牌
+type JWKSet struct {
+	Keys []*JWK `json:"keys"`
+}
+
+// JWK 单个公钥, 字段含义见RFC 7517/8037
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+func encodeBase64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func bigEndian(n int) []byte {
+	return big.NewInt(int64(n)).Bytes()
+}
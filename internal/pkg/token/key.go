@@ -0,0 +1,129 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 支持的签名算法
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// Key 带kid的签名密钥, 只有公钥的密钥只能用于验证
+type Key struct {
+	Kid       string
+	Alg       string
+	method    jwt.SigningMethod
+	signKey   any // 为nil时不能签名
+	verifyKey any
+}
+
+// CanSign 是否可用于签发令牌
+func (k *Key) CanSign() bool {
+	return k.signKey != nil
+}
+
+// NewHMACKey 创建HS256对称密钥
+func NewHMACKey(kid string, secret string) (*Key, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("密钥%s的SECRET为空", kid)
+	}
+	return &Key{
+		Kid:       kid,
+		Alg:       AlgHS256,
+		method:    jwt.SigningMethodHS256,
+		signKey:   []byte(secret),
+		verifyKey: []byte(secret),
+	}, nil
+}
+
+// LoadKey 从PEM文件加载RS256或EdDSA密钥, 配置私钥时可签名, 只配置公钥时只用于验证
+func LoadKey(kid string, alg string, privateKeyFile string, publicKeyFile string) (*Key, error) {
+	if privateKeyFile == "" && publicKeyFile == "" {
+		return nil, fmt.Errorf("密钥%s未配置私钥或公钥文件", kid)
+	}
+	key := &Key{Kid: kid, Alg: alg}
+	switch alg {
+	case AlgRS256:
+		key.method = jwt.SigningMethodRS256
+		if privateKeyFile != "" {
+			pem, err := os.ReadFile(privateKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, fmt.Errorf("解析密钥%s的RSA私钥失败: %w", kid, err)
+			}
+			key.signKey, key.verifyKey = privateKey, &privateKey.PublicKey
+		} else {
+			pem, err := os.ReadFile(publicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			publicKey, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+			if err != nil {
+				return nil, fmt.Errorf("解析密钥%s的RSA公钥失败: %w", kid, err)
+			}
+			key.verifyKey = publicKey
+		}
+	case AlgEdDSA:
+		key.method = jwt.SigningMethodEdDSA
+		if privateKeyFile != "" {
+			pem, err := os.ReadFile(privateKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			privateKey, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, fmt.Errorf("解析密钥%s的Ed25519私钥失败: %w", kid, err)
+			}
+			key.signKey, key.verifyKey = privateKey, privateKey.(ed25519.PrivateKey).Public()
+		} else {
+			pem, err := os.ReadFile(publicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			publicKey, err := jwt.ParseEdPublicKeyFromPEM(pem)
+			if err != nil {
+				return nil, fmt.Errorf("解析密钥%s的Ed25519公钥失败: %w", kid, err)
+			}
+			key.verifyKey = publicKey
+		}
+	default:
+		return nil, fmt.Errorf("密钥%s使用了不支持的算法: %s", kid, alg)
+	}
+	return key, nil
+}
+
+// jwk 返回公钥的JWK表示, 对称密钥不公开
+func (k *Key) jwk() (*JWK, bool) {
+	switch publicKey := k.verifyKey.(type) {
+	case *rsa.PublicKey:
+		return &JWK{
+			Kty: "RSA",
+			Kid: k.Kid,
+			Alg: k.Alg,
+			Use: "sig",
+			N:   encodeBase64URL(publicKey.N.Bytes()),
+			E:   encodeBase64URL(bigEndian(publicKey.E)),
+		}, true
+	case ed25519.PublicKey:
+		return &JWK{
+			Kty: "OKP",
+			Kid: k.Kid,
+			Alg: k.Alg,
+			Use: "sig",
+			Crv: "Ed25519",
+			X:   encodeBase64URL(publicKey),
+		}, true
+	}
+	return nil, false
+}
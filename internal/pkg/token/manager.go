@@ -0,0 +1,133 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	issuer = "stellux"
+	// audienceTwoFactor 通过密码验证、等待二次验证的临时令牌, 不能作为访问令牌使用
+	audienceTwoFactor = "2fa"
+)
+
+// Claims 访问令牌的声明
+type Claims struct {
+	ID        string
+	SessionId string `json:"sid,omitempty"` // 所属登录会话, 会话吊销后令牌随之失效
+	jwt.RegisteredClaims
+}
+
+// Manager 按kid管理多把密钥, 用当前密钥签发令牌, 用令牌头中kid对应的密钥验证
+// 轮换时先加入新密钥并切换为当前密钥, 旧密钥保留到其签发的令牌全部过期后再移除
+type Manager struct {
+	keys   map[string]*Key
+	order  []*Key // 按配置顺序输出JWKS
+	active *Key
+}
+
+// NewManager 创建令牌管理器, activeKid为空时使用第一把可签名的密钥
+func NewManager(activeKid string, keys ...*Key) (*Manager, error) {
+	m := &Manager{keys: make(map[string]*Key, len(keys))}
+	for _, key := range keys {
+		if key.Kid == "" {
+			return nil, errors.New("密钥kid不能为空")
+		}
+		if _, ok := m.keys[key.Kid]; ok {
+			return nil, fmt.Errorf("密钥kid重复: %s", key.Kid)
+		}
+		m.keys[key.Kid] = key
+		m.order = append(m.order, key)
+		if m.active == nil && activeKid == "" && key.CanSign() {
+			m.active = key
+		}
+	}
+	if activeKid != "" {
+		m.active = m.keys[activeKid]
+	}
+	if m.active == nil || !m.active.CanSign() {
+		return nil, fmt.Errorf("没有可用于签发令牌的密钥: %s", activeKid)
+	}
+	return m, nil
+}
+
+// GenerateAccessToken 签发访问令牌, ttl为有效期
+func (m *Manager) GenerateAccessToken(id string, sessionId string, ttl time.Duration) (string, error) {
+	return m.sign(&Claims{ID: id, SessionId: sessionId}, ttl)
+}
+
+// GenerateTwoFactorToken 签发二次验证临时令牌, 只能用于提交二次验证码
+func (m *Manager) GenerateTwoFactorToken(id string, ttl time.Duration) (string, error) {
+	claims := &Claims{ID: id}
+	claims.Audience = jwt.ClaimStrings{audienceTwoFactor}
+	return m.sign(claims, ttl)
+}
+
+func (m *Manager) sign(claims *Claims, ttl time.Duration) (string, error) {
+	now := time.Now().Local()
+	claims.Issuer = issuer
+	claims.Subject = issuer
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.NotBefore = jwt.NewNumericDate(now)
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(ttl))
+	token := jwt.NewWithClaims(m.active.method, claims)
+	token.Header["kid"] = m.active.Kid
+	return token.SignedString(m.active.signKey)
+}
+
+// ParseToken 验证并解析访问令牌
+func (m *Manager) ParseToken(tokenStr string) (*Claims, error) {
+	claims, err := m.parse(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(claims.Audience, audienceTwoFactor) {
+		return nil, errors.New("二次验证令牌不能作为访问令牌使用")
+	}
+	return claims, nil
+}
+
+// ParseTwoFactorToken 验证并解析二次验证临时令牌
+func (m *Manager) ParseTwoFactorToken(tokenStr string) (*Claims, error) {
+	return m.parse(tokenStr, jwt.WithAudience(audienceTwoFactor))
+}
+
+// parse 验证签名和有效期, 令牌头的alg必须与kid对应密钥的算法一致, 没有kid的令牌按当前密钥验证
+func (m *Manager) parse(tokenStr string, opts ...jwt.ParserOption) (*Claims, error) {
+	claims := new(Claims)
+	var key *Key
+	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (any, error) {
+		key = m.active
+		if kid, ok := token.Header["kid"].(string); ok {
+			if key, ok = m.keys[kid]; !ok {
+				return nil, fmt.Errorf("未知的kid: %s", kid)
+			}
+		}
+		if token.Method.Alg() != key.Alg {
+			return nil, fmt.Errorf("kid与签名算法不匹配: %s", token.Method.Alg())
+		}
+		return key.verifyKey, nil
+	}, append(opts, jwt.WithIssuer(issuer), jwt.WithValidMethods([]string{AlgHS256, AlgRS256, AlgEdDSA}))...)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("无效的token")
+	}
+	return claims, nil
+}
+
+// JWKS 返回全部非对称密钥的公钥, 包括只用于验证的旧密钥
+func (m *Manager) JWKS() *JWKSet {
+	set := &JWKSet{Keys: []*JWK{}}
+	for _, key := range m.order {
+		if jwk, ok := key.jwk(); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
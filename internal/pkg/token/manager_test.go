@@ -0,0 +1,165 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newEdKey(t *testing.T, kid string) *Key {
+	t.Helper()
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Key{Kid: kid, Alg: AlgEdDSA, method: jwt.SigningMethodEdDSA, signKey: privateKey, verifyKey: publicKey}
+}
+
+func newHMACKey(t *testing.T, kid string, secret string) *Key {
+	t.Helper()
+	key, err := NewHMACKey(kid, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// signWith 用指定的算法和密钥签发令牌, kid为空时不写入令牌头
+func signWith(t *testing.T, method jwt.SigningMethod, signKey any, kid string, claims *Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	tokenStr, err := token.SignedString(signKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokenStr
+}
+
+func validClaims() *Claims {
+	now := time.Now()
+	return &Claims{
+		ID: "user",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		},
+	}
+}
+
+func TestManagerParseToken(t *testing.T) {
+	hsKey := newHMACKey(t, "hs", "secret")
+	edKey := newEdKey(t, "ed")
+	otherEdKey := newEdKey(t, "ed")
+	// 只有公钥的旧密钥只用于验证
+	oldKey := newEdKey(t, "old")
+	oldVerifyOnly := &Key{Kid: oldKey.Kid, Alg: oldKey.Alg, method: oldKey.method, verifyKey: oldKey.verifyKey}
+
+	manager, err := NewManager("ed", hsKey, edKey, oldVerifyOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expired := validClaims()
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	wrongIssuer := validClaims()
+	wrongIssuer.Issuer = "other"
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "当前密钥签发", token: signWith(t, jwt.SigningMethodEdDSA, edKey.signKey, "ed", validClaims())},
+		{name: "其他kid的HS256密钥", token: signWith(t, jwt.SigningMethodHS256, []byte("secret"), "hs", validClaims())},
+		{name: "只用于验证的旧密钥", token: signWith(t, jwt.SigningMethodEdDSA, oldKey.signKey, "old", validClaims())},
+		{name: "没有kid按当前密钥验证", token: signWith(t, jwt.SigningMethodEdDSA, edKey.signKey, "", validClaims())},
+		{name: "没有kid且不是当前密钥签发", token: signWith(t, jwt.SigningMethodHS256, []byte("secret"), "", validClaims()), wantErr: true},
+		{name: "未知的kid", token: signWith(t, jwt.SigningMethodEdDSA, edKey.signKey, "unknown", validClaims()), wantErr: true},
+		{name: "同kid的其他密钥签发", token: signWith(t, jwt.SigningMethodEdDSA, otherEdKey.signKey, "ed", validClaims()), wantErr: true},
+		{name: "错误的HS256密钥", token: signWith(t, jwt.SigningMethodHS256, []byte("guess"), "hs", validClaims()), wantErr: true},
+		// 算法混淆: 用公开的公钥作为HS256密钥伪造EdDSA密钥的令牌
+		{name: "kid与算法不匹配", token: signWith(t, jwt.SigningMethodHS256, []byte(edKey.verifyKey.(ed25519.PublicKey)), "ed", validClaims()), wantErr: true},
+		{name: "alg为none", token: signWith(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "ed", validClaims()), wantErr: true},
+		{name: "已过期", token: signWith(t, jwt.SigningMethodEdDSA, edKey.signKey, "ed", expired), wantErr: true},
+		{name: "签发者错误", token: signWith(t, jwt.SigningMethodEdDSA, edKey.signKey, "ed", wrongIssuer), wantErr: true},
+		{name: "格式错误", token: "not.a.token", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := manager.ParseToken(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && claims.ID != "user" {
+				t.Errorf("ParseToken() ID = %s, want user", claims.ID)
+			}
+		})
+	}
+}
+
+func TestManagerTwoFactorToken(t *testing.T) {
+	manager, err := NewManager("", newHMACKey(t, "hs", "secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	accessToken, err := manager.GenerateAccessToken("user", "session", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	twoFactorToken, err := manager.GenerateTwoFactorToken("user", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		parse   func(string) (*Claims, error)
+		token   string
+		wantErr bool
+	}{
+		{name: "访问令牌作为访问令牌", parse: manager.ParseToken, token: accessToken},
+		{name: "二次验证令牌作为访问令牌", parse: manager.ParseToken, token: twoFactorToken, wantErr: true},
+		{name: "二次验证令牌", parse: manager.ParseTwoFactorToken, token: twoFactorToken},
+		{name: "访问令牌作为二次验证令牌", parse: manager.ParseTwoFactorToken, token: accessToken, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.parse(tt.token); (err != nil) != tt.wantErr {
+				t.Errorf("parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewManager(t *testing.T) {
+	verifyOnly := newEdKey(t, "verify")
+	verifyOnly.signKey = nil
+	tests := []struct {
+		name      string
+		activeKid string
+		keys      []*Key
+		wantErr   bool
+	}{
+		{name: "默认使用第一把可签名的密钥", keys: []*Key{verifyOnly, newHMACKey(t, "hs", "secret")}},
+		{name: "指定当前密钥", activeKid: "hs", keys: []*Key{newEdKey(t, "ed"), newHMACKey(t, "hs", "secret")}},
+		{name: "当前密钥不存在", activeKid: "missing", keys: []*Key{newHMACKey(t, "hs", "secret")}, wantErr: true},
+		{name: "当前密钥只能验证", activeKid: "verify", keys: []*Key{verifyOnly}, wantErr: true},
+		{name: "kid重复", keys: []*Key{newHMACKey(t, "hs", "a"), newHMACKey(t, "hs", "b")}, wantErr: true},
+		{name: "没有密钥", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewManager(tt.activeKid, tt.keys...); (err != nil) != tt.wantErr {
+				t.Errorf("NewManager() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
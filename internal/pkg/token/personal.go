@@ -0,0 +1,33 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// PersonalTokenPrefix 个人访问令牌前缀, 用于与JWT区分, 也便于密钥扫描工具识别泄露的令牌
+const PersonalTokenPrefix = "stx_pat_"
+
+// NewPersonalToken 生成个人访问令牌, 返回明文和哈希, 数据库只保存哈希
+func NewPersonalToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	plain := PersonalTokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return plain, HashPersonalToken(plain), nil
+}
+
+// HashPersonalToken 计算个人访问令牌的哈希
+func HashPersonalToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsPersonalToken 是否为个人访问令牌
+func IsPersonalToken(s string) bool {
+	return strings.HasPrefix(s, PersonalTokenPrefix)
+}
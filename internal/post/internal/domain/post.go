@@ -17,12 +17,15 @@ type Post struct {
 	Content     string          // 内容
 	Description string          // 描述
 	Author      string          // 作者
+	AuthorId    bson.ObjectID   // 创建者用户ID, 作者角色只能编辑自己创建的文章
 	Alias       string          // 别名
 	CategoryId  bson.ObjectID   // 分类ID
 	TagsId      []bson.ObjectID // 标签ID
 	IsPublish   bool            // 是否发布
 	IsTop       bool            // 是否置顶
 	Thumbnail   string          // 缩略图
+	PublishAt   time.Time       // 定时发布时间, 零值表示未设置
+	PublishedAt time.Time       // 首次发布时间, 零值表示从未发布
 }
 
 type PostDetail struct {
@@ -41,6 +44,8 @@ type PostDetail struct {
 	IsPublish   bool            // 是否发布
 	IsTop       bool            // 是否置顶
 	Thumbnail   string          // 缩略图
+	PublishAt   time.Time       // 定时发布时间, 零值表示未设置
+	PublishedAt time.Time       // 首次发布时间, 零值表示从未发布
 }
 
 // PostQueryPage Post模块的分页查询参数（包含特殊过滤字段）
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// PostRevision 文章历史版本, 保存每次更新前的文章快照
+type PostRevision struct {
+	Id          bson.ObjectID   // 版本ID
+	CreatedAt   time.Time       // 快照时间
+	PostId      bson.ObjectID   // 文章ID
+	Title       string          // 标题
+	Content     string          // 内容
+	Description string          // 描述
+	Author      string          // 作者
+	Alias       string          // 别名
+	CategoryId  bson.ObjectID   // 分类ID
+	TagsId      []bson.ObjectID // 标签ID
+	IsPublish   bool            // 是否发布
+	IsTop       bool            // 是否置顶
+	Thumbnail   string          // 缩略图
+}
+
+// PostRevisionDiff 两个版本之间的差异
+type PostRevisionDiff struct {
+	OldId bson.ObjectID // 旧版本ID
+	NewId bson.ObjectID // 新版本ID, 为空表示当前文章
+	Diff  string        // unified diff
+}
@@ -21,12 +21,15 @@ type Post struct {
 	Content     string          `bson:"content"`
 	Description string          `bson:"description"`
 	Author      string          `bson:"author"`
+	AuthorID    bson.ObjectID   `bson:"author_id,omitempty"`
 	Alias       string          `bson:"alias"`
 	CategoryID  bson.ObjectID   `bson:"category_id"`
 	TagsID      []bson.ObjectID `bson:"tags_id"`
 	IsPublish   bool            `bson:"is_publish"`
 	IsTop       bool            `bson:"is_top"`
 	Thumbnail   string          `bson:"thumbnail"`
+	PublishAt   *time.Time      `bson:"publish_at,omitempty"`
+	PublishedAt *time.Time      `bson:"published_at,omitempty"`
 }
 
 type PostUpdate struct {
@@ -57,6 +60,8 @@ type PostCategoryTags struct {
 	IsPublish   bool           `bson:"is_publish"`
 	IsTop       bool           `bson:"is_top"`
 	Thumbnail   string         `bson:"thumbnail"`
+	PublishAt   *time.Time     `bson:"publish_at,omitempty"`
+	PublishedAt *time.Time     `bson:"published_at,omitempty"`
 }
 
 type UpdatePost struct {
@@ -77,6 +82,9 @@ type IPostDao interface {
 	Create(ctx context.Context, post *Post) error
 	Update(ctx context.Context, id bson.ObjectID, post *UpdatePost) error
 	UpdatePostPublishStatus(ctx context.Context, id bson.ObjectID, isPublish bool) error
+	SchedulePublish(ctx context.Context, id bson.ObjectID, publishAt time.Time) error
+	UnschedulePublish(ctx context.Context, id bson.ObjectID) error
+	PublishDue(ctx context.Context, now time.Time) (int64, error)
 	SoftDelete(ctx context.Context, id bson.ObjectID) error
 	SoftDeleteBatch(ctx context.Context, ids []bson.ObjectID) error
 	Delete(ctx context.Context, id bson.ObjectID) error
@@ -84,13 +92,17 @@ type IPostDao interface {
 	Restore(ctx context.Context, id bson.ObjectID) error
 	RestoreBatch(ctx context.Context, ids []bson.ObjectID) error
 	GetByID(ctx context.Context, id bson.ObjectID) (*Post, error)
+	GetPublishByID(ctx context.Context, id bson.ObjectID) (*Post, error)
 	GetByKeyWord(ctx context.Context, keyWord string) ([]*Post, error)
 	GetDetailByID(ctx context.Context, id bson.ObjectID) (*PostCategoryTags, error)
+	GetPublishDetailByID(ctx context.Context, id bson.ObjectID) (*PostCategoryTags, error)
 	GetList(ctx context.Context, pagePipeline mongo.Pipeline, cond bson.D) ([]*PostCategoryTags, int64, error)
 	GetListWithTagFilter(ctx context.Context, pagePipeline mongo.Pipeline, cond bson.D, hasTagFilter bool, labelName string) ([]*PostCategoryTags, int64, error)
 	GetListWithFilter(ctx context.Context, pagePipeline mongo.Pipeline, cond bson.D, hasTagFilter bool, labelName string, hasCategoryFilter bool, categoryName string) ([]*PostCategoryTags, int64, error)
 	GetAllPublishPost(ctx context.Context) ([]*Post, error)
 	FindByAlias(ctx context.Context, alias string) (*Post, error)
+	FindPublishByAlias(ctx context.Context, alias string) (*Post, error)
+	CountNotOwned(ctx context.Context, ids []bson.ObjectID, authorId bson.ObjectID) (int64, error)
 }
 
 var _ IPostDao = (*PostDao)(nil)
@@ -108,6 +120,9 @@ func (d *PostDao) Create(ctx context.Context, post *Post) error {
 	post.ID = bson.NewObjectID()
 	post.CreatedAt = time.Now()
 	post.UpdatedAt = time.Now()
+	if post.IsPublish && post.PublishedAt == nil {
+		post.PublishedAt = &post.CreatedAt
+	}
 	insertResult, err := d.coll.InsertOne(ctx, post)
 	if err != nil {
 		return err
@@ -142,16 +157,76 @@ func (d *PostDao) Update(ctx context.Context, id bson.ObjectID, post *UpdatePost
 	if updateResult.ModifiedCount == 0 {
 		return errors.New("文章修改失败")
 	}
+	if post.IsPublish {
+		return d.markPublished(ctx, bson.M{"_id": id}, time.Now())
+	}
 	return nil
 }
 
-// UpdatePostPublishStatus 更新文章发布状态
-func (d *PostDao) UpdatePostPublishStatus(ctx context.Context, id bson.ObjectID, isPublish bool) error {
-	update := bson.M{"$set": bson.M{"is_publish": isPublish, "updated_at": time.Now()}}
-	_, err := d.coll.UpdateOne(ctx, bson.M{"_id": id}, update)
+// markPublished 为首次上线的文章记录发布时间, 已有发布时间的文章保持不变
+func (d *PostDao) markPublished(ctx context.Context, filter bson.M, now time.Time) error {
+	filter["published_at"] = nil
+	_, err := d.coll.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"published_at": now}})
 	return err
 }
 
+// UpdatePostPublishStatus 更新文章发布状态, 手动修改发布状态时清除待执行的定时发布, 保留已记录的发布时间
+func (d *PostDao) UpdatePostPublishStatus(ctx context.Context, id bson.ObjectID, isPublish bool) error {
+	now := time.Now()
+	update := bson.M{"$set": bson.M{"is_publish": isPublish, "updated_at": now}, "$unset": bson.M{"publish_at": ""}}
+	if _, err := d.coll.UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		return err
+	}
+	if isPublish {
+		return d.markPublished(ctx, bson.M{"_id": id}, now)
+	}
+	return nil
+}
+
+// SchedulePublish 设置定时发布, 到期前文章保持未发布状态
+func (d *PostDao) SchedulePublish(ctx context.Context, id bson.ObjectID, publishAt time.Time) error {
+	update := bson.M{"$set": bson.M{"is_publish": false, "publish_at": publishAt, "updated_at": time.Now()}}
+	updateResult, err := d.coll.UpdateOne(ctx, bson.M{"_id": id, "deleted_at": nil}, update)
+	if err != nil {
+		return err
+	}
+	if updateResult.MatchedCount == 0 {
+		return errors.New("文章不存在或已删除")
+	}
+	return nil
+}
+
+// UnschedulePublish 取消定时发布, 文章回到草稿状态, 保留已记录的发布时间
+func (d *PostDao) UnschedulePublish(ctx context.Context, id bson.ObjectID) error {
+	update := bson.M{"$set": bson.M{"is_publish": false, "updated_at": time.Now()}, "$unset": bson.M{"publish_at": ""}}
+	updateResult, err := d.coll.UpdateOne(ctx, bson.M{"_id": id, "publish_at": bson.M{"$ne": nil}}, update)
+	if err != nil {
+		return err
+	}
+	if updateResult.MatchedCount == 0 {
+		return errors.New("文章未设置定时发布")
+	}
+	return nil
+}
+
+// PublishDue 发布所有到期的定时文章, 以定时发布时间作为首次发布时间, 返回发布数量
+func (d *PostDao) PublishDue(ctx context.Context, now time.Time) (int64, error) {
+	filter := bson.M{"publish_at": bson.M{"$lte": now}, "deleted_at": nil}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{
+			{Key: "is_publish", Value: true},
+			{Key: "updated_at", Value: now},
+			{Key: "published_at", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$published_at", "$publish_at"}}}},
+		}}},
+		{{Key: "$unset", Value: "publish_at"}},
+	}
+	updateResult, err := d.coll.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return updateResult.ModifiedCount, nil
+}
+
 // SoftDelete 软删除文章
 func (d *PostDao) SoftDelete(ctx context.Context, id bson.ObjectID) error {
 	now := time.Now()
@@ -181,8 +256,21 @@ func (d *PostDao) Restore(ctx context.Context, id bson.ObjectID) error {
 
 // GetDetailByID 获取文章
 func (d *PostDao) GetDetailByID(ctx context.Context, id bson.ObjectID) (*PostCategoryTags, error) {
+	return d.getDetail(ctx, bson.D{{Key: "_id", Value: id}})
+}
+
+// GetPublishDetailByID 获取已发布的文章, 未发布或定时发布未到期的文章视为不存在
+func (d *PostDao) GetPublishDetailByID(ctx context.Context, id bson.ObjectID) (*PostCategoryTags, error) {
+	return d.getDetail(ctx, bson.D{
+		{Key: "_id", Value: id},
+		{Key: "is_publish", Value: true},
+		{Key: "publish_at", Value: notAfter(time.Now())},
+	})
+}
+
+func (d *PostDao) getDetail(ctx context.Context, cond bson.D) (*PostCategoryTags, error) {
 	pipeline := mongo.Pipeline{
-		{{Key: "$match", Value: bson.D{{Key: "_id", Value: id}}}},
+		{{Key: "$match", Value: cond}},
 		{{Key: "$lookup", Value: bson.D{
 			{Key: "from", Value: "label"},
 			{Key: "localField", Value: "category_id"},
@@ -216,25 +304,33 @@ func (d *PostDao) GetDetailByID(ctx context.Context, id bson.ObjectID) (*PostCat
 
 // GetByID 获取文章
 func (d *PostDao) GetByID(ctx context.Context, id bson.ObjectID) (*Post, error) {
+	return d.findOne(ctx, bson.M{"_id": id})
+}
+
+// GetPublishByID 获取已发布的文章, 未发布或定时发布未到期的文章视为不存在
+func (d *PostDao) GetPublishByID(ctx context.Context, id bson.ObjectID) (*Post, error) {
+	return d.findOne(ctx, bson.M{"_id": id, "is_publish": true, "publish_at": notAfter(time.Now())})
+}
+
+func (d *PostDao) findOne(ctx context.Context, filter bson.M) (*Post, error) {
 	var post Post
-	err := d.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&post)
+	err := d.coll.FindOne(ctx, filter).Decode(&post)
 	if err != nil {
 		return nil, err
 	}
 	return &post, nil
 }
 
-// GetByKeyWord 获取文章
+// GetByKeyWord 基于全文索引搜索文章, 按相关度排序
 func (d *PostDao) GetByKeyWord(ctx context.Context, keyWord string) ([]*Post, error) {
 	filter := bson.M{
-		"$or": []bson.M{
-			{"title": bson.M{"$regex": keyWord, "$options": "i"}},
-			{"description": bson.M{"$regex": keyWord, "$options": "i"}},
-		},
+		"$text":      bson.M{"$search": keyWord},
 		"deleted_at": nil,
 		"is_publish": true,
+		"publish_at": notAfter(time.Now()),
 	}
-	cursor, err := d.coll.Find(ctx, filter)
+	opts := options.Find().SetSort(bson.M{"score": bson.M{"$meta": "textScore"}})
+	cursor, err := d.coll.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -401,7 +497,7 @@ func (d *PostDao) RestoreBatch(ctx context.Context, ids []bson.ObjectID) error {
 // GetAllPublishPost 获取所有发布文章
 func (d *PostDao) GetAllPublishPost(ctx context.Context) ([]*Post, error) {
 	opts := options.Find().SetSort(bson.M{"updated_at": -1})
-	cursor, err := d.coll.Find(ctx, bson.M{"is_publish": true}, opts)
+	cursor, err := d.coll.Find(ctx, bson.M{"is_publish": true, "publish_at": notAfter(time.Now())}, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -416,10 +512,20 @@ func (d *PostDao) GetAllPublishPost(ctx context.Context) ([]*Post, error) {
 
 // FindByAlias 根据别名获取文章
 func (d *PostDao) FindByAlias(ctx context.Context, alias string) (*Post, error) {
-	var post Post
-	err := d.coll.FindOne(ctx, bson.M{"alias": alias}).Decode(&post)
-	if err != nil {
-		return nil, err
-	}
-	return &post, nil
+	return d.findOne(ctx, bson.M{"alias": alias})
+}
+
+// FindPublishByAlias 根据别名获取已发布的文章, 未发布或定时发布未到期的文章视为不存在
+func (d *PostDao) FindPublishByAlias(ctx context.Context, alias string) (*Post, error) {
+	return d.findOne(ctx, bson.M{"alias": alias, "is_publish": true, "publish_at": notAfter(time.Now())})
+}
+
+// CountNotOwned 统计ids中不属于指定创建者的文章数量, 包括已软删除的文章
+func (d *PostDao) CountNotOwned(ctx context.Context, ids []bson.ObjectID, authorId bson.ObjectID) (int64, error) {
+	return d.coll.CountDocuments(ctx, bson.M{"_id": bson.M{"$in": ids}, "author_id": bson.M{"$ne": authorId}})
+}
+
+// notAfter 匹配未设置或不晚于指定时间的字段, 用于隐藏尚未到期的定时文章
+func notAfter(t time.Time) bson.M {
+	return bson.M{"$not": bson.M{"$gt": t}}
 }
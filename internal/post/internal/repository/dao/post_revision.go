@@ -0,0 +1,97 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type PostRevision struct {
+	ID          bson.ObjectID   `bson:"_id,omitempty"`
+	CreatedAt   time.Time       `bson:"created_at"`
+	PostID      bson.ObjectID   `bson:"post_id"`
+	Title       string          `bson:"title"`
+	Content     string          `bson:"content"`
+	Description string          `bson:"description"`
+	Author      string          `bson:"author"`
+	Alias       string          `bson:"alias"`
+	CategoryID  bson.ObjectID   `bson:"category_id"`
+	TagsID      []bson.ObjectID `bson:"tags_id"`
+	IsPublish   bool            `bson:"is_publish"`
+	IsTop       bool            `bson:"is_top"`
+	Thumbnail   string          `bson:"thumbnail"`
+}
+
+type IPostRevisionDao interface {
+	Create(ctx context.Context, revision *PostRevision) error
+	GetByID(ctx context.Context, id bson.ObjectID) (*PostRevision, error)
+	GetListByPostID(ctx context.Context, postId bson.ObjectID) ([]*PostRevision, error)
+	DeleteBatch(ctx context.Context, ids []bson.ObjectID) error
+	DeleteByPostIDs(ctx context.Context, postIds []bson.ObjectID) error
+}
+
+var _ IPostRevisionDao = (*PostRevisionDao)(nil)
+
+func NewPostRevisionDao(db *mongo.Database) *PostRevisionDao {
+	return &PostRevisionDao{coll: db.Collection("post_revision")}
+}
+
+type PostRevisionDao struct {
+	coll *mongo.Collection
+}
+
+// Create 创建文章历史版本
+func (d *PostRevisionDao) Create(ctx context.Context, revision *PostRevision) error {
+	revision.ID = bson.NewObjectID()
+	revision.CreatedAt = time.Now()
+	insertResult, err := d.coll.InsertOne(ctx, revision)
+	if err != nil {
+		return err
+	}
+	if insertResult.InsertedID == nil {
+		return errors.New("插入文章历史版本失败")
+	}
+	return nil
+}
+
+// GetByID 根据id获取文章历史版本
+func (d *PostRevisionDao) GetByID(ctx context.Context, id bson.ObjectID) (*PostRevision, error) {
+	var revision PostRevision
+	err := d.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&revision)
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// GetListByPostID 获取文章的所有历史版本, 按时间倒序
+func (d *PostRevisionDao) GetListByPostID(ctx context.Context, postId bson.ObjectID) ([]*PostRevision, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := d.coll.Find(ctx, bson.M{"post_id": postId}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var revisions []*PostRevision
+	if err = cursor.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// DeleteBatch 批量删除历史版本
+func (d *PostRevisionDao) DeleteBatch(ctx context.Context, ids []bson.ObjectID) error {
+	_, err := d.coll.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	return err
+}
+
+// DeleteByPostIDs 删除文章的所有历史版本
+func (d *PostRevisionDao) DeleteByPostIDs(ctx context.Context, postIds []bson.ObjectID) error {
+	_, err := d.coll.DeleteMany(ctx, bson.M{"post_id": bson.M{"$in": postIds}})
+	return err
+}
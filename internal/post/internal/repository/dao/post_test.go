@@ -0,0 +1,172 @@
+package dao
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// newTestPostDao 连接STELLUX_TEST_MONGO_URI指定的MongoDB并使用临时数据库, 未设置时跳过测试
+func newTestPostDao(t *testing.T) *PostDao {
+	t.Helper()
+	uri := os.Getenv("STELLUX_TEST_MONGO_URI")
+	if uri == "" {
+		t.Skip("未设置STELLUX_TEST_MONGO_URI, 跳过需要MongoDB的测试")
+	}
+	client, err := mongo.Connect(options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := client.Database("stellux_test_" + bson.NewObjectID().Hex())
+	t.Cleanup(func() {
+		_ = db.Drop(context.Background())
+		_ = client.Disconnect(context.Background())
+	})
+	return NewPostDao(db)
+}
+
+func TestPostDaoPublishedAt(t *testing.T) {
+	d := newTestPostDao(t)
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Millisecond)
+	publishAt := now.Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		published bool
+		publish   func(t *testing.T, id bson.ObjectID)
+		want      time.Time
+	}{
+		{
+			name: "定时发布到期后以定时发布时间为发布时间",
+			publish: func(t *testing.T, id bson.ObjectID) {
+				if err := d.SchedulePublish(ctx, id, publishAt); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := d.PublishDue(ctx, now); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: publishAt,
+		},
+		{
+			name: "手动发布以发布操作的时间为发布时间",
+			publish: func(t *testing.T, id bson.ObjectID) {
+				if err := d.UpdatePostPublishStatus(ctx, id, true); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name:      "下线后重新发布保留首次发布时间",
+			published: true,
+			publish: func(t *testing.T, id bson.ObjectID) {
+				if err := d.UpdatePostPublishStatus(ctx, id, false); err != nil {
+					t.Fatal(err)
+				}
+				if err := d.SchedulePublish(ctx, id, publishAt); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := d.PublishDue(ctx, now); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			post := &Post{Title: tt.name, IsPublish: tt.published}
+			if err := d.Create(ctx, post); err != nil {
+				t.Fatal(err)
+			}
+			created, err := d.GetByID(ctx, post.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			before := time.Now()
+			tt.publish(t, post.ID)
+
+			got, err := d.GetPublishByID(ctx, post.ID)
+			if err != nil {
+				t.Fatalf("GetPublishByID() error = %v", err)
+			}
+			if got.PublishAt != nil {
+				t.Errorf("PublishAt = %v, want nil", got.PublishAt)
+			}
+			if got.PublishedAt == nil {
+				t.Fatal("PublishedAt = nil")
+			}
+			switch {
+			case !tt.want.IsZero():
+				if !got.PublishedAt.Equal(tt.want) {
+					t.Errorf("PublishedAt = %v, want %v", got.PublishedAt, tt.want)
+				}
+			case tt.published:
+				if !got.PublishedAt.Equal(*created.PublishedAt) {
+					t.Errorf("PublishedAt = %v, want %v", got.PublishedAt, created.PublishedAt)
+				}
+			default:
+				if got.PublishedAt.Before(before.Truncate(time.Millisecond)) {
+					t.Errorf("PublishedAt = %v, want not before %v", got.PublishedAt, before)
+				}
+			}
+		})
+	}
+}
+
+func TestPostDaoPublicLookup(t *testing.T) {
+	d := newTestPostDao(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		post    *Post
+		prepare func(t *testing.T, id bson.ObjectID)
+		want    bool
+	}{
+		{name: "已发布", post: &Post{Alias: "published", IsPublish: true}, want: true},
+		{name: "草稿", post: &Post{Alias: "draft"}},
+		{
+			name: "定时发布未到期",
+			post: &Post{Alias: "scheduled"},
+			prepare: func(t *testing.T, id bson.ObjectID) {
+				if err := d.SchedulePublish(ctx, id, time.Now().Add(time.Hour)); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "已软删除",
+			post: &Post{Alias: "deleted", IsPublish: true},
+			prepare: func(t *testing.T, id bson.ObjectID) {
+				if err := d.SoftDelete(ctx, id); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := d.Create(ctx, tt.post); err != nil {
+				t.Fatal(err)
+			}
+			if tt.prepare != nil {
+				tt.prepare(t, tt.post.ID)
+			}
+			if _, err := d.GetPublishByID(ctx, tt.post.ID); (err == nil) != tt.want {
+				t.Errorf("GetPublishByID() error = %v, want found %v", err, tt.want)
+			}
+			if _, err := d.FindPublishByAlias(ctx, tt.post.Alias); (err == nil) != tt.want {
+				t.Errorf("FindPublishByAlias() error = %v, want found %v", err, tt.want)
+			}
+			if _, err := d.GetByID(ctx, tt.post.ID); err != nil {
+				t.Errorf("GetByID() error = %v, 后台查询不应过滤", err)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/codepzj/Stellux-Server/internal/post/internal/domain"
 	"github.com/codepzj/Stellux-Server/internal/post/internal/repository/dao"
@@ -14,6 +15,9 @@ type IPostRepository interface {
 	Create(ctx context.Context, post *domain.Post) error
 	Update(ctx context.Context, post *domain.Post) error
 	UpdatePublishStatus(ctx context.Context, id bson.ObjectID, isPublish bool) error
+	SchedulePublish(ctx context.Context, id bson.ObjectID, publishAt time.Time) error
+	UnschedulePublish(ctx context.Context, id bson.ObjectID) error
+	PublishDue(ctx context.Context, now time.Time) (int64, error)
 	SoftDelete(ctx context.Context, id bson.ObjectID) error
 	SoftDeleteBatch(ctx context.Context, ids []bson.ObjectID) error
 	Delete(ctx context.Context, id bson.ObjectID) error
@@ -21,11 +25,15 @@ type IPostRepository interface {
 	Restore(ctx context.Context, id bson.ObjectID) error
 	RestoreBatch(ctx context.Context, ids []bson.ObjectID) error
 	GetByID(ctx context.Context, id bson.ObjectID) (*domain.Post, error)
+	GetPublishByID(ctx context.Context, id bson.ObjectID) (*domain.Post, error)
 	GetByKeyWord(ctx context.Context, keyWord string) ([]*domain.Post, error)
 	GetDetailByID(ctx context.Context, id bson.ObjectID) (*domain.PostDetail, error)
+	GetPublishDetailByID(ctx context.Context, id bson.ObjectID) (*domain.PostDetail, error)
 	GetList(ctx context.Context, page *domain.PostQueryPage, postType string) ([]*domain.PostDetail, int64, error)
 	GetAllPublishPost(ctx context.Context) ([]*domain.PostDetail, error)
 	FindByAlias(ctx context.Context, alias string) (*domain.Post, error)
+	FindPublishByAlias(ctx context.Context, alias string) (*domain.Post, error)
+	IsOwner(ctx context.Context, ids []bson.ObjectID, authorId bson.ObjectID) (bool, error)
 }
 
 var _ IPostRepository = (*PostRepository)(nil)
@@ -39,7 +47,12 @@ type PostRepository struct {
 }
 
 func (r *PostRepository) Create(ctx context.Context, post *domain.Post) error {
-	return r.dao.Create(ctx, r.PostDomainToPostDO(post))
+	postDO := r.PostDomainToPostDO(post)
+	if err := r.dao.Create(ctx, postDO); err != nil {
+		return err
+	}
+	post.Id = postDO.ID
+	return nil
 }
 
 func (r *PostRepository) Update(ctx context.Context, post *domain.Post) error {
@@ -51,6 +64,21 @@ func (r *PostRepository) UpdatePublishStatus(ctx context.Context, id bson.Object
 	return r.dao.UpdatePostPublishStatus(ctx, id, isPublish)
 }
 
+// SchedulePublish 设置定时发布
+func (r *PostRepository) SchedulePublish(ctx context.Context, id bson.ObjectID, publishAt time.Time) error {
+	return r.dao.SchedulePublish(ctx, id, publishAt)
+}
+
+// UnschedulePublish 取消定时发布
+func (r *PostRepository) UnschedulePublish(ctx context.Context, id bson.ObjectID) error {
+	return r.dao.UnschedulePublish(ctx, id)
+}
+
+// PublishDue 发布到期的定时文章
+func (r *PostRepository) PublishDue(ctx context.Context, now time.Time) (int64, error) {
+	return r.dao.PublishDue(ctx, now)
+}
+
 func (r *PostRepository) SoftDelete(ctx context.Context, id bson.ObjectID) error {
 	return r.dao.SoftDelete(ctx, id)
 }
@@ -89,6 +117,15 @@ func (r *PostRepository) GetByID(ctx context.Context, id bson.ObjectID) (*domain
 	return r.PostDOToPostDomain(post), nil
 }
 
+// GetPublishByID 获取已发布的文章
+func (r *PostRepository) GetPublishByID(ctx context.Context, id bson.ObjectID) (*domain.Post, error) {
+	post, err := r.dao.GetPublishByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.PostDOToPostDomain(post), nil
+}
+
 // GetDetailByID 获取文章
 func (r *PostRepository) GetDetailByID(ctx context.Context, id bson.ObjectID) (*domain.PostDetail, error) {
 	postCategoryTags, err := r.dao.GetDetailByID(ctx, id)
@@ -98,6 +135,15 @@ func (r *PostRepository) GetDetailByID(ctx context.Context, id bson.ObjectID) (*
 	return r.PostCategoryTagsDOToPostDetail(postCategoryTags), nil
 }
 
+// GetPublishDetailByID 获取已发布的文章详情
+func (r *PostRepository) GetPublishDetailByID(ctx context.Context, id bson.ObjectID) (*domain.PostDetail, error) {
+	postCategoryTags, err := r.dao.GetPublishDetailByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.PostCategoryTagsDOToPostDetail(postCategoryTags), nil
+}
+
 // GetByKeyWord 获取文章
 func (r *PostRepository) GetByKeyWord(ctx context.Context, keyWord string) ([]*domain.Post, error) {
 	posts, err := r.dao.GetByKeyWord(ctx, keyWord)
@@ -124,6 +170,7 @@ func (r *PostRepository) buildPostQueryCondition(page *domain.PostQueryPage, pos
 	case "publish":
 		conditions = append(conditions, bson.E{Key: "deleted_at", Value: nil})
 		conditions = append(conditions, bson.E{Key: "is_publish", Value: true})
+		conditions = append(conditions, bson.E{Key: "publish_at", Value: notAfter(time.Now())})
 	case "draft":
 		conditions = append(conditions, bson.E{Key: "deleted_at", Value: nil})
 		conditions = append(conditions, bson.E{Key: "is_publish", Value: false})
@@ -213,6 +260,7 @@ func (r *PostRepository) GetAllPublishPost(ctx context.Context) ([]*domain.PostD
 	cond := bson.D{
 		{Key: "deleted_at", Value: nil},
 		{Key: "is_publish", Value: true},
+		{Key: "publish_at", Value: notAfter(time.Now())},
 	}
 
 	pipeline := mongo.Pipeline{
@@ -251,19 +299,44 @@ func (r *PostRepository) FindByAlias(ctx context.Context, alias string) (*domain
 	return r.PostDOToPostDomain(post), nil
 }
 
+// FindPublishByAlias 根据别名获取已发布的文章
+func (r *PostRepository) FindPublishByAlias(ctx context.Context, alias string) (*domain.Post, error) {
+	post, err := r.dao.FindPublishByAlias(ctx, alias)
+	if err != nil {
+		return nil, err
+	}
+	return r.PostDOToPostDomain(post), nil
+}
+
+// IsOwner 判断ids中的文章是否都由指定用户创建
+func (r *PostRepository) IsOwner(ctx context.Context, ids []bson.ObjectID, authorId bson.ObjectID) (bool, error) {
+	count, err := r.dao.CountNotOwned(ctx, ids, authorId)
+	if err != nil {
+		return false, err
+	}
+	return count == 0, nil
+}
+
 func (r *PostRepository) PostDomainToPostDO(post *domain.Post) *dao.Post {
+	var publishAt *time.Time
+	if !post.PublishAt.IsZero() {
+		publishAt = &post.PublishAt
+	}
 	return &dao.Post{
 		CreatedAt:   post.CreatedAt,
 		Title:       post.Title,
 		Content:     post.Content,
 		Description: post.Description,
 		Author:      post.Author,
+		AuthorID:    post.AuthorId,
 		Alias:       post.Alias,
 		CategoryID:  post.CategoryId,
 		TagsID:      post.TagsId,
 		IsPublish:   post.IsPublish,
 		IsTop:       post.IsTop,
 		Thumbnail:   post.Thumbnail,
+		PublishAt:   publishAt,
+		PublishedAt: lo.EmptyableToPtr(post.PublishedAt),
 	}
 }
 
@@ -290,6 +363,10 @@ func (r *PostRepository) PostDOToPostDomainList(posts []*dao.Post) []*domain.Pos
 }
 
 func (r *PostRepository) PostDOToPostDomain(post *dao.Post) *domain.Post {
+	var publishAt time.Time
+	if post.PublishAt != nil {
+		publishAt = *post.PublishAt
+	}
 	return &domain.Post{
 		Id:          post.ID,
 		CreatedAt:   post.CreatedAt,
@@ -298,16 +375,23 @@ func (r *PostRepository) PostDOToPostDomain(post *dao.Post) *domain.Post {
 		Content:     post.Content,
 		Description: post.Description,
 		Author:      post.Author,
+		AuthorId:    post.AuthorID,
 		Alias:       post.Alias,
 		CategoryId:  post.CategoryID,
 		TagsId:      post.TagsID,
 		IsPublish:   post.IsPublish,
 		IsTop:       post.IsTop,
 		Thumbnail:   post.Thumbnail,
+		PublishAt:   publishAt,
+		PublishedAt: lo.FromPtr(post.PublishedAt),
 	}
 }
 
 func (r *PostRepository) PostCategoryTagsDOToPostDetail(postCategoryTags *dao.PostCategoryTags) *domain.PostDetail {
+	var publishAt time.Time
+	if postCategoryTags.PublishAt != nil {
+		publishAt = *postCategoryTags.PublishAt
+	}
 	return &domain.PostDetail{
 		Id:          postCategoryTags.Id,
 		CreatedAt:   postCategoryTags.CreatedAt,
@@ -322,6 +406,8 @@ func (r *PostRepository) PostCategoryTagsDOToPostDetail(postCategoryTags *dao.Po
 		Thumbnail:   postCategoryTags.Thumbnail,
 		IsPublish:   postCategoryTags.IsPublish,
 		IsTop:       postCategoryTags.IsTop,
+		PublishAt:   publishAt,
+		PublishedAt: lo.FromPtr(postCategoryTags.PublishedAt),
 	}
 }
 
@@ -331,6 +417,11 @@ func (r *PostRepository) PostCategoryTagsDOToPostDetailList(postCategoryTags []*
 	})
 }
 
+// notAfter 匹配未设置或不晚于指定时间的字段, 用于隐藏尚未到期的定时文章
+func notAfter(t time.Time) bson.M {
+	return bson.M{"$not": bson.M{"$gt": t}}
+}
+
 func (r *PostRepository) OrderConvertToInt(order string) int {
 	switch order {
 	case "DESC":
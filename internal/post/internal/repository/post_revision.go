@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/codepzj/Stellux-Server/internal/post/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/post/internal/repository/dao"
+	"github.com/samber/lo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type IPostRevisionRepository interface {
+	Create(ctx context.Context, revision *domain.PostRevision) error
+	GetByID(ctx context.Context, id bson.ObjectID) (*domain.PostRevision, error)
+	GetListByPostID(ctx context.Context, postId bson.ObjectID) ([]*domain.PostRevision, error)
+	DeleteBatch(ctx context.Context, ids []bson.ObjectID) error
+	DeleteByPostIDs(ctx context.Context, postIds []bson.ObjectID) error
+}
+
+var _ IPostRevisionRepository = (*PostRevisionRepository)(nil)
+
+func NewPostRevisionRepository(dao dao.IPostRevisionDao) *PostRevisionRepository {
+	return &PostRevisionRepository{dao: dao}
+}
+
+type PostRevisionRepository struct {
+	dao dao.IPostRevisionDao
+}
+
+func (r *PostRevisionRepository) Create(ctx context.Context, revision *domain.PostRevision) error {
+	return r.dao.Create(ctx, r.PostRevisionDomainToDO(revision))
+}
+
+func (r *PostRevisionRepository) GetByID(ctx context.Context, id bson.ObjectID) (*domain.PostRevision, error) {
+	revision, err := r.dao.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.PostRevisionDOToDomain(revision), nil
+}
+
+// GetListByPostID 获取文章的所有历史版本, 按时间倒序
+func (r *PostRevisionRepository) GetListByPostID(ctx context.Context, postId bson.ObjectID) ([]*domain.PostRevision, error) {
+	revisions, err := r.dao.GetListByPostID(ctx, postId)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(revisions, func(revision *dao.PostRevision, _ int) *domain.PostRevision {
+		return r.PostRevisionDOToDomain(revision)
+	}), nil
+}
+
+func (r *PostRevisionRepository) DeleteBatch(ctx context.Context, ids []bson.ObjectID) error {
+	return r.dao.DeleteBatch(ctx, ids)
+}
+
+func (r *PostRevisionRepository) DeleteByPostIDs(ctx context.Context, postIds []bson.ObjectID) error {
+	return r.dao.DeleteByPostIDs(ctx, postIds)
+}
+
+func (r *PostRevisionRepository) PostRevisionDomainToDO(revision *domain.PostRevision) *dao.PostRevision {
+	return &dao.PostRevision{
+		PostID:      revision.PostId,
+		Title:       revision.Title,
+		Content:     revision.Content,
+		Description: revision.Description,
+		Author:      revision.Author,
+		Alias:       revision.Alias,
+		CategoryID:  revision.CategoryId,
+		TagsID:      revision.TagsId,
+		IsPublish:   revision.IsPublish,
+		IsTop:       revision.IsTop,
+		Thumbnail:   revision.Thumbnail,
+	}
+}
+
+func (r *PostRevisionRepository) PostRevisionDOToDomain(revision *dao.PostRevision) *domain.PostRevision {
+	return &domain.PostRevision{
+		Id:          revision.ID,
+		CreatedAt:   revision.CreatedAt,
+		PostId:      revision.PostID,
+		Title:       revision.Title,
+		Content:     revision.Content,
+		Description: revision.Description,
+		Author:      revision.Author,
+		Alias:       revision.Alias,
+		CategoryId:  revision.CategoryID,
+		TagsId:      revision.TagsID,
+		IsPublish:   revision.IsPublish,
+		IsTop:       revision.IsTop,
+		Thumbnail:   revision.Thumbnail,
+	}
+}
@@ -3,12 +3,17 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/audit"
 	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
 
 	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
 	"github.com/codepzj/Stellux-Server/internal/post/internal/domain"
 	"github.com/codepzj/Stellux-Server/internal/post/internal/repository"
+	"github.com/codepzj/Stellux-Server/internal/search"
+	"github.com/codepzj/Stellux-Server/internal/sitemap"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
@@ -26,21 +31,43 @@ type IPostService interface {
 	GetPostById(ctx context.Context, id bson.ObjectID) (*domain.Post, error)
 	GetPostByKeyWord(ctx context.Context, keyWord string) ([]*domain.Post, error)
 	GetPostDetailById(ctx context.Context, id bson.ObjectID) (*domain.PostDetail, error)
+	AdminGetPostDetailById(ctx context.Context, id bson.ObjectID) (*domain.PostDetail, error)
 	GetPostList(ctx context.Context, page *apiwrap.Page, labelName, categoryName, postType string) ([]*domain.PostDetail, int64, error)
 	GetAllPublishPost(ctx context.Context) ([]*domain.PostDetail, error)
 	FindByAlias(ctx context.Context, alias string) (*domain.Post, error)
+	AdminGetPostRevisionList(ctx context.Context, postId bson.ObjectID) ([]*domain.PostRevision, error)
+	AdminGetPostRevision(ctx context.Context, id bson.ObjectID) (*domain.PostRevision, error)
+	AdminDiffPostRevision(ctx context.Context, oldId bson.ObjectID, newId bson.ObjectID) (*domain.PostRevisionDiff, error)
+	AdminRestorePostRevision(ctx context.Context, id bson.ObjectID) error
+	AdminSchedulePost(ctx context.Context, id bson.ObjectID, publishAt time.Time) error
+	AdminUnschedulePost(ctx context.Context, id bson.ObjectID) error
+	StartPublishScheduler(ctx context.Context)
+	AdminCheckPostOwner(ctx context.Context, authorId bson.ObjectID, ids []bson.ObjectID) error
 }
 
+// ErrPostNotOwned 操作了不属于自己的文章
+var ErrPostNotOwned = errors.New("只能操作自己创建的文章")
+
 var _ IPostService = (*PostService)(nil)
 
-func NewPostService(repo repository.IPostRepository) *PostService {
+func NewPostService(repo repository.IPostRepository, revisionRepo repository.IPostRevisionRepository, cfg *conf.Config, searchServ search.Service, sitemapServ sitemap.Service, auditServ audit.Service) *PostService {
 	return &PostService{
-		repo: repo,
+		repo:         repo,
+		revisionRepo: revisionRepo,
+		revisionCfg:  cfg.Revision,
+		searchServ:   searchServ,
+		sitemapServ:  sitemapServ,
+		auditServ:    auditServ,
 	}
 }
 
 type PostService struct {
-	repo repository.IPostRepository
+	repo         repository.IPostRepository
+	revisionRepo repository.IPostRevisionRepository
+	revisionCfg  conf.Revision
+	searchServ   search.Service
+	sitemapServ  sitemap.Service
+	auditServ    audit.Service
 }
 
 func (s *PostService) AdminCreatePost(ctx context.Context, post *domain.Post) error {
@@ -67,6 +94,10 @@ func (s *PostService) AdminCreatePost(ctx context.Context, post *domain.Post) er
 		return err
 	}
 
+	s.searchServ.SyncPost(ctx, post.Id)
+	s.sitemapServ.Invalidate()
+	s.recordAudit(ctx, audit.ActionCreate, nil, post.Id)
+
 	logger.Info("创建文章成功",
 		logger.WithString("postId", post.Id.Hex()),
 		logger.WithString("title", post.Title),
@@ -90,7 +121,19 @@ func (s *PostService) AdminUpdatePost(ctx context.Context, post *domain.Post) er
 		return errors.New("别名已存在")
 	}
 
-	err := s.repo.Update(ctx, post)
+	oldPost, err := s.repo.GetByID(ctx, post.Id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return errors.New("文章不存在")
+		}
+		logger.Error("查询文章失败",
+			logger.WithError(err),
+			logger.WithString("postId", post.Id.Hex()),
+		)
+		return err
+	}
+
+	err = s.repo.Update(ctx, post)
 	if err != nil {
 		logger.Error("更新文章失败",
 			logger.WithError(err),
@@ -99,6 +142,15 @@ func (s *PostService) AdminUpdatePost(ctx context.Context, post *domain.Post) er
 		return err
 	}
 
+	// 保存更新前的快照, 内容未变化时不产生新版本
+	if isPostChanged(oldPost, post) {
+		s.saveRevision(ctx, oldPost)
+	}
+
+	s.searchServ.SyncPost(ctx, post.Id)
+	s.sitemapServ.Invalidate()
+	s.recordAudit(ctx, audit.ActionUpdate, map[bson.ObjectID]*domain.Post{post.Id: oldPost}, post.Id)
+
 	logger.Info("更新文章成功",
 		logger.WithString("postId", post.Id.Hex()),
 		logger.WithString("title", post.Title),
@@ -108,6 +160,7 @@ func (s *PostService) AdminUpdatePost(ctx context.Context, post *domain.Post) er
 }
 
 func (s *PostService) AdminUpdatePostPublishStatus(ctx context.Context, id bson.ObjectID, isPublish bool) error {
+	before := s.postSnapshots(ctx, id)
 	err := s.repo.UpdatePublishStatus(ctx, id, isPublish)
 	if err != nil {
 		logger.Error("更新发布状态失败",
@@ -117,6 +170,10 @@ func (s *PostService) AdminUpdatePostPublishStatus(ctx context.Context, id bson.
 		return err
 	}
 
+	s.searchServ.SyncPost(ctx, id)
+	s.sitemapServ.Invalidate()
+	s.recordAudit(ctx, audit.ActionUpdate, before, id)
+
 	logger.Info("更新发布状态成功",
 		logger.WithString("postId", id.Hex()),
 		logger.WithAny("isPublish", isPublish),
@@ -126,6 +183,7 @@ func (s *PostService) AdminUpdatePostPublishStatus(ctx context.Context, id bson.
 }
 
 func (s *PostService) AdminSoftDeletePost(ctx context.Context, id bson.ObjectID) error {
+	before := s.postSnapshots(ctx, id)
 	err := s.repo.SoftDelete(ctx, id)
 	if err != nil {
 		logger.Error("软删除文章失败",
@@ -135,6 +193,10 @@ func (s *PostService) AdminSoftDeletePost(ctx context.Context, id bson.ObjectID)
 		return err
 	}
 
+	s.searchServ.SyncPost(ctx, id)
+	s.sitemapServ.Invalidate()
+	s.recordAudit(ctx, audit.ActionSoftDelete, before, id)
+
 	logger.Info("软删除文章成功",
 		logger.WithString("postId", id.Hex()),
 	)
@@ -143,6 +205,7 @@ func (s *PostService) AdminSoftDeletePost(ctx context.Context, id bson.ObjectID)
 }
 
 func (s *PostService) AdminSoftDeletePostBatch(ctx context.Context, ids []bson.ObjectID) error {
+	before := s.postSnapshots(ctx, ids...)
 	err := s.repo.SoftDeleteBatch(ctx, ids)
 	if err != nil {
 		logger.Error("批量软删除失败",
@@ -152,6 +215,10 @@ func (s *PostService) AdminSoftDeletePostBatch(ctx context.Context, ids []bson.O
 		return err
 	}
 
+	s.searchServ.SyncPost(ctx, ids...)
+	s.sitemapServ.Invalidate()
+	s.recordAudit(ctx, audit.ActionSoftDelete, before, ids...)
+
 	logger.Info("批量软删除成功",
 		logger.WithInt("count", len(ids)),
 	)
@@ -160,6 +227,7 @@ func (s *PostService) AdminSoftDeletePostBatch(ctx context.Context, ids []bson.O
 }
 
 func (s *PostService) AdminDeletePost(ctx context.Context, id bson.ObjectID) error {
+	before := s.postSnapshots(ctx, id)
 	err := s.repo.Delete(ctx, id)
 	if err != nil {
 		logger.Error("删除文章失败",
@@ -169,6 +237,17 @@ func (s *PostService) AdminDeletePost(ctx context.Context, id bson.ObjectID) err
 		return err
 	}
 
+	if err := s.revisionRepo.DeleteByPostIDs(ctx, []bson.ObjectID{id}); err != nil {
+		logger.Error("删除文章历史版本失败",
+			logger.WithError(err),
+			logger.WithString("postId", id.Hex()),
+		)
+	}
+
+	s.searchServ.SyncPost(ctx, id)
+	s.sitemapServ.Invalidate()
+	s.recordAudit(ctx, audit.ActionDelete, before, id)
+
 	logger.Info("删除文章成功",
 		logger.WithString("postId", id.Hex()),
 	)
@@ -177,6 +256,7 @@ func (s *PostService) AdminDeletePost(ctx context.Context, id bson.ObjectID) err
 }
 
 func (s *PostService) AdminDeletePostBatch(ctx context.Context, ids []bson.ObjectID) error {
+	before := s.postSnapshots(ctx, ids...)
 	err := s.repo.DeleteBatch(ctx, ids)
 	if err != nil {
 		logger.Error("批量删除失败",
@@ -186,6 +266,17 @@ func (s *PostService) AdminDeletePostBatch(ctx context.Context, ids []bson.Objec
 		return err
 	}
 
+	if err := s.revisionRepo.DeleteByPostIDs(ctx, ids); err != nil {
+		logger.Error("删除文章历史版本失败",
+			logger.WithError(err),
+			logger.WithInt("count", len(ids)),
+		)
+	}
+
+	s.searchServ.SyncPost(ctx, ids...)
+	s.sitemapServ.Invalidate()
+	s.recordAudit(ctx, audit.ActionDelete, before, ids...)
+
 	logger.Info("批量删除成功",
 		logger.WithInt("count", len(ids)),
 	)
@@ -194,6 +285,7 @@ func (s *PostService) AdminDeletePostBatch(ctx context.Context, ids []bson.Objec
 }
 
 func (s *PostService) AdminRestorePost(ctx context.Context, id bson.ObjectID) error {
+	before := s.postSnapshots(ctx, id)
 	err := s.repo.Restore(ctx, id)
 	if err != nil {
 		logger.Error("恢复文章失败",
@@ -203,6 +295,10 @@ func (s *PostService) AdminRestorePost(ctx context.Context, id bson.ObjectID) er
 		return err
 	}
 
+	s.searchServ.SyncPost(ctx, id)
+	s.sitemapServ.Invalidate()
+	s.recordAudit(ctx, audit.ActionRestore, before, id)
+
 	logger.Info("恢复文章成功",
 		logger.WithString("postId", id.Hex()),
 	)
@@ -211,6 +307,7 @@ func (s *PostService) AdminRestorePost(ctx context.Context, id bson.ObjectID) er
 }
 
 func (s *PostService) AdminRestorePostBatch(ctx context.Context, ids []bson.ObjectID) error {
+	before := s.postSnapshots(ctx, ids...)
 	err := s.repo.RestoreBatch(ctx, ids)
 	if err != nil {
 		logger.Error("批量恢复失败",
@@ -220,14 +317,19 @@ func (s *PostService) AdminRestorePostBatch(ctx context.Context, ids []bson.Obje
 		return err
 	}
 
+	s.searchServ.SyncPost(ctx, ids...)
+	s.sitemapServ.Invalidate()
+	s.recordAudit(ctx, audit.ActionRestore, before, ids...)
+
 	logger.Info("批量恢复成功",
 		logger.WithInt("count", len(ids)),
 	)
 	return nil
 }
 
+// GetPostById 获取已发布的文章, 草稿和定时发布未到期的文章对外不可见
 func (s *PostService) GetPostById(ctx context.Context, id bson.ObjectID) (*domain.Post, error) {
-	post, err := s.repo.GetByID(ctx, id)
+	post, err := s.repo.GetPublishByID(ctx, id)
 	if err != nil {
 		logger.Error("查询文章失败",
 			logger.WithError(err),
@@ -250,7 +352,21 @@ func (s *PostService) GetPostByKeyWord(ctx context.Context, keyWord string) ([]*
 	return posts, nil
 }
 
+// GetPostDetailById 获取已发布的文章详情, 草稿和定时发布未到期的文章对外不可见
 func (s *PostService) GetPostDetailById(ctx context.Context, id bson.ObjectID) (*domain.PostDetail, error) {
+	detail, err := s.repo.GetPublishDetailByID(ctx, id)
+	if err != nil {
+		logger.Error("查询文章详情失败",
+			logger.WithError(err),
+			logger.WithString("postId", id.Hex()),
+		)
+		return nil, err
+	}
+	return detail, nil
+}
+
+// AdminGetPostDetailById 获取任意状态的文章详情, 用于后台编辑草稿和定时文章
+func (s *PostService) AdminGetPostDetailById(ctx context.Context, id bson.ObjectID) (*domain.PostDetail, error) {
 	detail, err := s.repo.GetDetailByID(ctx, id)
 	if err != nil {
 		logger.Error("查询文章详情失败",
@@ -310,9 +426,9 @@ func (s *PostService) GetAllPublishPost(ctx context.Context) ([]*domain.PostDeta
 	return posts, nil
 }
 
-// FindByAlias 根据别名获取文章
+// FindByAlias 根据别名获取已发布的文章
 func (s *PostService) FindByAlias(ctx context.Context, alias string) (*domain.Post, error) {
-	post, err := s.repo.FindByAlias(ctx, alias)
+	post, err := s.repo.FindPublishByAlias(ctx, alias)
 	if err != nil {
 		logger.Error("查询文章失败",
 			logger.WithError(err),
@@ -322,3 +438,23 @@ func (s *PostService) FindByAlias(ctx context.Context, alias string) (*domain.Po
 	}
 	return post, nil
 }
+
+// AdminCheckPostOwner 校验ids中的文章是否都由指定用户创建, 否则返回ErrPostNotOwned
+func (s *PostService) AdminCheckPostOwner(ctx context.Context, authorId bson.ObjectID, ids []bson.ObjectID) error {
+	owned, err := s.repo.IsOwner(ctx, ids, authorId)
+	if err != nil {
+		logger.Error("校验文章归属失败",
+			logger.WithError(err),
+			logger.WithString("authorId", authorId.Hex()),
+		)
+		return err
+	}
+	if !owned {
+		logger.Warn("操作了不属于自己的文章",
+			logger.WithString("authorId", authorId.Hex()),
+			logger.WithAny("postIds", ids),
+		)
+		return ErrPostNotOwned
+	}
+	return nil
+}
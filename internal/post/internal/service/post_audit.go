@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+
+	"github.com/codepzj/Stellux-Server/internal/audit"
+	"github.com/codepzj/Stellux-Server/internal/post/internal/domain"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// postSnapshots 查询修改前的文章, 用于审计日志对比, 查询失败的文章按不存在处理
+func (s *PostService) postSnapshots(ctx context.Context, ids ...bson.ObjectID) map[bson.ObjectID]*domain.Post {
+	snapshots := make(map[bson.ObjectID]*domain.Post, len(ids))
+	for _, id := range ids {
+		if post, err := s.repo.GetByID(ctx, id); err == nil {
+			snapshots[id] = post
+		}
+	}
+	return snapshots
+}
+
+// recordAudit 修改成功后逐篇记录审计日志, 删除操作不再查询修改后的文章
+func (s *PostService) recordAudit(ctx context.Context, action string, before map[bson.ObjectID]*domain.Post, ids ...bson.ObjectID) {
+	after := map[bson.ObjectID]*domain.Post{}
+	if action != audit.ActionDelete {
+		after = s.postSnapshots(ctx, ids...)
+	}
+	for _, id := range ids {
+		s.auditServ.Record(ctx, action, audit.EntityPost, id.Hex(), before[id], after[id])
+	}
+}
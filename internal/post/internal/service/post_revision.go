@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/codepzj/Stellux-Server/internal/post/internal/domain"
+	"github.com/pmezard/go-difflib/difflib"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// AdminGetPostRevisionList 获取文章的历史版本列表, 按时间倒序
+func (s *PostService) AdminGetPostRevisionList(ctx context.Context, postId bson.ObjectID) ([]*domain.PostRevision, error) {
+	revisions, err := s.revisionRepo.GetListByPostID(ctx, postId)
+	if err != nil {
+		logger.Error("查询文章历史版本失败",
+			logger.WithError(err),
+			logger.WithString("postId", postId.Hex()),
+		)
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// AdminGetPostRevision 获取文章历史版本详情
+func (s *PostService) AdminGetPostRevision(ctx context.Context, id bson.ObjectID) (*domain.PostRevision, error) {
+	revision, err := s.revisionRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.New("历史版本不存在")
+		}
+		logger.Error("查询文章历史版本失败",
+			logger.WithError(err),
+			logger.WithString("revisionId", id.Hex()),
+		)
+		return nil, err
+	}
+	return revision, nil
+}
+
+// AdminDiffPostRevision 对比两个历史版本, newId为空时与当前文章对比
+func (s *PostService) AdminDiffPostRevision(ctx context.Context, oldId bson.ObjectID, newId bson.ObjectID) (*domain.PostRevisionDiff, error) {
+	oldRevision, err := s.AdminGetPostRevision(ctx, oldId)
+	if err != nil {
+		return nil, err
+	}
+
+	var newRevision *domain.PostRevision
+	toFile := "current"
+	if newId.IsZero() {
+		post, err := s.repo.GetByID(ctx, oldRevision.PostId)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return nil, errors.New("文章不存在")
+			}
+			return nil, err
+		}
+		newRevision = postToRevision(post)
+		newRevision.CreatedAt = post.UpdatedAt
+	} else {
+		newRevision, err = s.AdminGetPostRevision(ctx, newId)
+		if err != nil {
+			return nil, err
+		}
+		if newRevision.PostId != oldRevision.PostId {
+			return nil, errors.New("两个历史版本不属于同一篇文章")
+		}
+		toFile = newId.Hex()
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(revisionText(oldRevision)),
+		B:        difflib.SplitLines(revisionText(newRevision)),
+		FromFile: oldId.Hex(),
+		FromDate: oldRevision.CreatedAt.Format(time.DateTime),
+		ToFile:   toFile,
+		ToDate:   newRevision.CreatedAt.Format(time.DateTime),
+		Context:  3,
+	})
+	if err != nil {
+		logger.Error("生成版本差异失败",
+			logger.WithError(err),
+			logger.WithString("oldId", oldId.Hex()),
+		)
+		return nil, err
+	}
+	return &domain.PostRevisionDiff{OldId: oldId, NewId: newId, Diff: diff}, nil
+}
+
+// AdminRestorePostRevision 将文章恢复到指定历史版本, 恢复前的内容同样会保存为历史版本
+func (s *PostService) AdminRestorePostRevision(ctx context.Context, id bson.ObjectID) error {
+	revision, err := s.AdminGetPostRevision(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.AdminUpdatePost(ctx, &domain.Post{
+		Id:          revision.PostId,
+		Title:       revision.Title,
+		Content:     revision.Content,
+		Description: revision.Description,
+		Author:      revision.Author,
+		Alias:       revision.Alias,
+		CategoryId:  revision.CategoryId,
+		TagsId:      revision.TagsId,
+		IsPublish:   revision.IsPublish,
+		IsTop:       revision.IsTop,
+		Thumbnail:   revision.Thumbnail,
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("恢复文章历史版本成功",
+		logger.WithString("postId", revision.PostId.Hex()),
+		logger.WithString("revisionId", id.Hex()),
+	)
+	return nil
+}
+
+// saveRevision 保存文章快照并按保留策略清理旧版本
+func (s *PostService) saveRevision(ctx context.Context, post *domain.Post) {
+	if err := s.revisionRepo.Create(ctx, postToRevision(post)); err != nil {
+		logger.Error("保存文章历史版本失败",
+			logger.WithError(err),
+			logger.WithString("postId", post.Id.Hex()),
+		)
+		return
+	}
+	s.pruneRevisions(ctx, post.Id)
+}
+
+// pruneRevisions 删除超出保留数量或保留天数的历史版本
+func (s *PostService) pruneRevisions(ctx context.Context, postId bson.ObjectID) {
+	if s.revisionCfg.MaxCount <= 0 && s.revisionCfg.MaxDays <= 0 {
+		return
+	}
+	revisions, err := s.revisionRepo.GetListByPostID(ctx, postId)
+	if err != nil {
+		logger.Error("查询文章历史版本失败",
+			logger.WithError(err),
+			logger.WithString("postId", postId.Hex()),
+		)
+		return
+	}
+
+	deadline := time.Now().AddDate(0, 0, -s.revisionCfg.MaxDays)
+	var expiredIds []bson.ObjectID
+	for i, revision := range revisions {
+		overCount := s.revisionCfg.MaxCount > 0 && i >= s.revisionCfg.MaxCount
+		overDays := s.revisionCfg.MaxDays > 0 && revision.CreatedAt.Before(deadline)
+		if overCount || overDays {
+			expiredIds = append(expiredIds, revision.Id)
+		}
+	}
+	if len(expiredIds) == 0 {
+		return
+	}
+	if err := s.revisionRepo.DeleteBatch(ctx, expiredIds); err != nil {
+		logger.Error("清理文章历史版本失败",
+			logger.WithError(err),
+			logger.WithString("postId", postId.Hex()),
+		)
+		return
+	}
+	logger.Info("清理文章历史版本成功",
+		logger.WithString("postId", postId.Hex()),
+		logger.WithInt("count", len(expiredIds)),
+	)
+}
+
+// isPostChanged 判断更新内容与当前文章是否有差异
+func isPostChanged(old *domain.Post, post *domain.Post) bool {
+	return old.Title != post.Title ||
+		old.Content != post.Content ||
+		old.Description != post.Description ||
+		old.Author != post.Author ||
+		old.Alias != post.Alias ||
+		old.CategoryId != post.CategoryId ||
+		!slices.Equal(old.TagsId, post.TagsId) ||
+		old.IsPublish != post.IsPublish ||
+		old.IsTop != post.IsTop ||
+		old.Thumbnail != post.Thumbnail
+}
+
+func postToRevision(post *domain.Post) *domain.PostRevision {
+	return &domain.PostRevision{
+		PostId:      post.Id,
+		Title:       post.Title,
+		Content:     post.Content,
+		Description: post.Description,
+		Author:      post.Author,
+		Alias:       post.Alias,
+		CategoryId:  post.CategoryId,
+		TagsId:      post.TagsId,
+		IsPublish:   post.IsPublish,
+		IsTop:       post.IsTop,
+		Thumbnail:   post.Thumbnail,
+	}
+}
+
+// revisionText 将版本渲染为用于对比的纯文本, 元信息在前, 正文在后
+func revisionText(revision *domain.PostRevision) string {
+	return fmt.Sprintf("标题: %s\n别名: %s\n作者: %s\n描述: %s\n缩略图: %s\n\n%s\n",
+		revision.Title,
+		revision.Alias,
+		revision.Author,
+		revision.Description,
+		revision.Thumbnail,
+		revision.Content,
+	)
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/audit"
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const publishPollInterval = 30 * time.Second // 定时发布轮询间隔
+
+// AdminSchedulePost 设置文章定时发布, 到期前文章对外不可见
+func (s *PostService) AdminSchedulePost(ctx context.Context, id bson.ObjectID, publishAt time.Time) error {
+	if !publishAt.After(time.Now()) {
+		return errors.New("定时发布时间必须晚于当前时间")
+	}
+	before := s.postSnapshots(ctx, id)
+	err := s.repo.SchedulePublish(ctx, id, publishAt)
+	if err != nil {
+		logger.Error("设置定时发布失败",
+			logger.WithError(err),
+			logger.WithString("postId", id.Hex()),
+		)
+		return err
+	}
+
+	s.searchServ.SyncPost(ctx, id)
+	s.sitemapServ.Invalidate()
+	s.recordAudit(ctx, audit.ActionUpdate, before, id)
+
+	logger.Info("设置定时发布成功",
+		logger.WithString("postId", id.Hex()),
+		logger.WithString("publishAt", publishAt.Format(time.DateTime)),
+	)
+	return nil
+}
+
+// AdminUnschedulePost 取消文章定时发布
+func (s *PostService) AdminUnschedulePost(ctx context.Context, id bson.ObjectID) error {
+	before := s.postSnapshots(ctx, id)
+	err := s.repo.UnschedulePublish(ctx, id)
+	if err != nil {
+		logger.Error("取消定时发布失败",
+			logger.WithError(err),
+			logger.WithString("postId", id.Hex()),
+		)
+		return err
+	}
+
+	s.searchServ.SyncPost(ctx, id)
+	s.sitemapServ.Invalidate()
+	s.recordAudit(ctx, audit.ActionUpdate, before, id)
+
+	logger.Info("取消定时发布成功",
+		logger.WithString("postId", id.Hex()),
+	)
+	return nil
+}
+
+// StartPublishScheduler 启动定时发布协程, 按间隔发布到期文章, ctx取消后退出
+func (s *PostService) StartPublishScheduler(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(publishPollInterval)
+		defer ticker.Stop()
+		for {
+			s.publishDue(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	logger.Info("定时发布协程已启动")
+}
+
+// publishDue 发布所有到期的定时文章
+func (s *PostService) publishDue(ctx context.Context) {
+	count, err := s.repo.PublishDue(ctx, time.Now())
+	if err != nil {
+		logger.Error("发布定时文章失败",
+			logger.WithError(err),
+		)
+		return
+	}
+	if count > 0 {
+		s.sitemapServ.Invalidate()
+		logger.Info("发布定时文章成功",
+			logger.WithInt("count", int(count)),
+		)
+	}
+}
@@ -1,43 +1,58 @@
 package web
 
 import (
+	"errors"
+	"strconv"
+
 	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+	"github.com/codepzj/Stellux-Server/internal/pkg/markdown"
+	"github.com/codepzj/Stellux-Server/internal/pkg/middleware"
+	"github.com/codepzj/Stellux-Server/internal/pkg/permission"
 	"github.com/codepzj/Stellux-Server/internal/post/internal/service"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
-func NewPostHandler(serv service.IPostService) *PostHandler {
+func NewPostHandler(serv service.IPostService, renderer *markdown.Renderer) *PostHandler {
 	return &PostHandler{
-		serv: serv,
+		serv:     serv,
+		renderer: renderer,
 	}
 }
 
 type PostHandler struct {
-	serv service.IPostService
+	serv     service.IPostService
+	renderer *markdown.Renderer
 }
 
 func (h *PostHandler) RegisterGinRoutes(engine *gin.Engine) {
 	adminGroup := engine.Group("/admin-api/post")
 	{
-		adminGroup.GET("draft/list", apiwrap.WrapWithQuery(h.AdminGetDraftDetailPostList))
-		adminGroup.GET("bin/list", apiwrap.WrapWithQuery(h.AdminGetBinDetailPostList))
-		adminGroup.POST("create", apiwrap.WrapWithJson(h.AdminCreatePost))
-		adminGroup.PUT("update", apiwrap.WrapWithJson(h.AdminUpdatePost))
-		adminGroup.PUT("update/publish-status", apiwrap.WrapWithJson(h.AdminUpdatePostPublishStatus))
-		adminGroup.PUT("restore/:id", apiwrap.WrapWithUri(h.AdminRestorePost))
-		adminGroup.PUT("restore/batch", apiwrap.WrapWithJson(h.AdminRestorePostBatch))
-		adminGroup.DELETE("soft-delete/:id", apiwrap.WrapWithUri(h.AdminSoftDeletePost))
-		adminGroup.DELETE("soft-delete/batch", apiwrap.WrapWithJson(h.AdminSoftDeletePostBatch))
-		adminGroup.DELETE("delete/:id", apiwrap.WrapWithUri(h.AdminDeletePost))
-		adminGroup.DELETE("delete/batch", apiwrap.WrapWithJson(h.AdminDeletePostBatch))
+		adminGroup.GET("draft/list", middleware.RequirePermission(permission.PostRead), apiwrap.WrapWithQuery(h.AdminGetDraftDetailPostList))
+		adminGroup.GET("bin/list", middleware.RequirePermission(permission.PostRead), apiwrap.WrapWithQuery(h.AdminGetBinDetailPostList))
+		adminGroup.GET("detail/:id", middleware.RequirePermission(permission.PostRead), apiwrap.WrapWithUri(h.AdminGetPostDetailById))
+		adminGroup.POST("create", middleware.RequirePermission(permission.PostWrite), apiwrap.WrapWithJson(h.AdminCreatePost))
+		adminGroup.PUT("update", middleware.RequirePermission(permission.PostWrite), apiwrap.WrapWithJson(h.AdminUpdatePost))
+		adminGroup.PUT("update/publish-status", middleware.RequirePermission(permission.PostWrite), apiwrap.WrapWithJson(h.AdminUpdatePostPublishStatus))
+		adminGroup.PUT("schedule", middleware.RequirePermission(permission.PostWrite), apiwrap.WrapWithJson(h.AdminSchedulePost))
+		adminGroup.PUT("unschedule/:id", middleware.RequirePermission(permission.PostWrite), apiwrap.WrapWithUri(h.AdminUnschedulePost))
+		adminGroup.PUT("restore/:id", middleware.RequirePermission(permission.PostWrite), apiwrap.WrapWithUri(h.AdminRestorePost))
+		adminGroup.PUT("restore/batch", middleware.RequirePermission(permission.PostWrite), apiwrap.WrapWithJson(h.AdminRestorePostBatch))
+		adminGroup.DELETE("soft-delete/:id", middleware.RequirePermission(permission.PostWrite), apiwrap.WrapWithUri(h.AdminSoftDeletePost))
+		adminGroup.DELETE("soft-delete/batch", middleware.RequirePermission(permission.PostWrite), apiwrap.WrapWithJson(h.AdminSoftDeletePostBatch))
+		adminGroup.DELETE("delete/:id", middleware.RequirePermission(permission.PostWrite), apiwrap.WrapWithUri(h.AdminDeletePost))
+		adminGroup.DELETE("delete/batch", middleware.RequirePermission(permission.PostWrite), apiwrap.WrapWithJson(h.AdminDeletePostBatch))
+		adminGroup.GET("revision/list/:id", middleware.RequirePermission(permission.PostRead), apiwrap.WrapWithUri(h.AdminGetPostRevisionList))
+		adminGroup.GET("revision/detail/:id", middleware.RequirePermission(permission.PostRead), apiwrap.WrapWithUri(h.AdminGetPostRevision))
+		adminGroup.GET("revision/diff", middleware.RequirePermission(permission.PostRead), apiwrap.WrapWithQuery(h.AdminDiffPostRevision))
+		adminGroup.PUT("revision/restore/:id", middleware.RequirePermission(permission.PostWrite), apiwrap.WrapWithUri(h.AdminRestorePostRevision))
 	}
 	postGroup := engine.Group("/post")
 	{
 		postGroup.GET("/list", apiwrap.WrapWithQuery(h.GetPublishPostList))    // 获取发布文章列表
-		postGroup.GET("/:id", apiwrap.WrapWithUri(h.GetPostById))              // 获取文章
-		postGroup.GET("/detail/:id", apiwrap.WrapWithUri(h.GetPostDetailById)) // 获取文章详情
-		postGroup.GET("/alias/:alias", apiwrap.Wrap(h.FindByAlias))            // 根据别名获取文章详情
+		postGroup.GET("/:id", apiwrap.WrapWithUri(h.GetPostById))              // 获取文章, render=true时返回渲染后的HTML和目录
+		postGroup.GET("/detail/:id", apiwrap.WrapWithUri(h.GetPostDetailById)) // 获取文章详情, render=true时返回渲染后的HTML和目录
+		postGroup.GET("/alias/:alias", apiwrap.Wrap(h.FindByAlias))            // 根据别名获取文章详情, render=true时返回渲染后的HTML和目录
 		postGroup.GET("/search", apiwrap.Wrap(h.GetPostByKeyWord))             // 搜索文章
 		postGroup.GET("/all", apiwrap.Wrap(h.GetAllPublishPost))               // 获取所有发布文章
 	}
@@ -45,6 +60,7 @@ func (h *PostHandler) RegisterGinRoutes(engine *gin.Engine) {
 
 func (h *PostHandler) AdminCreatePost(c *gin.Context, postReq PostDto) (int, string, any) {
 	post := h.PostDTOToDomain(postReq)
+	post.AuthorId, _ = bson.ObjectIDFromHex(c.GetString("userId"))
 	err := h.serv.AdminCreatePost(c, post)
 	if err != nil {
 		return 500, err.Error(), nil
@@ -54,6 +70,9 @@ func (h *PostHandler) AdminCreatePost(c *gin.Context, postReq PostDto) (int, str
 
 func (h *PostHandler) AdminUpdatePost(c *gin.Context, postUpdateReq PostUpdateDto) (int, string, any) {
 	postUpdate := h.PostUpdateDTOToDomain(postUpdateReq)
+	if code, msg := h.checkOwner(c, postUpdate.Id); code != 200 {
+		return code, msg, nil
+	}
 	err := h.serv.AdminUpdatePost(c, postUpdate)
 	if err != nil {
 		return 500, err.Error(), nil
@@ -66,6 +85,9 @@ func (h *PostHandler) AdminUpdatePostPublishStatus(c *gin.Context, postPublishSt
 	if err != nil {
 		return 400, "id格式错误", nil
 	}
+	if code, msg := h.checkOwner(c, objId); code != 200 {
+		return code, msg, nil
+	}
 	err = h.serv.AdminUpdatePostPublishStatus(c, objId, *postPublishStatusRequest.IsPublish)
 	if err != nil {
 		return 500, err.Error(), nil
@@ -73,11 +95,44 @@ func (h *PostHandler) AdminUpdatePostPublishStatus(c *gin.Context, postPublishSt
 	return 200, "更新文章发布状态成功", nil
 }
 
+func (h *PostHandler) AdminSchedulePost(c *gin.Context, postScheduleRequest PostScheduleRequest) (int, string, any) {
+	objId, err := bson.ObjectIDFromHex(postScheduleRequest.ID)
+	if err != nil {
+		return 400, "id格式错误", nil
+	}
+	if code, msg := h.checkOwner(c, objId); code != 200 {
+		return code, msg, nil
+	}
+	err = h.serv.AdminSchedulePost(c, objId, postScheduleRequest.PublishAt)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "设置定时发布成功", nil
+}
+
+func (h *PostHandler) AdminUnschedulePost(c *gin.Context, postIDRequest PostIdRequest) (int, string, any) {
+	objId, err := bson.ObjectIDFromHex(postIDRequest.Id)
+	if err != nil {
+		return 400, "id格式错误", nil
+	}
+	if code, msg := h.checkOwner(c, objId); code != 200 {
+		return code, msg, nil
+	}
+	err = h.serv.AdminUnschedulePost(c, objId)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "取消定时发布成功", nil
+}
+
 func (h *PostHandler) AdminRestorePost(c *gin.Context, postIDRequest PostIdRequest) (int, string, any) {
 	objId, err := bson.ObjectIDFromHex(postIDRequest.Id)
 	if err != nil {
 		return 400, "id格式错误", nil
 	}
+	if code, msg := h.checkOwner(c, objId); code != 200 {
+		return code, msg, nil
+	}
 	err = h.serv.AdminRestorePost(c, objId)
 	if err != nil {
 		return 500, err.Error(), nil
@@ -96,6 +151,9 @@ func (h *PostHandler) AdminRestorePostBatch(c *gin.Context, postIDListRequest Po
 		objIdList = append(objIdList, objId)
 	}
 
+	if code, msg := h.checkOwner(c, objIdList...); code != 200 {
+		return code, msg, nil
+	}
 	err = h.serv.AdminRestorePostBatch(c, objIdList)
 	if err != nil {
 		return 500, err.Error(), nil
@@ -108,6 +166,9 @@ func (h *PostHandler) AdminSoftDeletePost(c *gin.Context, postIDRequest PostIdRe
 	if err != nil {
 		return 400, "id格式错误", nil
 	}
+	if code, msg := h.checkOwner(c, objId); code != 200 {
+		return code, msg, nil
+	}
 	err = h.serv.AdminSoftDeletePost(c, objId)
 	if err != nil {
 		return 500, err.Error(), nil
@@ -125,6 +186,9 @@ func (h *PostHandler) AdminSoftDeletePostBatch(c *gin.Context, postIDListRequest
 		}
 		objIdList = append(objIdList, objId)
 	}
+	if code, msg := h.checkOwner(c, objIdList...); code != 200 {
+		return code, msg, nil
+	}
 	err = h.serv.AdminSoftDeletePostBatch(c, objIdList)
 	if err != nil {
 		return 500, err.Error(), nil
@@ -137,6 +201,9 @@ func (h *PostHandler) AdminDeletePost(c *gin.Context, postIDRequest PostIdReques
 	if err != nil {
 		return 400, "id格式错误", nil
 	}
+	if code, msg := h.checkOwner(c, objId); code != 200 {
+		return code, msg, nil
+	}
 	err = h.serv.AdminDeletePost(c, objId)
 	if err != nil {
 		return 500, err.Error(), nil
@@ -154,6 +221,9 @@ func (h *PostHandler) AdminDeletePostBatch(c *gin.Context, postIDListRequest Pos
 		}
 		objIdList = append(objIdList, objId)
 	}
+	if code, msg := h.checkOwner(c, objIdList...); code != 200 {
+		return code, msg, nil
+	}
 	err = h.serv.AdminDeletePostBatch(c, objIdList)
 	if err != nil {
 		return 500, err.Error(), nil
@@ -214,6 +284,21 @@ func (h *PostHandler) AdminGetBinDetailPostList(c *gin.Context, pageReq Page) (i
 	return 200, "获取回收站文章列表成功", pageVo
 }
 
+// AdminGetPostDetailById 获取任意状态的文章详情
+func (h *PostHandler) AdminGetPostDetailById(c *gin.Context, postIDRequest PostIdRequest) (int, string, any) {
+	objId, err := bson.ObjectIDFromHex(postIDRequest.Id)
+	if err != nil {
+		return 400, "id格式错误", nil
+	}
+	postDetail, err := h.serv.AdminGetPostDetailById(c, objId)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	vo := h.PostDetailToVO(postDetail)
+	vo.SetRendered(h.render(c, postDetail.Content))
+	return 200, "获取文章详情成功", vo
+}
+
 // GetPostDetailById 获取文章详情
 func (h *PostHandler) GetPostDetailById(c *gin.Context, postIDRequest PostIdRequest) (int, string, any) {
 	objId, err := bson.ObjectIDFromHex(postIDRequest.Id)
@@ -224,7 +309,9 @@ func (h *PostHandler) GetPostDetailById(c *gin.Context, postIDRequest PostIdRequ
 	if err != nil {
 		return 500, err.Error(), nil
 	}
-	return 200, "获取文章详情成功", h.PostDetailToVO(postDetail)
+	vo := h.PostDetailToVO(postDetail)
+	vo.SetRendered(h.render(c, postDetail.Content))
+	return 200, "获取文章详情成功", vo
 }
 
 // GetPostById 获取文章详情
@@ -237,7 +324,9 @@ func (h *PostHandler) GetPostById(c *gin.Context, postIDRequest PostIdRequest) (
 	if err != nil {
 		return 500, err.Error(), nil
 	}
-	return 200, "获取文章详情成功", h.PostToVO(post)
+	vo := h.PostToVO(post)
+	vo.SetRendered(h.render(c, post.Content))
+	return 200, "获取文章详情成功", vo
 }
 
 // FindByAlias 根据别名获取文章详情
@@ -250,7 +339,9 @@ func (h *PostHandler) FindByAlias(c *gin.Context) (int, string, any) {
 	if err != nil {
 		return 500, err.Error(), nil
 	}
-	return 200, "获取文章详情成功", h.PostToVO(post)
+	vo := h.PostToVO(post)
+	vo.SetRendered(h.render(c, post.Content))
+	return 200, "获取文章详情成功", vo
 }
 
 func (h *PostHandler) GetPostByKeyWord(c *gin.Context) (int, string, any) {
@@ -269,3 +360,96 @@ func (h *PostHandler) GetAllPublishPost(c *gin.Context) (int, string, any) {
 	}
 	return 200, "获取所有发布文章成功", h.PostDetailListToVOList(postDetailList)
 }
+
+func (h *PostHandler) AdminGetPostRevisionList(c *gin.Context, postIDRequest PostIdRequest) (int, string, any) {
+	objId, err := bson.ObjectIDFromHex(postIDRequest.Id)
+	if err != nil {
+		return 400, "id格式错误", nil
+	}
+	revisions, err := h.serv.AdminGetPostRevisionList(c, objId)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "获取文章历史版本列表成功", h.PostRevisionDomainToListVOList(revisions)
+}
+
+func (h *PostHandler) AdminGetPostRevision(c *gin.Context, revisionIDRequest PostIdRequest) (int, string, any) {
+	objId, err := bson.ObjectIDFromHex(revisionIDRequest.Id)
+	if err != nil {
+		return 400, "id格式错误", nil
+	}
+	revision, err := h.serv.AdminGetPostRevision(c, objId)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "获取文章历史版本成功", h.PostRevisionDomainToVO(revision)
+}
+
+func (h *PostHandler) AdminDiffPostRevision(c *gin.Context, diffReq PostRevisionDiffRequest) (int, string, any) {
+	oldId, err := bson.ObjectIDFromHex(diffReq.OldId)
+	if err != nil {
+		return 400, "id格式错误", nil
+	}
+	var newId bson.ObjectID
+	if diffReq.NewId != "" {
+		newId, err = bson.ObjectIDFromHex(diffReq.NewId)
+		if err != nil {
+			return 400, "id格式错误", nil
+		}
+	}
+	diff, err := h.serv.AdminDiffPostRevision(c, oldId, newId)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "获取版本差异成功", h.PostRevisionDiffDomainToVO(diff)
+}
+
+func (h *PostHandler) AdminRestorePostRevision(c *gin.Context, revisionIDRequest PostIdRequest) (int, string, any) {
+	objId, err := bson.ObjectIDFromHex(revisionIDRequest.Id)
+	if err != nil {
+		return 400, "id格式错误", nil
+	}
+	revision, err := h.serv.AdminGetPostRevision(c, objId)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	if code, msg := h.checkOwner(c, revision.PostId); code != 200 {
+		return code, msg, nil
+	}
+	err = h.serv.AdminRestorePostRevision(c, objId)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "恢复文章历史版本成功", nil
+}
+
+// checkOwner 没有post:manage权限的用户只能操作自己创建的文章
+func (h *PostHandler) checkOwner(c *gin.Context, ids ...bson.ObjectID) (int, string) {
+	canManage, err := middleware.HasPermission(c, permission.PostManage)
+	if err != nil {
+		return 500, err.Error()
+	}
+	if canManage {
+		return 200, ""
+	}
+	authorId, err := bson.ObjectIDFromHex(c.GetString("userId"))
+	if err != nil {
+		return 401, "用户身份无效"
+	}
+	err = h.serv.AdminCheckPostOwner(c, authorId, ids)
+	if errors.Is(err, service.ErrPostNotOwned) {
+		return 403, err.Error()
+	}
+	if err != nil {
+		return 500, err.Error()
+	}
+	return 200, ""
+}
+
+// render 请求携带render=true时渲染Markdown正文, 否则返回nil
+func (h *PostHandler) render(c *gin.Context, content string) *markdown.Rendered {
+	if ok, _ := strconv.ParseBool(c.Query("render")); !ok {
+		return nil
+	}
+	return h.renderer.Render(content)
+}
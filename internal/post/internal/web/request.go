@@ -59,6 +59,18 @@ type PostPublishStatusRequest struct {
 	IsPublish *bool  `json:"is_publish" binding:"required"`
 }
 
+type PostScheduleRequest struct {
+	ID        string    `json:"id" binding:"required"`
+	PublishAt time.Time `json:"publish_at" binding:"required"`
+}
+
 type PostIDListRequest struct {
 	IDList []string `json:"id_list" binding:"required"`
 }
+
+type PostRevisionDiffRequest struct {
+	// 旧版本id
+	OldId string `form:"old_id" binding:"required"`
+	// 新版本id, 为空时与当前文章对比
+	NewId string `form:"new_id"`
+}
@@ -4,41 +4,78 @@ import (
 	"time"
 
 	"github.com/codepzj/Stellux-Server/internal/label"
+	"github.com/codepzj/Stellux-Server/internal/pkg/markdown"
 	"github.com/codepzj/Stellux-Server/internal/post/internal/domain"
 	"github.com/samber/lo"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 type PostVO struct {
-	Id          string    `json:"id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Title       string    `json:"title"`
-	Content     string    `json:"content"`
-	Description string    `json:"description"`
-	Author      string    `json:"author"`
-	Alias       string    `json:"alias"`
-	CategoryID  string    `json:"category_id"`
-	TagsID      []string  `json:"tags_id"`
-	IsPublish   bool      `json:"is_publish"`
-	IsTop       bool      `json:"is_top"`
-	Thumbnail   string    `json:"thumbnail"`
+	Id          string     `json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Title       string     `json:"title"`
+	Content     string     `json:"content"`
+	Description string     `json:"description"`
+	Author      string     `json:"author"`
+	Alias       string     `json:"alias"`
+	CategoryID  string     `json:"category_id"`
+	TagsID      []string   `json:"tags_id"`
+	IsPublish   bool       `json:"is_publish"`
+	IsTop       bool       `json:"is_top"`
+	Thumbnail   string     `json:"thumbnail"`
+	PublishAt   *time.Time `json:"publish_at,omitempty"`
+
+	// 以下字段仅在请求携带render=true时返回
+	Html        string              `json:"html,omitempty"`
+	Toc         []*markdown.TocItem `json:"toc,omitempty"`
+	WordCount   int                 `json:"word_count,omitempty"`
+	ReadingTime int                 `json:"reading_time,omitempty"`
 }
 
 type PostDetailVO struct {
-	ID          string    `json:"id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Title       string    `json:"title"`
-	Content     string    `json:"content"`
-	Description string    `json:"description"`
-	Author      string    `json:"author"`
-	Alias       string    `json:"alias"`
-	Category    string    `json:"category"`
-	Tags        []string  `json:"tags"`
-	IsPublish   bool      `json:"is_publish"`
-	IsTop       bool      `json:"is_top"`
-	Thumbnail   string    `json:"thumbnail"`
+	ID          string     `json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Title       string     `json:"title"`
+	Content     string     `json:"content"`
+	Description string     `json:"description"`
+	Author      string     `json:"author"`
+	Alias       string     `json:"alias"`
+	Category    string     `json:"category"`
+	Tags        []string   `json:"tags"`
+	IsPublish   bool       `json:"is_publish"`
+	IsTop       bool       `json:"is_top"`
+	Thumbnail   string     `json:"thumbnail"`
+	PublishAt   *time.Time `json:"publish_at,omitempty"`
+
+	// 以下字段仅在请求携带render=true时返回
+	Html        string              `json:"html,omitempty"`
+	Toc         []*markdown.TocItem `json:"toc,omitempty"`
+	WordCount   int                 `json:"word_count,omitempty"`
+	ReadingTime int                 `json:"reading_time,omitempty"`
+}
+
+// SetRendered 填充渲染结果, rendered为nil时不做处理
+func (vo *PostVO) SetRendered(rendered *markdown.Rendered) {
+	if rendered == nil {
+		return
+	}
+	vo.Html = rendered.HTML
+	vo.Toc = rendered.Toc
+	vo.WordCount = rendered.WordCount
+	vo.ReadingTime = rendered.ReadingTime
+}
+
+// SetRendered 填充渲染结果, rendered为nil时不做处理
+func (vo *PostDetailVO) SetRendered(rendered *markdown.Rendered) {
+	if rendered == nil {
+		return
+	}
+	vo.Html = rendered.HTML
+	vo.Toc = rendered.Toc
+	vo.WordCount = rendered.WordCount
+	vo.ReadingTime = rendered.ReadingTime
 }
 
 func GetCategoryNameFromLabel(label label.Domain) string {
@@ -51,6 +88,14 @@ func GetTagNamesFromLabels(labels []label.Domain) []string {
 	})
 }
 
+// PublishAtToVO 未设置定时发布时返回nil, 避免输出零值时间
+func PublishAtToVO(publishAt time.Time) *time.Time {
+	if publishAt.IsZero() {
+		return nil
+	}
+	return &publishAt
+}
+
 func (h *PostHandler) PostDTOToDomain(postReq PostDto) *domain.Post {
 	categoryId, _ := bson.ObjectIDFromHex(postReq.CategoryID)
 	var tagsId []bson.ObjectID
@@ -112,6 +157,7 @@ func (h *PostHandler) PostDetailToVO(post *domain.PostDetail) *PostDetailVO {
 		IsPublish:   post.IsPublish,
 		IsTop:       post.IsTop,
 		Thumbnail:   post.Thumbnail,
+		PublishAt:   PublishAtToVO(post.PublishAt),
 	}
 }
 
@@ -147,6 +193,7 @@ func (h *PostHandler) PostToVO(post *domain.Post) *PostVO {
 		IsPublish:   post.IsPublish,
 		IsTop:       post.IsTop,
 		Thumbnail:   post.Thumbnail,
+		PublishAt:   PublishAtToVO(post.PublishAt),
 	}
 }
 
@@ -155,3 +202,80 @@ func (h *PostHandler) PostListToVOList(posts []*domain.Post) []*PostVO {
 		return h.PostToVO(post)
 	})
 }
+
+type PostRevisionListVO struct {
+	Id        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	PostId    string    `json:"post_id"`
+	Title     string    `json:"title"`
+	Author    string    `json:"author"`
+	Alias     string    `json:"alias"`
+	IsPublish bool      `json:"is_publish"`
+}
+
+type PostRevisionVO struct {
+	Id          string    `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	PostId      string    `json:"post_id"`
+	Title       string    `json:"title"`
+	Content     string    `json:"content"`
+	Description string    `json:"description"`
+	Author      string    `json:"author"`
+	Alias       string    `json:"alias"`
+	CategoryID  string    `json:"category_id"`
+	TagsID      []string  `json:"tags_id"`
+	IsPublish   bool      `json:"is_publish"`
+	IsTop       bool      `json:"is_top"`
+	Thumbnail   string    `json:"thumbnail"`
+}
+
+type PostRevisionDiffVO struct {
+	OldId string `json:"old_id"`
+	NewId string `json:"new_id,omitempty"`
+	Diff  string `json:"diff"`
+}
+
+func (h *PostHandler) PostRevisionDomainToListVOList(revisions []*domain.PostRevision) []*PostRevisionListVO {
+	return lo.Map(revisions, func(revision *domain.PostRevision, _ int) *PostRevisionListVO {
+		return &PostRevisionListVO{
+			Id:        revision.Id.Hex(),
+			CreatedAt: revision.CreatedAt,
+			PostId:    revision.PostId.Hex(),
+			Title:     revision.Title,
+			Author:    revision.Author,
+			Alias:     revision.Alias,
+			IsPublish: revision.IsPublish,
+		}
+	})
+}
+
+func (h *PostHandler) PostRevisionDomainToVO(revision *domain.PostRevision) *PostRevisionVO {
+	return &PostRevisionVO{
+		Id:          revision.Id.Hex(),
+		CreatedAt:   revision.CreatedAt,
+		PostId:      revision.PostId.Hex(),
+		Title:       revision.Title,
+		Content:     revision.Content,
+		Description: revision.Description,
+		Author:      revision.Author,
+		Alias:       revision.Alias,
+		CategoryID:  revision.CategoryId.Hex(),
+		TagsID: lo.Map(revision.TagsId, func(tagId bson.ObjectID, _ int) string {
+			return tagId.Hex()
+		}),
+		IsPublish: revision.IsPublish,
+		IsTop:     revision.IsTop,
+		Thumbnail: revision.Thumbnail,
+	}
+}
+
+func (h *PostHandler) PostRevisionDiffDomainToVO(diff *domain.PostRevisionDiff) *PostRevisionDiffVO {
+	vo := &PostRevisionDiffVO{
+		OldId: diff.OldId.Hex(),
+		Diff:  diff.Diff,
+	}
+	if !diff.NewId.IsZero() {
+		vo.NewId = diff.NewId.Hex()
+	}
+	return vo
+}
@@ -2,6 +2,7 @@ package post
 
 import (
 	"github.com/codepzj/Stellux-Server/internal/label"
+	"github.com/codepzj/Stellux-Server/internal/post/internal/domain"
 	"github.com/codepzj/Stellux-Server/internal/post/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/post/internal/web"
 )
@@ -10,6 +11,7 @@ type (
 	Handler     = web.PostHandler
 	Service     = service.IPostService
 	LabelDomain = label.Domain
+	Detail      = domain.PostDetail
 	Module      struct {
 		Svc Service
 		Hdl *Handler
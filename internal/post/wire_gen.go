@@ -7,22 +7,30 @@
 package post
 
 import (
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/audit"
+	"github.com/codepzj/Stellux-Server/internal/pkg/markdown"
 	"github.com/codepzj/Stellux-Server/internal/post/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/post/internal/repository/dao"
 	"github.com/codepzj/Stellux-Server/internal/post/internal/service"
 	"github.com/codepzj/Stellux-Server/internal/post/internal/web"
+	"github.com/codepzj/Stellux-Server/internal/search"
+	"github.com/codepzj/Stellux-Server/internal/sitemap"
 	"github.com/google/wire"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 // Injectors from wire.go:
 
-func InitPostModule(mongoDB *mongo.Database) *Module {
+func InitPostModule(mongoDB *mongo.Database, cfg *conf.Config, searchServ search.Service, sitemapServ sitemap.Service, renderer *markdown.Renderer, auditServ audit.Service) *Module {
 	postDao := dao.NewPostDao(mongoDB)
 	postRepository := repository.NewPostRepository(postDao)
-	postService := service.NewPostService(postRepository)
-	postHandler := web.NewPostHandler(postService)
+	postRevisionDao := dao.NewPostRevisionDao(mongoDB)
+	postRevisionRepository := repository.NewPostRevisionRepository(postRevisionDao)
+	postService := service.NewPostService(postRepository, postRevisionRepository, cfg, searchServ, sitemapServ, auditServ)
+	postHandler := web.NewPostHandler(postService, renderer)
 	module := &Module{
+		Svc: postService,
 		Hdl: postHandler,
 	}
 	return module
@@ -30,4 +38,4 @@ func InitPostModule(mongoDB *mongo.Database) *Module {
 
 // wire.go:
 
-var PostProviders = wire.NewSet(web.NewPostHandler, service.NewPostService, repository.NewPostRepository, dao.NewPostDao, wire.Bind(new(service.IPostService), new(*service.PostService)), wire.Bind(new(repository.IPostRepository), new(*repository.PostRepository)), wire.Bind(new(dao.IPostDao), new(*dao.PostDao)))
+var PostProviders = wire.NewSet(web.NewPostHandler, service.NewPostService, repository.NewPostRepository, dao.NewPostDao, repository.NewPostRevisionRepository, dao.NewPostRevisionDao, wire.Bind(new(service.IPostService), new(*service.PostService)), wire.Bind(new(repository.IPostRepository), new(*repository.PostRepository)), wire.Bind(new(dao.IPostDao), new(*dao.PostDao)), wire.Bind(new(repository.IPostRevisionRepository), new(*repository.PostRevisionRepository)), wire.Bind(new(dao.IPostRevisionDao), new(*dao.PostRevisionDao)))
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Role 角色, RoleId与User.RoleId对应
+type Role struct {
+	RoleId      int       // 角色Id
+	CreatedAt   time.Time // 创建时间, 未落库的内置角色为零值
+	UpdatedAt   time.Time // 更新时间, 未落库的内置角色为零值
+	Name        string    // 名称
+	Description string    // 描述
+	Permissions []string  // 权限码
+	Builtin     bool      // 是否内置角色, 内置角色不可删除
+}
+
+// Permission 权限定义
+type Permission struct {
+	Code        string // 权限码
+	Group       string // 所属路由分组
+	Description string // 描述
+}
@@ -0,0 +1,121 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type Role struct {
+	ID          bson.ObjectID `bson:"_id,omitempty"`
+	CreatedAt   time.Time     `bson:"created_at"`
+	UpdatedAt   time.Time     `bson:"updated_at"`
+	RoleId      int           `bson:"role_id"`
+	Name        string        `bson:"name"`
+	Description string        `bson:"description"`
+	Permissions []string      `bson:"permissions"`
+}
+
+// UserRole 用户所属角色
+type UserRole struct {
+	ID     bson.ObjectID `bson:"_id"`
+	RoleId int           `bson:"role_id"`
+}
+
+type IRoleDao interface {
+	GetList(ctx context.Context) ([]*Role, error)
+	Upsert(ctx context.Context, role *Role) error
+	Delete(ctx context.Context, roleId int) error
+	GetUserRole(ctx context.Context, userId bson.ObjectID) (*UserRole, error)
+	CountUserByRoleId(ctx context.Context, roleId int) (int64, error)
+}
+
+var _ IRoleDao = (*RoleDao)(nil)
+
+func NewRoleDao(db *mongo.Database) *RoleDao {
+	d := &RoleDao{
+		coll:     db.Collection("role"),
+		userColl: db.Collection("user"),
+	}
+	d.ensureIndexes()
+	return d
+}
+
+type RoleDao struct {
+	coll     *mongo.Collection
+	userColl *mongo.Collection
+}
+
+// ensureIndexes 角色Id唯一
+func (d *RoleDao) ensureIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := d.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "role_id", Value: 1}},
+		Options: options.Index().SetName("role_id_unique").SetUnique(true),
+	})
+	if err != nil {
+		logger.Warn("创建角色索引失败",
+			logger.WithError(err),
+		)
+	}
+}
+
+// GetList 获取全部角色, 按角色Id升序
+func (d *RoleDao) GetList(ctx context.Context) ([]*Role, error) {
+	opts := options.Find().SetSort(bson.M{"role_id": 1})
+	cursor, err := d.coll.Find(ctx, bson.D{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var roles []*Role
+	if err = cursor.All(ctx, &roles); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// Upsert 按角色Id创建或更新角色
+func (d *RoleDao) Upsert(ctx context.Context, role *Role) error {
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"name":        role.Name,
+			"description": role.Description,
+			"permissions": role.Permissions,
+			"updated_at":  now,
+		},
+		"$setOnInsert": bson.M{"created_at": now},
+	}
+	_, err := d.coll.UpdateOne(ctx, bson.M{"role_id": role.RoleId}, update, options.UpdateOne().SetUpsert(true))
+	return err
+}
+
+// Delete 删除角色
+func (d *RoleDao) Delete(ctx context.Context, roleId int) error {
+	_, err := d.coll.DeleteOne(ctx, bson.M{"role_id": roleId})
+	return err
+}
+
+// GetUserRole 获取未删除用户的角色
+func (d *RoleDao) GetUserRole(ctx context.Context, userId bson.ObjectID) (*UserRole, error) {
+	var userRole UserRole
+	opts := options.FindOne().SetProjection(bson.M{"role_id": 1})
+	err := d.userColl.FindOne(ctx, bson.M{"_id": userId, "deleted_at": nil}, opts).Decode(&userRole)
+	if err != nil {
+		return nil, err
+	}
+	return &userRole, nil
+}
+
+// CountUserByRoleId 统计属于指定角色的未删除用户数量
+func (d *RoleDao) CountUserByRoleId(ctx context.Context, roleId int) (int64, error) {
+	return d.userColl.CountDocuments(ctx, bson.M{"role_id": roleId, "deleted_at": nil})
+}
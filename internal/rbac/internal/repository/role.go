@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/codepzj/Stellux-Server/internal/rbac/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/rbac/internal/repository/dao"
+	"github.com/samber/lo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type IRoleRepository interface {
+	GetList(ctx context.Context) ([]*domain.Role, error)
+	Save(ctx context.Context, role *domain.Role) error
+	Delete(ctx context.Context, roleId int) error
+	GetUserRoleId(ctx context.Context, userId bson.ObjectID) (int, error)
+	CountUserByRoleId(ctx context.Context, roleId int) (int64, error)
+}
+
+var _ IRoleRepository = (*RoleRepository)(nil)
+
+func NewRoleRepository(dao dao.IRoleDao) *RoleRepository {
+	return &RoleRepository{dao: dao}
+}
+
+type RoleRepository struct {
+	dao dao.IRoleDao
+}
+
+func (r *RoleRepository) GetList(ctx context.Context) ([]*domain.Role, error) {
+	roles, err := r.dao.GetList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(roles, func(role *dao.Role, _ int) *domain.Role {
+		return r.RoleDOToDomain(role)
+	}), nil
+}
+
+func (r *RoleRepository) Save(ctx context.Context, role *domain.Role) error {
+	return r.dao.Upsert(ctx, r.RoleDomainToDO(role))
+}
+
+func (r *RoleRepository) Delete(ctx context.Context, roleId int) error {
+	return r.dao.Delete(ctx, roleId)
+}
+
+func (r *RoleRepository) GetUserRoleId(ctx context.Context, userId bson.ObjectID) (int, error) {
+	userRole, err := r.dao.GetUserRole(ctx, userId)
+	if err != nil {
+		return 0, err
+	}
+	return userRole.RoleId, nil
+}
+
+func (r *RoleRepository) CountUserByRoleId(ctx context.Context, roleId int) (int64, error) {
+	return r.dao.CountUserByRoleId(ctx, roleId)
+}
+
+func (r *RoleRepository) RoleDomainToDO(role *domain.Role) *dao.Role {
+	return &dao.Role{
+		RoleId:      role.RoleId,
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: role.Permissions,
+	}
+}
+
+func (r *RoleRepository) RoleDOToDomain(role *dao.Role) *domain.Role {
+	return &domain.Role{
+		RoleId:      role.RoleId,
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: role.Permissions,
+	}
+}
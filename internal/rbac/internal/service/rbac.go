@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/codepzj/Stellux-Server/internal/pkg/permission"
+	"github.com/codepzj/Stellux-Server/internal/rbac/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/rbac/internal/repository"
+	"github.com/samber/lo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// rolesCacheTTL 角色缓存的有效期, 其他实例修改角色后最多在该时间内生效
+const rolesCacheTTL = 10 * time.Second
+
+var (
+	ErrRoleNotFound      = errors.New("角色不存在")
+	ErrRoleExist         = errors.New("角色Id已存在")
+	ErrAdminRole         = errors.New("管理员角色不可修改")
+	ErrBuiltinRole       = errors.New("内置角色不可删除")
+	ErrRoleInUse         = errors.New("角色下仍有用户, 不可删除")
+	ErrInvalidPermission = errors.New("权限码不存在")
+)
+
+type IRbacService interface {
+	Authorize(ctx context.Context, userId string, permission string) (bool, error)
+	GetUserRole(ctx context.Context, userId string) (*domain.Role, error)
+	ExistRole(ctx context.Context, roleId int) (bool, error)
+	GetPermissionList() []*domain.Permission
+	AdminGetRoleList(ctx context.Context) ([]*domain.Role, error)
+	AdminCreateRole(ctx context.Context, role *domain.Role) error
+	AdminUpdateRole(ctx context.Context, role *domain.Role) error
+	AdminDeleteRole(ctx context.Context, roleId int) error
+}
+
+var _ IRbacService = (*RbacService)(nil)
+
+func NewRbacService(repo repository.IRoleRepository) *RbacService {
+	return &RbacService{
+		repo: repo,
+	}
+}
+
+type RbacService struct {
+	repo repository.IRoleRepository
+
+	mu       sync.RWMutex
+	roles    map[int]*domain.Role // 角色缓存, 为nil或过期时从数据库重新加载
+	loadedAt time.Time            // 角色缓存的加载时间
+}
+
+// Authorize 判断用户是否拥有指定权限, 用户不存在或角色不存在时视为无权限
+func (s *RbacService) Authorize(ctx context.Context, userId string, permission string) (bool, error) {
+	role, err := s.GetUserRole(ctx, userId)
+	if errors.Is(err, ErrRoleNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(role.Permissions, permission), nil
+}
+
+// GetUserRole 获取用户所属角色及权限
+func (s *RbacService) GetUserRole(ctx context.Context, userId string) (*domain.Role, error) {
+	objId, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, ErrRoleNotFound
+	}
+	roleId, err := s.repo.GetUserRoleId(ctx, objId)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrRoleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	roles, err := s.getRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	role, ok := roles[roleId]
+	if !ok {
+		logger.Warn("用户角色不存在",
+			logger.WithString("userId", userId),
+			logger.WithInt("roleId", roleId),
+		)
+		return nil, ErrRoleNotFound
+	}
+	return role, nil
+}
+
+// ExistRole 判断角色是否存在
+func (s *RbacService) ExistRole(ctx context.Context, roleId int) (bool, error) {
+	roles, err := s.getRoles(ctx)
+	if err != nil {
+		return false, err
+	}
+	_, ok := roles[roleId]
+	return ok, nil
+}
+
+// GetPermissionList 获取全部权限定义
+func (s *RbacService) GetPermissionList() []*domain.Permission {
+	return lo.Map(permission.Definitions, func(definition permission.Definition, _ int) *domain.Permission {
+		return &domain.Permission{
+			Code:        definition.Code,
+			Group:       definition.Group,
+			Description: definition.Description,
+		}
+	})
+}
+
+// AdminGetRoleList 获取全部角色, 按角色Id升序
+func (s *RbacService) AdminGetRoleList(ctx context.Context) ([]*domain.Role, error) {
+	roles, err := s.getRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := lo.Values(roles)
+	slices.SortFunc(list, func(a, b *domain.Role) int {
+		return a.RoleId - b.RoleId
+	})
+	return list, nil
+}
+
+// AdminCreateRole 创建自定义角色
+func (s *RbacService) AdminCreateRole(ctx context.Context, role *domain.Role) error {
+	exist, err := s.ExistRole(ctx, role.RoleId)
+	if err != nil {
+		return err
+	}
+	if exist {
+		return ErrRoleExist
+	}
+	return s.save(ctx, role)
+}
+
+// AdminUpdateRole 更新角色名称、描述和权限, 管理员角色不可修改
+func (s *RbacService) AdminUpdateRole(ctx context.Context, role *domain.Role) error {
+	if role.RoleId == permission.RoleAdmin {
+		return ErrAdminRole
+	}
+	exist, err := s.ExistRole(ctx, role.RoleId)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return ErrRoleNotFound
+	}
+	return s.save(ctx, role)
+}
+
+// AdminDeleteRole 删除自定义角色, 内置角色和仍有用户的角色不可删除
+func (s *RbacService) AdminDeleteRole(ctx context.Context, roleId int) error {
+	if isBuiltin(roleId) {
+		return ErrBuiltinRole
+	}
+	exist, err := s.ExistRole(ctx, roleId)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return ErrRoleNotFound
+	}
+	count, err := s.repo.CountUserByRoleId(ctx, roleId)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrRoleInUse
+	}
+	if err := s.repo.Delete(ctx, roleId); err != nil {
+		logger.Error("删除角色失败",
+			logger.WithError(err),
+			logger.WithInt("roleId", roleId),
+		)
+		return err
+	}
+	s.invalidate()
+	logger.Info("删除角色成功",
+		logger.WithInt("roleId", roleId),
+	)
+	return nil
+}
+
+func (s *RbacService) save(ctx context.Context, role *domain.Role) error {
+	role.Permissions = lo.Uniq(role.Permissions)
+	for _, code := range role.Permissions {
+		if !permission.IsValid(code) {
+			return fmt.Errorf("%w: %s", ErrInvalidPermission, code)
+		}
+	}
+	if err := s.repo.Save(ctx, role); err != nil {
+		logger.Error("保存角色失败",
+			logger.WithError(err),
+			logger.WithInt("roleId", role.RoleId),
+		)
+		return err
+	}
+	s.invalidate()
+	logger.Info("保存角色成功",
+		logger.WithInt("roleId", role.RoleId),
+		logger.WithAny("permissions", role.Permissions),
+	)
+	return nil
+}
+
+// getRoles 获取全部角色, 数据库中的记录覆盖内置角色的默认权限, 管理员始终拥有全部权限
+// 本实例修改角色时立即清除缓存, 多实例部署时其他实例的修改在缓存过期后生效
+func (s *RbacService) getRoles(ctx context.Context) (map[int]*domain.Role, error) {
+	s.mu.RLock()
+	roles, loadedAt := s.roles, s.loadedAt
+	s.mu.RUnlock()
+	if roles != nil && time.Since(loadedAt) < rolesCacheTTL {
+		return roles, nil
+	}
+
+	stored, err := s.repo.GetList(ctx)
+	if err != nil {
+		logger.Error("查询角色列表失败",
+			logger.WithError(err),
+		)
+		return nil, err
+	}
+	roles = make(map[int]*domain.Role, len(permission.Builtin)+len(stored))
+	for _, builtin := range permission.Builtin {
+		roles[builtin.RoleId] = &domain.Role{
+			RoleId:      builtin.RoleId,
+			Name:        builtin.Name,
+			Description: builtin.Description,
+			Permissions: builtin.Permissions,
+			Builtin:     true,
+		}
+	}
+	for _, role := range stored {
+		role.Builtin = isBuiltin(role.RoleId)
+		roles[role.RoleId] = role
+	}
+	roles[permission.RoleAdmin].Permissions = permission.Codes()
+
+	s.mu.Lock()
+	s.roles, s.loadedAt = roles, time.Now()
+	s.mu.Unlock()
+	return roles, nil
+}
+
+func (s *RbacService) invalidate() {
+	s.mu.Lock()
+	s.roles = nil
+	s.mu.Unlock()
+}
+
+func isBuiltin(roleId int) bool {
+	return slices.ContainsFunc(permission.Builtin, func(builtin permission.BuiltinRole) bool {
+		return builtin.RoleId == roleId
+	})
+}
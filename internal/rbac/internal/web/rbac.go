@@ -0,0 +1,103 @@
+package web
+
+import (
+	"errors"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+	"github.com/codepzj/Stellux-Server/internal/pkg/middleware"
+	"github.com/codepzj/Stellux-Server/internal/pkg/permission"
+	"github.com/codepzj/Stellux-Server/internal/rbac/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/rbac/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+func NewRbacHandler(serv service.IRbacService) *RbacHandler {
+	return &RbacHandler{
+		serv: serv,
+	}
+}
+
+type RbacHandler struct {
+	serv service.IRbacService
+}
+
+func (h *RbacHandler) RegisterGinRoutes(engine *gin.Engine) {
+	adminGroup := engine.Group("/admin-api/rbac")
+	{
+		adminGroup.GET("/mine", apiwrap.Wrap(h.GetMyRole))                                                                                      // 当前用户的角色和权限
+		adminGroup.GET("/permission/list", middleware.RequirePermission(permission.RoleManage), apiwrap.Wrap(h.AdminGetPermissionList))         // 获取全部权限定义
+		adminGroup.GET("/role/list", middleware.RequirePermission(permission.RoleManage), apiwrap.Wrap(h.AdminGetRoleList))                     // 获取角色列表
+		adminGroup.POST("/role/create", middleware.RequirePermission(permission.RoleManage), apiwrap.WrapWithJson(h.AdminCreateRole))           // 创建自定义角色
+		adminGroup.PUT("/role/update", middleware.RequirePermission(permission.RoleManage), apiwrap.WrapWithJson(h.AdminUpdateRole))            // 更新角色权限
+		adminGroup.DELETE("/role/delete/:role_id", middleware.RequirePermission(permission.RoleManage), apiwrap.WrapWithUri(h.AdminDeleteRole)) // 删除自定义角色
+	}
+}
+
+func (h *RbacHandler) GetMyRole(c *gin.Context) (int, string, any) {
+	role, err := h.serv.GetUserRole(c, c.GetString("userId"))
+	if errors.Is(err, service.ErrRoleNotFound) {
+		return 403, err.Error(), nil
+	}
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "获取当前用户角色成功", h.RoleToVO(role)
+}
+
+func (h *RbacHandler) AdminGetPermissionList(c *gin.Context) (int, string, any) {
+	return 200, "获取权限列表成功", h.PermissionListToVOList(h.serv.GetPermissionList())
+}
+
+func (h *RbacHandler) AdminGetRoleList(c *gin.Context) (int, string, any) {
+	roles, err := h.serv.AdminGetRoleList(c)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "获取角色列表成功", h.RoleListToVOList(roles)
+}
+
+func (h *RbacHandler) AdminCreateRole(c *gin.Context, roleReq RoleRequest) (int, string, any) {
+	err := h.serv.AdminCreateRole(c, h.roleRequestToDomain(roleReq))
+	if err != nil {
+		return roleErrorCode(err), err.Error(), nil
+	}
+	return 200, "创建角色成功", nil
+}
+
+func (h *RbacHandler) AdminUpdateRole(c *gin.Context, roleReq RoleRequest) (int, string, any) {
+	err := h.serv.AdminUpdateRole(c, h.roleRequestToDomain(roleReq))
+	if err != nil {
+		return roleErrorCode(err), err.Error(), nil
+	}
+	return 200, "更新角色成功", nil
+}
+
+func (h *RbacHandler) AdminDeleteRole(c *gin.Context, roleIdReq RoleIdRequest) (int, string, any) {
+	err := h.serv.AdminDeleteRole(c, roleIdReq.RoleId)
+	if err != nil {
+		return roleErrorCode(err), err.Error(), nil
+	}
+	return 200, "删除角色成功", nil
+}
+
+func (h *RbacHandler) roleRequestToDomain(roleReq RoleRequest) *domain.Role {
+	return &domain.Role{
+		RoleId:      *roleReq.RoleId,
+		Name:        roleReq.Name,
+		Description: roleReq.Description,
+		Permissions: roleReq.Permissions,
+	}
+}
+
+// roleErrorCode 角色校验失败返回400, 其余为500
+func roleErrorCode(err error) int {
+	for _, target := range []error{
+		service.ErrRoleNotFound, service.ErrRoleExist, service.ErrAdminRole,
+		service.ErrBuiltinRole, service.ErrRoleInUse, service.ErrInvalidPermission,
+	} {
+		if errors.Is(err, target) {
+			return 400
+		}
+	}
+	return 500
+}
@@ -0,0 +1,13 @@
+package web
+
+// RoleRequest 创建或更新角色请求
+type RoleRequest struct {
+	RoleId      *int     `json:"role_id" binding:"required,min=0"`
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type RoleIdRequest struct {
+	RoleId int `uri:"role_id" binding:"min=0"`
+}
@@ -0,0 +1,59 @@
+package web
+
+import (
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/rbac/internal/domain"
+	"github.com/samber/lo"
+)
+
+type RoleVO struct {
+	RoleId      int        `json:"role_id"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Permissions []string   `json:"permissions"`
+	Builtin     bool       `json:"builtin"`
+}
+
+type PermissionVO struct {
+	Code        string `json:"code"`
+	Group       string `json:"group"`
+	Description string `json:"description"`
+}
+
+func (h *RbacHandler) RoleToVO(role *domain.Role) *RoleVO {
+	vo := &RoleVO{
+		RoleId:      role.RoleId,
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: role.Permissions,
+		Builtin:     role.Builtin,
+	}
+	if vo.Permissions == nil {
+		vo.Permissions = []string{}
+	}
+	// 未落库的内置角色不返回时间
+	if !role.CreatedAt.IsZero() {
+		vo.CreatedAt = &role.CreatedAt
+		vo.UpdatedAt = &role.UpdatedAt
+	}
+	return vo
+}
+
+func (h *RbacHandler) RoleListToVOList(roles []*domain.Role) []*RoleVO {
+	return lo.Map(roles, func(role *domain.Role, _ int) *RoleVO {
+		return h.RoleToVO(role)
+	})
+}
+
+func (h *RbacHandler) PermissionListToVOList(permissions []*domain.Permission) []*PermissionVO {
+	return lo.Map(permissions, func(permission *domain.Permission, _ int) *PermissionVO {
+		return &PermissionVO{
+			Code:        permission.Code,
+			Group:       permission.Group,
+			Description: permission.Description,
+		}
+	})
+}
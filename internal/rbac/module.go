@@ -0,0 +1,15 @@
+package rbac
+
+import (
+	"github.com/codepzj/Stellux-Server/internal/rbac/internal/service"
+	"github.com/codepzj/Stellux-Server/internal/rbac/internal/web"
+)
+
+type (
+	Handler = web.RbacHandler
+	Service = service.IRbacService
+	Module  struct {
+		Svc Service
+		Hdl *Handler
+	}
+)
@@ -0,0 +1,24 @@
+//go:build wireinject
+
+package rbac
+
+import (
+	"github.com/codepzj/Stellux-Server/internal/rbac/internal/repository"
+	"github.com/codepzj/Stellux-Server/internal/rbac/internal/repository/dao"
+	"github.com/codepzj/Stellux-Server/internal/rbac/internal/service"
+	"github.com/codepzj/Stellux-Server/internal/rbac/internal/web"
+	"github.com/google/wire"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var RbacProviders = wire.NewSet(web.NewRbacHandler, service.NewRbacService, repository.NewRoleRepository, dao.NewRoleDao,
+	wire.Bind(new(service.IRbacService), new(*service.RbacService)),
+	wire.Bind(new(repository.IRoleRepository), new(*repository.RoleRepository)),
+	wire.Bind(new(dao.IRoleDao), new(*dao.RoleDao)))
+
+func InitRbacModule(mongoDB *mongo.Database) *Module {
+	panic(wire.Build(
+		RbacProviders,
+		wire.Struct(new(Module), "Svc", "Hdl"),
+	))
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	SearchTypePost     = "post"     // 文章
+	SearchTypeDocument = "document" // 文档内容
+)
+
+// SearchResult 搜索结果
+type SearchResult struct {
+	Type           string        // 结果类型 post/document
+	Id             bson.ObjectID // 文章或文档内容ID
+	CreatedAt      time.Time     // 创建时间
+	UpdatedAt      time.Time     // 更新时间
+	Title          string        // 标题
+	Description    string        // 描述
+	Content        string        // 正文, 用于生成摘要
+	Alias          string        // 别名
+	DocumentId     bson.ObjectID // 所属文档ID, 仅文档内容
+	DocumentTitle  string        // 所属文档标题, 仅文档内容
+	DocumentAlias  string        // 所属文档别名, 仅文档内容
+	Score          float64       // 相关度得分
+	TitleHighlight string        // 高亮后的标题
+	Snippet        string        // 高亮后的摘要
+}
+
+// SearchQuery 搜索条件
+type SearchQuery struct {
+	apiwrap.Page
+	Type       string        // 结果类型, 为空时搜索全部
+	CategoryId bson.ObjectID // 按分类过滤, 仅文章
+	TagId      bson.ObjectID // 按标签过滤, 仅文章
+	DocumentId bson.ObjectID // 按所属文档过滤, 仅文档内容
+	Public     bool          // 是否只搜索对外可见的内容
+}
+
+// Suggestion 输入联想结果
+type Suggestion struct {
+	Terms   []string        // 补全后的搜索词
+	Results []*SearchResult // 标题匹配的文章或文档内容
+}
+
+// Indexable 待写入全文索引的文章或文档内容
+type Indexable struct {
+	Type        string
+	Id          bson.ObjectID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Title       string
+	Description string
+	Content     string
+	Alias       string
+	CategoryId  bson.ObjectID
+	TagIds      []bson.ObjectID
+	DocumentId  bson.ObjectID
+	IsPublish   bool
+	PublishAt   time.Time
+}
+
+// DocumentInfo 文档基本信息
+type DocumentInfo struct {
+	Id    bson.ObjectID
+	Title string
+	Alias string
+}
@@ -0,0 +1,358 @@
+package engine
+
+import (
+	"encoding/gob"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	FieldTitle = iota
+	FieldDescription
+	FieldContent
+	fieldCount
+)
+
+const (
+	indexVersion = 1 // 索引文件格式版本, 分词或结构变化时递增以触发重建
+	bm25K1       = 1.2
+	bm25B        = 0.75
+)
+
+// fieldWeights 标题命中的权重高于描述和正文
+var fieldWeights = [fieldCount]float64{3, 2, 1}
+
+var ErrIndexVersion = errors.New("索引文件版本不匹配")
+
+// Document 被索引的文章或文档内容
+type Document struct {
+	Key         string // 类型与ID组成的唯一键
+	Type        string
+	Id          bson.ObjectID
+	Title       string
+	Description string
+	Content     string
+	Alias       string
+	CategoryId  bson.ObjectID
+	TagIds      []bson.ObjectID
+	DocumentId  bson.ObjectID
+	IsPublish   bool
+	PublishAt   time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Tokens      [fieldCount][]Token
+}
+
+// Posting 某个词在某篇文档各字段中出现的位置
+type Posting struct {
+	Positions [fieldCount][]int
+}
+
+// Hit 检索命中
+type Hit struct {
+	Doc   *Document
+	Score float64
+}
+
+// Index 内存倒排索引, 可整体持久化到磁盘
+type Index struct {
+	mu        sync.RWMutex
+	tokenizer *Tokenizer
+	docs      map[string]*Document
+	docLen    map[string][fieldCount]int
+	postings  map[string]map[string]*Posting
+	totalLen  [fieldCount]int
+	dirty     bool
+}
+
+func NewIndex(tokenizer *Tokenizer) *Index {
+	return &Index{
+		tokenizer: tokenizer,
+		docs:      map[string]*Document{},
+		docLen:    map[string][fieldCount]int{},
+		postings:  map[string]map[string]*Posting{},
+	}
+}
+
+func (idx *Index) Tokenizer() *Tokenizer {
+	return idx.tokenizer
+}
+
+// Put 分词并写入文档, 已存在时先删除旧的倒排记录
+func (idx *Index) Put(doc *Document) {
+	doc.Tokens[FieldTitle] = idx.tokenizer.Tokenize(doc.Title)
+	doc.Tokens[FieldDescription] = idx.tokenizer.Tokenize(doc.Description)
+	doc.Tokens[FieldContent] = idx.tokenizer.Tokenize(doc.Content)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(doc.Key)
+	idx.add(doc)
+	idx.dirty = true
+}
+
+// Remove 删除文档, 返回文档是否存在
+func (idx *Index) Remove(key string) bool {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if _, ok := idx.docs[key]; !ok {
+		return false
+	}
+	idx.remove(key)
+	idx.dirty = true
+	return true
+}
+
+// Versions 返回所有文档的更新时间, 用于和数据库做增量同步
+func (idx *Index) Versions() map[string]time.Time {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	versions := make(map[string]time.Time, len(idx.docs))
+	for key, doc := range idx.docs {
+		versions[key] = doc.UpdatedAt
+	}
+	return versions
+}
+
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// Search 检索满足所有子句且不含排除词的文档, 按BM25加权得分降序
+func (idx *Index) Search(q Query, filter func(*Document) bool) []Hit {
+	if q.Empty() {
+		return nil
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var candidates map[string]struct{}
+	for _, clause := range q.Clauses {
+		matched := idx.matchClause(clause, candidates)
+		if len(matched) == 0 {
+			return nil
+		}
+		candidates = matched
+	}
+	for _, term := range q.Excludes {
+		for key := range idx.postings[term] {
+			delete(candidates, key)
+		}
+	}
+
+	var terms []string
+	for _, clause := range q.Clauses {
+		terms = append(terms, clause...)
+	}
+	slices.Sort(terms)
+	terms = slices.Compact(terms)
+
+	hits := make([]Hit, 0, len(candidates))
+	for key := range candidates {
+		doc := idx.docs[key]
+		if filter != nil && !filter(doc) {
+			continue
+		}
+		hits = append(hits, Hit{Doc: doc, Score: idx.score(key, terms)})
+	}
+	slices.SortFunc(hits, func(a, b Hit) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		}
+		return b.Doc.UpdatedAt.Compare(a.Doc.UpdatedAt)
+	})
+	return hits
+}
+
+// Save 将索引写入临时文件后替换, 避免写入中断导致文件损坏
+func (idx *Index) Save(path string) error {
+	idx.mu.Lock()
+	docs := make([]*Document, 0, len(idx.docs))
+	for _, doc := range idx.docs {
+		docs = append(docs, doc)
+	}
+	idx.dirty = false
+	idx.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	err = gob.NewEncoder(f).Encode(snapshot{Version: indexVersion, Docs: docs})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Load 从磁盘加载索引, 文档保存了分词结果, 加载时无需重新分词
+func (idx *Index) Load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var snap snapshot
+	if err := gob.NewDecoder(f).Decode(&snap); err != nil {
+		return err
+	}
+	if snap.Version != indexVersion {
+		return ErrIndexVersion
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for _, doc := range snap.Docs {
+		idx.add(doc)
+	}
+	return nil
+}
+
+// Dirty 索引自上次保存后是否有变更
+func (idx *Index) Dirty() bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.dirty
+}
+
+type snapshot struct {
+	Version int
+	Docs    []*Document
+}
+
+func (idx *Index) add(doc *Document) {
+	idx.docs[doc.Key] = doc
+	var lens [fieldCount]int
+	for field, tokens := range doc.Tokens {
+		for _, token := range tokens {
+			byDoc, ok := idx.postings[token.Term]
+			if !ok {
+				byDoc = map[string]*Posting{}
+				idx.postings[token.Term] = byDoc
+			}
+			posting, ok := byDoc[doc.Key]
+			if !ok {
+				posting = &Posting{}
+				byDoc[doc.Key] = posting
+			}
+			posting.Positions[field] = append(posting.Positions[field], token.Pos)
+			lens[field] = max(lens[field], token.Pos+1)
+		}
+		idx.totalLen[field] += lens[field]
+	}
+	idx.docLen[doc.Key] = lens
+}
+
+func (idx *Index) remove(key string) {
+	doc, ok := idx.docs[key]
+	if !ok {
+		return
+	}
+	for _, tokens := range doc.Tokens {
+		for _, token := range tokens {
+			byDoc := idx.postings[token.Term]
+			delete(byDoc, key)
+			if len(byDoc) == 0 {
+				delete(idx.postings, token.Term)
+			}
+		}
+	}
+	for field, l := range idx.docLen[key] {
+		idx.totalLen[field] -= l
+	}
+	delete(idx.docLen, key)
+	delete(idx.docs, key)
+}
+
+// matchClause 返回包含子句的文档, 多词子句要求各词在同一字段中位置相邻
+func (idx *Index) matchClause(clause []string, within map[string]struct{}) map[string]struct{} {
+	matched := map[string]struct{}{}
+	for key, first := range idx.postings[clause[0]] {
+		if within != nil {
+			if _, ok := within[key]; !ok {
+				continue
+			}
+		}
+		if len(clause) == 1 || idx.matchPhrase(key, first, clause[1:]) {
+			matched[key] = struct{}{}
+		}
+	}
+	return matched
+}
+
+func (idx *Index) matchPhrase(key string, first *Posting, rest []string) bool {
+	postings := make([]*Posting, len(rest))
+	for i, term := range rest {
+		posting, ok := idx.postings[term][key]
+		if !ok {
+			return false
+		}
+		postings[i] = posting
+	}
+	for field := range fieldCount {
+	next:
+		for _, pos := range first.Positions[field] {
+			for i, posting := range postings {
+				if _, found := slices.BinarySearch(posting.Positions[field], pos+i+1); !found {
+					continue next
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (idx *Index) score(key string, terms []string) float64 {
+	n := float64(len(idx.docs))
+	lens := idx.docLen[key]
+	var score float64
+	for _, term := range terms {
+		byDoc := idx.postings[term]
+		posting, ok := byDoc[key]
+		if !ok {
+			continue
+		}
+		df := float64(len(byDoc))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for field := range fieldCount {
+			tf := float64(len(posting.Positions[field]))
+			if tf == 0 {
+				continue
+			}
+			avg := float64(idx.totalLen[field]) / n
+			if avg == 0 {
+				avg = 1
+			}
+			norm := tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*float64(lens[field])/avg))
+			score += fieldWeights[field] * idf * norm
+		}
+	}
+	return score
+}
+
+// DocKey 生成文档唯一键
+func DocKey(docType string, id bson.ObjectID) string {
+	return docType + ":" + id.Hex()
+}
@@ -0,0 +1,258 @@
+package engine
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func newTestDoc(title, description, content string) *Document {
+	id := bson.NewObjectID()
+	return &Document{Key: DocKey("post", id), Type: "post", Id: id, Title: title, Description: description, Content: content}
+}
+
+// search 返回命中文档的标题, 按得分排序
+func search(idx *Index, raw string, filter func(*Document) bool) []string {
+	var titles []string
+	for _, hit := range idx.Search(ParseQuery(raw, idx.Tokenizer()), filter) {
+		titles = append(titles, hit.Doc.Title)
+	}
+	return titles
+}
+
+func TestIndexSearch(t *testing.T) {
+	idx := NewIndex(testTokenizer(t))
+	for _, doc := range []*Document{
+		newTestDoc("Go并发编程", "goroutine与channel", "Go语言的并发编程模型"),
+		newTestDoc("Java并发", "线程池", "Java中的编程与并发工具"),
+		newTestDoc("使用Docker部署", "容器化", "Running containers with Docker"),
+		newTestDoc("MongoDB全文索引", "搜索", "MongoDB的全文索引与文本搜索"),
+	} {
+		idx.Put(doc)
+	}
+
+	tests := []struct {
+		name   string
+		raw    string
+		filter func(*Document) bool
+		want   []string
+	}{
+		{name: "单个词", raw: "docker", want: []string{"使用Docker部署"}},
+		{name: "大小写无关", raw: "DOCKER", want: []string{"使用Docker部署"}},
+		{name: "词干匹配", raw: "run container", want: []string{"使用Docker部署"}},
+		{name: "多个词同时出现", raw: "java 并发", want: []string{"Java并发"}},
+		{name: "短语要求相邻", raw: `"并发编程"`, want: []string{"Go并发编程"}},
+		{name: "不加引号时不要求相邻", raw: "并发 编程", want: []string{"Go并发编程", "Java并发"}},
+		{name: "排除词", raw: "并发 -java", want: []string{"Go并发编程"}},
+		{name: "长词的子词", raw: "索引", want: []string{"MongoDB全文索引"}},
+		{name: "没有命中", raw: "kubernetes"},
+		{name: "只有排除词", raw: "-java"},
+		{
+			name:   "过滤",
+			raw:    "并发",
+			filter: func(doc *Document) bool { return doc.Title != "Go并发编程" },
+			want:   []string{"Java并发"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := search(idx, tt.raw, tt.filter)
+			// 排序单独测试, 这里只比较命中的文档
+			slices.Sort(got)
+			want := slices.Clone(tt.want)
+			slices.Sort(want)
+			if !slices.Equal(got, want) {
+				t.Errorf("Search(%q) = %q, want %q", tt.raw, got, want)
+			}
+		})
+	}
+}
+
+func TestIndexUpdateAndRemove(t *testing.T) {
+	idx := NewIndex(testTokenizer(t))
+	doc := newTestDoc("Docker入门", "", "容器")
+	idx.Put(newTestDoc("Kubernetes", "", "集群"))
+	idx.Put(doc)
+
+	updated := *doc
+	updated.Title = "Podman入门"
+	updated.UpdatedAt = time.Now()
+	idx.Put(&updated)
+
+	steps := []struct {
+		name    string
+		raw     string
+		want    []string
+		wantLen int
+	}{
+		{name: "更新后旧词不再命中", raw: "docker", wantLen: 2},
+		{name: "更新后新词命中", raw: "podman", want: []string{"Podman入门"}, wantLen: 2},
+		{name: "未修改的词仍然命中", raw: "容器", want: []string{"Podman入门"}, wantLen: 2},
+	}
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			if got := search(idx, step.raw, nil); !slices.Equal(got, step.want) {
+				t.Errorf("Search(%q) = %q, want %q", step.raw, got, step.want)
+			}
+			if idx.Len() != step.wantLen {
+				t.Errorf("Len() = %d, want %d", idx.Len(), step.wantLen)
+			}
+		})
+	}
+	if !idx.Versions()[doc.Key].Equal(updated.UpdatedAt) {
+		t.Errorf("Versions()[%s] = %v, want %v", doc.Key, idx.Versions()[doc.Key], updated.UpdatedAt)
+	}
+
+	if !idx.Remove(doc.Key) {
+		t.Fatal("Remove() = false, want true")
+	}
+	if idx.Remove(doc.Key) {
+		t.Error("重复删除 Remove() = true, want false")
+	}
+	if got := search(idx, "容器", nil); got != nil {
+		t.Errorf("删除后 Search() = %q, want nil", got)
+	}
+	if idx.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", idx.Len())
+	}
+	// 删除后不应残留倒排记录和长度统计
+	for term, byDoc := range idx.postings {
+		if _, ok := byDoc[doc.Key]; ok {
+			t.Errorf("词%q仍指向已删除的文档", term)
+		}
+	}
+	if _, ok := idx.docLen[doc.Key]; ok {
+		t.Error("已删除文档的长度统计未清除")
+	}
+}
+
+func TestIndexRanking(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		docs []*Document
+		raw  string
+		want []string
+	}{
+		{
+			name: "标题命中高于描述, 描述高于正文",
+			docs: []*Document{
+				newTestDoc("正文", "容器 入门", "Docker 教程"),
+				newTestDoc("Docker", "容器 入门", "教程 文章"),
+				newTestDoc("描述", "Docker 入门", "教程 文章"),
+				newTestDoc("其他", "容器 入门", "教程 文章"),
+			},
+			raw:  "docker",
+			want: []string{"Docker", "描述", "正文"},
+		},
+		{
+			name: "出现次数多的得分高",
+			docs: []*Document{
+				newTestDoc("一次", "", "Docker 容器 镜像 仓库"),
+				newTestDoc("三次", "", "Docker 容器 Docker Docker"),
+				newTestDoc("其他", "", "容器 镜像 仓库 集群"),
+			},
+			raw:  "docker",
+			want: []string{"三次", "一次"},
+		},
+		{
+			name: "短文档的得分高于长文档",
+			docs: []*Document{
+				newTestDoc("长", "", "Docker 容器 镜像 仓库 集群 网络 存储 日志"),
+				newTestDoc("短", "", "Docker 容器"),
+				newTestDoc("其他", "", "容器 镜像"),
+			},
+			raw:  "docker",
+			want: []string{"短", "长"},
+		},
+		{
+			name: "得分相同时更新时间晚的在前",
+			docs: []*Document{
+				{Key: "post:old", Title: "旧", Content: "docker", UpdatedAt: now.Add(-time.Hour)},
+				{Key: "post:new", Title: "新", Content: "docker", UpdatedAt: now},
+			},
+			raw:  "docker",
+			want: []string{"新", "旧"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := NewIndex(testTokenizer(t))
+			for _, doc := range tt.docs {
+				idx.Put(doc)
+			}
+			if got := search(idx, tt.raw, nil); !slices.Equal(got, tt.want) {
+				t.Errorf("Search(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndexSuggest(t *testing.T) {
+	idx := NewIndex(testTokenizer(t))
+	for _, doc := range []*Document{
+		newTestDoc("Docker部署", "kubernetes集群", ""),
+		newTestDoc("Docker镜像", "documentation", ""),
+		newTestDoc("草稿docker", "dockerfile", ""),
+	} {
+		idx.Put(doc)
+	}
+	hideDraft := func(doc *Document) bool { return doc.Title != "草稿docker" }
+
+	tests := []struct {
+		name   string
+		input  string
+		limit  int
+		filter func(*Document) bool
+		want   []string
+	}{
+		{name: "前缀补全按出现次数排序", input: "doc", limit: 3, want: []string{"docker", "dockerfile", "documentation"}},
+		{name: "保留前面已输入的词", input: "部署 dock", limit: 1, want: []string{"部署 docker"}},
+		{name: "允许拼写错误", input: "kubernets", limit: 5, want: []string{"kubernetes"}},
+		{name: "过短的输入不容错", input: "dx", limit: 5, want: []string{}},
+		{name: "过滤不可见文档", input: "dockerf", limit: 5, filter: hideDraft, want: []string{}},
+		{name: "与输入完全相同的词不作为建议", input: "dockerfile", limit: 5, want: []string{}},
+		{name: "输入以空格结尾", input: "docker ", limit: 5},
+		{name: "limit为0", input: "doc", limit: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := idx.Suggest(tt.input, tt.limit, tt.filter)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Suggest(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndexSaveLoad(t *testing.T) {
+	tk := testTokenizer(t)
+	idx := NewIndex(tk)
+	doc := newTestDoc("Go并发编程", "", "goroutine")
+	idx.Put(doc)
+	if !idx.Dirty() {
+		t.Error("写入后 Dirty() = false")
+	}
+
+	path := filepath.Join(t.TempDir(), "search", "index.gob")
+	if err := idx.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if idx.Dirty() {
+		t.Error("保存后 Dirty() = true")
+	}
+
+	loaded := NewIndex(tk)
+	if err := loaded.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := search(loaded, `"并发编程"`, nil); !slices.Equal(got, []string{"Go并发编程"}) {
+		t.Errorf("加载后 Search() = %q", got)
+	}
+	if loaded.Len() != 1 {
+		t.Errorf("加载后 Len() = %d, want 1", loaded.Len())
+	}
+}
@@ -0,0 +1,39 @@
+package engine
+
+import (
+	"regexp"
+	"strings"
+)
+
+var phraseRegexp = regexp.MustCompile(`"([^"]*)"`)
+
+// Query 解析后的查询, 每个子句内的词须按顺序相邻出现, 子句之间为且的关系
+type Query struct {
+	Clauses  [][]string
+	Excludes []string
+}
+
+// ParseQuery 解析查询语句, 双引号内为短语, 减号开头的词为排除词, 其余每个词单独成句
+func ParseQuery(raw string, tokenizer *Tokenizer) Query {
+	var q Query
+	for _, match := range phraseRegexp.FindAllStringSubmatch(raw, -1) {
+		if terms := tokenizer.Terms(match[1]); len(terms) > 0 {
+			q.Clauses = append(q.Clauses, terms)
+		}
+	}
+	rest := phraseRegexp.ReplaceAllString(raw, " ")
+	for _, field := range strings.Fields(rest) {
+		if strings.HasPrefix(field, "-") {
+			q.Excludes = append(q.Excludes, tokenizer.Terms(field[1:])...)
+			continue
+		}
+		for _, term := range tokenizer.Terms(field) {
+			q.Clauses = append(q.Clauses, []string{term})
+		}
+	}
+	return q
+}
+
+func (q Query) Empty() bool {
+	return len(q.Clauses) == 0
+}
@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Suggest 根据输入的最后一个词给出补全建议, 候选词来自可见文档的标题和描述, 允许少量拼写错误
+func (idx *Index) Suggest(input string, limit int, filter func(*Document) bool) []string {
+	input = strings.ToLower(strings.TrimLeft(input, " "))
+	cut := strings.LastIndexAny(input, " \t") + 1
+	prefix, last := input[:cut], input[cut:]
+	if last == "" || limit <= 0 {
+		return nil
+	}
+
+	idx.mu.RLock()
+	freq := map[string]int{}
+	for _, doc := range idx.docs {
+		if filter != nil && !filter(doc) {
+			continue
+		}
+		seen := map[string]struct{}{}
+		for _, field := range []int{FieldTitle, FieldDescription} {
+			for _, token := range doc.Tokens[field] {
+				if _, ok := seen[token.Surface]; !ok {
+					seen[token.Surface] = struct{}{}
+					freq[token.Surface]++
+				}
+			}
+		}
+	}
+	idx.mu.RUnlock()
+
+	type candidate struct {
+		word string
+		dist int
+		freq int
+	}
+	lastRunes := []rune(last)
+	maxTypos := allowedTypos(len(lastRunes))
+	var candidates []candidate
+	for word, n := range freq {
+		if word == last || utf8.RuneCountInString(word) < len(lastRunes) {
+			continue
+		}
+		if dist := prefixDistance(lastRunes, []rune(word)); dist <= maxTypos {
+			candidates = append(candidates, candidate{word: word, dist: dist, freq: n})
+		}
+	}
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		if a.dist != b.dist {
+			return a.dist - b.dist
+		}
+		if a.freq != b.freq {
+			return b.freq - a.freq
+		}
+		if len(a.word) != len(b.word) {
+			return len(a.word) - len(b.word)
+		}
+		return strings.Compare(a.word, b.word)
+	})
+
+	suggestions := make([]string, 0, min(limit, len(candidates)))
+	for _, c := range candidates[:min(limit, len(candidates))] {
+		suggestions = append(suggestions, prefix+c.word)
+	}
+	return suggestions
+}
+
+// allowedTypos 输入越长允许的拼写错误越多, 过短的输入只做精确前缀匹配
+func allowedTypos(n int) int {
+	switch {
+	case n < 3:
+		return 0
+	case n < 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// prefixDistance 计算输入与候选词任意前缀之间的最小编辑距离
+func prefixDistance(input []rune, word []rune) int {
+	prev := make([]int, len(word)+1)
+	curr := make([]int, len(word)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(input); i++ {
+		curr[0] = i
+		for j := 1; j <= len(word); j++ {
+			cost := 1
+			if input[i-1] == word[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return slices.Min(prev)
+}
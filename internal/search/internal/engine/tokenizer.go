@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-ego/gse"
+	"github.com/kljensen/snowball/english"
+)
+
+// Token 分词结果, Term为索引词, Surface为原始词形, Pos为词在字段中的位置
+type Token struct {
+	Term    string
+	Surface string
+	Pos     int
+}
+
+// Tokenizer 中文按词典分词, 英文转小写后做词干提取
+type Tokenizer struct {
+	seg gse.Segmenter
+}
+
+// NewTokenizer 加载内置中文词典, 耗时约1秒, 应在后台调用
+func NewTokenizer() (*Tokenizer, error) {
+	seg, err := gse.NewEmbed("zh")
+	if err != nil {
+		return nil, err
+	}
+	return &Tokenizer{seg: seg}, nil
+}
+
+// Tokenize 切分用于建索引的文本, 长中文词额外索引其子词, 子词与原词位置相同
+func (t *Tokenizer) Tokenize(text string) []Token {
+	var tokens []Token
+	pos := 0
+	for _, word := range t.seg.Cut(strings.ToLower(text), true) {
+		word = strings.TrimSpace(word)
+		if !isWord(word) {
+			continue
+		}
+		tokens = append(tokens, Token{Term: normalize(word), Surface: word, Pos: pos})
+		if hasHan(word) && utf8.RuneCountInString(word) > 2 {
+			for _, sub := range t.seg.CutSearch(word, true) {
+				if sub != word && isWord(sub) {
+					tokens = append(tokens, Token{Term: sub, Surface: sub, Pos: pos})
+				}
+			}
+		}
+		pos++
+	}
+	return tokens
+}
+
+// Terms 切分查询文本, 不展开子词, 保证短语查询的位置连续
+func (t *Tokenizer) Terms(text string) []string {
+	var terms []string
+	for _, word := range t.seg.Cut(strings.ToLower(text), true) {
+		word = strings.TrimSpace(word)
+		if isWord(word) {
+			terms = append(terms, normalize(word))
+		}
+	}
+	return terms
+}
+
+// normalize 纯英文单词做词干提取, 其余原样返回
+func normalize(word string) string {
+	for _, r := range word {
+		if r > unicode.MaxASCII || !unicode.IsLetter(r) {
+			return word
+		}
+	}
+	return english.Stem(word, true)
+}
+
+// isWord 过滤纯标点和空白
+func isWord(word string) bool {
+	for _, r := range word {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasHan(word string) bool {
+	for _, r := range word {
+		if unicode.Is(unicode.Han, r) {
+			return true
+		}
+	}
+	return false
+}
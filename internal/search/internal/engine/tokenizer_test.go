@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+var (
+	tokenizerOnce sync.Once
+	tokenizer     *Tokenizer
+	tokenizerErr  error
+)
+
+// testTokenizer 加载词典较慢, 所有测试共用一个分词器
+func testTokenizer(t *testing.T) *Tokenizer {
+	t.Helper()
+	tokenizerOnce.Do(func() {
+		tokenizer, tokenizerErr = NewTokenizer()
+	})
+	if tokenizerErr != nil {
+		t.Fatal(tokenizerErr)
+	}
+	return tokenizer
+}
+
+func TestTokenize(t *testing.T) {
+	tk := testTokenizer(t)
+	tests := []struct {
+		name string
+		text string
+		want []Token
+	}{
+		{
+			name: "中英文混合",
+			text: "Go语言的并发编程",
+			want: []Token{{"go", "go", 0}, {"语言", "语言", 1}, {"的", "的", 2}, {"并发", "并发", 3}, {"编程", "编程", 4}},
+		},
+		{
+			name: "英文转小写并提取词干, 去掉标点",
+			text: "Running tests, quickly!",
+			want: []Token{{"run", "running", 0}, {"test", "tests", 1}, {"quick", "quickly", 2}},
+		},
+		{
+			name: "长中文词额外索引子词, 子词与原词位置相同",
+			text: "MongoDB全文索引",
+			want: []Token{{"mongodb", "mongodb", 0}, {"全文索引", "全文索引", 1}, {"全文", "全文", 1}, {"索引", "索引", 1}},
+		},
+		{
+			name: "数字保留",
+			text: "Go 1.22 发布",
+			want: []Token{{"go", "go", 0}, {"1.22", "1.22", 1}, {"发布", "发布", 2}},
+		},
+		{name: "只有标点和空白", text: " ，。!? ", want: nil},
+		{name: "空文本", text: "", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tk.Tokenize(tt.text); !slices.Equal(got, tt.want) {
+				t.Errorf("Tokenize(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerms(t *testing.T) {
+	tk := testTokenizer(t)
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "中英文混合", text: "使用Docker部署", want: []string{"使用", "docker", "部署"}},
+		{name: "查询不展开子词", text: "MongoDB全文索引", want: []string{"mongodb", "全文索引"}},
+		{name: "与索引使用相同的词干", text: "RUNNING Tests", want: []string{"run", "test"}},
+		{name: "只有标点", text: "，。", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tk.Terms(tt.text); !slices.Equal(got, tt.want) {
+				t.Errorf("Terms(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	tk := testTokenizer(t)
+	tests := []struct {
+		name         string
+		raw          string
+		wantClauses  [][]string
+		wantExcludes []string
+	}{
+		{name: "单个词", raw: "docker", wantClauses: [][]string{{"docker"}}},
+		{name: "多个词分别成句", raw: "docker 部署", wantClauses: [][]string{{"docker"}, {"部署"}}},
+		{name: "中文短句按词成句", raw: "并发编程", wantClauses: [][]string{{"并发"}, {"编程"}}},
+		{name: "双引号内为短语", raw: `"并发编程" docker`, wantClauses: [][]string{{"并发", "编程"}, {"docker"}}},
+		{name: "排除词", raw: "docker -java", wantClauses: [][]string{{"docker"}}, wantExcludes: []string{"java"}},
+		{name: "只有排除词", raw: "-java", wantExcludes: []string{"java"}},
+		{name: "空短语", raw: `""`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := ParseQuery(tt.raw, tk)
+			if !slices.EqualFunc(q.Clauses, tt.wantClauses, slices.Equal) {
+				t.Errorf("Clauses = %q, want %q", q.Clauses, tt.wantClauses)
+			}
+			if !slices.Equal(q.Excludes, tt.wantExcludes) {
+				t.Errorf("Excludes = %q, want %q", q.Excludes, tt.wantExcludes)
+			}
+			if q.Empty() != (len(tt.wantClauses) == 0) {
+				t.Errorf("Empty() = %v", q.Empty())
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// SearchHit 全文检索命中的文章或文档内容
+type SearchHit struct {
+	ID          bson.ObjectID `bson:"_id"`
+	CreatedAt   time.Time     `bson:"created_at"`
+	UpdatedAt   time.Time     `bson:"updated_at"`
+	Title       string        `bson:"title"`
+	Description string        `bson:"description"`
+	Content     string        `bson:"content"`
+	Alias       string        `bson:"alias"`
+	DocumentId  bson.ObjectID `bson:"document_id,omitempty"`
+	Score       float64       `bson:"score"`
+}
+
+// PostRecord 建索引所需的文章字段
+type PostRecord struct {
+	ID          bson.ObjectID   `bson:"_id"`
+	CreatedAt   time.Time       `bson:"created_at"`
+	UpdatedAt   time.Time       `bson:"updated_at"`
+	Title       string          `bson:"title"`
+	Description string          `bson:"description"`
+	Content     string          `bson:"content"`
+	Alias       string          `bson:"alias"`
+	CategoryID  bson.ObjectID   `bson:"category_id"`
+	TagsID      []bson.ObjectID `bson:"tags_id"`
+	IsPublish   bool            `bson:"is_publish"`
+	PublishAt   *time.Time      `bson:"publish_at,omitempty"`
+}
+
+// DocumentContentRecord 建索引所需的文档内容字段
+type DocumentContentRecord struct {
+	ID          bson.ObjectID `bson:"_id"`
+	CreatedAt   time.Time     `bson:"created_at"`
+	UpdatedAt   time.Time     `bson:"updated_at"`
+	DocumentId  bson.ObjectID `bson:"document_id"`
+	Title       string        `bson:"title"`
+	Description string        `bson:"description"`
+	Content     string        `bson:"content"`
+	Alias       string        `bson:"alias"`
+}
+
+// Document 文档基本信息, 用于补全文档内容所属文档
+type Document struct {
+	ID    bson.ObjectID `bson:"_id"`
+	Title string        `bson:"title"`
+	Alias string        `bson:"alias"`
+}
+
+type ISearchDao interface {
+	SearchPost(ctx context.Context, filter bson.D, limit int64) ([]*SearchHit, error)
+	CountPost(ctx context.Context, filter bson.D) (int64, error)
+	SearchDocumentContent(ctx context.Context, filter bson.D, limit int64) ([]*SearchHit, error)
+	CountDocumentContent(ctx context.Context, filter bson.D) (int64, error)
+	GetDocumentList(ctx context.Context, filter bson.D) ([]*Document, error)
+	FindPosts(ctx context.Context, filter bson.D) ([]*PostRecord, error)
+	FindDocumentContents(ctx context.Context, filter bson.D) ([]*DocumentContentRecord, error)
+}
+
+var _ ISearchDao = (*SearchDao)(nil)
+
+func NewSearchDao(db *mongo.Database) *SearchDao {
+	d := &SearchDao{
+		postColl:            db.Collection("post"),
+		documentContentColl: db.Collection("document_content"),
+		documentColl:        db.Collection("document"),
+	}
+	d.ensureIndexes()
+	return d
+}
+
+type SearchDao struct {
+	postColl            *mongo.Collection
+	documentContentColl *mongo.Collection
+	documentColl        *mongo.Collection
+}
+
+// ensureIndexes 创建全文索引, 标题权重最高, 描述次之, 正文最低
+// default_language设为none, 不做词干提取和停用词过滤, 以兼容中英文混排内容
+func (d *SearchDao) ensureIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	textIndex := func(name string) mongo.IndexModel {
+		return mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "title", Value: "text"},
+				{Key: "description", Value: "text"},
+				{Key: "content", Value: "text"},
+			},
+			Options: options.Index().
+				SetName(name).
+				SetWeights(bson.D{{Key: "title", Value: 10}, {Key: "description", Value: 5}, {Key: "content", Value: 1}}).
+				SetDefaultLanguage("none"),
+		}
+	}
+	if _, err := d.postColl.Indexes().CreateOne(ctx, textIndex("post_text")); err != nil {
+		logger.Warn("创建文章全文索引失败",
+			logger.WithError(err),
+		)
+	}
+	if _, err := d.documentContentColl.Indexes().CreateOne(ctx, textIndex("document_content_text")); err != nil {
+		logger.Warn("创建文档内容全文索引失败",
+			logger.WithError(err),
+		)
+	}
+}
+
+// SearchPost 全文检索文章, 按相关度降序
+func (d *SearchDao) SearchPost(ctx context.Context, filter bson.D, limit int64) ([]*SearchHit, error) {
+	return d.search(ctx, d.postColl, filter, limit)
+}
+
+// CountPost 统计命中的文章数量
+func (d *SearchDao) CountPost(ctx context.Context, filter bson.D) (int64, error) {
+	return d.postColl.CountDocuments(ctx, filter)
+}
+
+// SearchDocumentContent 全文检索文档内容, 按相关度降序
+func (d *SearchDao) SearchDocumentContent(ctx context.Context, filter bson.D, limit int64) ([]*SearchHit, error) {
+	return d.search(ctx, d.documentContentColl, filter, limit)
+}
+
+// CountDocumentContent 统计命中的文档内容数量
+func (d *SearchDao) CountDocumentContent(ctx context.Context, filter bson.D) (int64, error) {
+	return d.documentContentColl.CountDocuments(ctx, filter)
+}
+
+// GetDocumentList 查询文档基本信息
+func (d *SearchDao) GetDocumentList(ctx context.Context, filter bson.D) ([]*Document, error) {
+	opts := options.Find().SetProjection(bson.M{"title": 1, "alias": 1})
+	cursor, err := d.documentColl.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var documents []*Document
+	if err = cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+func (d *SearchDao) search(ctx context.Context, coll *mongo.Collection, filter bson.D, limit int64) ([]*SearchHit, error) {
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().
+		SetProjection(bson.M{
+			"created_at":  1,
+			"updated_at":  1,
+			"title":       1,
+			"description": 1,
+			"content":     1,
+			"alias":       1,
+			"document_id": 1,
+			"score":       score,
+		}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "_id", Value: -1}}).
+		SetLimit(limit)
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var hits []*SearchHit
+	if err = cursor.All(ctx, &hits); err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+// FindPosts 查询建索引所需的文章
+func (d *SearchDao) FindPosts(ctx context.Context, filter bson.D) ([]*PostRecord, error) {
+	cursor, err := d.postColl.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var posts []*PostRecord
+	if err = cursor.All(ctx, &posts); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// FindDocumentContents 查询建索引所需的文档内容
+func (d *SearchDao) FindDocumentContents(ctx context.Context, filter bson.D) ([]*DocumentContentRecord, error) {
+	cursor, err := d.documentContentColl.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var contents []*DocumentContentRecord
+	if err = cursor.All(ctx, &contents); err != nil {
+		return nil, err
+	}
+	return contents, nil
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/search/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/search/internal/repository/dao"
+	"github.com/samber/lo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type ISearchRepository interface {
+	SearchPost(ctx context.Context, query *domain.SearchQuery, limit int64) ([]*domain.SearchResult, int64, error)
+	SearchDocumentContent(ctx context.Context, query *domain.SearchQuery, limit int64) ([]*domain.SearchResult, int64, error)
+	GetDocumentMap(ctx context.Context, public bool) (map[bson.ObjectID]*domain.DocumentInfo, error)
+	GetIndexablePosts(ctx context.Context, ids []bson.ObjectID) ([]*domain.Indexable, error)
+	GetIndexableDocumentContents(ctx context.Context, ids []bson.ObjectID) ([]*domain.Indexable, error)
+}
+
+var _ ISearchRepository = (*SearchRepository)(nil)
+
+func NewSearchRepository(dao dao.ISearchDao) *SearchRepository {
+	return &SearchRepository{dao: dao}
+}
+
+type SearchRepository struct {
+	dao dao.ISearchDao
+}
+
+// SearchPost 检索文章, 只搜索公开内容时只返回已发布且到期的文章
+func (r *SearchRepository) SearchPost(ctx context.Context, query *domain.SearchQuery, limit int64) ([]*domain.SearchResult, int64, error) {
+	filter := bson.D{
+		{Key: "$text", Value: bson.M{"$search": query.Keyword}},
+		{Key: "deleted_at", Value: nil},
+	}
+	if query.Public {
+		filter = append(filter,
+			bson.E{Key: "is_publish", Value: true},
+			bson.E{Key: "publish_at", Value: bson.M{"$not": bson.M{"$gt": time.Now()}}},
+		)
+	}
+	if !query.CategoryId.IsZero() {
+		filter = append(filter, bson.E{Key: "category_id", Value: query.CategoryId})
+	}
+	if !query.TagId.IsZero() {
+		filter = append(filter, bson.E{Key: "tags_id", Value: query.TagId})
+	}
+	hits, err := r.dao.SearchPost(ctx, filter, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	count, err := r.dao.CountPost(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return lo.Map(hits, func(hit *dao.SearchHit, _ int) *domain.SearchResult {
+		return r.SearchHitToDomain(hit, domain.SearchTypePost)
+	}), count, nil
+}
+
+// SearchDocumentContent 检索文档内容, 只搜索公开内容时只返回公开文档下的内容
+func (r *SearchRepository) SearchDocumentContent(ctx context.Context, query *domain.SearchQuery, limit int64) ([]*domain.SearchResult, int64, error) {
+	documentMap, err := r.GetDocumentMap(ctx, query.Public)
+	if err != nil {
+		return nil, 0, err
+	}
+	documentIds := lo.Keys(documentMap)
+	if !query.DocumentId.IsZero() {
+		documentIds = lo.Filter(documentIds, func(id bson.ObjectID, _ int) bool {
+			return id == query.DocumentId
+		})
+	}
+	if len(documentIds) == 0 {
+		return nil, 0, nil
+	}
+
+	filter := bson.D{
+		{Key: "$text", Value: bson.M{"$search": query.Keyword}},
+		{Key: "is_deleted", Value: false},
+		{Key: "is_dir", Value: false},
+		{Key: "document_id", Value: bson.M{"$in": documentIds}},
+	}
+	hits, err := r.dao.SearchDocumentContent(ctx, filter, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	count, err := r.dao.CountDocumentContent(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return lo.Map(hits, func(hit *dao.SearchHit, _ int) *domain.SearchResult {
+		result := r.SearchHitToDomain(hit, domain.SearchTypeDocument)
+		if document, ok := documentMap[hit.DocumentId]; ok {
+			result.DocumentTitle = document.Title
+			result.DocumentAlias = document.Alias
+		}
+		return result
+	}), count, nil
+}
+
+// GetDocumentMap 获取未删除的文档, public为true时只返回公开文档
+func (r *SearchRepository) GetDocumentMap(ctx context.Context, public bool) (map[bson.ObjectID]*domain.DocumentInfo, error) {
+	filter := bson.D{{Key: "is_deleted", Value: false}}
+	if public {
+		filter = append(filter, bson.E{Key: "is_public", Value: true})
+	}
+	documents, err := r.dao.GetDocumentList(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return lo.SliceToMap(documents, func(document *dao.Document) (bson.ObjectID, *domain.DocumentInfo) {
+		return document.ID, &domain.DocumentInfo{Id: document.ID, Title: document.Title, Alias: document.Alias}
+	}), nil
+}
+
+// GetIndexablePosts 获取需要建索引的未删除文章, ids为空时返回全部
+func (r *SearchRepository) GetIndexablePosts(ctx context.Context, ids []bson.ObjectID) ([]*domain.Indexable, error) {
+	filter := bson.D{{Key: "deleted_at", Value: nil}}
+	if ids != nil {
+		filter = append(filter, bson.E{Key: "_id", Value: bson.M{"$in": ids}})
+	}
+	posts, err := r.dao.FindPosts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(posts, func(post *dao.PostRecord, _ int) *domain.Indexable {
+		var publishAt time.Time
+		if post.PublishAt != nil {
+			publishAt = *post.PublishAt
+		}
+		return &domain.Indexable{
+			Type:        domain.SearchTypePost,
+			Id:          post.ID,
+			CreatedAt:   post.CreatedAt,
+			UpdatedAt:   post.UpdatedAt,
+			Title:       post.Title,
+			Description: post.Description,
+			Content:     post.Content,
+			Alias:       post.Alias,
+			CategoryId:  post.CategoryID,
+			TagIds:      post.TagsID,
+			IsPublish:   post.IsPublish,
+			PublishAt:   publishAt,
+		}
+	}), nil
+}
+
+// GetIndexableDocumentContents 获取需要建索引的未删除文档内容, 目录不参与索引, ids为空时返回全部
+func (r *SearchRepository) GetIndexableDocumentContents(ctx context.Context, ids []bson.ObjectID) ([]*domain.Indexable, error) {
+	filter := bson.D{{Key: "is_deleted", Value: false}, {Key: "is_dir", Value: false}}
+	if ids != nil {
+		filter = append(filter, bson.E{Key: "_id", Value: bson.M{"$in": ids}})
+	}
+	contents, err := r.dao.FindDocumentContents(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(contents, func(content *dao.DocumentContentRecord, _ int) *domain.Indexable {
+		return &domain.Indexable{
+			Type:        domain.SearchTypeDocument,
+			Id:          content.ID,
+			CreatedAt:   content.CreatedAt,
+			UpdatedAt:   content.UpdatedAt,
+			Title:       content.Title,
+			Description: content.Description,
+			Content:     content.Content,
+			Alias:       content.Alias,
+			DocumentId:  content.DocumentId,
+		}
+	}), nil
+}
+
+func (r *SearchRepository) SearchHitToDomain(hit *dao.SearchHit, searchType string) *domain.SearchResult {
+	return &domain.SearchResult{
+		Type:        searchType,
+		Id:          hit.ID,
+		CreatedAt:   hit.CreatedAt,
+		UpdatedAt:   hit.UpdatedAt,
+		Title:       hit.Title,
+		Description: hit.Description,
+		Content:     hit.Content,
+		Alias:       hit.Alias,
+		DocumentId:  hit.DocumentId,
+		Score:       hit.Score,
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/codepzj/Stellux-Server/internal/search/internal/domain"
+)
+
+const (
+	snippetRadius = 60 // 摘要中命中词前后保留的字符数
+	markOpen      = "<mark>"
+	markClose     = "</mark>"
+)
+
+// searchTerms 从查询语句中提取需要高亮的词, 去掉引号和排除词
+func searchTerms(keyword string) []string {
+	var terms []string
+	for _, field := range strings.Fields(keyword) {
+		if strings.HasPrefix(field, "-") {
+			continue
+		}
+		field = strings.Trim(field, `"`)
+		if field != "" {
+			terms = append(terms, strings.ToLower(field))
+		}
+	}
+	return terms
+}
+
+// snippet 从正文中截取首个命中词附近的文本, 正文未命中时依次退回描述和正文开头
+func snippet(result *domain.SearchResult, terms []string) string {
+	for _, text := range []string{result.Content, result.Description} {
+		text = normalizeSpace(text)
+		if start, ok := firstMatch(text, terms); ok {
+			return highlight(cutAround(text, start), terms)
+		}
+	}
+	if result.Description != "" {
+		return highlight(cutAround(normalizeSpace(result.Description), 0), terms)
+	}
+	return highlight(cutAround(normalizeSpace(result.Content), 0), terms)
+}
+
+// highlight 转义HTML后用mark标签包裹命中词, 匹配不区分大小写
+func highlight(text string, terms []string) string {
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(lower) != len(runes) {
+		// 个别字符转小写后长度变化时放弃高亮, 避免错位
+		return html.EscapeString(text)
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(runes); {
+		matched := 0
+		for _, term := range terms {
+			termRunes := []rune(term)
+			if len(termRunes) > matched && hasPrefixAt(lower, termRunes, i) {
+				matched = len(termRunes)
+			}
+		}
+		if matched == 0 {
+			b.WriteString(html.EscapeString(string(runes[i])))
+			i++
+			continue
+		}
+		b.WriteString(markOpen)
+		b.WriteString(html.EscapeString(string(runes[i : i+matched])))
+		b.WriteString(markClose)
+		i += matched
+	}
+	return b.String()
+}
+
+// firstMatch 返回首个命中词的字符下标
+func firstMatch(text string, terms []string) (int, bool) {
+	lower := []rune(strings.ToLower(text))
+	for i := range lower {
+		for _, term := range terms {
+			if hasPrefixAt(lower, []rune(term), i) {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// cutAround 以start为中心截取摘要, 被截断的一侧补省略号
+func cutAround(text string, start int) string {
+	runes := []rune(text)
+	from := max(start-snippetRadius, 0)
+	to := min(start+snippetRadius*2, len(runes))
+	s := string(runes[from:to])
+	if from > 0 {
+		s = "..." + s
+	}
+	if to < len(runes) {
+		s += "..."
+	}
+	return s
+}
+
+func hasPrefixAt(s []rune, prefix []rune, i int) bool {
+	if len(prefix) == 0 || i+len(prefix) > len(s) {
+		return false
+	}
+	for j, r := range prefix {
+		if s[i+j] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeSpace 将连续空白折叠为单个空格, 避免摘要中出现大段换行
+func normalizeSpace(text string) string {
+	return strings.Join(strings.FieldsFunc(text, unicode.IsSpace), " ")
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/codepzj/Stellux-Server/internal/search/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/search/internal/engine"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const indexFlushInterval = 30 * time.Second // 索引落盘间隔
+
+// StartIndexer 启动索引协程: 加载磁盘索引并与数据库增量同步, 之后定期落盘, ctx取消时保存后退出
+func (s *SearchService) StartIndexer(ctx context.Context) {
+	go func() {
+		idx, err := s.loadIndex(ctx)
+		if err != nil {
+			logger.Error("初始化全文索引失败, 搜索将使用数据库全文索引",
+				logger.WithError(err),
+			)
+			return
+		}
+		s.setIndex(ctx, idx)
+
+		ticker := time.NewTicker(indexFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				s.saveIndex()
+				return
+			case <-ticker.C:
+				if idx := s.getIndex(); idx != nil && idx.Dirty() {
+					s.saveIndex()
+				}
+			}
+		}
+	}()
+	logger.Info("全文索引协程已启动")
+}
+
+// SyncPost 按数据库中的最新状态更新文章索引, 文章不存在或已删除时移出索引
+func (s *SearchService) SyncPost(ctx context.Context, ids ...bson.ObjectID) {
+	s.sync(ctx, domain.SearchTypePost, ids)
+}
+
+// SyncDocumentContent 按数据库中的最新状态更新文档内容索引, 内容不存在或已删除时移出索引
+func (s *SearchService) SyncDocumentContent(ctx context.Context, ids ...bson.ObjectID) {
+	s.sync(ctx, domain.SearchTypeDocument, ids)
+}
+
+// AdminRebuildIndex 从数据库全量重建索引
+func (s *SearchService) AdminRebuildIndex(ctx context.Context) error {
+	current := s.getIndex()
+	if current == nil {
+		return errors.New("全文索引尚未就绪")
+	}
+	idx := engine.NewIndex(current.Tokenizer())
+	if err := s.syncAll(ctx, idx); err != nil {
+		logger.Error("重建全文索引失败",
+			logger.WithError(err),
+		)
+		return err
+	}
+	s.setIndex(ctx, idx)
+	s.saveIndex()
+	logger.Info("重建全文索引成功",
+		logger.WithInt("count", idx.Len()),
+	)
+	return nil
+}
+
+// loadIndex 加载词典和磁盘索引, 文件不存在或版本不符时全量重建, 否则只同步有变化的内容
+func (s *SearchService) loadIndex(ctx context.Context) (*engine.Index, error) {
+	tokenizer, err := engine.NewTokenizer()
+	if err != nil {
+		return nil, err
+	}
+	idx := engine.NewIndex(tokenizer)
+	if err := idx.Load(s.indexPath); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			logger.Warn("加载全文索引文件失败, 将全量重建",
+				logger.WithError(err),
+				logger.WithString("path", s.indexPath),
+			)
+		}
+		idx = engine.NewIndex(tokenizer)
+	}
+	if err := s.syncAll(ctx, idx); err != nil {
+		return nil, err
+	}
+	logger.Info("全文索引加载完成",
+		logger.WithInt("count", idx.Len()),
+	)
+	return idx, nil
+}
+
+// setIndex 切换到新索引并补同步加载期间发生变更的内容
+func (s *SearchService) setIndex(ctx context.Context, idx *engine.Index) {
+	s.mu.Lock()
+	s.index = idx
+	pending := s.pending
+	s.pending = map[string]bson.ObjectID{}
+	s.mu.Unlock()
+
+	var postIds, contentIds []bson.ObjectID
+	for key, id := range pending {
+		if key == engine.DocKey(domain.SearchTypePost, id) {
+			postIds = append(postIds, id)
+		} else {
+			contentIds = append(contentIds, id)
+		}
+	}
+	if len(postIds) > 0 {
+		s.SyncPost(ctx, postIds...)
+	}
+	if len(contentIds) > 0 {
+		s.SyncDocumentContent(ctx, contentIds...)
+	}
+}
+
+func (s *SearchService) saveIndex() {
+	idx := s.getIndex()
+	if idx == nil {
+		return
+	}
+	if err := idx.Save(s.indexPath); err != nil {
+		logger.Error("保存全文索引失败",
+			logger.WithError(err),
+			logger.WithString("path", s.indexPath),
+		)
+	}
+}
+
+func (s *SearchService) sync(ctx context.Context, docType string, ids []bson.ObjectID) {
+	if len(ids) == 0 {
+		return
+	}
+	s.mu.Lock()
+	idx := s.index
+	if idx == nil {
+		for _, id := range ids {
+			s.pending[engine.DocKey(docType, id)] = id
+		}
+		s.mu.Unlock()
+		return
+	}
+	s.mu.Unlock()
+
+	var items []*domain.Indexable
+	var err error
+	if docType == domain.SearchTypePost {
+		items, err = s.repo.GetIndexablePosts(ctx, ids)
+	} else {
+		items, err = s.repo.GetIndexableDocumentContents(ctx, ids)
+	}
+	if err != nil {
+		logger.Error("同步全文索引失败",
+			logger.WithError(err),
+			logger.WithString("type", docType),
+		)
+		return
+	}
+
+	found := map[bson.ObjectID]struct{}{}
+	for _, item := range items {
+		found[item.Id] = struct{}{}
+		idx.Put(indexableToDocument(item))
+	}
+	for _, id := range ids {
+		if _, ok := found[id]; !ok {
+			idx.Remove(engine.DocKey(docType, id))
+		}
+	}
+}
+
+// syncAll 与数据库全量比对, 只重新分词更新时间有变化的内容, 并移除数据库中已不存在的内容
+func (s *SearchService) syncAll(ctx context.Context, idx *engine.Index) error {
+	posts, err := s.repo.GetIndexablePosts(ctx, nil)
+	if err != nil {
+		return err
+	}
+	contents, err := s.repo.GetIndexableDocumentContents(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	versions := idx.Versions()
+	changed := 0
+	for _, item := range append(posts, contents...) {
+		key := engine.DocKey(item.Type, item.Id)
+		updatedAt, ok := versions[key]
+		delete(versions, key)
+		if ok && updatedAt.Equal(item.UpdatedAt) {
+			continue
+		}
+		idx.Put(indexableToDocument(item))
+		changed++
+	}
+	for key := range versions {
+		idx.Remove(key)
+	}
+	if changed > 0 || len(versions) > 0 {
+		logger.Info("全文索引增量同步完成",
+			logger.WithInt("updated", changed),
+			logger.WithInt("removed", len(versions)),
+		)
+	}
+	return nil
+}
+
+func indexableToDocument(item *domain.Indexable) *engine.Document {
+	return &engine.Document{
+		Key:         engine.DocKey(item.Type, item.Id),
+		Type:        item.Type,
+		Id:          item.Id,
+		Title:       item.Title,
+		Description: item.Description,
+		Content:     item.Content,
+		Alias:       item.Alias,
+		CategoryId:  item.CategoryId,
+		TagIds:      item.TagIds,
+		DocumentId:  item.DocumentId,
+		IsPublish:   item.IsPublish,
+		PublishAt:   item.PublishAt,
+		CreatedAt:   item.CreatedAt,
+		UpdatedAt:   item.UpdatedAt,
+	}
+}
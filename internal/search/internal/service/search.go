@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/codepzj/Stellux-Server/internal/search/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/search/internal/engine"
+	"github.com/codepzj/Stellux-Server/internal/search/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	maxSearchWindow  = 1000 // 最多可翻到的结果条数, 防止深分页拖垮数据库
+	defaultIndexPath = "data/search.idx"
+)
+
+type ISearchService interface {
+	Search(ctx context.Context, query *domain.SearchQuery) ([]*domain.SearchResult, int64, error)
+	Suggest(ctx context.Context, keyword string, limit int, public bool) (*domain.Suggestion, error)
+	SyncPost(ctx context.Context, ids ...bson.ObjectID)
+	SyncDocumentContent(ctx context.Context, ids ...bson.ObjectID)
+	AdminRebuildIndex(ctx context.Context) error
+	StartIndexer(ctx context.Context)
+}
+
+var _ ISearchService = (*SearchService)(nil)
+
+func NewSearchService(repo repository.ISearchRepository, cfg *conf.Config) *SearchService {
+	indexPath := cfg.Search.IndexPath
+	if indexPath == "" {
+		indexPath = defaultIndexPath
+	}
+	return &SearchService{
+		repo:      repo,
+		indexPath: indexPath,
+		pending:   map[string]bson.ObjectID{},
+	}
+}
+
+type SearchService struct {
+	repo      repository.ISearchRepository
+	indexPath string
+
+	mu      sync.Mutex
+	index   *engine.Index            // 索引加载完成前为nil, 此时搜索退回数据库全文索引
+	pending map[string]bson.ObjectID // 索引加载期间发生变更的内容, 加载完成后补同步
+}
+
+// Search 全文检索文章和文档内容, 优先使用内置倒排索引, 索引未就绪时退回数据库全文索引
+func (s *SearchService) Search(ctx context.Context, query *domain.SearchQuery) ([]*domain.SearchResult, int64, error) {
+	query.Keyword = strings.TrimSpace(query.Keyword)
+	if query.Keyword == "" {
+		return nil, 0, errors.New("关键词不能为空")
+	}
+	skip := (query.PageNo - 1) * query.PageSize
+	if skip >= maxSearchWindow {
+		return nil, 0, errors.New("页码超出搜索范围")
+	}
+	// 分类和标签只对文章生效, 所属文档只对文档内容生效
+	if !query.CategoryId.IsZero() || !query.TagId.IsZero() {
+		if query.Type == domain.SearchTypeDocument {
+			return []*domain.SearchResult{}, 0, nil
+		}
+		query.Type = domain.SearchTypePost
+	}
+	if !query.DocumentId.IsZero() {
+		if query.Type == domain.SearchTypePost {
+			return []*domain.SearchResult{}, 0, nil
+		}
+		query.Type = domain.SearchTypeDocument
+	}
+
+	var results []*domain.SearchResult
+	var total int64
+	var err error
+	if idx := s.getIndex(); idx != nil {
+		results, total, err = s.searchIndex(ctx, idx, query, skip)
+	} else {
+		results, total, err = s.searchDatabase(ctx, query, skip)
+	}
+	if err != nil {
+		logger.Error("搜索失败",
+			logger.WithError(err),
+			logger.WithString("keyword", query.Keyword),
+		)
+		return nil, 0, err
+	}
+
+	terms := searchTerms(query.Keyword)
+	for _, result := range results {
+		result.TitleHighlight = highlight(result.Title, terms)
+		result.Snippet = snippet(result, terms)
+	}
+	return results, total, nil
+}
+
+// Suggest 搜索框输入联想, 返回补全词和标题最相关的结果
+func (s *SearchService) Suggest(ctx context.Context, keyword string, limit int, public bool) (*domain.Suggestion, error) {
+	suggestion := &domain.Suggestion{Terms: []string{}, Results: []*domain.SearchResult{}}
+	idx := s.getIndex()
+	if idx == nil || strings.TrimSpace(keyword) == "" {
+		return suggestion, nil
+	}
+	visible, documentMap, err := s.visibleFilter(ctx, &domain.SearchQuery{Public: public})
+	if err != nil {
+		logger.Error("查询文档列表失败",
+			logger.WithError(err),
+		)
+		return nil, err
+	}
+
+	suggestion.Terms = idx.Suggest(keyword, limit, visible)
+	best := keyword
+	if len(suggestion.Terms) > 0 {
+		best = suggestion.Terms[0]
+	}
+	hits := idx.Search(engine.ParseQuery(best, idx.Tokenizer()), visible)
+	terms := searchTerms(best)
+	for _, hit := range hits[:min(limit, len(hits))] {
+		result := hitToResult(hit, documentMap)
+		result.TitleHighlight = highlight(result.Title, terms)
+		result.Content = ""
+		suggestion.Results = append(suggestion.Results, result)
+	}
+	return suggestion, nil
+}
+
+// searchIndex 使用内置倒排索引检索
+func (s *SearchService) searchIndex(ctx context.Context, idx *engine.Index, query *domain.SearchQuery, skip int64) ([]*domain.SearchResult, int64, error) {
+	q := engine.ParseQuery(query.Keyword, idx.Tokenizer())
+	visible, documentMap, err := s.visibleFilter(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	hits := idx.Search(q, visible)
+	total := int64(len(hits))
+	if skip >= total {
+		return []*domain.SearchResult{}, total, nil
+	}
+	results := make([]*domain.SearchResult, 0, query.PageSize)
+	for _, hit := range hits[skip:min(skip+query.PageSize, total)] {
+		results = append(results, hitToResult(hit, documentMap))
+	}
+	return results, total, nil
+}
+
+// visibleFilter 根据搜索条件生成索引文档的过滤函数
+func (s *SearchService) visibleFilter(ctx context.Context, query *domain.SearchQuery) (func(*engine.Document) bool, map[bson.ObjectID]*domain.DocumentInfo, error) {
+	documentMap, err := s.repo.GetDocumentMap(ctx, query.Public)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	return func(doc *engine.Document) bool {
+		if query.Type != "" && doc.Type != query.Type {
+			return false
+		}
+		switch doc.Type {
+		case domain.SearchTypePost:
+			if query.Public && !postVisible(doc, now) {
+				return false
+			}
+			if !query.CategoryId.IsZero() && doc.CategoryId != query.CategoryId {
+				return false
+			}
+			if !query.TagId.IsZero() && !slices.Contains(doc.TagIds, query.TagId) {
+				return false
+			}
+		case domain.SearchTypeDocument:
+			if _, ok := documentMap[doc.DocumentId]; !ok {
+				return false
+			}
+			if !query.DocumentId.IsZero() && doc.DocumentId != query.DocumentId {
+				return false
+			}
+		}
+		return true
+	}, documentMap, nil
+}
+
+// postVisible 设置了定时发布的文章到期后可见, 其余按发布状态判断
+func postVisible(doc *engine.Document, now time.Time) bool {
+	if !doc.PublishAt.IsZero() {
+		return !doc.PublishAt.After(now)
+	}
+	return doc.IsPublish
+}
+
+// searchDatabase 使用数据库全文索引检索, 两个集合各取前limit条后合并排序
+func (s *SearchService) searchDatabase(ctx context.Context, query *domain.SearchQuery, skip int64) ([]*domain.SearchResult, int64, error) {
+	limit := skip + query.PageSize
+
+	var results []*domain.SearchResult
+	var total int64
+	if query.Type == "" || query.Type == domain.SearchTypePost {
+		posts, count, err := s.repo.SearchPost(ctx, query, limit)
+		if err != nil {
+			return nil, 0, err
+		}
+		results = append(results, posts...)
+		total += count
+	}
+	if query.Type == "" || query.Type == domain.SearchTypeDocument {
+		contents, count, err := s.repo.SearchDocumentContent(ctx, query, limit)
+		if err != nil {
+			return nil, 0, err
+		}
+		results = append(results, contents...)
+		total += count
+	}
+
+	slices.SortStableFunc(results, func(a, b *domain.SearchResult) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		}
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+	if skip >= int64(len(results)) {
+		return []*domain.SearchResult{}, total, nil
+	}
+	return results[skip:min(limit, int64(len(results)))], total, nil
+}
+
+func (s *SearchService) getIndex() *engine.Index {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.index
+}
+
+func hitToResult(hit engine.Hit, documentMap map[bson.ObjectID]*domain.DocumentInfo) *domain.SearchResult {
+	result := &domain.SearchResult{
+		Type:        hit.Doc.Type,
+		Id:          hit.Doc.Id,
+		CreatedAt:   hit.Doc.CreatedAt,
+		UpdatedAt:   hit.Doc.UpdatedAt,
+		Title:       hit.Doc.Title,
+		Description: hit.Doc.Description,
+		Content:     hit.Doc.Content,
+		Alias:       hit.Doc.Alias,
+		DocumentId:  hit.Doc.DocumentId,
+		Score:       hit.Score,
+	}
+	if document, ok := documentMap[hit.Doc.DocumentId]; ok {
+		result.DocumentTitle = document.Title
+		result.DocumentAlias = document.Alias
+	}
+	return result
+}
@@ -0,0 +1,25 @@
+package web
+
+type SearchRequest struct {
+	// 当前页
+	PageNo int64 `form:"page_no" binding:"required,gte=1"`
+	// 每页条数
+	PageSize int64 `form:"page_size" binding:"required,gte=1,lte=50"`
+	// 搜索内容, 支持双引号短语和减号排除
+	Keyword string `form:"keyword" binding:"required"`
+	// 结果类型, 为空时搜索全部
+	Type string `form:"type" binding:"omitempty,oneof=post document"`
+	// 分类ID, 仅过滤文章
+	CategoryId string `form:"category_id" binding:"omitempty,len=24"`
+	// 标签ID, 仅过滤文章
+	TagId string `form:"tag_id" binding:"omitempty,len=24"`
+	// 所属文档ID, 仅过滤文档内容
+	DocumentId string `form:"document_id" binding:"omitempty,len=24"`
+}
+
+type SuggestRequest struct {
+	// 当前输入内容
+	Keyword string `form:"keyword" binding:"required"`
+	// 返回条数, 默认5
+	Limit int `form:"limit" binding:"omitempty,gte=1,lte=20"`
+}
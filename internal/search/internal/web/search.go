@@ -0,0 +1,109 @@
+package web
+
+import (
+	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+	"github.com/codepzj/Stellux-Server/internal/pkg/middleware"
+	"github.com/codepzj/Stellux-Server/internal/pkg/permission"
+	"github.com/codepzj/Stellux-Server/internal/search/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/search/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func NewSearchHandler(serv service.ISearchService) *SearchHandler {
+	return &SearchHandler{
+		serv: serv,
+	}
+}
+
+type SearchHandler struct {
+	serv service.ISearchService
+}
+
+func (h *SearchHandler) RegisterGinRoutes(engine *gin.Engine) {
+	searchGroup := engine.Group("/search")
+	{
+		searchGroup.GET("", apiwrap.WrapWithQuery(h.Search))          // 全文搜索已发布文章和公开文档
+		searchGroup.GET("/suggest", apiwrap.WrapWithQuery(h.Suggest)) // 搜索框输入联想
+	}
+	adminGroup := engine.Group("/admin-api/search")
+	{
+		adminGroup.GET("", middleware.RequirePermission(permission.SearchRead), apiwrap.WrapWithQuery(h.AdminSearch))          // 全文搜索全部未删除的文章和文档
+		adminGroup.GET("/suggest", middleware.RequirePermission(permission.SearchRead), apiwrap.WrapWithQuery(h.AdminSuggest)) // 后台搜索框输入联想
+		adminGroup.POST("/rebuild", middleware.RequirePermission(permission.SearchWrite), apiwrap.Wrap(h.AdminRebuildIndex))   // 从数据库全量重建全文索引
+	}
+}
+
+// Search 全文搜索已发布文章和公开文档
+func (h *SearchHandler) Search(c *gin.Context, req SearchRequest) (int, string, any) {
+	return h.search(c, req, true)
+}
+
+// AdminSearch 全文搜索全部未删除的文章和文档, 包括草稿和非公开文档
+func (h *SearchHandler) AdminSearch(c *gin.Context, req SearchRequest) (int, string, any) {
+	return h.search(c, req, false)
+}
+
+// Suggest 搜索框输入联想, 只返回已发布文章和公开文档
+func (h *SearchHandler) Suggest(c *gin.Context, req SuggestRequest) (int, string, any) {
+	return h.suggest(c, req, true)
+}
+
+// AdminSuggest 后台搜索框输入联想
+func (h *SearchHandler) AdminSuggest(c *gin.Context, req SuggestRequest) (int, string, any) {
+	return h.suggest(c, req, false)
+}
+
+// AdminRebuildIndex 从数据库全量重建全文索引
+func (h *SearchHandler) AdminRebuildIndex(c *gin.Context) (int, string, any) {
+	if err := h.serv.AdminRebuildIndex(c); err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "重建索引成功", nil
+}
+
+func (h *SearchHandler) search(c *gin.Context, req SearchRequest, public bool) (int, string, any) {
+	query := &domain.SearchQuery{
+		Page: apiwrap.Page{
+			PageNo:   req.PageNo,
+			PageSize: req.PageSize,
+			Keyword:  req.Keyword,
+		},
+		Type:   req.Type,
+		Public: public,
+	}
+	var err error
+	if query.CategoryId, err = parseObjectID(req.CategoryId); err != nil {
+		return 400, "分类ID格式错误", nil
+	}
+	if query.TagId, err = parseObjectID(req.TagId); err != nil {
+		return 400, "标签ID格式错误", nil
+	}
+	if query.DocumentId, err = parseObjectID(req.DocumentId); err != nil {
+		return 400, "文档ID格式错误", nil
+	}
+	results, total, err := h.serv.Search(c, query)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "搜索成功", apiwrap.ToPageVO(query.PageNo, query.PageSize, total, h.SearchResultDomainToVOList(results))
+}
+
+func (h *SearchHandler) suggest(c *gin.Context, req SuggestRequest, public bool) (int, string, any) {
+	if req.Limit == 0 {
+		req.Limit = 5
+	}
+	suggestion, err := h.serv.Suggest(c, req.Keyword, req.Limit, public)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "获取联想词成功", h.SuggestionDomainToVO(suggestion)
+}
+
+// parseObjectID 空字符串视为未设置
+func parseObjectID(id string) (bson.ObjectID, error) {
+	if id == "" {
+		return bson.NilObjectID, nil
+	}
+	return bson.ObjectIDFromHex(id)
+}
@@ -0,0 +1,63 @@
+package web
+
+import (
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/search/internal/domain"
+	"github.com/samber/lo"
+)
+
+type SearchResultVO struct {
+	Type           string    `json:"type"`
+	Id             string    `json:"id"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	Title          string    `json:"title"`
+	TitleHighlight string    `json:"title_highlight"`
+	Description    string    `json:"description"`
+	Snippet        string    `json:"snippet"`
+	Alias          string    `json:"alias"`
+	DocumentId     string    `json:"document_id,omitempty"`
+	DocumentTitle  string    `json:"document_title,omitempty"`
+	DocumentAlias  string    `json:"document_alias,omitempty"`
+	Score          float64   `json:"score"`
+}
+
+type SuggestionVO struct {
+	Terms   []string          `json:"terms"`
+	Results []*SearchResultVO `json:"results"`
+}
+
+func (h *SearchHandler) SuggestionDomainToVO(suggestion *domain.Suggestion) *SuggestionVO {
+	return &SuggestionVO{
+		Terms:   suggestion.Terms,
+		Results: h.SearchResultDomainToVOList(suggestion.Results),
+	}
+}
+
+func (h *SearchHandler) SearchResultDomainToVOList(results []*domain.SearchResult) []*SearchResultVO {
+	return lo.Map(results, func(result *domain.SearchResult, _ int) *SearchResultVO {
+		return SearchResultDomainToVO(result)
+	})
+}
+
+func SearchResultDomainToVO(result *domain.SearchResult) *SearchResultVO {
+	vo := &SearchResultVO{
+		Type:           result.Type,
+		Id:             result.Id.Hex(),
+		CreatedAt:      result.CreatedAt,
+		UpdatedAt:      result.UpdatedAt,
+		Title:          result.Title,
+		TitleHighlight: result.TitleHighlight,
+		Description:    result.Description,
+		Snippet:        result.Snippet,
+		Alias:          result.Alias,
+		DocumentTitle:  result.DocumentTitle,
+		DocumentAlias:  result.DocumentAlias,
+		Score:          result.Score,
+	}
+	if !result.DocumentId.IsZero() {
+		vo.DocumentId = result.DocumentId.Hex()
+	}
+	return vo
+}
@@ -0,0 +1,23 @@
+package search
+
+import (
+	"github.com/codepzj/Stellux-Server/internal/search/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/search/internal/service"
+	"github.com/codepzj/Stellux-Server/internal/search/internal/web"
+)
+
+const (
+	TypePost     = domain.SearchTypePost
+	TypeDocument = domain.SearchTypeDocument
+)
+
+type (
+	Handler = web.SearchHandler
+	Service = service.ISearchService
+	Result  = domain.SearchResult
+	Query   = domain.SearchQuery
+	Module  struct {
+		Svc Service
+		Hdl *Handler
+	}
+)
@@ -0,0 +1,25 @@
+//go:build wireinject
+
+package search
+
+import (
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/search/internal/repository"
+	"github.com/codepzj/Stellux-Server/internal/search/internal/repository/dao"
+	"github.com/codepzj/Stellux-Server/internal/search/internal/service"
+	"github.com/codepzj/Stellux-Server/internal/search/internal/web"
+	"github.com/google/wire"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var SearchProviders = wire.NewSet(web.NewSearchHandler, service.NewSearchService, repository.NewSearchRepository, dao.NewSearchDao,
+	wire.Bind(new(service.ISearchService), new(*service.SearchService)),
+	wire.Bind(new(repository.ISearchRepository), new(*repository.SearchRepository)),
+	wire.Bind(new(dao.ISearchDao), new(*dao.SearchDao)))
+
+func InitSearchModule(mongoDB *mongo.Database, cfg *conf.Config) *Module {
+	panic(wire.Build(
+		SearchProviders,
+		wire.Struct(new(Module), "Svc", "Hdl"),
+	))
+}
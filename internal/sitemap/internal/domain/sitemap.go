@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// URL 站点地图中的一个地址
+type URL struct {
+	Loc     string    // 完整地址
+	LastMod time.Time // 最后修改时间, 零值时不输出
+}
+
+// File 已生成的站点地图或robots.txt
+type File struct {
+	Body    []byte    // 文件内容
+	ModTime time.Time // 最后修改时间
+}
+
+// Post 已发布文章
+type Post struct {
+	Id         bson.ObjectID
+	UpdatedAt  time.Time
+	Alias      string
+	CategoryId bson.ObjectID
+	TagIds     []bson.ObjectID
+}
+
+// Document 公开文档
+type Document struct {
+	Id        bson.ObjectID
+	UpdatedAt time.Time
+	Alias     string
+}
+
+// DocumentContent 公开文档下的页面
+type DocumentContent struct {
+	Id         bson.ObjectID
+	UpdatedAt  time.Time
+	DocumentId bson.ObjectID
+	Alias      string
+}
+
+// Label 分类或标签
+type Label struct {
+	Id        bson.ObjectID
+	LabelType string
+	Name      string
+}
+
+// Page 网站配置, 首页和关于页配置对应站点页面, seo配置提供站点地址和robots指令
+type Page struct {
+	Type         string
+	UpdatedAt    time.Time
+	RobotsMeta   string // 仅seo配置
+	CanonicalURL string // 仅seo配置
+}
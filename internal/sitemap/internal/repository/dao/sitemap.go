@@ -0,0 +1,139 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type Post struct {
+	ID         bson.ObjectID   `bson:"_id"`
+	UpdatedAt  time.Time       `bson:"updated_at"`
+	Alias      string          `bson:"alias"`
+	CategoryID bson.ObjectID   `bson:"category_id"`
+	TagsID     []bson.ObjectID `bson:"tags_id"`
+}
+
+type Document struct {
+	ID        bson.ObjectID `bson:"_id"`
+	UpdatedAt time.Time     `bson:"updated_at"`
+	Alias     string        `bson:"alias"`
+}
+
+type DocumentContent struct {
+	ID         bson.ObjectID `bson:"_id"`
+	UpdatedAt  time.Time     `bson:"updated_at"`
+	DocumentId bson.ObjectID `bson:"document_id"`
+	Alias      string        `bson:"alias"`
+}
+
+type Label struct {
+	ID        bson.ObjectID `bson:"_id"`
+	LabelType string        `bson:"type"`
+	Name      string        `bson:"name"`
+}
+
+type Config struct {
+	Type      string    `bson:"type"`
+	UpdatedAt time.Time `bson:"updated_at"`
+	Content   struct {
+		RobotsMeta   string `bson:"robots_meta"`
+		CanonicalURL string `bson:"canonical_url"`
+	} `bson:"content"`
+}
+
+type ISitemapDao interface {
+	FindPublishedPosts(ctx context.Context, now time.Time) ([]*Post, error)
+	FindPublicDocuments(ctx context.Context) ([]*Document, error)
+	FindDocumentContents(ctx context.Context, documentIds []bson.ObjectID) ([]*DocumentContent, error)
+	FindLabels(ctx context.Context) ([]*Label, error)
+	FindConfigs(ctx context.Context) ([]*Config, error)
+}
+
+var _ ISitemapDao = (*SitemapDao)(nil)
+
+func NewSitemapDao(db *mongo.Database) *SitemapDao {
+	return &SitemapDao{
+		postColl:            db.Collection("post"),
+		documentColl:        db.Collection("document"),
+		documentContentColl: db.Collection("document_content"),
+		labelColl:           db.Collection("label"),
+		configColl:          db.Collection("config"),
+	}
+}
+
+type SitemapDao struct {
+	postColl            *mongo.Collection
+	documentColl        *mongo.Collection
+	documentContentColl *mongo.Collection
+	labelColl           *mongo.Collection
+	configColl          *mongo.Collection
+}
+
+// FindPublishedPosts 查询已发布且到期的文章
+func (d *SitemapDao) FindPublishedPosts(ctx context.Context, now time.Time) ([]*Post, error) {
+	filter := bson.D{
+		{Key: "deleted_at", Value: nil},
+		{Key: "is_publish", Value: true},
+		{Key: "publish_at", Value: bson.M{"$not": bson.M{"$gt": now}}},
+	}
+	opts := options.Find().
+		SetProjection(bson.M{"updated_at": 1, "alias": 1, "category_id": 1, "tags_id": 1}).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+	return find[Post](ctx, d.postColl, filter, opts)
+}
+
+// FindPublicDocuments 查询公开且未删除的文档
+func (d *SitemapDao) FindPublicDocuments(ctx context.Context) ([]*Document, error) {
+	filter := bson.D{{Key: "is_public", Value: true}, {Key: "is_deleted", Value: false}}
+	opts := options.Find().
+		SetProjection(bson.M{"updated_at": 1, "alias": 1}).
+		SetSort(bson.D{{Key: "sort", Value: 1}})
+	return find[Document](ctx, d.documentColl, filter, opts)
+}
+
+// FindDocumentContents 查询文档下未删除的页面, 不包括目录
+func (d *SitemapDao) FindDocumentContents(ctx context.Context, documentIds []bson.ObjectID) ([]*DocumentContent, error) {
+	filter := bson.D{
+		{Key: "document_id", Value: bson.M{"$in": documentIds}},
+		{Key: "is_deleted", Value: false},
+		{Key: "is_dir", Value: false},
+	}
+	opts := options.Find().
+		SetProjection(bson.M{"updated_at": 1, "document_id": 1, "alias": 1}).
+		SetSort(bson.D{{Key: "sort", Value: 1}})
+	return find[DocumentContent](ctx, d.documentContentColl, filter, opts)
+}
+
+// FindLabels 查询全部分类和标签
+func (d *SitemapDao) FindLabels(ctx context.Context) ([]*Label, error) {
+	return find[Label](ctx, d.labelColl, bson.D{}, options.Find())
+}
+
+// FindConfigs 查询全部网站配置
+func (d *SitemapDao) FindConfigs(ctx context.Context) ([]*Config, error) {
+	opts := options.Find().SetProjection(bson.M{
+		"type":                  1,
+		"updated_at":            1,
+		"content.robots_meta":   1,
+		"content.canonical_url": 1,
+	})
+	return find[Config](ctx, d.configColl, bson.D{}, opts)
+}
+
+func find[T any](ctx context.Context, coll *mongo.Collection, filter bson.D, opts *options.FindOptionsBuilder) ([]*T, error) {
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var list []*T
+	if err = cursor.All(ctx, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/sitemap/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/sitemap/internal/repository/dao"
+	"github.com/samber/lo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type ISitemapRepository interface {
+	GetPublishedPosts(ctx context.Context) ([]*domain.Post, error)
+	GetPublicDocuments(ctx context.Context) ([]*domain.Document, error)
+	GetDocumentContents(ctx context.Context, documentIds []bson.ObjectID) ([]*domain.DocumentContent, error)
+	GetLabels(ctx context.Context) ([]*domain.Label, error)
+	GetPages(ctx context.Context) ([]*domain.Page, error)
+}
+
+var _ ISitemapRepository = (*SitemapRepository)(nil)
+
+func NewSitemapRepository(dao dao.ISitemapDao) *SitemapRepository {
+	return &SitemapRepository{dao: dao}
+}
+
+type SitemapRepository struct {
+	dao dao.ISitemapDao
+}
+
+func (r *SitemapRepository) GetPublishedPosts(ctx context.Context) ([]*domain.Post, error) {
+	posts, err := r.dao.FindPublishedPosts(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(posts, func(post *dao.Post, _ int) *domain.Post {
+		return &domain.Post{
+			Id:         post.ID,
+			UpdatedAt:  post.UpdatedAt,
+			Alias:      post.Alias,
+			CategoryId: post.CategoryID,
+			TagIds:     post.TagsID,
+		}
+	}), nil
+}
+
+func (r *SitemapRepository) GetPublicDocuments(ctx context.Context) ([]*domain.Document, error) {
+	docs, err := r.dao.FindPublicDocuments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(docs, func(doc *dao.Document, _ int) *domain.Document {
+		return &domain.Document{
+			Id:        doc.ID,
+			UpdatedAt: doc.UpdatedAt,
+			Alias:     doc.Alias,
+		}
+	}), nil
+}
+
+func (r *SitemapRepository) GetDocumentContents(ctx context.Context, documentIds []bson.ObjectID) ([]*domain.DocumentContent, error) {
+	if len(documentIds) == 0 {
+		return []*domain.DocumentContent{}, nil
+	}
+	contents, err := r.dao.FindDocumentContents(ctx, documentIds)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(contents, func(content *dao.DocumentContent, _ int) *domain.DocumentContent {
+		return &domain.DocumentContent{
+			Id:         content.ID,
+			UpdatedAt:  content.UpdatedAt,
+			DocumentId: content.DocumentId,
+			Alias:      content.Alias,
+		}
+	}), nil
+}
+
+func (r *SitemapRepository) GetLabels(ctx context.Context) ([]*domain.Label, error) {
+	labels, err := r.dao.FindLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(labels, func(label *dao.Label, _ int) *domain.Label {
+		return &domain.Label{
+			Id:        label.ID,
+			LabelType: label.LabelType,
+			Name:      label.Name,
+		}
+	}), nil
+}
+
+func (r *SitemapRepository) GetPages(ctx context.Context) ([]*domain.Page, error) {
+	configs, err := r.dao.FindConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(configs, func(config *dao.Config, _ int) *domain.Page {
+		return &domain.Page{
+			Type:         config.Type,
+			UpdatedAt:    config.UpdatedAt,
+			RobotsMeta:   config.Content.RobotsMeta,
+			CanonicalURL: config.Content.CanonicalURL,
+		}
+	}), nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/sitemap/internal/domain"
+)
+
+const sitemapXmlns = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type urlSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	Xmlns    string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapURL `xml:"sitemap"`
+}
+
+// renderURLSet 生成单个站点地图文件
+func renderURLSet(urls []*domain.URL) (*domain.File, error) {
+	set := urlSet{Xmlns: sitemapXmlns, URLs: make([]sitemapURL, 0, len(urls))}
+	var modTime time.Time
+	for _, u := range urls {
+		set.URLs = append(set.URLs, sitemapURL{Loc: u.Loc, LastMod: lastModString(u.LastMod)})
+		modTime = latest(modTime, u.LastMod)
+	}
+	body, err := marshalXML(set)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.File{Body: body, ModTime: modTime}, nil
+}
+
+// renderIndex 生成站点地图索引文件
+func renderIndex(sitemaps []*domain.URL) (*domain.File, error) {
+	index := sitemapIndex{Xmlns: sitemapXmlns, Sitemaps: make([]sitemapURL, 0, len(sitemaps))}
+	var modTime time.Time
+	for _, u := range sitemaps {
+		index.Sitemaps = append(index.Sitemaps, sitemapURL{Loc: u.Loc, LastMod: lastModString(u.LastMod)})
+		modTime = latest(modTime, u.LastMod)
+	}
+	body, err := marshalXML(index)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.File{Body: body, ModTime: modTime}, nil
+}
+
+func marshalXML(v any) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+func lastModString(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/codepzj/Stellux-Server/internal/sitemap/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/sitemap/internal/repository"
+	"github.com/samber/lo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const maxSitemapURLs = 50000 // 单个站点地图文件的地址上限, 超出后拆分并生成索引
+
+var ErrSitemapNotFound = errors.New("站点地图不存在")
+
+type ISitemapService interface {
+	GetSitemap(ctx context.Context, fallbackBase string) (*domain.File, error)
+	GetSitemapPart(ctx context.Context, fallbackBase string, n int) (*domain.File, error)
+	GetRobots(ctx context.Context, fallbackBase string) (*domain.File, error)
+	Invalidate()
+}
+
+var _ ISitemapService = (*SitemapService)(nil)
+
+func NewSitemapService(repo repository.ISitemapRepository) *SitemapService {
+	return &SitemapService{
+		repo: repo,
+	}
+}
+
+type SitemapService struct {
+	repo    repository.ISitemapRepository
+	version atomic.Int64 // 内容版本, 每次内容变更加一
+
+	mu    sync.Mutex
+	cache *snapshot
+}
+
+// snapshot 某一内容版本下生成的全部文件
+type snapshot struct {
+	version   int64
+	base      string
+	canonical bool         // 站点地址是否来自seo配置, 否则取自请求地址
+	index     *domain.File // 地址未超出上限时为nil
+	parts     []*domain.File
+	robots    *domain.File
+}
+
+// GetSitemap 返回/sitemap.xml, 地址超出上限时为站点地图索引
+func (s *SitemapService) GetSitemap(ctx context.Context, fallbackBase string) (*domain.File, error) {
+	snap, err := s.load(ctx, fallbackBase)
+	if err != nil {
+		return nil, err
+	}
+	if snap.index != nil {
+		return snap.index, nil
+	}
+	return snap.parts[0], nil
+}
+
+// GetSitemapPart 返回索引中的第n个站点地图, n从1开始
+func (s *SitemapService) GetSitemapPart(ctx context.Context, fallbackBase string, n int) (*domain.File, error) {
+	snap, err := s.load(ctx, fallbackBase)
+	if err != nil {
+		return nil, err
+	}
+	if snap.index == nil || n < 1 || n > len(snap.parts) {
+		return nil, ErrSitemapNotFound
+	}
+	return snap.parts[n-1], nil
+}
+
+// GetRobots 返回/robots.txt
+func (s *SitemapService) GetRobots(ctx context.Context, fallbackBase string) (*domain.File, error) {
+	snap, err := s.load(ctx, fallbackBase)
+	if err != nil {
+		return nil, err
+	}
+	return snap.robots, nil
+}
+
+// Invalidate 内容变更后调用, 下次请求时重新生成
+func (s *SitemapService) Invalidate() {
+	s.version.Add(1)
+}
+
+// load 缓存有效时直接返回, 否则重新生成, 并发请求只生成一次
+func (s *SitemapService) load(ctx context.Context, fallbackBase string) (*snapshot, error) {
+	version := s.version.Load()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c := s.cache; c != nil && c.version == version && (c.canonical || c.base == fallbackBase) {
+		return c, nil
+	}
+
+	snap, err := s.build(ctx, fallbackBase)
+	if err != nil {
+		logger.Error("生成站点地图失败",
+			logger.WithError(err),
+		)
+		return nil, err
+	}
+	// 生成期间内容又发生变更时仍记录旧版本号, 下次请求会再次生成
+	snap.version = version
+	s.cache = snap
+	logger.Info("生成站点地图成功",
+		logger.WithInt("parts", len(snap.parts)),
+	)
+	return snap, nil
+}
+
+func (s *SitemapService) build(ctx context.Context, fallbackBase string) (*snapshot, error) {
+	pages, err := s.repo.GetPages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pageMap := lo.KeyBy(pages, func(page *domain.Page) string { return page.Type })
+
+	snap := &snapshot{base: strings.TrimRight(fallbackBase, "/")}
+	seo := pageMap["seo"]
+	if seo != nil && seo.CanonicalURL != "" {
+		snap.base = strings.TrimRight(seo.CanonicalURL, "/")
+		snap.canonical = true
+	}
+
+	urls, err := s.collectURLs(ctx, snap.base, pageMap)
+	if err != nil {
+		return nil, err
+	}
+	for chunk := range slices.Chunk(urls, maxSitemapURLs) {
+		part, err := renderURLSet(chunk)
+		if err != nil {
+			return nil, err
+		}
+		snap.parts = append(snap.parts, part)
+	}
+	if len(snap.parts) > 1 {
+		snap.index, err = renderIndex(lo.Map(snap.parts, func(part *domain.File, i int) *domain.URL {
+			return &domain.URL{Loc: snap.base + "/sitemaps/" + strconv.Itoa(i+1) + ".xml", LastMod: part.ModTime}
+		}))
+		if err != nil {
+			return nil, err
+		}
+	}
+	snap.robots = renderRobots(snap.base, seo)
+	return snap, nil
+}
+
+// collectURLs 依次收集首页、关于页、文章、分类、标签、文档及文档页面的地址
+func (s *SitemapService) collectURLs(ctx context.Context, base string, pageMap map[string]*domain.Page) ([]*domain.URL, error) {
+	posts, err := s.repo.GetPublishedPosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := s.repo.GetLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := s.repo.GetPublicDocuments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	contents, err := s.repo.GetDocumentContents(ctx, lo.Map(docs, func(doc *domain.Document, _ int) bson.ObjectID {
+		return doc.Id
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	// 首页展示最新文章, 取首页配置和文章中较晚的更新时间
+	var postModTime time.Time
+	labelModTime := map[bson.ObjectID]time.Time{}
+	for _, post := range posts {
+		postModTime = latest(postModTime, post.UpdatedAt)
+		for _, id := range append([]bson.ObjectID{post.CategoryId}, post.TagIds...) {
+			labelModTime[id] = latest(labelModTime[id], post.UpdatedAt)
+		}
+	}
+	home := &domain.URL{Loc: base + "/", LastMod: postModTime}
+	if page, ok := pageMap["home"]; ok {
+		home.LastMod = latest(home.LastMod, page.UpdatedAt)
+	}
+	urls := []*domain.URL{home}
+	if page, ok := pageMap["about"]; ok {
+		urls = append(urls, &domain.URL{Loc: base + "/about", LastMod: page.UpdatedAt})
+	}
+
+	for _, post := range posts {
+		urls = append(urls, &domain.URL{
+			Loc:     base + "/post/" + url.PathEscape(aliasOrId(post.Alias, post.Id)),
+			LastMod: post.UpdatedAt,
+		})
+	}
+
+	// 没有已发布文章的分类和标签页面不收录
+	for _, label := range labels {
+		modTime, ok := labelModTime[label.Id]
+		if !ok {
+			continue
+		}
+		urls = append(urls, &domain.URL{
+			Loc:     base + "/" + label.LabelType + "/" + url.PathEscape(label.Name),
+			LastMod: modTime,
+		})
+	}
+
+	contentMap := lo.GroupBy(contents, func(content *domain.DocumentContent) bson.ObjectID {
+		return content.DocumentId
+	})
+	for _, doc := range docs {
+		docURL := &domain.URL{Loc: base + "/document/" + url.PathEscape(doc.Alias), LastMod: doc.UpdatedAt}
+		urls = append(urls, docURL)
+		for _, content := range contentMap[doc.Id] {
+			docURL.LastMod = latest(docURL.LastMod, content.UpdatedAt)
+			urls = append(urls, &domain.URL{
+				Loc:     docURL.Loc + "/" + url.PathEscape(aliasOrId(content.Alias, content.Id)),
+				LastMod: content.UpdatedAt,
+			})
+		}
+	}
+	return urls, nil
+}
+
+// renderRobots 按seo配置的robots指令生成robots.txt, 指令包含noindex时禁止抓取全站
+func renderRobots(base string, seo *domain.Page) *domain.File {
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	file := &domain.File{}
+	if seo != nil {
+		file.ModTime = seo.UpdatedAt
+	}
+	if seo != nil && strings.Contains(strings.ToLower(seo.RobotsMeta), "noindex") {
+		b.WriteString("Disallow: /\n")
+	} else {
+		b.WriteString("Allow: /\n")
+		b.WriteString("\nSitemap: " + base + "/sitemap.xml\n")
+	}
+	file.Body = []byte(b.String())
+	return file
+}
+
+func aliasOrId(alias string, id bson.ObjectID) string {
+	if alias != "" {
+		return alias
+	}
+	return id.Hex()
+}
@@ -0,0 +1,81 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+	"github.com/codepzj/Stellux-Server/internal/sitemap/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/sitemap/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	xmlContentType  = "application/xml; charset=utf-8"
+	textContentType = "text/plain; charset=utf-8"
+)
+
+func NewSitemapHandler(serv service.ISitemapService) *SitemapHandler {
+	return &SitemapHandler{
+		serv: serv,
+	}
+}
+
+type SitemapHandler struct {
+	serv service.ISitemapService
+}
+
+func (h *SitemapHandler) RegisterGinRoutes(engine *gin.Engine) {
+	engine.GET("/sitemap.xml", h.serve(h.Sitemap, xmlContentType))        // 站点地图, 地址过多时为索引
+	engine.GET("/sitemaps/:file", h.serve(h.SitemapPart, xmlContentType)) // 索引中的分片站点地图, 如/sitemaps/1.xml
+	engine.GET("/robots.txt", h.serve(h.Robots, textContentType))         // robots.txt
+}
+
+// Sitemap 站点地图
+func (h *SitemapHandler) Sitemap(c *gin.Context) (*domain.File, error) {
+	return h.serv.GetSitemap(c, requestBase(c))
+}
+
+// SitemapPart 分片站点地图
+func (h *SitemapHandler) SitemapPart(c *gin.Context) (*domain.File, error) {
+	n, err := strconv.Atoi(strings.TrimSuffix(c.Param("file"), ".xml"))
+	if err != nil {
+		return nil, service.ErrSitemapNotFound
+	}
+	return h.serv.GetSitemapPart(c, requestBase(c), n)
+}
+
+// Robots robots.txt
+func (h *SitemapHandler) Robots(c *gin.Context) (*domain.File, error) {
+	return h.serv.GetRobots(c, requestBase(c))
+}
+
+// serve 搜索引擎直接读取文件内容, 不使用统一响应结构
+func (h *SitemapHandler) serve(load func(c *gin.Context) (*domain.File, error), contentType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		file, err := load(c)
+		if err != nil {
+			if errors.Is(err, service.ErrSitemapNotFound) {
+				c.String(http.StatusNotFound, err.Error())
+				return
+			}
+			c.String(http.StatusInternalServerError, "生成站点地图失败")
+			return
+		}
+		apiwrap.Conditional(c, contentType, file.Body, file.ModTime)
+	}
+}
+
+// requestBase seo配置未设置站点地址时, 使用当前请求的地址
+func requestBase(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host
+}
@@ -0,0 +1,15 @@
+package sitemap
+
+import (
+	"github.com/codepzj/Stellux-Server/internal/sitemap/internal/service"
+	"github.com/codepzj/Stellux-Server/internal/sitemap/internal/web"
+)
+
+type (
+	Handler = web.SitemapHandler
+	Service = service.ISitemapService
+	Module  struct {
+		Svc Service
+		Hdl *Handler
+	}
+)
@@ -0,0 +1,24 @@
+//go:build wireinject
+
+package sitemap
+
+import (
+	"github.com/codepzj/Stellux-Server/internal/sitemap/internal/repository"
+	"github.com/codepzj/Stellux-Server/internal/sitemap/internal/repository/dao"
+	"github.com/codepzj/Stellux-Server/internal/sitemap/internal/service"
+	"github.com/codepzj/Stellux-Server/internal/sitemap/internal/web"
+	"github.com/google/wire"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+var SitemapProviders = wire.NewSet(web.NewSitemapHandler, service.NewSitemapService, repository.NewSitemapRepository, dao.NewSitemapDao,
+	wire.Bind(new(service.ISitemapService), new(*service.SitemapService)),
+	wire.Bind(new(repository.ISitemapRepository), new(*repository.SitemapRepository)),
+	wire.Bind(new(dao.ISitemapDao), new(*dao.SitemapDao)))
+
+func InitSitemapModule(mongoDB *mongo.Database) *Module {
+	panic(wire.Build(
+		SitemapProviders,
+		wire.Struct(new(Module), "Svc", "Hdl"),
+	))
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// 登录审计结果
+const (
+	LoginReasonSuccess            = "success"
+	LoginReasonInvalidCredentials = "invalid_credentials" // 用户名或密码错误
+	LoginReasonThrottled          = "throttled"           // 失败次数过多, 处于退避等待中
+	LoginReasonLocked             = "locked"              // 账号或IP已被临时锁定
+	LoginReasonTwoFactorRequired  = "two_factor_required" // 密码正确, 等待二次验证
+	LoginReasonInvalidTwoFactor   = "invalid_two_factor"  // 二次验证码错误
+)
+
+// 限流对象类型
+const (
+	LockoutTypeUser = "user"
+	LockoutTypeIP   = "ip"
+)
+
+// LoginAudit 登录审计记录, 成功和失败的登录都会记录
+type LoginAudit struct {
+	ID        bson.ObjectID
+	CreatedAt time.Time
+	Username  string
+	UserId    string // 登录成功时的用户Id
+	IP        string
+	UserAgent string
+	Success   bool
+	Reason    string
+}
+
+// LoginAuditQuery 登录审计查询条件, 零值表示不过滤
+type LoginAuditQuery struct {
+	Username string
+	IP       string
+	Success  *bool
+}
+
+// LoginLockout 用户名或IP的连续登录失败记录
+type LoginLockout struct {
+	Key           string
+	Type          string
+	Value         string
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   time.Time // 零值表示未锁定
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// PersonalToken 个人访问令牌, 用于脚本和CI调用后台接口, 权限不超过所属用户的角色权限
+type PersonalToken struct {
+	ID         bson.ObjectID
+	CreatedAt  time.Time
+	UserId     bson.ObjectID
+	Name       string
+	Prefix     string // 令牌明文的前几位, 用于辨认令牌
+	Scopes     []string
+	ExpiresAt  time.Time // 零值表示永不过期
+	LastUsedAt time.Time // 零值表示从未使用
+	LastUsedIP string
+}
+
+// Expired 在now时刻是否已过期
+func (t *PersonalToken) Expired(now time.Time) bool {
+	return !t.ExpiresAt.IsZero() && !t.ExpiresAt.After(now)
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Session 登录会话, 同一会话内轮换的刷新令牌属于同一个令牌族
+type Session struct {
+	ID           bson.ObjectID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time // 最近一次刷新时间
+	ExpiresAt    time.Time // 刷新令牌过期时间
+	RevokedAt    time.Time // 吊销时间, 零值表示未吊销
+	RevokeReason string
+	UserId       bson.ObjectID
+	UserAgent    string
+	IP           string
+}
+
+// Client 发起登录或刷新的客户端信息
+type Client struct {
+	UserAgent string
+	IP        string
+}
+
+// TokenPair 访问令牌和刷新令牌
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration // 访问令牌有效期
+	SessionId    string
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// TwoFactor 用户的TOTP二次验证设置
+type TwoFactor struct {
+	UserId            bson.ObjectID
+	Enabled           bool
+	EnabledAt         time.Time
+	Secret            string // 已启用的TOTP密钥
+	PendingSecret     string // 绑定中、尚未确认的TOTP密钥
+	RecoveryCodesLeft int    // 剩余可用的恢复码数量
+}
+
+// TwoFactorEnrollment 绑定二次验证时返回给用户的信息
+type TwoFactorEnrollment struct {
+	Secret string
+	URI    string // otpauth://totp/...
+	QRCode []byte // PNG格式的二维码
+}
+
+// LoginResult 登录结果, 开启二次验证的用户先拿到临时令牌, 提交验证码后才签发正式令牌
+type LoginResult struct {
+	TokenPair          *TokenPair
+	TwoFactorToken     string
+	TwoFactorExpiresIn time.Duration
+}
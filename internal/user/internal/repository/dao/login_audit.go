@@ -0,0 +1,86 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type LoginAudit struct {
+	ID        bson.ObjectID `bson:"_id,omitempty"`
+	CreatedAt time.Time     `bson:"created_at"`
+	Username  string        `bson:"username"`
+	UserId    string        `bson:"user_id,omitempty"`
+	IP        string        `bson:"ip"`
+	UserAgent string        `bson:"user_agent"`
+	Success   bool          `bson:"success"`
+	Reason    string        `bson:"reason"`
+}
+
+type ILoginAuditDao interface {
+	Create(ctx context.Context, audit *LoginAudit) error
+	FindList(ctx context.Context, filter bson.D, skip int64, limit int64) ([]*LoginAudit, error)
+	Count(ctx context.Context, filter bson.D) (int64, error)
+}
+
+var _ ILoginAuditDao = (*LoginAuditDao)(nil)
+
+func NewLoginAuditDao(db *mongo.Database) *LoginAuditDao {
+	d := &LoginAuditDao{coll: db.Collection("login_audit")}
+	d.ensureIndexes()
+	return d
+}
+
+type LoginAuditDao struct {
+	coll *mongo.Collection
+}
+
+// ensureIndexes 按时间倒序查看, 按用户名或IP过滤
+func (d *LoginAuditDao) ensureIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := d.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "created_at", Value: -1}}, Options: options.Index().SetName("created_at")},
+		{Keys: bson.D{{Key: "username", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("username_created_at")},
+		{Keys: bson.D{{Key: "ip", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("ip_created_at")},
+	})
+	if err != nil {
+		logger.Warn("创建登录审计索引失败",
+			logger.WithError(err),
+		)
+	}
+}
+
+// Create 写入审计记录
+func (d *LoginAuditDao) Create(ctx context.Context, audit *LoginAudit) error {
+	audit.ID = bson.NewObjectID()
+	audit.CreatedAt = time.Now()
+	_, err := d.coll.InsertOne(ctx, audit)
+	return err
+}
+
+// FindList 分页查询审计记录, 按时间倒序
+func (d *LoginAuditDao) FindList(ctx context.Context, filter bson.D, skip int64, limit int64) ([]*LoginAudit, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetSkip(skip).SetLimit(limit)
+	cursor, err := d.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var audits []*LoginAudit
+	if err = cursor.All(ctx, &audits); err != nil {
+		return nil, err
+	}
+	return audits, nil
+}
+
+// Count 统计审计记录数量
+func (d *LoginAuditDao) Count(ctx context.Context, filter bson.D) (int64, error) {
+	return d.coll.CountDocuments(ctx, filter)
+}
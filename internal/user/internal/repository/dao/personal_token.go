@@ -0,0 +1,127 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// lastUsedPrecision 最近使用时间的更新精度, 避免每个请求都写库
+const lastUsedPrecision = time.Minute
+
+type PersonalToken struct {
+	ID         bson.ObjectID `bson:"_id,omitempty"`
+	CreatedAt  time.Time     `bson:"created_at"`
+	UserId     bson.ObjectID `bson:"user_id"`
+	Name       string        `bson:"name"`
+	TokenHash  string        `bson:"token_hash"`
+	Prefix     string        `bson:"prefix"`
+	Scopes     []string      `bson:"scopes"`
+	ExpiresAt  *time.Time    `bson:"expires_at,omitempty"`
+	LastUsedAt *time.Time    `bson:"last_used_at,omitempty"`
+	LastUsedIP string        `bson:"last_used_ip,omitempty"`
+}
+
+type IPersonalTokenDao interface {
+	Create(ctx context.Context, personalToken *PersonalToken) error
+	GetByHash(ctx context.Context, hash string) (*PersonalToken, error)
+	FindByUserID(ctx context.Context, userId bson.ObjectID) ([]*PersonalToken, error)
+	Touch(ctx context.Context, id bson.ObjectID, ip string) error
+	Delete(ctx context.Context, id bson.ObjectID, userId bson.ObjectID) (bool, error)
+	DeleteByUserID(ctx context.Context, userId bson.ObjectID) error
+}
+
+var _ IPersonalTokenDao = (*PersonalTokenDao)(nil)
+
+func NewPersonalTokenDao(db *mongo.Database) *PersonalTokenDao {
+	d := &PersonalTokenDao{coll: db.Collection("personal_token")}
+	d.ensureIndexes()
+	return d
+}
+
+type PersonalTokenDao struct {
+	coll *mongo.Collection
+}
+
+// ensureIndexes 按令牌哈希认证, 按用户列出令牌
+func (d *PersonalTokenDao) ensureIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := d.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetName("token_hash").SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetName("user_id")},
+	})
+	if err != nil {
+		logger.Warn("创建个人访问令牌索引失败",
+			logger.WithError(err),
+		)
+	}
+}
+
+// Create 创建令牌
+func (d *PersonalTokenDao) Create(ctx context.Context, personalToken *PersonalToken) error {
+	personalToken.ID = bson.NewObjectID()
+	personalToken.CreatedAt = time.Now()
+	_, err := d.coll.InsertOne(ctx, personalToken)
+	return err
+}
+
+// GetByHash 根据令牌哈希获取令牌
+func (d *PersonalTokenDao) GetByHash(ctx context.Context, hash string) (*PersonalToken, error) {
+	var personalToken PersonalToken
+	if err := d.coll.FindOne(ctx, bson.M{"token_hash": hash}).Decode(&personalToken); err != nil {
+		return nil, err
+	}
+	return &personalToken, nil
+}
+
+// FindByUserID 获取用户的全部令牌, 按创建时间倒序
+func (d *PersonalTokenDao) FindByUserID(ctx context.Context, userId bson.ObjectID) ([]*PersonalToken, error) {
+	opts := options.Find().SetSort(bson.M{"created_at": -1})
+	cursor, err := d.coll.Find(ctx, bson.M{"user_id": userId}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var personalTokens []*PersonalToken
+	if err = cursor.All(ctx, &personalTokens); err != nil {
+		return nil, err
+	}
+	return personalTokens, nil
+}
+
+// Touch 记录最近使用时间和IP, 距上次记录不足lastUsedPrecision时不更新
+func (d *PersonalTokenDao) Touch(ctx context.Context, id bson.ObjectID, ip string) error {
+	now := time.Now()
+	filter := bson.M{
+		"_id": id,
+		"$or": bson.A{
+			bson.M{"last_used_at": nil},
+			bson.M{"last_used_at": bson.M{"$lt": now.Add(-lastUsedPrecision)}},
+			bson.M{"last_used_ip": bson.M{"$ne": ip}},
+		},
+	}
+	_, err := d.coll.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"last_used_at": now, "last_used_ip": ip}})
+	return err
+}
+
+// Delete 删除用户的指定令牌, 返回令牌是否存在
+func (d *PersonalTokenDao) Delete(ctx context.Context, id bson.ObjectID, userId bson.ObjectID) (bool, error) {
+	res, err := d.coll.DeleteOne(ctx, bson.M{"_id": id, "user_id": userId})
+	if err != nil {
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
+
+// DeleteByUserID 删除用户的全部令牌
+func (d *PersonalTokenDao) DeleteByUserID(ctx context.Context, userId bson.ObjectID) error {
+	_, err := d.coll.DeleteMany(ctx, bson.M{"user_id": userId})
+	return err
+}
@@ -0,0 +1,171 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// maxUsedHashes 每个会话保留的已轮换刷新令牌哈希数量, 用于检测令牌重放
+const maxUsedHashes = 100
+
+type Session struct {
+	ID           bson.ObjectID `bson:"_id,omitempty"`
+	CreatedAt    time.Time     `bson:"created_at"`
+	UpdatedAt    time.Time     `bson:"updated_at"`
+	ExpiresAt    time.Time     `bson:"expires_at"`
+	RevokedAt    *time.Time    `bson:"revoked_at,omitempty"`
+	RevokeReason string        `bson:"revoke_reason,omitempty"`
+	UserId       bson.ObjectID `bson:"user_id"`
+	RefreshHash  string        `bson:"refresh_hash"`
+	UsedHashes   []string      `bson:"used_hashes"`
+	UserAgent    string        `bson:"user_agent"`
+	IP           string        `bson:"ip"`
+}
+
+type ISessionDao interface {
+	Create(ctx context.Context, session *Session) error
+	Rotate(ctx context.Context, oldHash string, newHash string, expiresAt time.Time, userAgent string, ip string) (*Session, error)
+	GetActiveByID(ctx context.Context, id bson.ObjectID) (*Session, error)
+	GetByRefreshHash(ctx context.Context, hash string) (*Session, error)
+	GetByUsedHash(ctx context.Context, hash string) (*Session, error)
+	FindActiveByUserID(ctx context.Context, userId bson.ObjectID) ([]*Session, error)
+	Revoke(ctx context.Context, id bson.ObjectID, reason string) error
+	RevokeByUserID(ctx context.Context, userId bson.ObjectID, reason string) (int64, error)
+}
+
+var _ ISessionDao = (*SessionDao)(nil)
+
+func NewSessionDao(db *mongo.Database) *SessionDao {
+	d := &SessionDao{coll: db.Collection("session")}
+	d.ensureIndexes()
+	return d
+}
+
+type SessionDao struct {
+	coll *mongo.Collection
+}
+
+// ensureIndexes 按令牌哈希和用户查询会话, 刷新令牌过期后由TTL索引自动清理
+func (d *SessionDao) ensureIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := d.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "refresh_hash", Value: 1}}, Options: options.Index().SetName("refresh_hash")},
+		{Keys: bson.D{{Key: "used_hashes", Value: 1}}, Options: options.Index().SetName("used_hashes")},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetName("user_id")},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0)},
+	})
+	if err != nil {
+		logger.Warn("创建会话索引失败",
+			logger.WithError(err),
+		)
+	}
+}
+
+// Create 创建会话
+func (d *SessionDao) Create(ctx context.Context, session *Session) error {
+	session.ID = bson.NewObjectID()
+	session.CreatedAt = time.Now()
+	session.UpdatedAt = session.CreatedAt
+	session.UsedHashes = []string{}
+	_, err := d.coll.InsertOne(ctx, session)
+	return err
+}
+
+// Rotate 原子地把有效会话的刷新令牌从oldHash换成newHash, 旧哈希记入used_hashes
+// 令牌不存在、已轮换、会话已吊销或已过期时返回mongo.ErrNoDocuments
+func (d *SessionDao) Rotate(ctx context.Context, oldHash string, newHash string, expiresAt time.Time, userAgent string, ip string) (*Session, error) {
+	now := time.Now()
+	filter := bson.M{
+		"refresh_hash": oldHash,
+		"revoked_at":   nil,
+		"expires_at":   bson.M{"$gt": now},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"refresh_hash": newHash,
+			"expires_at":   expiresAt,
+			"updated_at":   now,
+			"user_agent":   userAgent,
+			"ip":           ip,
+		},
+		"$push": bson.M{"used_hashes": bson.M{"$each": bson.A{oldHash}, "$slice": -maxUsedHashes}},
+	}
+	var session Session
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if err := d.coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetActiveByID 获取未吊销且未过期的会话
+func (d *SessionDao) GetActiveByID(ctx context.Context, id bson.ObjectID) (*Session, error) {
+	var session Session
+	filter := bson.M{"_id": id, "revoked_at": nil, "expires_at": bson.M{"$gt": time.Now()}}
+	if err := d.coll.FindOne(ctx, filter).Decode(&session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetByRefreshHash 根据当前刷新令牌哈希获取会话
+func (d *SessionDao) GetByRefreshHash(ctx context.Context, hash string) (*Session, error) {
+	var session Session
+	if err := d.coll.FindOne(ctx, bson.M{"refresh_hash": hash}).Decode(&session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetByUsedHash 根据已轮换的刷新令牌哈希获取会话
+func (d *SessionDao) GetByUsedHash(ctx context.Context, hash string) (*Session, error) {
+	var session Session
+	if err := d.coll.FindOne(ctx, bson.M{"used_hashes": hash}).Decode(&session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindActiveByUserID 获取用户未吊销且未过期的会话, 按最近刷新时间倒序
+func (d *SessionDao) FindActiveByUserID(ctx context.Context, userId bson.ObjectID) ([]*Session, error) {
+	filter := bson.M{"user_id": userId, "revoked_at": nil, "expires_at": bson.M{"$gt": time.Now()}}
+	opts := options.Find().SetSort(bson.M{"updated_at": -1}).SetProjection(bson.M{"used_hashes": 0})
+	cursor, err := d.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var sessions []*Session
+	if err = cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// Revoke 吊销会话, 已吊销的会话保留首次吊销的原因
+func (d *SessionDao) Revoke(ctx context.Context, id bson.ObjectID, reason string) error {
+	_, err := d.coll.UpdateOne(ctx, bson.M{"_id": id, "revoked_at": nil}, revokeUpdate(reason))
+	return err
+}
+
+// RevokeByUserID 吊销用户的全部会话
+func (d *SessionDao) RevokeByUserID(ctx context.Context, userId bson.ObjectID, reason string) (int64, error) {
+	res, err := d.coll.UpdateMany(ctx, bson.M{"user_id": userId, "revoked_at": nil}, revokeUpdate(reason))
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+func revokeUpdate(reason string) bson.M {
+	now := time.Now()
+	return bson.M{"$set": bson.M{"revoked_at": now, "revoke_reason": reason, "updated_at": now}}
+}
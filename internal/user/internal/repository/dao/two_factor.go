@@ -0,0 +1,121 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type TwoFactor struct {
+	UserId         bson.ObjectID `bson:"_id"`
+	CreatedAt      time.Time     `bson:"created_at"`
+	UpdatedAt      time.Time     `bson:"updated_at"`
+	Enabled        bool          `bson:"enabled"`
+	EnabledAt      *time.Time    `bson:"enabled_at,omitempty"`
+	Secret         string        `bson:"secret,omitempty"`
+	PendingSecret  string        `bson:"pending_secret,omitempty"`
+	RecoveryHashes []string      `bson:"recovery_hashes"`
+	LastUsedStep   int64         `bson:"last_used_step"` // 最近一次使用的TOTP时间步, 防止验证码重放
+}
+
+type ITwoFactorDao interface {
+	Get(ctx context.Context, userId bson.ObjectID) (*TwoFactor, error)
+	SetPending(ctx context.Context, userId bson.ObjectID, secret string) error
+	Enable(ctx context.Context, userId bson.ObjectID, secret string, recoveryHashes []string, step int64) (bool, error)
+	UseStep(ctx context.Context, userId bson.ObjectID, step int64) (bool, error)
+	UseRecoveryHash(ctx context.Context, userId bson.ObjectID, hash string) (bool, error)
+	SetRecoveryHashes(ctx context.Context, userId bson.ObjectID, recoveryHashes []string) error
+	Delete(ctx context.Context, userId bson.ObjectID) (bool, error)
+}
+
+var _ ITwoFactorDao = (*TwoFactorDao)(nil)
+
+func NewTwoFactorDao(db *mongo.Database) *TwoFactorDao {
+	return &TwoFactorDao{coll: db.Collection("two_factor")}
+}
+
+type TwoFactorDao struct {
+	coll *mongo.Collection
+}
+
+// Get 获取用户的二次验证设置
+func (d *TwoFactorDao) Get(ctx context.Context, userId bson.ObjectID) (*TwoFactor, error) {
+	var twoFactor TwoFactor
+	if err := d.coll.FindOne(ctx, bson.M{"_id": userId}).Decode(&twoFactor); err != nil {
+		return nil, err
+	}
+	return &twoFactor, nil
+}
+
+// SetPending 保存绑定中的密钥, 不影响已启用的设置
+func (d *TwoFactorDao) SetPending(ctx context.Context, userId bson.ObjectID, secret string) error {
+	now := time.Now()
+	update := bson.M{
+		"$set":         bson.M{"pending_secret": secret, "updated_at": now},
+		"$setOnInsert": bson.M{"created_at": now, "enabled": false, "recovery_hashes": []string{}, "last_used_step": 0},
+	}
+	_, err := d.coll.UpdateOne(ctx, bson.M{"_id": userId}, update, options.UpdateOne().SetUpsert(true))
+	return err
+}
+
+// Enable 确认绑定, 只有绑定中的密钥仍为secret时才生效, 返回是否启用成功
+func (d *TwoFactorDao) Enable(ctx context.Context, userId bson.ObjectID, secret string, recoveryHashes []string, step int64) (bool, error) {
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"enabled":         true,
+			"enabled_at":      now,
+			"secret":          secret,
+			"recovery_hashes": recoveryHashes,
+			"last_used_step":  step,
+			"updated_at":      now,
+		},
+		"$unset": bson.M{"pending_secret": ""},
+	}
+	res, err := d.coll.UpdateOne(ctx, bson.M{"_id": userId, "pending_secret": secret}, update)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+// UseStep 原子地记录已使用的时间步, 时间步不大于上次使用的时间步时返回false
+func (d *TwoFactorDao) UseStep(ctx context.Context, userId bson.ObjectID, step int64) (bool, error) {
+	filter := bson.M{"_id": userId, "enabled": true, "last_used_step": bson.M{"$lt": step}}
+	update := bson.M{"$set": bson.M{"last_used_step": step, "updated_at": time.Now()}}
+	res, err := d.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+// UseRecoveryHash 原子地消耗一个恢复码, 恢复码不存在或已使用时返回false
+func (d *TwoFactorDao) UseRecoveryHash(ctx context.Context, userId bson.ObjectID, hash string) (bool, error) {
+	filter := bson.M{"_id": userId, "enabled": true, "recovery_hashes": hash}
+	update := bson.M{"$pull": bson.M{"recovery_hashes": hash}, "$set": bson.M{"updated_at": time.Now()}}
+	res, err := d.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+// SetRecoveryHashes 替换全部恢复码
+func (d *TwoFactorDao) SetRecoveryHashes(ctx context.Context, userId bson.ObjectID, recoveryHashes []string) error {
+	update := bson.M{"$set": bson.M{"recovery_hashes": recoveryHashes, "updated_at": time.Now()}}
+	_, err := d.coll.UpdateOne(ctx, bson.M{"_id": userId, "enabled": true}, update)
+	return err
+}
+
+// Delete 删除二次验证设置, 返回是否存在
+func (d *TwoFactorDao) Delete(ctx context.Context, userId bson.ObjectID) (bool, error) {
+	res, err := d.coll.DeleteOne(ctx, bson.M{"_id": userId})
+	if err != nil {
+		return false, err
+	}
+	return res.DeletedCount > 0, nil
+}
@@ -34,6 +34,7 @@ type IUserDao interface {
 	GetByUsername(ctx context.Context, username string) (*User, error)
 	Update(ctx context.Context, id bson.ObjectID, user *User) error
 	UpdatePassword(ctx context.Context, id bson.ObjectID, password string) error
+	UpdateRole(ctx context.Context, id bson.ObjectID, roleId int) error
 	Delete(ctx context.Context, id bson.ObjectID) error
 	FindByCondition(ctx context.Context, skip, limit int64, sort bson.M) ([]*User, int64, error)
 	GetByID(ctx context.Context, id bson.ObjectID) (*User, error)
@@ -108,6 +109,23 @@ func (d *UserDao) UpdatePassword(ctx context.Context, id bson.ObjectID, password
 	return nil
 }
 
+func (d *UserDao) UpdateRole(ctx context.Context, id bson.ObjectID, roleId int) error {
+	update := bson.M{
+		"$set": bson.M{
+			"role_id":    roleId,
+			"updated_at": time.Now(),
+		},
+	}
+	res, err := d.coll.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("用户不存在")
+	}
+	return nil
+}
+
 func (d *UserDao) Delete(ctx context.Context, id bson.ObjectID) error {
 	res, err := d.coll.DeleteOne(ctx, bson.M{"_id": id})
 	if err != nil {
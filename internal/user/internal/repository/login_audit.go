@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+	"github.com/codepzj/Stellux-Server/internal/user/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/user/internal/repository/dao"
+	"github.com/samber/lo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type ILoginAuditRepository interface {
+	Create(ctx context.Context, audit *domain.LoginAudit) error
+	GetList(ctx context.Context, query *domain.LoginAuditQuery, page *apiwrap.Page) ([]*domain.LoginAudit, int64, error)
+}
+
+var _ ILoginAuditRepository = (*LoginAuditRepository)(nil)
+
+func NewLoginAuditRepository(dao dao.ILoginAuditDao) *LoginAuditRepository {
+	return &LoginAuditRepository{dao: dao}
+}
+
+type LoginAuditRepository struct {
+	dao dao.ILoginAuditDao
+}
+
+func (r *LoginAuditRepository) Create(ctx context.Context, audit *domain.LoginAudit) error {
+	return r.dao.Create(ctx, &dao.LoginAudit{
+		Username:  audit.Username,
+		UserId:    audit.UserId,
+		IP:        audit.IP,
+		UserAgent: audit.UserAgent,
+		Success:   audit.Success,
+		Reason:    audit.Reason,
+	})
+}
+
+func (r *LoginAuditRepository) GetList(ctx context.Context, query *domain.LoginAuditQuery, page *apiwrap.Page) ([]*domain.LoginAudit, int64, error) {
+	filter := bson.D{}
+	if query.Username != "" {
+		filter = append(filter, bson.E{Key: "username", Value: query.Username})
+	}
+	if query.IP != "" {
+		filter = append(filter, bson.E{Key: "ip", Value: query.IP})
+	}
+	if query.Success != nil {
+		filter = append(filter, bson.E{Key: "success", Value: *query.Success})
+	}
+	count, err := r.dao.Count(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	audits, err := r.dao.FindList(ctx, filter, (page.PageNo-1)*page.PageSize, page.PageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	return lo.Map(audits, func(audit *dao.LoginAudit, _ int) *domain.LoginAudit {
+		return &domain.LoginAudit{
+			ID:        audit.ID,
+			CreatedAt: audit.CreatedAt,
+			Username:  audit.Username,
+			UserId:    audit.UserId,
+			IP:        audit.IP,
+			UserAgent: audit.UserAgent,
+			Success:   audit.Success,
+			Reason:    audit.Reason,
+		}
+	}), count, nil
+}
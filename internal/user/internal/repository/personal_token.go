@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/codepzj/Stellux-Server/internal/user/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/user/internal/repository/dao"
+	"github.com/samber/lo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type IPersonalTokenRepository interface {
+	Create(ctx context.Context, personalToken *domain.PersonalToken, tokenHash string) error
+	GetByHash(ctx context.Context, hash string) (*domain.PersonalToken, error)
+	FindByUserID(ctx context.Context, userId string) ([]*domain.PersonalToken, error)
+	Touch(ctx context.Context, id bson.ObjectID, ip string) error
+	Delete(ctx context.Context, id string, userId string) (bool, error)
+	DeleteByUserID(ctx context.Context, userId string) error
+}
+
+var _ IPersonalTokenRepository = (*PersonalTokenRepository)(nil)
+
+func NewPersonalTokenRepository(dao dao.IPersonalTokenDao) *PersonalTokenRepository {
+	return &PersonalTokenRepository{dao: dao}
+}
+
+type PersonalTokenRepository struct {
+	dao dao.IPersonalTokenDao
+}
+
+func (r *PersonalTokenRepository) Create(ctx context.Context, personalToken *domain.PersonalToken, tokenHash string) error {
+	personalTokenDO := &dao.PersonalToken{
+		UserId:    personalToken.UserId,
+		Name:      personalToken.Name,
+		TokenHash: tokenHash,
+		Prefix:    personalToken.Prefix,
+		Scopes:    personalToken.Scopes,
+	}
+	if !personalToken.ExpiresAt.IsZero() {
+		personalTokenDO.ExpiresAt = &personalToken.ExpiresAt
+	}
+	if err := r.dao.Create(ctx, personalTokenDO); err != nil {
+		return err
+	}
+	personalToken.ID = personalTokenDO.ID
+	personalToken.CreatedAt = personalTokenDO.CreatedAt
+	return nil
+}
+
+func (r *PersonalTokenRepository) GetByHash(ctx context.Context, hash string) (*domain.PersonalToken, error) {
+	personalToken, err := r.dao.GetByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return r.PersonalTokenDOToDomain(personalToken), nil
+}
+
+func (r *PersonalTokenRepository) FindByUserID(ctx context.Context, userId string) ([]*domain.PersonalToken, error) {
+	bid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, err
+	}
+	personalTokens, err := r.dao.FindByUserID(ctx, bid)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(personalTokens, func(personalToken *dao.PersonalToken, _ int) *domain.PersonalToken {
+		return r.PersonalTokenDOToDomain(personalToken)
+	}), nil
+}
+
+func (r *PersonalTokenRepository) Touch(ctx context.Context, id bson.ObjectID, ip string) error {
+	return r.dao.Touch(ctx, id, ip)
+}
+
+func (r *PersonalTokenRepository) Delete(ctx context.Context, id string, userId string) (bool, error) {
+	bid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+	uid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return false, err
+	}
+	return r.dao.Delete(ctx, bid, uid)
+}
+
+func (r *PersonalTokenRepository) DeleteByUserID(ctx context.Context, userId string) error {
+	bid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return err
+	}
+	return r.dao.DeleteByUserID(ctx, bid)
+}
+
+func (r *PersonalTokenRepository) PersonalTokenDOToDomain(personalToken *dao.PersonalToken) *domain.PersonalToken {
+	return &domain.PersonalToken{
+		ID:         personalToken.ID,
+		CreatedAt:  personalToken.CreatedAt,
+		UserId:     personalToken.UserId,
+		Name:       personalToken.Name,
+		Prefix:     personalToken.Prefix,
+		Scopes:     personalToken.Scopes,
+		ExpiresAt:  lo.FromPtr(personalToken.ExpiresAt),
+		LastUsedAt: lo.FromPtr(personalToken.LastUsedAt),
+		LastUsedIP: personalToken.LastUsedIP,
+	}
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/user/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/user/internal/repository/dao"
+	"github.com/samber/lo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type ISessionRepository interface {
+	Create(ctx context.Context, session *domain.Session, refreshHash string) error
+	Rotate(ctx context.Context, oldHash string, newHash string, expiresAt time.Time, client *domain.Client) (*domain.Session, error)
+	GetActiveByID(ctx context.Context, id string) (*domain.Session, error)
+	GetByRefreshHash(ctx context.Context, hash string) (*domain.Session, error)
+	GetByUsedHash(ctx context.Context, hash string) (*domain.Session, error)
+	FindActiveByUserID(ctx context.Context, userId string) ([]*domain.Session, error)
+	Revoke(ctx context.Context, id string, reason string) error
+	RevokeByUserID(ctx context.Context, userId string, reason string) (int64, error)
+}
+
+var _ ISessionRepository = (*SessionRepository)(nil)
+
+func NewSessionRepository(dao dao.ISessionDao) *SessionRepository {
+	return &SessionRepository{dao: dao}
+}
+
+type SessionRepository struct {
+	dao dao.ISessionDao
+}
+
+func (r *SessionRepository) Create(ctx context.Context, session *domain.Session, refreshHash string) error {
+	sessionDO := &dao.Session{
+		ExpiresAt:   session.ExpiresAt,
+		UserId:      session.UserId,
+		RefreshHash: refreshHash,
+		UserAgent:   session.UserAgent,
+		IP:          session.IP,
+	}
+	if err := r.dao.Create(ctx, sessionDO); err != nil {
+		return err
+	}
+	session.ID = sessionDO.ID
+	session.CreatedAt = sessionDO.CreatedAt
+	session.UpdatedAt = sessionDO.UpdatedAt
+	return nil
+}
+
+func (r *SessionRepository) Rotate(ctx context.Context, oldHash string, newHash string, expiresAt time.Time, client *domain.Client) (*domain.Session, error) {
+	session, err := r.dao.Rotate(ctx, oldHash, newHash, expiresAt, client.UserAgent, client.IP)
+	if err != nil {
+		return nil, err
+	}
+	return r.SessionDOToDomain(session), nil
+}
+
+func (r *SessionRepository) GetActiveByID(ctx context.Context, id string) (*domain.Session, error) {
+	bid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	session, err := r.dao.GetActiveByID(ctx, bid)
+	if err != nil {
+		return nil, err
+	}
+	return r.SessionDOToDomain(session), nil
+}
+
+func (r *SessionRepository) GetByRefreshHash(ctx context.Context, hash string) (*domain.Session, error) {
+	session, err := r.dao.GetByRefreshHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return r.SessionDOToDomain(session), nil
+}
+
+func (r *SessionRepository) GetByUsedHash(ctx context.Context, hash string) (*domain.Session, error) {
+	session, err := r.dao.GetByUsedHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return r.SessionDOToDomain(session), nil
+}
+
+func (r *SessionRepository) FindActiveByUserID(ctx context.Context, userId string) ([]*domain.Session, error) {
+	bid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := r.dao.FindActiveByUserID(ctx, bid)
+	if err != nil {
+		return nil, err
+	}
+	return lo.Map(sessions, func(session *dao.Session, _ int) *domain.Session {
+		return r.SessionDOToDomain(session)
+	}), nil
+}
+
+func (r *SessionRepository) Revoke(ctx context.Context, id string, reason string) error {
+	bid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	return r.dao.Revoke(ctx, bid, reason)
+}
+
+func (r *SessionRepository) RevokeByUserID(ctx context.Context, userId string, reason string) (int64, error) {
+	bid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return 0, err
+	}
+	return r.dao.RevokeByUserID(ctx, bid, reason)
+}
+
+func (r *SessionRepository) SessionDOToDomain(session *dao.Session) *domain.Session {
+	var revokedAt time.Time
+	if session.RevokedAt != nil {
+		revokedAt = *session.RevokedAt
+	}
+	return &domain.Session{
+		ID:           session.ID,
+		CreatedAt:    session.CreatedAt,
+		UpdatedAt:    session.UpdatedAt,
+		ExpiresAt:    session.ExpiresAt,
+		RevokedAt:    revokedAt,
+		RevokeReason: session.RevokeReason,
+		UserId:       session.UserId,
+		UserAgent:    session.UserAgent,
+		IP:           session.IP,
+	}
+}
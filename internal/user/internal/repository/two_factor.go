@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/codepzj/Stellux-Server/internal/user/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/user/internal/repository/dao"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type ITwoFactorRepository interface {
+	Get(ctx context.Context, userId string) (*domain.TwoFactor, error)
+	SetPending(ctx context.Context, userId string, secret string) error
+	Enable(ctx context.Context, userId string, secret string, recoveryHashes []string, step int64) (bool, error)
+	UseStep(ctx context.Context, userId string, step int64) (bool, error)
+	UseRecoveryHash(ctx context.Context, userId string, hash string) (bool, error)
+	SetRecoveryHashes(ctx context.Context, userId string, recoveryHashes []string) error
+	Delete(ctx context.Context, userId string) (bool, error)
+}
+
+var _ ITwoFactorRepository = (*TwoFactorRepository)(nil)
+
+func NewTwoFactorRepository(dao dao.ITwoFactorDao) *TwoFactorRepository {
+	return &TwoFactorRepository{dao: dao}
+}
+
+type TwoFactorRepository struct {
+	dao dao.ITwoFactorDao
+}
+
+func (r *TwoFactorRepository) Get(ctx context.Context, userId string) (*domain.TwoFactor, error) {
+	bid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, err
+	}
+	twoFactor, err := r.dao.Get(ctx, bid)
+	if err != nil {
+		return nil, err
+	}
+	return r.TwoFactorDOToDomain(twoFactor), nil
+}
+
+func (r *TwoFactorRepository) SetPending(ctx context.Context, userId string, secret string) error {
+	bid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return err
+	}
+	return r.dao.SetPending(ctx, bid, secret)
+}
+
+func (r *TwoFactorRepository) Enable(ctx context.Context, userId string, secret string, recoveryHashes []string, step int64) (bool, error) {
+	bid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return false, err
+	}
+	return r.dao.Enable(ctx, bid, secret, recoveryHashes, step)
+}
+
+func (r *TwoFactorRepository) UseStep(ctx context.Context, userId string, step int64) (bool, error) {
+	bid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return false, err
+	}
+	return r.dao.UseStep(ctx, bid, step)
+}
+
+func (r *TwoFactorRepository) UseRecoveryHash(ctx context.Context, userId string, hash string) (bool, error) {
+	bid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return false, err
+	}
+	return r.dao.UseRecoveryHash(ctx, bid, hash)
+}
+
+func (r *TwoFactorRepository) SetRecoveryHashes(ctx context.Context, userId string, recoveryHashes []string) error {
+	bid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return err
+	}
+	return r.dao.SetRecoveryHashes(ctx, bid, recoveryHashes)
+}
+
+func (r *TwoFactorRepository) Delete(ctx context.Context, userId string) (bool, error) {
+	bid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return false, err
+	}
+	return r.dao.Delete(ctx, bid)
+}
+
+func (r *TwoFactorRepository) TwoFactorDOToDomain(twoFactor *dao.TwoFactor) *domain.TwoFactor {
+	result := &domain.TwoFactor{
+		UserId:            twoFactor.UserId,
+		Enabled:           twoFactor.Enabled,
+		Secret:            twoFactor.Secret,
+		PendingSecret:     twoFactor.PendingSecret,
+		RecoveryCodesLeft: len(twoFactor.RecoveryHashes),
+	}
+	if twoFactor.EnabledAt != nil {
+		result.EnabledAt = *twoFactor.EnabledAt
+	}
+	return result
+}
@@ -15,6 +15,7 @@ type IUserRepository interface {
 	GetByUsername(ctx context.Context, username string) (*domain.User, error)
 	Update(ctx context.Context, user *domain.User) error
 	UpdatePassword(ctx context.Context, id string, password string) error
+	UpdateRole(ctx context.Context, id string, roleId int) error
 	Delete(ctx context.Context, id string) error
 	FindByPage(ctx context.Context, page *apiwrap.Page) ([]*domain.User, int64, error)
 	GetByID(ctx context.Context, id string) (*domain.User, error)
@@ -58,6 +59,14 @@ func (r *UserRepository) UpdatePassword(ctx context.Context, id string, password
 	return r.dao.UpdatePassword(ctx, bid, password)
 }
 
+func (r *UserRepository) UpdateRole(ctx context.Context, id string, roleId int) error {
+	bid, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	return r.dao.UpdateRole(ctx, bid, roleId)
+}
+
 func (r *UserRepository) Delete(ctx context.Context, id string) error {
 	bid, err := bson.ObjectIDFromHex(id)
 	if err != nil {
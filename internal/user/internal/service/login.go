@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+	"github.com/codepzj/Stellux-Server/internal/pkg/limiter"
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/codepzj/Stellux-Server/internal/pkg/token"
+	"github.com/codepzj/Stellux-Server/internal/user/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/user/internal/repository"
+)
+
+const (
+	defaultFreeAttempts  = 3
+	defaultMaxFailures   = 10
+	defaultIPMaxFailures = 50
+	defaultMaxDelay      = time.Minute
+	defaultLockDuration  = 15 * time.Minute
+	loginKeyPrefix       = "login:"
+	twoFactorTokenTTL    = 5 * time.Minute // 二次验证临时令牌有效期
+)
+
+var (
+	ErrInvalidCredentials    = errors.New("用户名或密码错误")
+	ErrLockoutNotFound       = errors.New("锁定记录不存在")
+	ErrInvalidTwoFactorToken = errors.New("二次验证已过期, 请重新登录")
+)
+
+// LoginLimitedError 失败次数过多时拒绝登录, RetryAfter为需要等待的时长
+type LoginLimitedError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LoginLimitedError) Error() string {
+	seconds := int(math.Ceil(e.RetryAfter.Seconds()))
+	if e.Locked {
+		return fmt.Sprintf("登录失败次数过多, 已临时锁定, 请%d秒后再试", seconds)
+	}
+	return fmt.Sprintf("登录尝试过于频繁, 请%d秒后再试", seconds)
+}
+
+type ILoginService interface {
+	Login(ctx context.Context, username string, password string, client *domain.Client) (*domain.LoginResult, error)
+	VerifyTwoFactor(ctx context.Context, twoFactorToken string, code string, client *domain.Client) (*domain.TokenPair, error)
+	GetLockouts(ctx context.Context) ([]*domain.LoginLockout, error)
+	ClearLockout(ctx context.Context, key string) error
+	GetAuditList(ctx context.Context, query *domain.LoginAuditQuery, page *apiwrap.Page) ([]*domain.LoginAudit, int64, error)
+}
+
+var _ ILoginService = (*LoginService)(nil)
+
+// NewLoginService 用户名和IP分别限流, IP的免限制次数与用户名的锁定阈值相同, 避免同一出口下的正常用户互相影响
+func NewLoginService(userServ IUserService, sessionServ ISessionService, twoFactorServ ITwoFactorService, tokenManager *token.Manager, auditRepo repository.ILoginAuditRepository, store limiter.Store, cfg *conf.Config) *LoginService {
+	policy := limiter.Policy{
+		FreeAttempts: defaultFreeAttempts,
+		MaxFailures:  defaultMaxFailures,
+		BaseDelay:    time.Second,
+		MaxDelay:     defaultMaxDelay,
+		LockDuration: defaultLockDuration,
+	}
+	ipMaxFailures := defaultIPMaxFailures
+	if cfg.Login.FreeAttempts > 0 {
+		policy.FreeAttempts = cfg.Login.FreeAttempts
+	}
+	if cfg.Login.MaxFailures > 0 {
+		policy.MaxFailures = cfg.Login.MaxFailures
+	}
+	if cfg.Login.IPMaxFailures > 0 {
+		ipMaxFailures = cfg.Login.IPMaxFailures
+	}
+	if cfg.Login.MaxDelaySeconds > 0 {
+		policy.MaxDelay = time.Duration(cfg.Login.MaxDelaySeconds) * time.Second
+	}
+	if cfg.Login.LockMinutes > 0 {
+		policy.LockDuration = time.Duration(cfg.Login.LockMinutes) * time.Minute
+	}
+	policy.Window = policy.LockDuration
+	ipPolicy := policy
+	ipPolicy.FreeAttempts = policy.MaxFailures
+	ipPolicy.MaxFailures = ipMaxFailures
+
+	return &LoginService{
+		userServ:      userServ,
+		sessionServ:   sessionServ,
+		twoFactorServ: twoFactorServ,
+		tokenManager:  tokenManager,
+		auditRepo:     auditRepo,
+		store:         store,
+		userLimiter:   limiter.New(store, policy),
+		ipLimiter:     limiter.New(store, ipPolicy),
+	}
+}
+
+type LoginService struct {
+	userServ      IUserService
+	sessionServ   ISessionService
+	twoFactorServ ITwoFactorService
+	tokenManager  *token.Manager
+	auditRepo     repository.ILoginAuditRepository
+	store         limiter.Store
+	userLimiter   *limiter.Limiter
+	ipLimiter     *limiter.Limiter
+}
+
+// Login 校验失败次数限制后验证密码, 每次登录都写入审计记录
+// 开启二次验证的用户只返回临时令牌, 未开启的直接创建会话
+// 限流存储不可用时只记录日志并放行, 避免存储故障导致所有人无法登录
+func (s *LoginService) Login(ctx context.Context, username string, password string, client *domain.Client) (*domain.LoginResult, error) {
+	audit := &domain.LoginAudit{Username: username, IP: client.IP, UserAgent: client.UserAgent}
+	userKey, ipKey := lockoutKey(domain.LockoutTypeUser, username), lockoutKey(domain.LockoutTypeIP, client.IP)
+
+	if limitedErr := s.check(ctx, userKey, ipKey); limitedErr != nil {
+		audit.Reason = domain.LoginReasonThrottled
+		if limitedErr.Locked {
+			audit.Reason = domain.LoginReasonLocked
+		}
+		s.audit(ctx, audit)
+		return nil, limitedErr
+	}
+
+	exist, id := s.userServ.CheckUserExist(ctx, &domain.User{Username: username, Password: password})
+	if !exist {
+		s.fail(ctx, s.userLimiter, userKey)
+		s.fail(ctx, s.ipLimiter, ipKey)
+		audit.Reason = domain.LoginReasonInvalidCredentials
+		s.audit(ctx, audit)
+		return nil, ErrInvalidCredentials
+	}
+
+	audit.UserId = id
+	enabled, err := s.twoFactorServ.IsEnabled(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		twoFactorToken, err := s.tokenManager.GenerateTwoFactorToken(id, twoFactorTokenTTL)
+		if err != nil {
+			return nil, err
+		}
+		audit.Reason = domain.LoginReasonTwoFactorRequired
+		s.audit(ctx, audit)
+		return &domain.LoginResult{TwoFactorToken: twoFactorToken, TwoFactorExpiresIn: twoFactorTokenTTL}, nil
+	}
+
+	tokenPair, err := s.succeed(ctx, audit, userKey, client)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.LoginResult{TokenPair: tokenPair}, nil
+}
+
+// VerifyTwoFactor 校验临时令牌和二次验证码后创建会话, 验证码错误与密码错误一样计入失败次数
+func (s *LoginService) VerifyTwoFactor(ctx context.Context, twoFactorToken string, code string, client *domain.Client) (*domain.TokenPair, error) {
+	claims, err := s.tokenManager.ParseTwoFactorToken(twoFactorToken)
+	if err != nil {
+		return nil, ErrInvalidTwoFactorToken
+	}
+	user, err := s.userServ.GetUserInfo(ctx, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	audit := &domain.LoginAudit{Username: user.Username, UserId: claims.ID, IP: client.IP, UserAgent: client.UserAgent}
+	userKey, ipKey := lockoutKey(domain.LockoutTypeUser, user.Username), lockoutKey(domain.LockoutTypeIP, client.IP)
+
+	if limitedErr := s.check(ctx, userKey, ipKey); limitedErr != nil {
+		audit.Reason = domain.LoginReasonThrottled
+		if limitedErr.Locked {
+			audit.Reason = domain.LoginReasonLocked
+		}
+		s.audit(ctx, audit)
+		return nil, limitedErr
+	}
+
+	err = s.twoFactorServ.Verify(ctx, claims.ID, code)
+	if errors.Is(err, ErrInvalidTwoFactorCode) {
+		s.fail(ctx, s.userLimiter, userKey)
+		s.fail(ctx, s.ipLimiter, ipKey)
+		audit.Reason = domain.LoginReasonInvalidTwoFactor
+		s.audit(ctx, audit)
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.succeed(ctx, audit, userKey, client)
+}
+
+// succeed 登录成功, 清空用户名的失败记录并创建会话, IP的失败记录保留到自然过期
+func (s *LoginService) succeed(ctx context.Context, audit *domain.LoginAudit, userKey string, client *domain.Client) (*domain.TokenPair, error) {
+	if err := s.userLimiter.Reset(ctx, userKey); err != nil {
+		logger.Error("清空登录失败记录失败",
+			logger.WithError(err),
+			logger.WithString("key", userKey),
+		)
+	}
+	tokenPair, err := s.sessionServ.CreateSession(ctx, audit.UserId, client)
+	if err != nil {
+		return nil, err
+	}
+	audit.Success = true
+	audit.Reason = domain.LoginReasonSuccess
+	s.audit(ctx, audit)
+	return tokenPair, nil
+}
+
+// GetLockouts 获取仍在统计期内的登录失败记录, 包括已锁定和退避中的用户名与IP
+func (s *LoginService) GetLockouts(ctx context.Context) ([]*domain.LoginLockout, error) {
+	records, err := s.store.List(ctx)
+	if err != nil {
+		logger.Error("查询登录失败记录失败",
+			logger.WithError(err),
+		)
+		return nil, err
+	}
+	lockouts := make([]*domain.LoginLockout, 0, len(records))
+	for _, record := range records {
+		lockoutType, value, ok := parseLockoutKey(record.Key)
+		if !ok {
+			continue
+		}
+		lockouts = append(lockouts, &domain.LoginLockout{
+			Key:           record.Key,
+			Type:          lockoutType,
+			Value:         value,
+			Failures:      record.Failures,
+			LastFailureAt: record.LastFailureAt,
+			LockedUntil:   record.LockedUntil,
+		})
+	}
+	return lockouts, nil
+}
+
+// ClearLockout 解除锁定并清空失败次数
+func (s *LoginService) ClearLockout(ctx context.Context, key string) error {
+	if _, _, ok := parseLockoutKey(key); !ok {
+		return ErrLockoutNotFound
+	}
+	record, err := s.store.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return ErrLockoutNotFound
+	}
+	if err = s.store.Delete(ctx, key); err != nil {
+		logger.Error("解除登录锁定失败",
+			logger.WithError(err),
+			logger.WithString("key", key),
+		)
+		return err
+	}
+	logger.Info("解除登录锁定",
+		logger.WithString("key", key),
+	)
+	return nil
+}
+
+// GetAuditList 分页查询登录审计记录
+func (s *LoginService) GetAuditList(ctx context.Context, query *domain.LoginAuditQuery, page *apiwrap.Page) ([]*domain.LoginAudit, int64, error) {
+	return s.auditRepo.GetList(ctx, query, page)
+}
+
+// check 用户名和IP任一受限即拒绝, 返回需要等待更久的一方
+func (s *LoginService) check(ctx context.Context, userKey string, ipKey string) *LoginLimitedError {
+	var limitedErr *LoginLimitedError
+	for _, item := range []struct {
+		limiter *limiter.Limiter
+		key     string
+	}{{s.userLimiter, userKey}, {s.ipLimiter, ipKey}} {
+		wait, locked, err := item.limiter.Check(ctx, item.key)
+		if err != nil {
+			logger.Error("查询登录失败记录失败",
+				logger.WithError(err),
+				logger.WithString("key", item.key),
+			)
+			continue
+		}
+		if wait > 0 && (limitedErr == nil || wait > limitedErr.RetryAfter) {
+			limitedErr = &LoginLimitedError{RetryAfter: wait, Locked: locked}
+		}
+	}
+	return limitedErr
+}
+
+func (s *LoginService) fail(ctx context.Context, l *limiter.Limiter, key string) {
+	record, err := l.Fail(ctx, key)
+	if err != nil {
+		logger.Error("记录登录失败次数失败",
+			logger.WithError(err),
+			logger.WithString("key", key),
+		)
+		return
+	}
+	if record.Locked(time.Now()) {
+		logger.Warn("登录失败次数过多, 已临时锁定",
+			logger.WithString("key", key),
+			logger.WithInt("failures", record.Failures),
+		)
+	}
+}
+
+func (s *LoginService) audit(ctx context.Context, audit *domain.LoginAudit) {
+	if err := s.auditRepo.Create(ctx, audit); err != nil {
+		logger.Error("写入登录审计失败",
+			logger.WithError(err),
+			logger.WithString("username", audit.Username),
+		)
+	}
+}
+
+func lockoutKey(lockoutType string, value string) string {
+	return loginKeyPrefix + lockoutType + ":" + value
+}
+
+// parseLockoutKey 解析登录限流键, 非登录限流的键返回false
+func parseLockoutKey(key string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(key, loginKeyPrefix)
+	if !ok {
+		return "", "", false
+	}
+	lockoutType, value, ok := strings.Cut(rest, ":")
+	if !ok || (lockoutType != domain.LockoutTypeUser && lockoutType != domain.LockoutTypeIP) {
+		return "", "", false
+	}
+	return lockoutType, value, true
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/codepzj/Stellux-Server/internal/pkg/permission"
+	"github.com/codepzj/Stellux-Server/internal/pkg/token"
+	"github.com/codepzj/Stellux-Server/internal/rbac"
+	"github.com/codepzj/Stellux-Server/internal/user/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/user/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const (
+	maxPersonalTokens      = 50
+	personalTokenPrefixLen = len(token.PersonalTokenPrefix) + 4 // 展示的令牌前缀长度
+)
+
+var (
+	ErrPersonalTokenNotFound  = errors.New("访问令牌不存在")
+	ErrPersonalTokenLimit     = errors.New("访问令牌数量已达上限")
+	ErrPersonalTokenExpiresAt = errors.New("过期时间必须晚于当前时间")
+	ErrPersonalTokenScope     = errors.New("访问令牌的授权范围无效或超出当前用户权限")
+)
+
+type IPersonalTokenService interface {
+	Create(ctx context.Context, personalToken *domain.PersonalToken) (string, error)
+	GetList(ctx context.Context, userId string) ([]*domain.PersonalToken, error)
+	Revoke(ctx context.Context, id string, userId string) error
+	RevokeUserTokens(ctx context.Context, userId string) error
+	VerifyPersonalToken(ctx context.Context, plain string, ip string) (string, []string, bool, error)
+}
+
+var _ IPersonalTokenService = (*PersonalTokenService)(nil)
+
+func NewPersonalTokenService(repo repository.IPersonalTokenRepository, rbacServ rbac.Service) *PersonalTokenService {
+	return &PersonalTokenService{
+		repo:     repo,
+		rbacServ: rbacServ,
+	}
+}
+
+type PersonalTokenService struct {
+	repo     repository.IPersonalTokenRepository
+	rbacServ rbac.Service
+}
+
+// Create 创建访问令牌, 授权范围必须是当前用户拥有的权限, 返回只展示一次的令牌明文
+func (s *PersonalTokenService) Create(ctx context.Context, personalToken *domain.PersonalToken) (string, error) {
+	if personalToken.Expired(time.Now()) {
+		return "", ErrPersonalTokenExpiresAt
+	}
+	userId := personalToken.UserId.Hex()
+	if err := s.checkScopes(ctx, userId, personalToken.Scopes); err != nil {
+		return "", err
+	}
+	personalTokens, err := s.repo.FindByUserID(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+	if len(personalTokens) >= maxPersonalTokens {
+		return "", ErrPersonalTokenLimit
+	}
+
+	plain, hash, err := token.NewPersonalToken()
+	if err != nil {
+		return "", err
+	}
+	personalToken.Prefix = plain[:personalTokenPrefixLen]
+	if err = s.repo.Create(ctx, personalToken, hash); err != nil {
+		logger.Error("创建访问令牌失败",
+			logger.WithError(err),
+			logger.WithString("userId", userId),
+		)
+		return "", err
+	}
+	logger.Info("创建访问令牌",
+		logger.WithString("userId", userId),
+		logger.WithString("tokenId", personalToken.ID.Hex()),
+		logger.WithAny("scopes", personalToken.Scopes),
+	)
+	return plain, nil
+}
+
+// GetList 获取用户的全部访问令牌, 包括已过期的
+func (s *PersonalTokenService) GetList(ctx context.Context, userId string) ([]*domain.PersonalToken, error) {
+	return s.repo.FindByUserID(ctx, userId)
+}
+
+// Revoke 吊销用户自己的访问令牌
+func (s *PersonalTokenService) Revoke(ctx context.Context, id string, userId string) error {
+	deleted, err := s.repo.Delete(ctx, id, userId)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrPersonalTokenNotFound
+	}
+	logger.Info("吊销访问令牌",
+		logger.WithString("userId", userId),
+		logger.WithString("tokenId", id),
+	)
+	return nil
+}
+
+// RevokeUserTokens 吊销用户的全部访问令牌
+func (s *PersonalTokenService) RevokeUserTokens(ctx context.Context, userId string) error {
+	return s.repo.DeleteByUserID(ctx, userId)
+}
+
+// VerifyPersonalToken 校验访问令牌并记录最近使用时间, 令牌不存在或已过期时ok为false
+func (s *PersonalTokenService) VerifyPersonalToken(ctx context.Context, plain string, ip string) (string, []string, bool, error) {
+	personalToken, err := s.repo.GetByHash(ctx, token.HashPersonalToken(plain))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", nil, false, nil
+	}
+	if err != nil {
+		return "", nil, false, err
+	}
+	if personalToken.Expired(time.Now()) {
+		return "", nil, false, nil
+	}
+	if err = s.repo.Touch(ctx, personalToken.ID, ip); err != nil {
+		logger.Warn("更新访问令牌使用时间失败",
+			logger.WithError(err),
+			logger.WithString("tokenId", personalToken.ID.Hex()),
+		)
+	}
+	return personalToken.UserId.Hex(), personalToken.Scopes, true, nil
+}
+
+func (s *PersonalTokenService) checkScopes(ctx context.Context, userId string, scopes []string) error {
+	if len(scopes) == 0 {
+		return ErrPersonalTokenScope
+	}
+	for _, scope := range scopes {
+		if !permission.IsValid(scope) {
+			return ErrPersonalTokenScope
+		}
+		ok, err := s.rbacServ.Authorize(ctx, userId, scope)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrPersonalTokenScope
+		}
+	}
+	return nil
+}
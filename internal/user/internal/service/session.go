@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/codepzj/Stellux-Server/internal/pkg/token"
+	"github.com/codepzj/Stellux-Server/internal/user/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/user/internal/repository"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const (
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 7 * 24 * time.Hour
+)
+
+// 会话吊销原因
+const (
+	RevokeReasonLogout          = "logout"
+	RevokeReasonReuse           = "refresh_token_reuse"
+	RevokeReasonAdmin           = "admin"
+	RevokeReasonPasswordChanged = "password_changed"
+	RevokeReasonUserDeleted     = "user_deleted"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("refresh_token无效或已过期")
+	ErrRefreshTokenReused  = errors.New("refresh_token已被使用, 会话已吊销, 请重新登录")
+)
+
+type ISessionService interface {
+	CreateSession(ctx context.Context, userId string, client *domain.Client) (*domain.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string, client *domain.Client) (*domain.TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
+	RevokeSession(ctx context.Context, sessionId string, reason string) error
+	RevokeUserSessions(ctx context.Context, userId string, reason string) error
+	IsSessionActive(ctx context.Context, sessionId string) (bool, error)
+	GetSession(ctx context.Context, sessionId string) (*domain.Session, error)
+	GetActiveSessions(ctx context.Context, userId string) ([]*domain.Session, error)
+}
+
+var _ ISessionService = (*SessionService)(nil)
+
+func NewSessionService(repo repository.ISessionRepository, tokenManager *token.Manager, cfg *conf.Config) *SessionService {
+	s := &SessionService{
+		repo:            repo,
+		tokenManager:    tokenManager,
+		accessTokenTTL:  defaultAccessTokenTTL,
+		refreshTokenTTL: defaultRefreshTokenTTL,
+	}
+	if cfg.Auth.AccessTokenMinutes > 0 {
+		s.accessTokenTTL = time.Duration(cfg.Auth.AccessTokenMinutes) * time.Minute
+	}
+	if cfg.Auth.RefreshTokenDays > 0 {
+		s.refreshTokenTTL = time.Duration(cfg.Auth.RefreshTokenDays) * 24 * time.Hour
+	}
+	return s
+}
+
+type SessionService struct {
+	repo            repository.ISessionRepository
+	tokenManager    *token.Manager
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+}
+
+// CreateSession 登录成功后创建会话, 签发访问令牌和刷新令牌
+func (s *SessionService) CreateSession(ctx context.Context, userId string, client *domain.Client) (*domain.TokenPair, error) {
+	uid, err := bson.ObjectIDFromHex(userId)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, refreshHash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	session := &domain.Session{
+		ExpiresAt: time.Now().Add(s.refreshTokenTTL),
+		UserId:    uid,
+		UserAgent: client.UserAgent,
+		IP:        client.IP,
+	}
+	if err := s.repo.Create(ctx, session, refreshHash); err != nil {
+		logger.Error("创建会话失败",
+			logger.WithError(err),
+			logger.WithString("userId", userId),
+		)
+		return nil, err
+	}
+
+	logger.Info("创建会话成功",
+		logger.WithString("userId", userId),
+		logger.WithString("sessionId", session.ID.Hex()),
+		logger.WithString("ip", client.IP),
+	)
+	return s.issue(session, refreshToken)
+}
+
+// Refresh 轮换刷新令牌并签发新的访问令牌
+// 已轮换过的刷新令牌再次出现说明令牌可能泄露, 吊销整个会话
+func (s *SessionService) Refresh(ctx context.Context, refreshToken string, client *domain.Client) (*domain.TokenPair, error) {
+	oldHash := hashToken(refreshToken)
+	newToken, newHash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.repo.Rotate(ctx, oldHash, newHash, time.Now().Add(s.refreshTokenTTL), client)
+	if err == nil {
+		return s.issue(session, newToken)
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		logger.Error("轮换刷新令牌失败",
+			logger.WithError(err),
+		)
+		return nil, err
+	}
+
+	reused, err := s.repo.GetByUsedHash(ctx, oldHash)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Revoke(ctx, reused.ID.Hex(), RevokeReasonReuse); err != nil {
+		return nil, err
+	}
+	logger.Warn("检测到刷新令牌重放, 已吊销会话",
+		logger.WithString("userId", reused.UserId.Hex()),
+		logger.WithString("sessionId", reused.ID.Hex()),
+		logger.WithString("ip", client.IP),
+	)
+	return nil, ErrRefreshTokenReused
+}
+
+// Logout 根据刷新令牌吊销会话
+func (s *SessionService) Logout(ctx context.Context, refreshToken string) error {
+	session, err := s.repo.GetByRefreshHash(ctx, hashToken(refreshToken))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return err
+	}
+	return s.RevokeSession(ctx, session.ID.Hex(), RevokeReasonLogout)
+}
+
+// RevokeSession 吊销会话, 会话下的访问令牌随之失效
+func (s *SessionService) RevokeSession(ctx context.Context, sessionId string, reason string) error {
+	if err := s.repo.Revoke(ctx, sessionId, reason); err != nil {
+		logger.Error("吊销会话失败",
+			logger.WithError(err),
+			logger.WithString("sessionId", sessionId),
+		)
+		return err
+	}
+	logger.Info("吊销会话成功",
+		logger.WithString("sessionId", sessionId),
+		logger.WithString("reason", reason),
+	)
+	return nil
+}
+
+// RevokeUserSessions 吊销用户的全部会话
+func (s *SessionService) RevokeUserSessions(ctx context.Context, userId string, reason string) error {
+	count, err := s.repo.RevokeByUserID(ctx, userId, reason)
+	if err != nil {
+		logger.Error("吊销用户会话失败",
+			logger.WithError(err),
+			logger.WithString("userId", userId),
+		)
+		return err
+	}
+	logger.Info("吊销用户会话成功",
+		logger.WithString("userId", userId),
+		logger.WithString("reason", reason),
+		logger.WithInt("count", int(count)),
+	)
+	return nil
+}
+
+// IsSessionActive 判断会话是否未吊销且未过期, 由JWT中间件在每次请求时调用
+func (s *SessionService) IsSessionActive(ctx context.Context, sessionId string) (bool, error) {
+	_, err := s.repo.GetActiveByID(ctx, sessionId)
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, bson.ErrInvalidHex) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetSession 获取有效会话
+func (s *SessionService) GetSession(ctx context.Context, sessionId string) (*domain.Session, error) {
+	return s.repo.GetActiveByID(ctx, sessionId)
+}
+
+// GetActiveSessions 获取用户的有效会话
+func (s *SessionService) GetActiveSessions(ctx context.Context, userId string) ([]*domain.Session, error) {
+	sessions, err := s.repo.FindActiveByUserID(ctx, userId)
+	if err != nil {
+		logger.Error("查询用户会话失败",
+			logger.WithError(err),
+			logger.WithString("userId", userId),
+		)
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (s *SessionService) issue(session *domain.Session, refreshToken string) (*domain.TokenPair, error) {
+	sessionId := session.ID.Hex()
+	accessToken, err := s.tokenManager.GenerateAccessToken(session.UserId.Hex(), sessionId, s.accessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    s.accessTokenTTL,
+		SessionId:    sessionId,
+	}, nil
+}
+
+// newRefreshToken 生成随机刷新令牌, 数据库只保存其哈希
+func newRefreshToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
+	"github.com/codepzj/Stellux-Server/internal/user/internal/domain"
+	"github.com/codepzj/Stellux-Server/internal/user/internal/repository"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const (
+	totpIssuer        = "Stellux"
+	totpPeriod        = 30 // 时间步长(秒)
+	totpSkew          = 1  // 允许前后偏差的时间步数, 容忍客户端时钟误差
+	totpQRCodeSize    = 256
+	recoveryCodeCount = 10
+)
+
+var (
+	ErrTwoFactorEnabled      = errors.New("已开启二次验证, 请先关闭后再重新绑定")
+	ErrTwoFactorNotEnabled   = errors.New("未开启二次验证")
+	ErrTwoFactorNotEnrolling = errors.New("请先获取二次验证绑定信息")
+	ErrInvalidTwoFactorCode  = errors.New("验证码错误或已使用")
+)
+
+type ITwoFactorService interface {
+	GetStatus(ctx context.Context, userId string) (*domain.TwoFactor, error)
+	Enroll(ctx context.Context, userId string) (*domain.TwoFactorEnrollment, error)
+	Enable(ctx context.Context, userId string, code string) ([]string, error)
+	Disable(ctx context.Context, userId string, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userId string, code string) ([]string, error)
+	Reset(ctx context.Context, userId string) error
+	IsEnabled(ctx context.Context, userId string) (bool, error)
+	Verify(ctx context.Context, userId string, code string) error
+}
+
+var _ ITwoFactorService = (*TwoFactorService)(nil)
+
+func NewTwoFactorService(repo repository.ITwoFactorRepository, userServ IUserService) *TwoFactorService {
+	return &TwoFactorService{
+		repo:     repo,
+		userServ: userServ,
+	}
+}
+
+type TwoFactorService struct {
+	repo     repository.ITwoFactorRepository
+	userServ IUserService
+}
+
+// GetStatus 获取二次验证状态, 未设置过时返回未开启
+func (s *TwoFactorService) GetStatus(ctx context.Context, userId string) (*domain.TwoFactor, error) {
+	twoFactor, err := s.repo.Get(ctx, userId)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &domain.TwoFactor{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return twoFactor, nil
+}
+
+// Enroll 生成新的TOTP密钥, 用户用验证器扫码后需调用Enable确认
+func (s *TwoFactorService) Enroll(ctx context.Context, userId string) (*domain.TwoFactorEnrollment, error) {
+	twoFactor, err := s.GetStatus(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor.Enabled {
+		return nil, ErrTwoFactorEnabled
+	}
+	user, err := s.userServ.GetUserInfo(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      totpIssuer,
+		AccountName: user.Username,
+		Period:      totpPeriod,
+		Digits:      otp.DigitsSix,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	img, err := key.Image(totpQRCodeSize, totpQRCodeSize)
+	if err != nil {
+		return nil, err
+	}
+	var qrCode bytes.Buffer
+	if err = png.Encode(&qrCode, img); err != nil {
+		return nil, err
+	}
+	if err = s.repo.SetPending(ctx, userId, key.Secret()); err != nil {
+		logger.Error("保存二次验证密钥失败",
+			logger.WithError(err),
+			logger.WithString("userId", userId),
+		)
+		return nil, err
+	}
+	return &domain.TwoFactorEnrollment{
+		Secret: key.Secret(),
+		URI:    key.URL(),
+		QRCode: qrCode.Bytes(),
+	}, nil
+}
+
+// Enable 校验绑定中密钥生成的验证码并开启二次验证, 返回只展示一次的恢复码
+func (s *TwoFactorService) Enable(ctx context.Context, userId string, code string) ([]string, error) {
+	twoFactor, err := s.GetStatus(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor.Enabled {
+		return nil, ErrTwoFactorEnabled
+	}
+	if twoFactor.PendingSecret == "" {
+		return nil, ErrTwoFactorNotEnrolling
+	}
+	step, ok := matchTOTP(twoFactor.PendingSecret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	ok, err = s.repo.Enable(ctx, userId, twoFactor.PendingSecret, hashes, step)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrTwoFactorNotEnrolling
+	}
+	logger.Info("开启二次验证",
+		logger.WithString("userId", userId),
+	)
+	return codes, nil
+}
+
+// Disable 用户自行关闭二次验证, 需要提供验证码或恢复码
+func (s *TwoFactorService) Disable(ctx context.Context, userId string, code string) error {
+	if err := s.Verify(ctx, userId, code); err != nil {
+		return err
+	}
+	if _, err := s.repo.Delete(ctx, userId); err != nil {
+		return err
+	}
+	logger.Info("关闭二次验证",
+		logger.WithString("userId", userId),
+	)
+	return nil
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码, 旧的恢复码全部作废
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userId string, code string) ([]string, error) {
+	if err := s.Verify(ctx, userId, code); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err = s.repo.SetRecoveryHashes(ctx, userId, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Reset 管理员重置用户的二次验证, 用于用户丢失验证器且没有恢复码的情况
+func (s *TwoFactorService) Reset(ctx context.Context, userId string) error {
+	deleted, err := s.repo.Delete(ctx, userId)
+	if err != nil {
+		logger.Error("重置二次验证失败",
+			logger.WithError(err),
+			logger.WithString("userId", userId),
+		)
+		return err
+	}
+	if !deleted {
+		return ErrTwoFactorNotEnabled
+	}
+	logger.Warn("管理员重置二次验证",
+		logger.WithString("userId", userId),
+	)
+	return nil
+}
+
+// IsEnabled 用户是否已开启二次验证
+func (s *TwoFactorService) IsEnabled(ctx context.Context, userId string) (bool, error) {
+	twoFactor, err := s.GetStatus(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+	return twoFactor.Enabled, nil
+}
+
+// Verify 校验TOTP验证码或恢复码, 同一时间步的验证码和已用过的恢复码不能再次使用
+func (s *TwoFactorService) Verify(ctx context.Context, userId string, code string) error {
+	twoFactor, err := s.GetStatus(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if !twoFactor.Enabled {
+		return ErrTwoFactorNotEnabled
+	}
+	code = strings.TrimSpace(code)
+	var ok bool
+	if step, matched := matchTOTP(twoFactor.Secret, code, time.Now()); matched {
+		ok, err = s.repo.UseStep(ctx, userId, step)
+	} else {
+		ok, err = s.repo.UseRecoveryHash(ctx, userId, hashRecoveryCode(code))
+		if ok {
+			logger.Warn("使用恢复码通过二次验证",
+				logger.WithString("userId", userId),
+				logger.WithInt("recoveryCodesLeft", twoFactor.RecoveryCodesLeft-1),
+			)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// matchTOTP 在允许的时钟偏差内匹配验证码, 返回匹配到的时间步
+func matchTOTP(secret string, code string, now time.Time) (int64, bool) {
+	if len(code) != otp.DigitsSix.Length() {
+		return 0, false
+	}
+	opts := totp.ValidateOpts{Period: totpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+	for i := -totpSkew; i <= totpSkew; i++ {
+		t := now.Add(time.Duration(i*totpPeriod) * time.Second)
+		expected, err := totp.GenerateCodeCustom(secret, t, opts)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return t.Unix() / totpPeriod, true
+		}
+	}
+	return 0, false
+}
+
+// newRecoveryCodes 生成恢复码, 返回明文和对应的哈希, 数据库只保存哈希
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	for range recoveryCodeCount {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(encoding.EncodeToString(b))
+		code = code[:4] + "-" + code[4:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode 忽略大小写和分隔符后计算哈希
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
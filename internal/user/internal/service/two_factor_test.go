@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+func TestMatchTOTP(t *testing.T) {
+	const secret = "JBSWY3DPEHPK3PXP"
+	now := time.Unix(1700000010, 0)
+	code := func(t *testing.T, at time.Time) string {
+		t.Helper()
+		c, err := totp.GenerateCodeCustom(secret, at, totp.ValidateOpts{Period: totpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	step := now.Unix() / totpPeriod
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "当前时间步", secret: secret, code: code(t, now), wantStep: step, wantOK: true},
+		{name: "上一个时间步", secret: secret, code: code(t, now.Add(-totpPeriod*time.Second)), wantStep: step - 1, wantOK: true},
+		{name: "下一个时间步", secret: secret, code: code(t, now.Add(totpPeriod*time.Second)), wantStep: step + 1, wantOK: true},
+		{name: "超出允许偏差", secret: secret, code: code(t, now.Add(-2*totpPeriod*time.Second))},
+		{name: "其他密钥", secret: "GEZDGNBVGY3TQOJQ", code: code(t, now)},
+		{name: "长度不足", secret: secret, code: code(t, now)[:5]},
+		{name: "长度过长", secret: secret, code: code(t, now) + "0"},
+		{name: "空验证码", secret: secret, code: ""},
+		{name: "非法密钥", secret: "not base32!", code: "123456"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, gotOK := matchTOTP(tt.secret, tt.code, now)
+			if gotOK != tt.wantOK || gotStep != tt.wantStep {
+				t.Errorf("matchTOTP() = (%d, %v), want (%d, %v)", gotStep, gotOK, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
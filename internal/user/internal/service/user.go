@@ -5,8 +5,10 @@ import (
 	"errors"
 	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
 
+	"github.com/codepzj/Stellux-Server/internal/audit"
 	"github.com/codepzj/Stellux-Server/internal/pkg/logger"
 	"github.com/codepzj/Stellux-Server/internal/pkg/utils"
+	"github.com/codepzj/Stellux-Server/internal/rbac"
 	"github.com/codepzj/Stellux-Server/internal/user/internal/domain"
 	"github.com/codepzj/Stellux-Server/internal/user/internal/repository"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -17,6 +19,7 @@ type IUserService interface {
 	AdminCreate(ctx context.Context, user *domain.User) error
 	AdminUpdatePassword(ctx context.Context, id string, oldPassword string, newPassword string) error
 	AdminUpdate(ctx context.Context, user *domain.User) error
+	AdminUpdateRole(ctx context.Context, id string, roleId int) error
 	AdminDelete(ctx context.Context, id string) error
 	GetUserList(ctx context.Context, page *apiwrap.Page) ([]*domain.User, int64, error)
 	GetUserInfo(ctx context.Context, id string) (*domain.User, error)
@@ -24,14 +27,22 @@ type IUserService interface {
 
 var _ IUserService = (*UserService)(nil)
 
-func NewUserService(repo repository.IUserRepository) *UserService {
+func NewUserService(repo repository.IUserRepository, sessionServ ISessionService, personalTokenServ IPersonalTokenService, rbacServ rbac.Service, auditServ audit.Service) *UserService {
 	return &UserService{
-		repo: repo,
+		repo:              repo,
+		sessionServ:       sessionServ,
+		personalTokenServ: personalTokenServ,
+		rbacServ:          rbacServ,
+		auditServ:         auditServ,
 	}
 }
 
 type UserService struct {
-	repo repository.IUserRepository
+	repo              repository.IUserRepository
+	sessionServ       ISessionService
+	personalTokenServ IPersonalTokenService
+	rbacServ          rbac.Service
+	auditServ         audit.Service
 }
 
 func (s *UserService) CheckUserExist(ctx context.Context, user *domain.User) (bool, string) {
@@ -68,6 +79,10 @@ func (s *UserService) CheckUserExist(ctx context.Context, user *domain.User) (bo
 
 // 管理员创建用户
 func (s *UserService) AdminCreate(ctx context.Context, user *domain.User) error {
+	if err := s.checkRole(ctx, user.RoleId); err != nil {
+		return err
+	}
+
 	u, err := s.repo.GetByUsername(ctx, user.Username)
 	if err != nil && err != mongo.ErrNoDocuments {
 		logger.Error("查询用户失败",
@@ -102,6 +117,7 @@ func (s *UserService) AdminCreate(ctx context.Context, user *domain.User) error
 		)
 		return err
 	}
+	s.recordAudit(ctx, audit.ActionCreate, id.Hex(), nil)
 
 	logger.Info("创建用户成功",
 		logger.WithString("username", user.Username),
@@ -147,17 +163,20 @@ func (s *UserService) AdminUpdatePassword(ctx context.Context, id string, oldPas
 		)
 		return err
 	}
+	s.recordAudit(ctx, audit.ActionUpdate, id, u)
 
 	logger.Info("更新用户密码成功",
 		logger.WithString("userId", id),
 		logger.WithString("username", u.Username),
 	)
 
-	return nil
+	// 修改密码后已登录的会话全部失效
+	return s.sessionServ.RevokeUserSessions(ctx, id, RevokeReasonPasswordChanged)
 }
 
 // 管理员更新用户
 func (s *UserService) AdminUpdate(ctx context.Context, user *domain.User) error {
+	before, _ := s.repo.GetByID(ctx, user.ID.Hex())
 	err := s.repo.Update(ctx, user)
 	if err != nil {
 		logger.Error("更新用户失败",
@@ -166,6 +185,7 @@ func (s *UserService) AdminUpdate(ctx context.Context, user *domain.User) error
 		)
 		return err
 	}
+	s.recordAudit(ctx, audit.ActionUpdate, user.ID.Hex(), before)
 
 	logger.Info("更新用户成功",
 		logger.WithString("userId", user.ID.Hex()),
@@ -175,8 +195,50 @@ func (s *UserService) AdminUpdate(ctx context.Context, user *domain.User) error
 	return nil
 }
 
+// 管理员修改用户角色
+func (s *UserService) AdminUpdateRole(ctx context.Context, id string, roleId int) error {
+	if err := s.checkRole(ctx, roleId); err != nil {
+		return err
+	}
+
+	before, _ := s.repo.GetByID(ctx, id)
+	err := s.repo.UpdateRole(ctx, id, roleId)
+	if err != nil {
+		logger.Error("修改用户角色失败",
+			logger.WithError(err),
+			logger.WithString("userId", id),
+			logger.WithInt("roleId", roleId),
+		)
+		return err
+	}
+	s.recordAudit(ctx, audit.ActionUpdate, id, before)
+
+	logger.Info("修改用户角色成功",
+		logger.WithString("userId", id),
+		logger.WithInt("roleId", roleId),
+	)
+
+	return nil
+}
+
+// checkRole 校验角色是否存在
+func (s *UserService) checkRole(ctx context.Context, roleId int) error {
+	exist, err := s.rbacServ.ExistRole(ctx, roleId)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		logger.Warn("角色不存在",
+			logger.WithInt("roleId", roleId),
+		)
+		return errors.New("角色不存在")
+	}
+	return nil
+}
+
 // 管理员删除用户
 func (s *UserService) AdminDelete(ctx context.Context, id string) error {
+	before, _ := s.repo.GetByID(ctx, id)
 	err := s.repo.Delete(ctx, id)
 	if err != nil {
 		logger.Error("删除用户失败",
@@ -185,12 +247,16 @@ func (s *UserService) AdminDelete(ctx context.Context, id string) error {
 		)
 		return err
 	}
+	s.recordAudit(ctx, audit.ActionDelete, id, before)
 
 	logger.Info("删除用户成功",
 		logger.WithString("userId", id),
 	)
 
-	return nil
+	if err = s.personalTokenServ.RevokeUserTokens(ctx, id); err != nil {
+		return err
+	}
+	return s.sessionServ.RevokeUserSessions(ctx, id, RevokeReasonUserDeleted)
 }
 
 // 获取用户列表
@@ -218,3 +284,12 @@ func (s *UserService) GetUserInfo(ctx context.Context, id string) (*domain.User,
 	}
 	return user, nil
 }
+
+// recordAudit 记录用户修改审计日志, 密码等敏感字段由审计服务脱敏
+func (s *UserService) recordAudit(ctx context.Context, action string, id string, before *domain.User) {
+	var after *domain.User
+	if action != audit.ActionDelete {
+		after, _ = s.repo.GetByID(ctx, id)
+	}
+	s.auditServ.Record(ctx, action, audit.EntityUser, id, before, after)
+}
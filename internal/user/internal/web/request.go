@@ -1,5 +1,11 @@
 package web
 
+import (
+	"time"
+
+	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
+)
+
 type LoginRequest struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
@@ -26,3 +32,50 @@ type UpdatePasswordRequest struct {
 	OldPassword string `json:"old_password" binding:"required"`
 	NewPassword string `json:"new_password" binding:"required"`
 }
+
+type UpdateRoleRequest struct {
+	ID     string `json:"id" binding:"required"`
+	RoleId *int   `json:"role_id" binding:"required,min=0"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type UserIdRequest struct {
+	Id string `uri:"id" binding:"required"`
+}
+
+type SessionIdRequest struct {
+	SessionId string `uri:"session_id" binding:"required"`
+}
+
+type LockoutKeyRequest struct {
+	Key string `form:"key" binding:"required"`
+}
+
+type LoginAuditListRequest struct {
+	apiwrap.Page
+	Username string `form:"username"`
+	IP       string `form:"ip"`
+	Success  *bool  `form:"success"`
+}
+
+type TwoFactorVerifyRequest struct {
+	TwoFactorToken string `json:"two_factor_token" binding:"required"`
+	Code           string `json:"code" binding:"required"` // TOTP验证码或恢复码
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"` // TOTP验证码或恢复码
+}
+
+type CreatePersonalTokenRequest struct {
+	Name      string     `json:"name" binding:"required,max=64"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"` // 权限码, 不能超出当前用户的权限
+	ExpiresAt *time.Time `json:"expires_at"`                      // 为空时永不过期
+}
+
+type PersonalTokenIdRequest struct {
+	Id string `uri:"id" binding:"required"`
+}
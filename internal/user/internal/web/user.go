@@ -1,59 +1,129 @@
 package web
 
 import (
+	"errors"
+	"math"
+	"strconv"
+
 	"github.com/codepzj/Stellux-Server/internal/pkg/apiwrap"
 	"github.com/codepzj/Stellux-Server/internal/pkg/middleware"
-	"github.com/codepzj/Stellux-Server/internal/pkg/utils"
+	"github.com/codepzj/Stellux-Server/internal/pkg/permission"
 	"github.com/codepzj/Stellux-Server/internal/user/internal/domain"
 	"github.com/codepzj/Stellux-Server/internal/user/internal/service"
 	"github.com/gin-gonic/gin"
+	"github.com/samber/lo"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
-func NewUserHandler(serv service.IUserService) *UserHandler {
+func NewUserHandler(serv service.IUserService, sessionServ service.ISessionService, loginServ service.ILoginService, twoFactorServ service.ITwoFactorService, personalTokenServ service.IPersonalTokenService) *UserHandler {
 	return &UserHandler{
-		serv: serv,
+		serv:              serv,
+		sessionServ:       sessionServ,
+		loginServ:         loginServ,
+		twoFactorServ:     twoFactorServ,
+		personalTokenServ: personalTokenServ,
 	}
 }
 
 type UserHandler struct {
-	serv service.IUserService
+	serv              service.IUserService
+	sessionServ       service.ISessionService
+	loginServ         service.ILoginService
+	twoFactorServ     service.ITwoFactorService
+	personalTokenServ service.IPersonalTokenService
 }
 
 func (h *UserHandler) RegisterGinRoutes(engine *gin.Engine) {
 	userGroup := engine.Group("/user")
 	{
 		userGroup.POST("/login", apiwrap.WrapWithJson(h.Login))
+		userGroup.POST("/2fa/verify", apiwrap.WrapWithJson(h.VerifyTwoFactor)) // 提交二次验证码完成登录
+		userGroup.POST("/refresh", apiwrap.WrapWithJson(h.Refresh))            // 轮换刷新令牌, 签发新的访问令牌
+		userGroup.POST("/logout", apiwrap.WrapWithJson(h.Logout))              // 退出登录, 吊销刷新令牌所属会话
 	}
 	adminGroup := engine.Group("/admin-api/user")
 	{
-		adminGroup.Use(middleware.JWT())
-		adminGroup.POST("/create", apiwrap.WrapWithJson(h.AdminCreateUser))
-		adminGroup.PUT("/update", apiwrap.WrapWithJson(h.AdminUpdateUser))
-		adminGroup.PUT("/update-password", apiwrap.WrapWithJson(h.AdminUpdatePassword))
-		adminGroup.DELETE("/delete/:id", apiwrap.Wrap(h.AdminDeleteUser))
-		adminGroup.GET("/list", apiwrap.WrapWithQuery(h.AdminGetUserList))
+		adminGroup.POST("/create", middleware.RequirePermission(permission.UserWrite), apiwrap.WrapWithJson(h.AdminCreateUser))
+		adminGroup.PUT("/update", middleware.RequirePermission(permission.UserWrite), apiwrap.WrapWithJson(h.AdminUpdateUser))
+		adminGroup.PUT("/update-password", middleware.RequireSession(), apiwrap.WrapWithJson(h.AdminUpdatePassword))
+		adminGroup.PUT("/update-role", middleware.RequirePermission(permission.UserWrite), apiwrap.WrapWithJson(h.AdminUpdateRole))
+		adminGroup.DELETE("/delete/:id", middleware.RequirePermission(permission.UserWrite), apiwrap.Wrap(h.AdminDeleteUser))
+		adminGroup.GET("/list", middleware.RequirePermission(permission.UserRead), apiwrap.WrapWithQuery(h.AdminGetUserList))
 		adminGroup.GET("/info", apiwrap.Wrap(h.AdminGetUserInfo))
+		adminGroup.GET("/sessions/:id", middleware.RequireSession(), apiwrap.WrapWithUri(h.AdminGetUserSessions))                           // 用户的有效会话
+		adminGroup.DELETE("/sessions/:id", middleware.RequireSession(), apiwrap.WrapWithUri(h.AdminRevokeUserSessions))                     // 吊销用户的全部会话
+		adminGroup.DELETE("/session/:session_id", middleware.RequireSession(), apiwrap.WrapWithUri(h.AdminRevokeSession))                   // 吊销单个会话
+		adminGroup.GET("/lockouts", middleware.RequirePermission(permission.UserRead), apiwrap.Wrap(h.AdminGetLockouts))                    // 登录失败记录和锁定
+		adminGroup.DELETE("/lockout", middleware.RequirePermission(permission.UserWrite), apiwrap.WrapWithQuery(h.AdminClearLockout))       // 解除锁定
+		adminGroup.GET("/2fa", middleware.RequireSession(), apiwrap.Wrap(h.AdminGetTwoFactorStatus))                                        // 当前用户的二次验证状态
+		adminGroup.POST("/2fa/enroll", middleware.RequireSession(), apiwrap.Wrap(h.AdminEnrollTwoFactor))                                   // 获取绑定二维码
+		adminGroup.POST("/2fa/enable", middleware.RequireSession(), apiwrap.WrapWithJson(h.AdminEnableTwoFactor))                           // 确认绑定并开启
+		adminGroup.POST("/2fa/disable", middleware.RequireSession(), apiwrap.WrapWithJson(h.AdminDisableTwoFactor))                         // 关闭二次验证
+		adminGroup.POST("/2fa/recovery-codes", middleware.RequireSession(), apiwrap.WrapWithJson(h.AdminRegenerateRecoveryCodes))           // 重新生成恢复码
+		adminGroup.DELETE("/2fa/:id", middleware.RequirePermission(permission.UserWrite), apiwrap.WrapWithUri(h.AdminResetTwoFactor))       // 重置用户的二次验证
+		adminGroup.GET("/tokens", middleware.RequireSession(), apiwrap.Wrap(h.AdminGetPersonalTokenList))                                   // 当前用户的个人访问令牌
+		adminGroup.POST("/tokens", middleware.RequireSession(), apiwrap.WrapWithJson(h.AdminCreatePersonalToken))                           // 创建个人访问令牌
+		adminGroup.DELETE("/tokens/:id", middleware.RequireSession(), apiwrap.WrapWithUri(h.AdminRevokePersonalToken))                      // 吊销个人访问令牌
+		adminGroup.GET("/login-audits", middleware.RequirePermission(permission.UserRead), apiwrap.WrapWithQuery(h.AdminGetLoginAuditList)) // 登录审计
 	}
 }
 
 func (h *UserHandler) Login(c *gin.Context, userRequest LoginRequest) (int, string, any) {
-	user := domain.User{
-		Username: userRequest.Username,
-		Password: userRequest.Password,
+	result, err := h.loginServ.Login(c, userRequest.Username, userRequest.Password, clientOf(c))
+	if code, msg := loginError(c, err); code != 200 {
+		return code, msg, nil
+	}
+	if result.TokenPair == nil {
+		return 200, "请输入二次验证码", h.LoginResultToVO(result)
+	}
+	return 200, "登录成功", h.LoginResultToVO(result)
+}
+
+func (h *UserHandler) VerifyTwoFactor(c *gin.Context, verifyRequest TwoFactorVerifyRequest) (int, string, any) {
+	tokenPair, err := h.loginServ.VerifyTwoFactor(c, verifyRequest.TwoFactorToken, verifyRequest.Code, clientOf(c))
+	if code, msg := loginError(c, err); code != 200 {
+		return code, msg, nil
+	}
+	return 200, "登录成功", h.TokenPairToLoginVO(tokenPair)
+}
+
+// loginError 登录失败的错误码, 失败次数过多时返回429并设置Retry-After
+func loginError(c *gin.Context, err error) (int, string) {
+	if err == nil {
+		return 200, ""
 	}
-	exist, id := h.serv.CheckUserExist(c, &user)
-	if !exist {
-		return 500, "用户名或密码错误", nil
+	var limitedErr *service.LoginLimitedError
+	if errors.As(err, &limitedErr) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(limitedErr.RetryAfter.Seconds()))))
+		return 429, err.Error()
+	}
+	if errors.Is(err, service.ErrInvalidTwoFactorToken) || errors.Is(err, service.ErrInvalidTwoFactorCode) || errors.Is(err, service.ErrTwoFactorNotEnabled) {
+		return 401, err.Error()
+	}
+	return 500, err.Error()
+}
+
+func (h *UserHandler) Refresh(c *gin.Context, refreshRequest RefreshRequest) (int, string, any) {
+	tokenPair, err := h.sessionServ.Refresh(c, refreshRequest.RefreshToken, clientOf(c))
+	if errors.Is(err, service.ErrInvalidRefreshToken) || errors.Is(err, service.ErrRefreshTokenReused) {
+		return 401, err.Error(), nil
 	}
-	accessToken, err := utils.GenerateAccessToken(id)
 	if err != nil {
 		return 500, err.Error(), nil
 	}
-	loginVO := LoginVO{
-		AccessToken: accessToken,
+	return 200, "刷新令牌成功", h.TokenPairToLoginVO(tokenPair)
+}
+
+func (h *UserHandler) Logout(c *gin.Context, refreshRequest RefreshRequest) (int, string, any) {
+	err := h.sessionServ.Logout(c, refreshRequest.RefreshToken)
+	if errors.Is(err, service.ErrInvalidRefreshToken) {
+		return 401, err.Error(), nil
 	}
-	return 200, "登录成功", loginVO
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "退出登录成功", nil
 }
 
 func (h *UserHandler) AdminCreateUser(c *gin.Context, createUserRequest CreateUserRequest) (int, string, any) {
@@ -91,6 +161,10 @@ func (h *UserHandler) AdminUpdateUser(c *gin.Context, updateUserRequest UpdateUs
 }
 
 func (h *UserHandler) AdminUpdatePassword(c *gin.Context, updatePasswordRequest UpdatePasswordRequest) (int, string, any) {
+	// 修改他人密码需要用户管理权限
+	if code, msg := h.checkSelfOr(c, updatePasswordRequest.ID, permission.UserWrite); code != 200 {
+		return code, msg, nil
+	}
 	err := h.serv.AdminUpdatePassword(c, updatePasswordRequest.ID, updatePasswordRequest.OldPassword, updatePasswordRequest.NewPassword)
 	if err != nil {
 		return 500, err.Error(), nil
@@ -98,8 +172,22 @@ func (h *UserHandler) AdminUpdatePassword(c *gin.Context, updatePasswordRequest
 	return 200, "更新密码成功", nil
 }
 
+func (h *UserHandler) AdminUpdateRole(c *gin.Context, updateRoleRequest UpdateRoleRequest) (int, string, any) {
+	if updateRoleRequest.ID == c.GetString("userId") {
+		return 400, "不能修改当前登录用户的角色", nil
+	}
+	err := h.serv.AdminUpdateRole(c, updateRoleRequest.ID, *updateRoleRequest.RoleId)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "修改用户角色成功", nil
+}
+
 func (h *UserHandler) AdminDeleteUser(c *gin.Context) (int, string, any) {
 	id := c.Param("id")
+	if id == c.GetString("userId") {
+		return 400, "不能删除当前登录用户", nil
+	}
 	err := h.serv.AdminDelete(c, id)
 	if err != nil {
 		return 500, err.Error(), nil
@@ -126,3 +214,206 @@ func (h *UserHandler) AdminGetUserInfo(c *gin.Context) (int, string, any) {
 	}
 	return 200, "获取用户信息成功", h.UserDomainToVO(user)
 }
+
+func (h *UserHandler) AdminGetUserSessions(c *gin.Context, userIdRequest UserIdRequest) (int, string, any) {
+	if code, msg := h.checkSelfOr(c, userIdRequest.Id, permission.UserRead); code != 200 {
+		return code, msg, nil
+	}
+	sessions, err := h.sessionServ.GetActiveSessions(c, userIdRequest.Id)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "获取用户会话成功", h.SessionDomainToVOList(sessions, c.GetString("sessionId"))
+}
+
+func (h *UserHandler) AdminRevokeUserSessions(c *gin.Context, userIdRequest UserIdRequest) (int, string, any) {
+	if code, msg := h.checkSelfOr(c, userIdRequest.Id, permission.UserWrite); code != 200 {
+		return code, msg, nil
+	}
+	err := h.sessionServ.RevokeUserSessions(c, userIdRequest.Id, service.RevokeReasonAdmin)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "吊销用户会话成功", nil
+}
+
+func (h *UserHandler) AdminRevokeSession(c *gin.Context, sessionIdRequest SessionIdRequest) (int, string, any) {
+	session, err := h.sessionServ.GetSession(c, sessionIdRequest.SessionId)
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, bson.ErrInvalidHex) {
+		return 404, "会话不存在或已失效", nil
+	}
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	if code, msg := h.checkSelfOr(c, session.UserId.Hex(), permission.UserWrite); code != 200 {
+		return code, msg, nil
+	}
+	err = h.sessionServ.RevokeSession(c, sessionIdRequest.SessionId, service.RevokeReasonAdmin)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "吊销会话成功", nil
+}
+
+func (h *UserHandler) AdminGetLockouts(c *gin.Context) (int, string, any) {
+	lockouts, err := h.loginServ.GetLockouts(c)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "获取登录锁定列表成功", h.LoginLockoutDomainToVOList(lockouts)
+}
+
+func (h *UserHandler) AdminClearLockout(c *gin.Context, lockoutKeyRequest LockoutKeyRequest) (int, string, any) {
+	err := h.loginServ.ClearLockout(c, lockoutKeyRequest.Key)
+	if errors.Is(err, service.ErrLockoutNotFound) {
+		return 404, err.Error(), nil
+	}
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "解除登录锁定成功", nil
+}
+
+func (h *UserHandler) AdminGetLoginAuditList(c *gin.Context, auditListRequest LoginAuditListRequest) (int, string, any) {
+	query := &domain.LoginAuditQuery{
+		Username: auditListRequest.Username,
+		IP:       auditListRequest.IP,
+		Success:  auditListRequest.Success,
+	}
+	audits, count, err := h.loginServ.GetAuditList(c, query, &auditListRequest.Page)
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "获取登录审计成功", apiwrap.ToPageVO(auditListRequest.PageNo, auditListRequest.PageSize, count, h.LoginAuditDomainToVOList(audits))
+}
+
+func (h *UserHandler) AdminGetTwoFactorStatus(c *gin.Context) (int, string, any) {
+	twoFactor, err := h.twoFactorServ.GetStatus(c, c.GetString("userId"))
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "获取二次验证状态成功", h.TwoFactorDomainToStatusVO(twoFactor)
+}
+
+func (h *UserHandler) AdminEnrollTwoFactor(c *gin.Context) (int, string, any) {
+	enrollment, err := h.twoFactorServ.Enroll(c, c.GetString("userId"))
+	if errors.Is(err, service.ErrTwoFactorEnabled) {
+		return 400, err.Error(), nil
+	}
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "请使用验证器扫描二维码", h.TwoFactorEnrollmentToVO(enrollment)
+}
+
+func (h *UserHandler) AdminEnableTwoFactor(c *gin.Context, codeRequest TwoFactorCodeRequest) (int, string, any) {
+	codes, err := h.twoFactorServ.Enable(c, c.GetString("userId"), codeRequest.Code)
+	if errors.Is(err, service.ErrTwoFactorEnabled) || errors.Is(err, service.ErrTwoFactorNotEnrolling) || errors.Is(err, service.ErrInvalidTwoFactorCode) {
+		return 400, err.Error(), nil
+	}
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "开启二次验证成功", &RecoveryCodesVO{RecoveryCodes: codes}
+}
+
+func (h *UserHandler) AdminDisableTwoFactor(c *gin.Context, codeRequest TwoFactorCodeRequest) (int, string, any) {
+	err := h.twoFactorServ.Disable(c, c.GetString("userId"), codeRequest.Code)
+	if errors.Is(err, service.ErrTwoFactorNotEnabled) || errors.Is(err, service.ErrInvalidTwoFactorCode) {
+		return 400, err.Error(), nil
+	}
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "关闭二次验证成功", nil
+}
+
+func (h *UserHandler) AdminRegenerateRecoveryCodes(c *gin.Context, codeRequest TwoFactorCodeRequest) (int, string, any) {
+	codes, err := h.twoFactorServ.RegenerateRecoveryCodes(c, c.GetString("userId"), codeRequest.Code)
+	if errors.Is(err, service.ErrTwoFactorNotEnabled) || errors.Is(err, service.ErrInvalidTwoFactorCode) {
+		return 400, err.Error(), nil
+	}
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "重新生成恢复码成功", &RecoveryCodesVO{RecoveryCodes: codes}
+}
+
+func (h *UserHandler) AdminResetTwoFactor(c *gin.Context, userIdRequest UserIdRequest) (int, string, any) {
+	err := h.twoFactorServ.Reset(c, userIdRequest.Id)
+	if errors.Is(err, bson.ErrInvalidHex) {
+		return 400, "id格式错误", nil
+	}
+	if errors.Is(err, service.ErrTwoFactorNotEnabled) {
+		return 404, err.Error(), nil
+	}
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "重置二次验证成功", nil
+}
+
+func (h *UserHandler) AdminGetPersonalTokenList(c *gin.Context) (int, string, any) {
+	personalTokens, err := h.personalTokenServ.GetList(c, c.GetString("userId"))
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "获取访问令牌列表成功", h.PersonalTokenDomainToVOList(personalTokens)
+}
+
+func (h *UserHandler) AdminCreatePersonalToken(c *gin.Context, createRequest CreatePersonalTokenRequest) (int, string, any) {
+	userId, err := bson.ObjectIDFromHex(c.GetString("userId"))
+	if err != nil {
+		return 400, "id格式错误", nil
+	}
+	personalToken := &domain.PersonalToken{
+		UserId:    userId,
+		Name:      createRequest.Name,
+		Scopes:    lo.Uniq(createRequest.Scopes),
+		ExpiresAt: lo.FromPtr(createRequest.ExpiresAt),
+	}
+	plain, err := h.personalTokenServ.Create(c, personalToken)
+	if errors.Is(err, service.ErrPersonalTokenScope) || errors.Is(err, service.ErrPersonalTokenExpiresAt) || errors.Is(err, service.ErrPersonalTokenLimit) {
+		return 400, err.Error(), nil
+	}
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "创建访问令牌成功", &CreatedPersonalTokenVO{PersonalTokenVO: h.PersonalTokenDomainToVO(personalToken), Token: plain}
+}
+
+func (h *UserHandler) AdminRevokePersonalToken(c *gin.Context, tokenIdRequest PersonalTokenIdRequest) (int, string, any) {
+	err := h.personalTokenServ.Revoke(c, tokenIdRequest.Id, c.GetString("userId"))
+	if errors.Is(err, bson.ErrInvalidHex) {
+		return 400, "id格式错误", nil
+	}
+	if errors.Is(err, service.ErrPersonalTokenNotFound) {
+		return 404, err.Error(), nil
+	}
+	if err != nil {
+		return 500, err.Error(), nil
+	}
+	return 200, "吊销访问令牌成功", nil
+}
+
+// checkSelfOr 操作自己的账号无需额外权限, 操作他人账号需要指定权限
+func (h *UserHandler) checkSelfOr(c *gin.Context, userId string, perm string) (int, string) {
+	if userId == c.GetString("userId") {
+		return 200, ""
+	}
+	ok, err := middleware.HasPermission(c, perm)
+	if err != nil {
+		return 500, err.Error()
+	}
+	if !ok {
+		return 403, "没有权限: " + perm
+	}
+	return 200, ""
+}
+
+func clientOf(c *gin.Context) *domain.Client {
+	return &domain.Client{
+		UserAgent: c.Request.UserAgent(),
+		IP:        c.ClientIP(),
+	}
+}
@@ -1,6 +1,9 @@
 package web
 
 import (
+	"encoding/base64"
+	"time"
+
 	"github.com/codepzj/Stellux-Server/internal/user/internal/domain"
 	"github.com/samber/lo"
 )
@@ -15,7 +18,42 @@ type UserVO struct {
 }
 
 type LoginVO struct {
-	AccessToken string `json:"access_token"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"` // 访问令牌有效期(秒)
+}
+
+// TwoFactorChallengeVO 开启二次验证的用户登录时返回, 凭临时令牌提交验证码换取正式令牌
+type TwoFactorChallengeVO struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	TwoFactorToken    string `json:"two_factor_token"`
+	ExpiresIn         int    `json:"expires_in"` // 临时令牌有效期(秒)
+}
+
+type TwoFactorStatusVO struct {
+	Enabled           bool       `json:"enabled"`
+	EnabledAt         *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesLeft int        `json:"recovery_codes_left"`
+}
+
+type TwoFactorEnrollVO struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+	QRCode     string `json:"qr_code"` // PNG二维码的data URI
+}
+
+type RecoveryCodesVO struct {
+	RecoveryCodes []string `json:"recovery_codes"` // 仅展示一次, 请妥善保存
+}
+
+type SessionVO struct {
+	Id        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	UserAgent string    `json:"user_agent"`
+	IP        string    `json:"ip"`
+	Current   bool      `json:"current"` // 是否为发起请求的会话
 }
 
 func (h *UserHandler) UserDomainToVO(user *domain.User) *UserVO {
@@ -34,3 +72,152 @@ func (h *UserHandler) UserDomainToVOList(users []*domain.User) []*UserVO {
 		return h.UserDomainToVO(user)
 	})
 }
+
+func (h *UserHandler) TokenPairToLoginVO(tokenPair *domain.TokenPair) *LoginVO {
+	return &LoginVO{
+		AccessToken:  tokenPair.AccessToken,
+		RefreshToken: tokenPair.RefreshToken,
+		ExpiresIn:    int(tokenPair.ExpiresIn.Seconds()),
+	}
+}
+
+type PersonalTokenVO struct {
+	Id         string     `json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP string     `json:"last_used_ip,omitempty"`
+	Expired    bool       `json:"expired"`
+}
+
+type CreatedPersonalTokenVO struct {
+	*PersonalTokenVO
+	Token string `json:"token"` // 令牌明文, 仅在创建时返回一次
+}
+
+func (h *UserHandler) PersonalTokenDomainToVO(personalToken *domain.PersonalToken) *PersonalTokenVO {
+	vo := &PersonalTokenVO{
+		Id:         personalToken.ID.Hex(),
+		CreatedAt:  personalToken.CreatedAt,
+		Name:       personalToken.Name,
+		Prefix:     personalToken.Prefix,
+		Scopes:     personalToken.Scopes,
+		LastUsedIP: personalToken.LastUsedIP,
+		Expired:    personalToken.Expired(time.Now()),
+	}
+	if !personalToken.ExpiresAt.IsZero() {
+		vo.ExpiresAt = &personalToken.ExpiresAt
+	}
+	if !personalToken.LastUsedAt.IsZero() {
+		vo.LastUsedAt = &personalToken.LastUsedAt
+	}
+	return vo
+}
+
+func (h *UserHandler) PersonalTokenDomainToVOList(personalTokens []*domain.PersonalToken) []*PersonalTokenVO {
+	return lo.Map(personalTokens, func(personalToken *domain.PersonalToken, _ int) *PersonalTokenVO {
+		return h.PersonalTokenDomainToVO(personalToken)
+	})
+}
+
+// LoginResultToVO 需要二次验证时返回临时令牌, 否则返回正式令牌
+func (h *UserHandler) LoginResultToVO(result *domain.LoginResult) any {
+	if result.TokenPair != nil {
+		return h.TokenPairToLoginVO(result.TokenPair)
+	}
+	return &TwoFactorChallengeVO{
+		TwoFactorRequired: true,
+		TwoFactorToken:    result.TwoFactorToken,
+		ExpiresIn:         int(result.TwoFactorExpiresIn.Seconds()),
+	}
+}
+
+func (h *UserHandler) TwoFactorDomainToStatusVO(twoFactor *domain.TwoFactor) *TwoFactorStatusVO {
+	vo := &TwoFactorStatusVO{
+		Enabled:           twoFactor.Enabled,
+		RecoveryCodesLeft: twoFactor.RecoveryCodesLeft,
+	}
+	if twoFactor.Enabled {
+		vo.EnabledAt = &twoFactor.EnabledAt
+	}
+	return vo
+}
+
+func (h *UserHandler) TwoFactorEnrollmentToVO(enrollment *domain.TwoFactorEnrollment) *TwoFactorEnrollVO {
+	return &TwoFactorEnrollVO{
+		Secret:     enrollment.Secret,
+		OtpauthURI: enrollment.URI,
+		QRCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(enrollment.QRCode),
+	}
+}
+
+func (h *UserHandler) SessionDomainToVOList(sessions []*domain.Session, currentSessionId string) []*SessionVO {
+	return lo.Map(sessions, func(session *domain.Session, _ int) *SessionVO {
+		return &SessionVO{
+			Id:        session.ID.Hex(),
+			CreatedAt: session.CreatedAt,
+			UpdatedAt: session.UpdatedAt,
+			ExpiresAt: session.ExpiresAt,
+			UserAgent: session.UserAgent,
+			IP:        session.IP,
+			Current:   session.ID.Hex() == currentSessionId,
+		}
+	})
+}
+
+type LoginLockoutVO struct {
+	Key           string     `json:"key"`
+	Type          string     `json:"type"`  // user: 用户名, ip: IP地址
+	Value         string     `json:"value"` // 用户名或IP
+	Failures      int        `json:"failures"`
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until,omitempty"`
+	Locked        bool       `json:"locked"`
+}
+
+type LoginAuditVO struct {
+	Id        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Username  string    `json:"username"`
+	UserId    string    `json:"user_id,omitempty"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	Success   bool      `json:"success"`
+	Reason    string    `json:"reason"`
+}
+
+func (h *UserHandler) LoginLockoutDomainToVOList(lockouts []*domain.LoginLockout) []*LoginLockoutVO {
+	now := time.Now()
+	return lo.Map(lockouts, func(lockout *domain.LoginLockout, _ int) *LoginLockoutVO {
+		vo := &LoginLockoutVO{
+			Key:           lockout.Key,
+			Type:          lockout.Type,
+			Value:         lockout.Value,
+			Failures:      lockout.Failures,
+			LastFailureAt: lockout.LastFailureAt,
+			Locked:        lockout.LockedUntil.After(now),
+		}
+		if vo.Locked {
+			vo.LockedUntil = &lockout.LockedUntil
+		}
+		return vo
+	})
+}
+
+func (h *UserHandler) LoginAuditDomainToVOList(audits []*domain.LoginAudit) []*LoginAuditVO {
+	return lo.Map(audits, func(audit *domain.LoginAudit, _ int) *LoginAuditVO {
+		return &LoginAuditVO{
+			Id:        audit.ID.Hex(),
+			CreatedAt: audit.CreatedAt,
+			Username:  audit.Username,
+			UserId:    audit.UserId,
+			IP:        audit.IP,
+			UserAgent: audit.UserAgent,
+			Success:   audit.Success,
+			Reason:    audit.Reason,
+		}
+	})
+}
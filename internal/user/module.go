@@ -6,10 +6,14 @@ import (
 )
 
 type (
-	Handler = web.UserHandler
-	Service = service.IUserService
-	Module  struct {
-		Svc Service
-		Hdl *Handler
+	Handler              = web.UserHandler
+	Service              = service.IUserService
+	SessionService       = service.ISessionService
+	PersonalTokenService = service.IPersonalTokenService
+	Module               struct {
+		Svc              Service
+		SessionSvc       SessionService
+		PersonalTokenSvc PersonalTokenService
+		Hdl              *Handler
 	}
 )
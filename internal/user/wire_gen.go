@@ -7,6 +7,11 @@
 package user
 
 import (
+	"github.com/codepzj/Stellux-Server/conf"
+	"github.com/codepzj/Stellux-Server/internal/audit"
+	"github.com/codepzj/Stellux-Server/internal/pkg/limiter"
+	"github.com/codepzj/Stellux-Server/internal/pkg/token"
+	"github.com/codepzj/Stellux-Server/internal/rbac"
 	"github.com/codepzj/Stellux-Server/internal/user/internal/repository"
 	"github.com/codepzj/Stellux-Server/internal/user/internal/repository/dao"
 	"github.com/codepzj/Stellux-Server/internal/user/internal/service"
@@ -17,18 +22,32 @@ import (
 
 // Injectors from wire.go:
 
-func InitUserModule(mongoDB *mongo.Database) *Module {
+func InitUserModule(mongoDB *mongo.Database, cfg *conf.Config, tokenManager *token.Manager, limiterStore limiter.Store, rbacServ rbac.Service, auditServ audit.Service) *Module {
 	userDao := dao.NewUserDao(mongoDB)
 	userRepository := repository.NewUserRepository(userDao)
-	userService := service.NewUserService(userRepository)
-	userHandler := web.NewUserHandler(userService)
+	sessionDao := dao.NewSessionDao(mongoDB)
+	sessionRepository := repository.NewSessionRepository(sessionDao)
+	sessionService := service.NewSessionService(sessionRepository, tokenManager, cfg)
+	personalTokenDao := dao.NewPersonalTokenDao(mongoDB)
+	personalTokenRepository := repository.NewPersonalTokenRepository(personalTokenDao)
+	personalTokenService := service.NewPersonalTokenService(personalTokenRepository, rbacServ)
+	userService := service.NewUserService(userRepository, sessionService, personalTokenService, rbacServ, auditServ)
+	twoFactorDao := dao.NewTwoFactorDao(mongoDB)
+	twoFactorRepository := repository.NewTwoFactorRepository(twoFactorDao)
+	twoFactorService := service.NewTwoFactorService(twoFactorRepository, userService)
+	loginAuditDao := dao.NewLoginAuditDao(mongoDB)
+	loginAuditRepository := repository.NewLoginAuditRepository(loginAuditDao)
+	loginService := service.NewLoginService(userService, sessionService, twoFactorService, tokenManager, loginAuditRepository, limiterStore, cfg)
+	userHandler := web.NewUserHandler(userService, sessionService, loginService, twoFactorService, personalTokenService)
 	module := &Module{
-		Svc: userService,
-		Hdl: userHandler,
+		Svc:              userService,
+		SessionSvc:       sessionService,
+		PersonalTokenSvc: personalTokenService,
+		Hdl:              userHandler,
 	}
 	return module
 }
 
 // wire.go:
 
-var UserProviders = wire.NewSet(web.NewUserHandler, service.NewUserService, repository.NewUserRepository, dao.NewUserDao, wire.Bind(new(service.IUserService), new(*service.UserService)), wire.Bind(new(repository.IUserRepository), new(*repository.UserRepository)), wire.Bind(new(dao.IUserDao), new(*dao.UserDao)))
+var UserProviders = wire.NewSet(web.NewUserHandler, service.NewUserService, repository.NewUserRepository, dao.NewUserDao, service.NewSessionService, repository.NewSessionRepository, dao.NewSessionDao, service.NewLoginService, repository.NewLoginAuditRepository, dao.NewLoginAuditDao, service.NewTwoFactorService, repository.NewTwoFactorRepository, dao.NewTwoFactorDao, service.NewPersonalTokenService, repository.NewPersonalTokenRepository, dao.NewPersonalTokenDao, wire.Bind(new(service.IUserService), new(*service.UserService)), wire.Bind(new(repository.IUserRepository), new(*repository.UserRepository)), wire.Bind(new(dao.IUserDao), new(*dao.UserDao)), wire.Bind(new(service.ISessionService), new(*service.SessionService)), wire.Bind(new(repository.ISessionRepository), new(*repository.SessionRepository)), wire.Bind(new(dao.ISessionDao), new(*dao.SessionDao)), wire.Bind(new(service.ILoginService), new(*service.LoginService)), wire.Bind(new(repository.ILoginAuditRepository), new(*repository.LoginAuditRepository)), wire.Bind(new(dao.ILoginAuditDao), new(*dao.LoginAuditDao)), wire.Bind(new(service.ITwoFactorService), new(*service.TwoFactorService)), wire.Bind(new(repository.ITwoFactorRepository), new(*repository.TwoFactorRepository)), wire.Bind(new(dao.ITwoFactorDao), new(*dao.TwoFactorDao)), wire.Bind(new(service.IPersonalTokenService), new(*service.PersonalTokenService)), wire.Bind(new(repository.IPersonalTokenRepository), new(*repository.PersonalTokenRepository)), wire.Bind(new(dao.IPersonalTokenDao), new(*dao.PersonalTokenDao)))